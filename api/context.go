@@ -1,9 +1,11 @@
 package api
 
 import (
+	"encoding/json"
 	"net/http"
 
 	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/uber-go/zap"
 )
 
 type contextKey int64
@@ -11,8 +13,19 @@ type contextKey int64
 const (
 	idContextKey contextKey = iota
 	claimsContextKey
+	requestInfoContextKey
 )
 
+// requestInfo holds per-request information shared between
+// LogRequests and the handlers it wraps. Inner middleware may
+// fill in fields, such as the user ID, as they become known
+type requestInfo struct {
+	id     string
+	route  string
+	userID int64
+	logger zap.Logger
+}
+
 // Retrieve an ID from the context. Will panic if there was
 // no ID stored using idContextKey or if the stored ID is not
 // an int64
@@ -26,6 +39,50 @@ func contextID(r *http.Request) int64 {
 // an int64
 func claimsID(r *http.Request) (int64, bool) {
 	claims := r.Context().Value(claimsContextKey).(jwt.MapClaims)
-	id, ok := claims["sub"].(int64)
-	return id, ok
+	return subjectID(claims)
+}
+
+// Retrieve the ID of the user viewing a route that may be requested
+// anonymously. Returns 0 if there are no claims within the context or
+// if they do not hold a user ID
+func viewerID(r *http.Request) int64 {
+	claims, ok := r.Context().Value(claimsContextKey).(jwt.MapClaims)
+	if !ok {
+		return 0
+	}
+	id, _ := subjectID(claims)
+	return id
+}
+
+// Retrieve the user ID from the subject of the claims. Claims
+// decoded from a token store numbers as float64 while claims
+// built in code may hold an int64, so both are accepted
+func subjectID(claims jwt.MapClaims) (int64, bool) {
+	switch sub := claims["sub"].(type) {
+	case int64:
+		return sub, true
+	case float64:
+		return int64(sub), sub == float64(int64(sub))
+	case json.Number:
+		id, err := sub.Int64()
+		return id, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// Retrieve the request info stored within an http context.
+// Returns nil if the request did not pass through LogRequests
+func contextRequestInfo(r *http.Request) *requestInfo {
+	info, _ := r.Context().Value(requestInfoContextKey).(*requestInfo)
+	return info
+}
+
+// Retrieve the request-scoped logger from an http context,
+// falling back to the package logger
+func requestLogger(r *http.Request) zap.Logger {
+	if info := contextRequestInfo(r); info != nil && info.logger != nil {
+		return info.logger
+	}
+	return logger
 }
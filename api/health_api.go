@@ -0,0 +1,127 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// Health statuses reported by the HealthAPI
+const (
+	HealthStatusOK           = "ok"
+	HealthStatusDegraded     = "degraded"
+	HealthStatusFailing      = "failing"
+	HealthStatusShuttingDown = "shutting_down"
+)
+
+// HealthCheck checks the health of a single dependency. A failing
+// critical check fails readiness while a failing non-critical check
+// only reports the instance as degraded
+type HealthCheck struct {
+	Name     string
+	Critical bool
+	Check    func(ctx context.Context) error
+}
+
+// HealthAPI contains state information for executing
+// MeIRL liveness and readiness route handlers
+type HealthAPI struct {
+	checks       []HealthCheck
+	timeout      time.Duration
+	shuttingDown int32
+}
+
+// NewHealthAPI returns an instance of the HealthAPI struct. Each
+// check is given timeout to complete when readiness is requested
+func NewHealthAPI(timeout time.Duration, checks ...HealthCheck) *HealthAPI {
+	return &HealthAPI{
+		checks:  checks,
+		timeout: timeout,
+	}
+}
+
+// Shutdown marks the instance as shutting down, causing
+// readiness to fail so that no new traffic is routed to it
+func (api *HealthAPI) Shutdown() {
+	atomic.StoreInt32(&api.shuttingDown, 1)
+}
+
+// Live returns an http handler that reports the process is alive
+func (api *HealthAPI) Live() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(HealthResponse{Status: HealthStatusOK}, w)
+	}
+}
+
+// Ready returns an http handler that reports whether the instance
+// can take traffic by running every health check. Responds with a
+// 503 Service Unavailable if shutting down or if a critical check fails
+func (api *HealthAPI) Ready() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&api.shuttingDown) == 1 {
+			w.Header().Add("Content-Type", "application/json")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			writeJSON(HealthResponse{Status: HealthStatusShuttingDown}, w)
+			return
+		}
+
+		resp := HealthResponse{
+			Status: HealthStatusOK,
+			Checks: api.runChecks(r.Context()),
+		}
+		for _, result := range resp.Checks {
+			if result.Status == HealthStatusOK {
+				continue
+			}
+			if result.Critical {
+				resp.Status = HealthStatusFailing
+			} else if resp.Status == HealthStatusOK {
+				resp.Status = HealthStatusDegraded
+			}
+		}
+		if resp.Status == HealthStatusFailing {
+			w.Header().Add("Content-Type", "application/json")
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		writeJSON(resp, w)
+	}
+}
+
+// Run all checks concurrently, bounding each by the API timeout
+func (api *HealthAPI) runChecks(ctx context.Context) map[string]HealthCheckResult {
+	ctx, cancel := context.WithTimeout(ctx, api.timeout)
+	defer cancel()
+
+	type namedResult struct {
+		name   string
+		result HealthCheckResult
+	}
+	resultc := make(chan namedResult, len(api.checks))
+	for _, check := range api.checks {
+		go func(check HealthCheck) {
+			errc := make(chan error, 1)
+			go func() {
+				errc <- check.Check(ctx)
+			}()
+			var err error
+			select {
+			case err = <-errc:
+			case <-ctx.Done():
+				err = ctx.Err()
+			}
+			result := HealthCheckResult{Status: HealthStatusOK, Critical: check.Critical}
+			if err != nil {
+				result.Status = HealthStatusFailing
+				result.Error = err.Error()
+			}
+			resultc <- namedResult{name: check.Name, result: result}
+		}(check)
+	}
+	results := make(map[string]HealthCheckResult, len(api.checks))
+	for range api.checks {
+		nr := <-resultc
+		results[nr.name] = nr.result
+	}
+	return results
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func okCheck(ctx context.Context) error {
+	return nil
+}
+
+func failingCheck(ctx context.Context) error {
+	return errors.New("down")
+}
+
+func readiness(t *testing.T, api *HealthAPI) (int, HealthResponse) {
+	r, _ := http.NewRequest("GET", "/readyz", nil)
+	w := httptest.NewRecorder()
+	api.Ready()(w, r)
+	var resp HealthResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	if err != nil {
+		t.Error(err.Error())
+		t.FailNow()
+	}
+	return w.Code, resp
+}
+
+func TestLive(t *testing.T) {
+	api := NewHealthAPI(time.Second, HealthCheck{Name: "db", Critical: true, Check: failingCheck})
+	r, _ := http.NewRequest("GET", "/healthz", nil)
+	w := httptest.NewRecorder()
+	api.Live()(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status code %d, received %d", http.StatusOK, w.Code)
+		t.Fail()
+	}
+}
+
+func TestReady(t *testing.T) {
+	api := NewHealthAPI(time.Second,
+		HealthCheck{Name: "db", Critical: true, Check: okCheck},
+		HealthCheck{Name: "cache", Check: okCheck},
+	)
+	code, resp := readiness(t, api)
+	if code != http.StatusOK {
+		t.Errorf("Expected status code %d, received %d", http.StatusOK, code)
+		t.Fail()
+	}
+	if resp.Status != HealthStatusOK || len(resp.Checks) != 2 {
+		t.Errorf("Expected ok status with 2 checks, got %s with %d", resp.Status, len(resp.Checks))
+		t.Fail()
+	}
+}
+
+func TestReadyDegraded(t *testing.T) {
+	api := NewHealthAPI(time.Second,
+		HealthCheck{Name: "db", Critical: true, Check: okCheck},
+		HealthCheck{Name: "cache", Check: failingCheck},
+	)
+	code, resp := readiness(t, api)
+	if code != http.StatusOK {
+		t.Errorf("Expected status code %d, received %d", http.StatusOK, code)
+		t.Fail()
+	}
+	if resp.Status != HealthStatusDegraded {
+		t.Errorf("Expected degraded status, got %s", resp.Status)
+		t.Fail()
+	}
+	if resp.Checks["cache"].Error != "down" {
+		t.Error("Expected failing check to report its error")
+		t.Fail()
+	}
+}
+
+func TestReadyFailing(t *testing.T) {
+	api := NewHealthAPI(10*time.Millisecond,
+		HealthCheck{Name: "db", Critical: true, Check: func(ctx context.Context) error {
+			time.Sleep(time.Second)
+			return nil
+		}},
+	)
+	code, resp := readiness(t, api)
+	if code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status code %d, received %d", http.StatusServiceUnavailable, code)
+		t.Fail()
+	}
+	if resp.Status != HealthStatusFailing {
+		t.Errorf("Expected failing status for timed out check, got %s", resp.Status)
+		t.Fail()
+	}
+}
+
+func TestReadyShuttingDown(t *testing.T) {
+	api := NewHealthAPI(time.Second, HealthCheck{Name: "db", Critical: true, Check: okCheck})
+	api.Shutdown()
+	code, resp := readiness(t, api)
+	if code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status code %d, received %d", http.StatusServiceUnavailable, code)
+		t.Fail()
+	}
+	if resp.Status != HealthStatusShuttingDown {
+		t.Errorf("Expected shutting down status, got %s", resp.Status)
+		t.Fail()
+	}
+}
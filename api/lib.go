@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/boxtown/meirl/data"
+	"github.com/boxtown/meirl/realtime"
 	jwt "github.com/dgrijalva/jwt-go"
 	"github.com/uber-go/zap"
 	"golang.org/x/crypto/bcrypt"
@@ -17,6 +19,9 @@ const (
 	apiVersion = "1.0"
 )
 
+// Version is the version of the MeIRL API
+const Version = apiVersion
+
 var logger = zap.New(zap.NewTextEncoder())
 
 // PrefixAPIPath appends API-specific prefixes to the
@@ -48,6 +53,117 @@ func ListOptionsFromRequest(request *http.Request) data.ListOptions {
 	return options
 }
 
+// Post sorts accepted by the sort query parameter
+const (
+	postSortDate = "date"
+	postSortTop  = "top"
+)
+
+// parseTimeMarker parses the marker of a list sorted by time, which is
+// either the marker of the last entity of the previous page or a time
+// in seconds since the epoch to list the entities after
+func parseTimeMarker(marker string) (interface{}, error) {
+	if strings.Contains(marker, ":") {
+		return data.ParseTimeMarker(marker)
+	}
+	unix, err := strconv.ParseInt(marker, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return time.Unix(unix, 0), nil
+}
+
+// postSortFromRequest returns the post sort requested by the sort query
+// parameter and parses the marker of the list options accordingly.
+// Posts sorted by date are marked as by parseTimeMarker while top
+// posts are marked by the marker of the last post of the previous page
+// and are listed best first unless desc is given. Returns false if the
+// sort or the marker is invalid
+func postSortFromRequest(r *http.Request, options *data.ListOptions) (data.PostSortMethod, bool) {
+	values := r.URL.Query()
+	marker := options.Marker.(string)
+	switch values.Get("sort") {
+	case "", postSortDate:
+		if marker != "" {
+			m, err := parseTimeMarker(marker)
+			if err != nil {
+				return 0, false
+			}
+			options.Marker = m
+		}
+		return data.PostSortByDate, true
+	case postSortTop:
+		if values.Get("desc") == "" {
+			options.Desc = true
+		}
+		if marker != "" {
+			m, err := data.ParseScoreMarker(marker)
+			if err != nil {
+				return 0, false
+			}
+			options.Marker = m
+		}
+		return data.PostSortByScore, true
+	}
+	return 0, false
+}
+
+// newestListFromRequest returns the list options and post sort of a
+// request for a list of posts that is sorted descending unless desc
+// is given. Returns false if the sort or the marker is invalid
+func newestListFromRequest(r *http.Request) (data.ListOptions, data.PostSortMethod, bool) {
+	options := ListOptionsFromRequest(r)
+	if r.URL.Query().Get("desc") == "" {
+		options.Desc = true
+	}
+	sort, ok := postSortFromRequest(r, &options)
+	return options, sort, ok
+}
+
+// canView returns true if the viewer may see the posts of the author,
+// which users blocked by or blocking the author may not, and which
+// only the followers of private authors may. Anonymous viewers have
+// an ID of 0
+func canView(stores data.Stores, viewerID int64, author *data.User) (bool, error) {
+	if viewerID == author.ID {
+		return true, nil
+	}
+	if viewerID != 0 {
+		blocked, err := stores.IsBlocked(viewerID, author.ID)
+		if err != nil || blocked {
+			return false, err
+		}
+	}
+	if !author.Private {
+		return true, nil
+	}
+	if viewerID == 0 {
+		return false, nil
+	}
+	return stores.IsFollowing(viewerID, author.ID)
+}
+
+// getVisiblePost retrieves the post with the given id, returning
+// data.ErrNoEnt if the viewer may not see it
+func getVisiblePost(stores data.Stores, id, viewerID int64) (*data.Post, error) {
+	post, err := stores.PostStore.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	author, err := stores.UserStore.Get(post.AuthorID)
+	if err != nil {
+		return nil, err
+	}
+	visible, err := canView(stores, viewerID, author)
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		return nil, data.ErrNoEnt
+	}
+	return post, nil
+}
+
 // Auth is an interface for API authentication
 type Auth interface {
 	SecurePassword(password string) (string, error)
@@ -101,12 +217,72 @@ func writeJSON(body interface{}, w http.ResponseWriter) {
 	json.NewEncoder(w).Encode(body)
 }
 
-// Write a 503 error response to the response writer. If debug is true,
-// will write the error message as well
-func writeError(err error, w http.ResponseWriter, debug bool) {
+// Write an RFC 7807 problem details response with the given status
+// to the response writer
+func writeProblem(status int, detail string, w http.ResponseWriter, r *http.Request) {
+	problem := ProblemResponse{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	}
+	if info := contextRequestInfo(r); info != nil {
+		problem.RequestID = info.id
+	}
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(problem)
+}
+
+// Publish the event, logging rather than failing the request
+// if it could not be published
+func publish(events realtime.Publisher, e realtime.Event, r *http.Request) {
+	if err := events.Publish(e); err != nil {
+		requestLogger(r).Error("could not publish event", zap.String("type", e.Type), zap.Error(err))
+	}
+}
+
+// Notify the user of activity by the actor and publish the notification,
+// logging rather than failing the request if it could not be created.
+// Users are not notified of their own activity, nor of activity by
+// users they blocked or were blocked by
+func notify(
+	stores data.Stores,
+	events realtime.Publisher,
+	userID, actorID int64,
+	notificationType data.NotificationType,
+	postID int64,
+	r *http.Request) {
+	if userID == actorID {
+		return
+	}
+	blocked, err := stores.IsBlocked(userID, actorID)
+	if err != nil {
+		requestLogger(r).Error("could not check blocks", zap.String("type", string(notificationType)), zap.Error(err))
+		return
+	}
+	if blocked {
+		return
+	}
+	id, err := stores.NotificationStore.Notify(userID, actorID, notificationType, postID)
+	if err != nil {
+		requestLogger(r).Error("could not create notification", zap.String("type", string(notificationType)), zap.Error(err))
+		return
+	}
+	if id == 0 {
+		// the user disabled notifications of the type
+		return
+	}
+	publish(events, realtime.Event{Type: realtime.NotificationCreated, ID: id, UserID: userID}, r)
+}
+
+// Write a 503 error response to the response writer and log the error
+// using the request-scoped logger. If debug is true, will write the error
+// message as well
+func writeError(err error, w http.ResponseWriter, r *http.Request, debug bool) {
+	requestLogger(r).Error(err.Error())
 	w.WriteHeader(http.StatusServiceUnavailable)
 	if debug {
-		logger.Error(err.Error())
 		fmt.Fprintf(w, err.Error())
 	}
 }
@@ -1,15 +1,39 @@
 package api
 
 import (
+	"bufio"
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"net"
 	"net/http"
+	"regexp"
+	"runtime/debug"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/boxtown/meirl/data"
+	"github.com/boxtown/meirl/metrics"
+	"github.com/boxtown/meirl/ratelimit"
 	jwt "github.com/dgrijalva/jwt-go"
 	"github.com/gorilla/mux"
+	"github.com/uber-go/zap"
 )
 
+// RequestIDHeader is the header used to receive and propagate
+// request IDs
+const RequestIDHeader = "X-Request-ID"
+
+// unmatchedRoute is the route template reported for requests
+// that did not match any route
+const unmatchedRoute = "unmatched"
+
+var requestIDRegex = regexp.MustCompile("^[0-9a-zA-Z._:-]{1,128}$")
+
 // CORS is a middleware function that handles CORS logic
 func CORS(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -57,31 +81,358 @@ func GetIDMiddleware(next http.HandlerFunc) http.HandlerFunc {
 // GetClaimsMiddleware is a middleware that attempts to parse a JWT from the
 // 'Authorization' header and injects it into API functions requesting a
 // JWT. Responds with a 400 Bad Request if the header is not found or
-// invalid
-func GetClaimsMiddleware(signingKey []byte, next http.HandlerFunc) http.HandlerFunc {
+// invalid, or if the user the JWT was issued to was deleted or disabled
+func GetClaimsMiddleware(signingKey []byte, users data.UserStore, next http.HandlerFunc) http.HandlerFunc {
+	return claimsMiddleware(signingKey, users, bearerToken, next)
+}
+
+// GetOptionalClaimsMiddleware is a GetClaimsMiddleware for routes that
+// may be requested anonymously. Requests without an 'Authorization'
+// header are passed on without claims, while invalid headers are
+// rejected with a 400 Bad Request
+func GetOptionalClaimsMiddleware(signingKey []byte, users data.UserStore, next http.HandlerFunc) http.HandlerFunc {
+	withClaims := GetClaimsMiddleware(signingKey, users, next)
 	return func(w http.ResponseWriter, r *http.Request) {
-		authHeader := r.Header.Get("Authorization")
-		if authHeader == "" {
-			w.WriteHeader(http.StatusBadRequest)
+		if r.Header.Get("Authorization") == "" {
+			next(w, r)
 			return
 		}
-		parts := strings.Split(strings.TrimSpace(authHeader), " ")
-		if parts[0] != "Bearer" || len(parts) < 2 {
+		withClaims(w, r)
+	}
+}
+
+// GetSocketClaimsMiddleware is a GetClaimsMiddleware for WebSocket
+// handshakes. Browsers cannot set headers on WebSocket handshakes, so
+// the JWT may instead be passed in the 'access_token' query param
+func GetSocketClaimsMiddleware(signingKey []byte, users data.UserStore, next http.HandlerFunc) http.HandlerFunc {
+	return claimsMiddleware(signingKey, users, func(r *http.Request) (string, bool) {
+		if token := r.URL.Query().Get("access_token"); token != "" {
+			return token, true
+		}
+		return bearerToken(r)
+	}, next)
+}
+
+func claimsMiddleware(signingKey []byte, users data.UserStore, tokenString func(*http.Request) (string, bool), next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		raw, ok := tokenString(r)
+		if !ok {
 			w.WriteHeader(http.StatusBadRequest)
 			return
 		}
-		token, err := jwt.Parse(parts[1], func(token *jwt.Token) (interface{}, error) {
+		token, err := jwt.Parse(raw, func(token *jwt.Token) (interface{}, error) {
 			return signingKey, nil
 		})
 		if err != nil {
 			w.WriteHeader(http.StatusBadRequest)
 			return
 		}
-		if _, ok := token.Claims.(jwt.MapClaims); !ok {
+		claims, ok := token.Claims.(jwt.MapClaims)
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		id, ok := subjectID(claims)
+		if !ok {
 			w.WriteHeader(http.StatusBadRequest)
 			return
 		}
+		// tokens remain valid after their users are deleted or disabled
+		user, err := users.Get(id)
+		if err == data.ErrNoEnt || err == nil && user.Disabled {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		} else if err != nil {
+			writeError(err, w, r, false)
+			return
+		}
+		if info := contextRequestInfo(r); info != nil {
+			info.userID = id
+			info.logger = info.logger.With(zap.Int64("user_id", id))
+		}
 		r = r.WithContext(context.WithValue(r.Context(), claimsContextKey, token.Claims))
 		next(w, r)
 	}
 }
+
+// Get the token from a 'Bearer' Authorization header
+func bearerToken(r *http.Request) (string, bool) {
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		return "", false
+	}
+	parts := strings.Split(strings.TrimSpace(authHeader), " ")
+	if parts[0] != "Bearer" || len(parts) < 2 {
+		return "", false
+	}
+	return parts[1], true
+}
+
+// RateLimitMiddleware is a middleware function that limits requests to
+// the given rate using a token bucket per client. Clients are identified
+// by the authenticated user if claims are present in the context, otherwise
+// by IP address. Buckets are namespaced by name so that routes may be
+// limited independently. Responds with a 429 Too Many Requests once the
+// bucket is empty. Requests are allowed if the store fails
+func RateLimitMiddleware(store ratelimit.Store, name string, rate ratelimit.Rate, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		result, err := store.Take(name+":"+rateLimitKey(r), rate)
+		if err != nil {
+			requestLogger(r).Error("rate limit store failed", zap.Error(err))
+			next(w, r)
+			return
+		}
+		w.Header().Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+		w.Header().Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		w.Header().Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+		if !result.Allowed {
+			w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+			writeProblem(http.StatusTooManyRequests, "Rate limit exceeded", w, r)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// Identify the client making a request by user ID if
+// authenticated, otherwise by IP address
+func rateLimitKey(r *http.Request) string {
+	if claims, ok := r.Context().Value(claimsContextKey).(jwt.MapClaims); ok {
+		if id, ok := subjectID(claims); ok {
+			return "user:" + strconv.FormatInt(id, 10)
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// RouteMatcher matches requests against a set of routes.
+// *mux.Router implements RouteMatcher
+type RouteMatcher interface {
+	Match(r *http.Request, match *mux.RouteMatch) bool
+}
+
+// RequestLogger is a middleware that assigns every request an ID,
+// makes a request-scoped logger available to handlers and writes
+// a structured access log entry once the request completes
+type RequestLogger struct {
+	h      http.Handler
+	logger zap.Logger
+	routes RouteMatcher
+}
+
+func (rl RequestLogger) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	id := r.Header.Get(RequestIDHeader)
+	if !requestIDRegex.MatchString(id) {
+		id = newRequestID()
+	}
+	info := &requestInfo{
+		id:    id,
+		route: routeTemplate(rl.routes, r),
+	}
+	info.logger = rl.logger.With(zap.String("request_id", id))
+	w.Header().Set(RequestIDHeader, id)
+
+	sw := &statusWriter{ResponseWriter: w}
+	rl.h.ServeHTTP(sw, r.WithContext(context.WithValue(r.Context(), requestInfoContextKey, info)))
+
+	fields := []zap.Field{
+		zap.String("method", r.Method),
+		zap.String("route", info.route),
+		zap.Int("status", sw.Status()),
+		zap.Duration("latency", time.Since(start)),
+		zap.Int64("bytes", sw.bytes),
+	}
+	// info.logger carries the request and user IDs
+	if sw.Status() >= http.StatusInternalServerError {
+		info.logger.Error("request", fields...)
+	} else {
+		info.logger.Info("request", fields...)
+	}
+}
+
+// LogRequests returns an http handler that wraps the given handler
+// within a RequestLogger. Incoming X-Request-ID headers are propagated,
+// otherwise a new ID is generated. Route templates are resolved using
+// routes, which may be nil
+func LogRequests(logger zap.Logger, routes RouteMatcher, handler http.Handler) http.Handler {
+	return &RequestLogger{
+		h:      handler,
+		logger: logger,
+		routes: routes,
+	}
+}
+
+// RequestInstrumenter is a middleware that records request counts
+// and latencies labeled by route template, method and status
+type RequestInstrumenter struct {
+	h      http.Handler
+	routes RouteMatcher
+}
+
+func (ri RequestInstrumenter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	sw, ok := w.(*statusWriter)
+	if !ok {
+		sw = &statusWriter{ResponseWriter: w}
+	}
+	ri.h.ServeHTTP(sw, r)
+
+	var route string
+	if info := contextRequestInfo(r); info != nil {
+		route = info.route
+	} else {
+		route = routeTemplate(ri.routes, r)
+	}
+	status := strconv.Itoa(sw.Status())
+	metrics.HTTPRequests.WithLabelValues(route, r.Method, status).Inc()
+	metrics.HTTPRequestDuration.WithLabelValues(route, r.Method, status).Observe(time.Since(start).Seconds())
+}
+
+// InstrumentRequests returns an http handler that wraps the given handler
+// within a RequestInstrumenter. Route templates are taken from LogRequests
+// if the handler is wrapped by it, otherwise they are resolved using routes,
+// which may be nil
+func InstrumentRequests(routes RouteMatcher, handler http.Handler) http.Handler {
+	return &RequestInstrumenter{
+		h:      handler,
+		routes: routes,
+	}
+}
+
+// PanicRecoverer is a middleware that recovers from panics within
+// the handlers it wraps, logging the panic and stack trace and
+// responding with a problem+json 500 Internal Server Error
+type PanicRecoverer struct {
+	h http.Handler
+}
+
+func (pr PanicRecoverer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	sw, ok := w.(*statusWriter)
+	if !ok {
+		sw = &statusWriter{ResponseWriter: w}
+	}
+	defer func() {
+		rec := recover()
+		if rec == nil {
+			return
+		}
+		// let the server abort the response as intended
+		if rec == http.ErrAbortHandler {
+			panic(rec)
+		}
+		route := unmatchedRoute
+		if info := contextRequestInfo(r); info != nil {
+			route = info.route
+		}
+		metrics.Panics.WithLabelValues(route).Inc()
+		requestLogger(r).Error("recovered from panic",
+			zap.String("panic", fmt.Sprint(rec)),
+			zap.String("stack", string(debug.Stack())),
+		)
+		if sw.status != 0 {
+			// too late to send an error response
+			return
+		}
+		writeProblem(http.StatusInternalServerError, "", sw, r)
+	}()
+	pr.h.ServeHTTP(sw, r)
+}
+
+// RecoverPanics returns an http handler that wraps the given
+// handler within a PanicRecoverer
+func RecoverPanics(handler http.Handler) http.Handler {
+	return &PanicRecoverer{h: handler}
+}
+
+// Generate a random 128-bit request ID
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return hex.EncodeToString(b)
+}
+
+// Resolve the template of the route matching the request
+func routeTemplate(routes RouteMatcher, r *http.Request) string {
+	if routes == nil {
+		return unmatchedRoute
+	}
+	var match mux.RouteMatch
+	if !routes.Match(r, &match) || match.Route == nil {
+		return unmatchedRoute
+	}
+	tmpl, err := match.Route.GetPathTemplate()
+	if err != nil {
+		return unmatchedRoute
+	}
+	return tmpl
+}
+
+// statusWriter is an http.ResponseWriter that records
+// the status code and number of bytes written
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (sw *statusWriter) WriteHeader(status int) {
+	if sw.status == 0 {
+		sw.status = status
+	}
+	sw.ResponseWriter.WriteHeader(status)
+}
+
+func (sw *statusWriter) Write(b []byte) (int, error) {
+	if sw.status == 0 {
+		sw.status = http.StatusOK
+	}
+	n, err := sw.ResponseWriter.Write(b)
+	sw.bytes += int64(n)
+	return n, err
+}
+
+// Flush implements http.Flusher if the wrapped writer does
+func (sw *statusWriter) Flush() {
+	if f, ok := sw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker if the wrapped writer does,
+// recording the connection as switched protocols
+func (sw *statusWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := sw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("hijacking is not supported")
+	}
+	conn, rw, err := h.Hijack()
+	if err == nil && sw.status == 0 {
+		sw.status = http.StatusSwitchingProtocols
+	}
+	return conn, rw, err
+}
+
+// Unwrap returns the wrapped writer for use by http.ResponseController
+func (sw *statusWriter) Unwrap() http.ResponseWriter {
+	return sw.ResponseWriter
+}
+
+// Status returns the status written, defaulting to 200
+// if nothing was written
+func (sw *statusWriter) Status() int {
+	if sw.status == 0 {
+		return http.StatusOK
+	}
+	return sw.status
+}
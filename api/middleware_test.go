@@ -0,0 +1,229 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/boxtown/meirl/api/apitest"
+	"github.com/boxtown/meirl/data"
+	"github.com/boxtown/meirl/metrics"
+	"github.com/boxtown/meirl/ratelimit"
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestLogRequestsGeneratesRequestID(t *testing.T) {
+	var info *requestInfo
+	handler := LogRequests(logger, nil, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		info = contextRequestInfo(r)
+	}))
+
+	r, _ := http.NewRequest("GET", "/test", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	id := w.HeaderMap.Get(RequestIDHeader)
+	if id == "" {
+		t.Error("Expected a generated request ID header")
+		t.FailNow()
+	}
+	if info == nil || info.id != id {
+		t.Error("Expected request info with the generated request ID in the context")
+		t.Fail()
+	}
+}
+
+func TestLogRequestsPropagatesRequestID(t *testing.T) {
+	handler := LogRequests(logger, nil, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	r, _ := http.NewRequest("GET", "/test", nil)
+	r.Header.Set(RequestIDHeader, "abc-123")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if id := w.HeaderMap.Get(RequestIDHeader); id != "abc-123" {
+		t.Errorf("Expected propagated request ID abc-123, got %s", id)
+		t.Fail()
+	}
+
+	r.Header.Set(RequestIDHeader, "bad id\n")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if id := w.HeaderMap.Get(RequestIDHeader); id == "bad id\n" || id == "" {
+		t.Errorf("Expected invalid request ID to be replaced, got %q", id)
+		t.Fail()
+	}
+}
+
+func TestLogRequestsRecordsRouteAndUser(t *testing.T) {
+	signingKey := []byte("test")
+	token, _ := NewAuth().GenerateAccessToken(&data.User{Mutable: data.Mutable{AutoIncr: data.AutoIncr{ID: 7}}}, signingKey)
+
+	var info *requestInfo
+	router := mux.NewRouter()
+	users := mockUserStore{
+		OnGet: func(id int64) (*data.User, error) {
+			return &data.User{Mutable: data.Mutable{AutoIncr: data.AutoIncr{ID: id}}}, nil
+		},
+	}
+	router.HandleFunc(PrefixAPIPath("user/{id:[0-9]+}"), GetClaimsMiddleware(signingKey, users, func(w http.ResponseWriter, r *http.Request) {
+		info = contextRequestInfo(r)
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	handler := LogRequests(logger, router, router)
+
+	r, _ := http.NewRequest("GET", PrefixAPIPath("user/3"), nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	if w.Code != http.StatusTeapot {
+		t.Errorf("Expected status code %d, received %d", http.StatusTeapot, w.Code)
+		t.FailNow()
+	}
+	if info.route != PrefixAPIPath("user/{id:[0-9]+}") {
+		t.Errorf("Expected route template, got %s", info.route)
+		t.Fail()
+	}
+	if info.userID != 7 {
+		t.Errorf("Expected user ID 7 from claims, got %d", info.userID)
+		t.Fail()
+	}
+}
+
+func TestClaimsMiddlewareRejectsDisabledUsers(t *testing.T) {
+	signingKey := []byte("test")
+	errUnavailable := data.NewError(errors.New("unavailable"))
+	tests := []struct {
+		user   *data.User
+		err    error
+		status int
+	}{
+		{user: &data.User{}, status: http.StatusTeapot},
+		{user: &data.User{Disabled: true}, status: http.StatusBadRequest},
+		{err: data.ErrNoEnt, status: http.StatusBadRequest},
+		{err: errUnavailable, status: http.StatusServiceUnavailable},
+	}
+	for _, test := range tests {
+		token, _ := NewAuth().GenerateAccessToken(&data.User{Mutable: data.Mutable{AutoIncr: data.AutoIncr{ID: 7}}}, signingKey)
+		users := mockUserStore{
+			OnGet: func(id int64) (*data.User, error) {
+				if id != 7 {
+					t.Errorf("Expected the user the token was issued to, got %d", id)
+				}
+				return test.user, test.err
+			},
+		}
+		handler := GetClaimsMiddleware(signingKey, users, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTeapot)
+		})
+
+		r, _ := http.NewRequest("GET", "/", nil)
+		r.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		handler(w, r)
+		if w.Code != test.status {
+			t.Errorf("Expected status code %d for user %v and error %v, received %d", test.status, test.user, test.err, w.Code)
+			t.Fail()
+		}
+	}
+}
+
+func TestInstrumentRequests(t *testing.T) {
+	router := mux.NewRouter()
+	router.HandleFunc(PrefixAPIPath("post/{id:[0-9]+}"), func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	handler := LogRequests(logger, router, InstrumentRequests(router, router))
+	route := PrefixAPIPath("post/{id:[0-9]+}")
+	before := testutil.ToFloat64(metrics.HTTPRequests.WithLabelValues(route, "GET", "404"))
+
+	r, _ := http.NewRequest("GET", PrefixAPIPath("post/1"), nil)
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+
+	after := testutil.ToFloat64(metrics.HTTPRequests.WithLabelValues(route, "GET", "404"))
+	if after-before != 1 {
+		t.Errorf("Expected request to be counted under route %s", route)
+		t.Fail()
+	}
+}
+
+func TestRecoverPanics(t *testing.T) {
+	handler := LogRequests(logger, nil, RecoverPanics(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contextID(r)
+	})))
+
+	r, _ := http.NewRequest("GET", "/test", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status code %d, received %d", http.StatusInternalServerError, w.Code)
+		t.Fail()
+	}
+	if ct := w.HeaderMap.Get("Content-Type"); ct != "application/problem+json" {
+		t.Errorf("Expected problem+json content type, got %s", ct)
+		t.Fail()
+	}
+	var problem ProblemResponse
+	err := json.NewDecoder(w.Body).Decode(&problem)
+	if err != nil {
+		t.Error(err.Error())
+		t.FailNow()
+	}
+	if problem.Status != http.StatusInternalServerError {
+		t.Errorf("Expected problem status %d, got %d", http.StatusInternalServerError, problem.Status)
+		t.Fail()
+	}
+	if problem.RequestID == "" || problem.RequestID != w.HeaderMap.Get(RequestIDHeader) {
+		t.Error("Expected problem to carry the request ID")
+		t.Fail()
+	}
+	if n := testutil.ToFloat64(metrics.Panics.WithLabelValues(unmatchedRoute)); n < 1 {
+		t.Error("Expected panic to be counted")
+		t.Fail()
+	}
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	store := ratelimit.NewMemoryStore()
+	rate := ratelimit.Rate{Limit: 1, Period: time.Minute}
+	handler := RateLimitMiddleware(store, "test", rate, func(w http.ResponseWriter, r *http.Request) {})
+
+	r, _ := http.NewRequest("POST", "/test", nil)
+	r.RemoteAddr = "10.0.0.1:1234"
+	w := httptest.NewRecorder()
+	handler(w, r)
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status code %d, received %d", http.StatusOK, w.Code)
+		t.Fail()
+	}
+	if w.HeaderMap.Get("RateLimit-Limit") != "1" || w.HeaderMap.Get("RateLimit-Remaining") != "0" {
+		t.Error("Expected rate limit headers on allowed request")
+		t.Fail()
+	}
+
+	w = httptest.NewRecorder()
+	handler(w, r)
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected status code %d, received %d", http.StatusTooManyRequests, w.Code)
+		t.Fail()
+	}
+	if w.HeaderMap.Get("Retry-After") != "60" {
+		t.Errorf("Expected Retry-After of 60 seconds, got %s", w.HeaderMap.Get("Retry-After"))
+		t.Fail()
+	}
+
+	// authenticated users get their own bucket
+	r = apitest.RequestWithClaims(r, claimsContextKey, jwt.MapClaims{"sub": int64(1)})
+	w = httptest.NewRecorder()
+	handler(w, r)
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status code %d for user bucket, received %d", http.StatusOK, w.Code)
+		t.Fail()
+	}
+}
@@ -1,5 +1,7 @@
 package api
 
+import "github.com/boxtown/meirl/data"
+
 // TokenResponse is the model for an Access Token response
 type TokenResponse struct {
 	AccessToken string `json:"accessToken"`
@@ -10,3 +12,96 @@ type TokenResponse struct {
 type IDResponse struct {
 	ID int64 `json:"id"`
 }
+
+// HealthResponse is the model for a liveness or
+// readiness response
+type HealthResponse struct {
+	Status string                       `json:"status"`
+	Checks map[string]HealthCheckResult `json:"checks,omitempty"`
+}
+
+// HealthCheckResult is the model for the result of
+// a single dependency health check
+type HealthCheckResult struct {
+	Status   string `json:"status"`
+	Critical bool   `json:"critical"`
+	Error    string `json:"error,omitempty"`
+}
+
+// ProblemResponse is the model for an RFC 7807
+// problem details response
+type ProblemResponse struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail,omitempty"`
+	RequestID string `json:"requestId,omitempty"`
+}
+
+// FollowResponse is the model for a response to following a
+// user, which is pending if the user must approve the request
+type FollowResponse struct {
+	Pending bool `json:"pending"`
+}
+
+// PrivacyRequest is the model for a request making
+// a user private or public
+type PrivacyRequest struct {
+	Private bool `json:"private"`
+}
+
+// UnreadCountResponse is the model for a response containing
+// the number of unread notifications
+type UnreadCountResponse struct {
+	Count int `json:"count"`
+}
+
+// MarkReadRequest is the model for a request marking
+// notifications as read
+type MarkReadRequest struct {
+	IDs []int64 `json:"ids"`
+}
+
+// ThreadResponse is the model for a response containing a post,
+// the posts it replies to starting from the start of the thread and
+// a page of the replies to it
+type ThreadResponse struct {
+	Ancestors []data.Post `json:"ancestors"`
+	Post      *data.Post  `json:"post"`
+	Replies   []ReplyNode `json:"replies"`
+}
+
+// ReplyNode is the model for a reply in a thread and its replies.
+// Replies beyond the depth of the thread are counted by the
+// replies field of the post but not included
+type ReplyNode struct {
+	data.Post
+	Children []ReplyNode `json:"children"`
+}
+
+// UserSuggestion is the model for a user suggested
+// while composing a mention
+type UserSuggestion struct {
+	ID         int64  `json:"id"`
+	Username   string `json:"username"`
+	ActualName string `json:"actualName"`
+}
+
+// SocketMessage is the model for messages exchanged over the
+// WebSocket gateway. Clients send subscribe and unsubscribe messages
+// naming a topic, and the ID of the post for the post topic. The
+// gateway replies with subscribed, unsubscribed or error messages
+// and pushes post, reactions and notification messages for each
+// subscribed topic
+type SocketMessage struct {
+	Type  string     `json:"type"`
+	Topic string     `json:"topic,omitempty"`
+	ID    int64      `json:"id,omitempty"`
+	Post  *data.Post `json:"post,omitempty"`
+	Keks  *int       `json:"keks,omitempty"`
+	Nos   *int       `json:"nos,omitempty"`
+
+	Notification *data.Notification `json:"notification,omitempty"`
+
+	Error string `json:"error,omitempty"`
+}
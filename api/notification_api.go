@@ -0,0 +1,153 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/boxtown/meirl/data"
+)
+
+// NotificationAPI contains state information for executing
+// MeIRL Notification API route handlers
+type NotificationAPI struct {
+	stores data.Stores
+	debug  bool
+}
+
+// NewNotificationAPI returns an instance of the NotificationAPI struct
+func NewNotificationAPI(stores data.Stores, debug bool) NotificationAPI {
+	return NotificationAPI{
+		stores: stores,
+		debug:  debug,
+	}
+}
+
+// ListNotifications returns an http handler that handles API requests
+// listing the notifications of the authenticated user. Notifications
+// are listed most recent first unless desc is false, and only unread
+// notifications are listed if unread is true
+func (api NotificationAPI) ListNotifications() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := claimsID(r)
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		options := ListOptionsFromRequest(r)
+		if r.URL.Query().Get("desc") == "" {
+			options.Desc = true
+		}
+		if marker := options.Marker.(string); marker != "" {
+			m, err := parseTimeMarker(marker)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			options.Marker = m
+		}
+		unreadOnly, _ := strconv.ParseBool(r.URL.Query().Get("unread"))
+		notifications, err := api.stores.NotificationStore.List(id, options, unreadOnly)
+		if err != nil {
+			writeError(err, w, r, api.debug)
+			return
+		}
+		if notifications == nil {
+			notifications = []data.Notification{}
+		}
+		writeJSON(notifications, w)
+	}
+}
+
+// UnreadCount returns an http handler that handles API requests for
+// the number of unread notifications of the authenticated user
+func (api NotificationAPI) UnreadCount() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := claimsID(r)
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		count, err := api.stores.NotificationStore.UnreadCount(id)
+		if err != nil {
+			writeError(err, w, r, api.debug)
+			return
+		}
+		writeJSON(UnreadCountResponse{Count: count}, w)
+	}
+}
+
+// MarkRead returns an http handler that handles API requests marking
+// notifications of the authenticated user as read. Every notification
+// is marked as read if no IDs are given
+func (api NotificationAPI) MarkRead() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := claimsID(r)
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var body MarkReadRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeProblem(http.StatusBadRequest, "Malformed request body", w, r)
+			return
+		}
+		var err error
+		if len(body.IDs) == 0 {
+			err = api.stores.NotificationStore.MarkAllRead(id)
+		} else {
+			err = api.stores.NotificationStore.MarkRead(id, body.IDs)
+		}
+		if err != nil {
+			writeError(err, w, r, api.debug)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
+
+// GetPreferences returns an http handler that handles API requests for
+// the notification preferences of the authenticated user
+func (api NotificationAPI) GetPreferences() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := claimsID(r)
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		preferences, err := api.stores.NotificationStore.Preferences(id)
+		if err != nil {
+			writeError(err, w, r, api.debug)
+			return
+		}
+		writeJSON(preferences, w)
+	}
+}
+
+// SetPreferences returns an http handler that handles API requests
+// updating the notification preferences of the authenticated user.
+// Notification types missing from the request are left unchanged
+func (api NotificationAPI) SetPreferences() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := claimsID(r)
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		preferences, err := api.stores.NotificationStore.Preferences(id)
+		if err != nil {
+			writeError(err, w, r, api.debug)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(preferences); err != nil {
+			writeProblem(http.StatusBadRequest, "Malformed request body", w, r)
+			return
+		}
+		err = api.stores.NotificationStore.SetPreferences(id, preferences)
+		if err != nil {
+			writeError(err, w, r, api.debug)
+			return
+		}
+		writeJSON(preferences, w)
+	}
+}
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/boxtown/meirl/api/apitest"
+	"github.com/boxtown/meirl/data"
+	jwt "github.com/dgrijalva/jwt-go"
+)
+
+func TestListNotifications(t *testing.T) {
+	var listOptions data.ListOptions
+	var listUnread bool
+	api := NewNotificationAPI(
+		data.Stores{
+			NotificationStore: mockNotificationStore{
+				OnList: func(userID int64, options data.ListOptions, unreadOnly bool) ([]data.Notification, error) {
+					listOptions, listUnread = options, unreadOnly
+					notification := data.Notification{UserID: userID, Type: data.NotificationKek, ActorCount: 5}
+					return []data.Notification{notification}, nil
+				},
+			},
+		},
+		false,
+	)
+
+	r, _ := http.NewRequest("GET", "/?marker=1500000000&unread=true", nil)
+	r = apitest.RequestWithClaims(r, claimsContextKey, jwt.MapClaims{
+		"sub": int64(1),
+	})
+	w := httptest.NewRecorder()
+	api.ListNotifications()(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status code %d, received %d", http.StatusOK, w.Code)
+		t.FailNow()
+	}
+	if !listOptions.Desc || !listUnread {
+		t.Error("Expected unread notifications, most recent first")
+		t.Fail()
+	}
+	if marker, ok := listOptions.Marker.(time.Time); !ok || marker.Unix() != 1500000000 {
+		t.Errorf("Expected marker to be converted to a time, got %v", listOptions.Marker)
+		t.Fail()
+	}
+	var notifications []data.Notification
+	if err := json.NewDecoder(w.Body).Decode(&notifications); err != nil {
+		t.Error(err.Error())
+		t.FailNow()
+	}
+	if len(notifications) != 1 || notifications[0].ActorCount != 5 {
+		t.Errorf("Unexpected notifications %v", notifications)
+		t.Fail()
+	}
+}
+
+func TestListNotificationsBadMarker(t *testing.T) {
+	api := NewNotificationAPI(data.Stores{}, false)
+
+	for _, marker := range []string{"yesterday", "1500000000:first"} {
+		r, _ := http.NewRequest("GET", "/?marker="+marker, nil)
+		r = apitest.RequestWithClaims(r, claimsContextKey, jwt.MapClaims{
+			"sub": int64(1),
+		})
+		w := httptest.NewRecorder()
+		api.ListNotifications()(w, r)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status code %d for %s, received %d", http.StatusBadRequest, marker, w.Code)
+			t.Fail()
+		}
+	}
+}
+
+func TestMarkNotificationsRead(t *testing.T) {
+	var marked []int64
+	var markedAll bool
+	api := NewNotificationAPI(
+		data.Stores{
+			NotificationStore: mockNotificationStore{
+				OnMarkRead: func(userID int64, ids []int64) error {
+					marked = ids
+					return nil
+				},
+				OnMarkAllRead: func(userID int64) error {
+					markedAll = true
+					return nil
+				},
+			},
+		},
+		false,
+	)
+
+	for _, body := range []string{`{"ids": [3, 4]}`, `{}`} {
+		r, _ := http.NewRequest("POST", "/", strings.NewReader(body))
+		r = apitest.RequestWithClaims(r, claimsContextKey, jwt.MapClaims{
+			"sub": int64(1),
+		})
+		w := httptest.NewRecorder()
+		api.MarkRead()(w, r)
+
+		if w.Code != http.StatusAccepted {
+			t.Errorf("Expected status code %d, received %d", http.StatusAccepted, w.Code)
+			t.Fail()
+		}
+	}
+	if len(marked) != 2 || marked[0] != 3 || marked[1] != 4 {
+		t.Errorf("Expected notifications 3 and 4 to be marked read, got %v", marked)
+		t.Fail()
+	}
+	if !markedAll {
+		t.Error("Expected every notification to be marked read")
+		t.Fail()
+	}
+}
+
+func TestSetNotificationPreferences(t *testing.T) {
+	var stored *data.NotificationPreferences
+	api := NewNotificationAPI(
+		data.Stores{
+			NotificationStore: mockNotificationStore{
+				OnPreferences: func(userID int64) (*data.NotificationPreferences, error) {
+					return &data.NotificationPreferences{Follow: true, Kek: true, No: false, Mention: true}, nil
+				},
+				OnSetPreferences: func(userID int64, preferences *data.NotificationPreferences) error {
+					stored = preferences
+					return nil
+				},
+			},
+		},
+		false,
+	)
+
+	r, _ := http.NewRequest("PUT", "/", strings.NewReader(`{"kek": false}`))
+	r = apitest.RequestWithClaims(r, claimsContextKey, jwt.MapClaims{
+		"sub": int64(1),
+	})
+	w := httptest.NewRecorder()
+	api.SetPreferences()(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status code %d, received %d", http.StatusOK, w.Code)
+		t.FailNow()
+	}
+	expected := data.NotificationPreferences{Follow: true, Kek: false, No: false, Mention: true}
+	if stored == nil || *stored != expected {
+		t.Errorf("Expected preferences %v, got %v", expected, stored)
+		t.Fail()
+	}
+}
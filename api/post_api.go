@@ -3,29 +3,39 @@ package api
 import (
 	"fmt"
 	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 
 	"encoding/json"
 
 	"github.com/boxtown/meirl/data"
+	"github.com/boxtown/meirl/metrics"
+	"github.com/boxtown/meirl/realtime"
+	"github.com/gorilla/mux"
 )
 
 // PostAPI contains state information for executing
 // MeIRL User API route handlers
 type PostAPI struct {
 	stores data.Stores
+	events realtime.Publisher
 	debug  bool
 }
 
 // NewPostAPI returns an instance of the UserAPI struct
-func NewPostAPI(stores data.Stores, debug bool) PostAPI {
+func NewPostAPI(stores data.Stores, events realtime.Publisher, debug bool) PostAPI {
 	return PostAPI{
 		stores: stores,
+		events: events,
 		debug:  debug,
 	}
 }
 
 // CreatePost returns an http handler that handles create post API
-// requests
+// requests. Posts the author may not see may not be replied to or
+// quoted and are not found
 func (api PostAPI) CreatePost() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		userID, ok := claimsID(r)
@@ -37,16 +47,42 @@ func (api PostAPI) CreatePost() http.HandlerFunc {
 		var p data.Post
 		err := json.NewDecoder(r.Body).Decode(&p)
 		if err != nil {
-			writeError(err, w, api.debug)
+			writeError(err, w, r, api.debug)
 			return
 		}
 		p.AuthorID = userID
+		p.Entities, err = postEntities(api.stores.UserStore, p.Contents)
+		if err != nil {
+			writeError(err, w, r, api.debug)
+			return
+		}
+
+		// posts the author may not see may not be replied to or quoted,
+		// and are indistinguishable from posts that do not exist
+		for _, related := range []int64{p.ParentID, p.QuoteID} {
+			if related == 0 {
+				continue
+			}
+			_, err = getVisiblePost(api.stores, related, userID)
+			if err == data.ErrNoEnt {
+				writeProblem(http.StatusNotFound, "The replied to or quoted post does not exist", w, r)
+				return
+			} else if err != nil {
+				writeError(err, w, r, api.debug)
+				return
+			}
+		}
 
 		id, err := api.stores.PostStore.Create(&p)
-		if err != nil {
-			writeError(err, w, api.debug)
+		if err == data.ErrNoEnt {
+			writeProblem(http.StatusNotFound, "The replied to or quoted post does not exist", w, r)
+			return
+		} else if err != nil {
+			writeError(err, w, r, api.debug)
 			return
 		}
+		metrics.PostsCreated.Inc()
+		api.notifyMentions(userID, id, p.Entities, r)
 		w.WriteHeader(http.StatusCreated)
 		w.Header().Add("Location", fmt.Sprintf("/%s/post/%d", apiVersion, id))
 		writeJSON(IDResponse{ID: id}, w)
@@ -54,18 +90,396 @@ func (api PostAPI) CreatePost() http.HandlerFunc {
 }
 
 // GetPost returns an http handler that handles get post API
-// requests
+// requests. Posts the viewer may not see are not found
 func (api PostAPI) GetPost() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		id, _ := r.Context().Value(idContextKey).(int64)
-		post, err := api.stores.PostStore.Get(id)
+		post, err := getVisiblePost(api.stores, id, viewerID(r))
 		if err == data.ErrNoEnt {
 			w.WriteHeader(http.StatusNotFound)
 			return
 		} else if err != nil {
-			writeError(err, w, api.debug)
+			writeError(err, w, r, api.debug)
 			return
 		}
 		writeJSON(post, w)
 	}
 }
+
+// Limits of threads
+const (
+	defaultThreadDepth = 3
+	maxThreadDepth     = 10
+	maxThreadAncestors = 50
+	maxThreadReplies   = 500
+)
+
+// GetThread returns an http handler that handles get thread API
+// requests, returning the post, the posts it replies to and a page of
+// the replies to it, oldest first unless desc is given, with their
+// replies down to the depth query parameter. The marker is the ID of
+// the last reply of the previous page. Threads of posts the viewer may
+// not see are not found, and posts the viewer may not see are omitted
+// along with the replies to them
+func (api PostAPI) GetThread() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := contextID(r)
+		depth := defaultThreadDepth
+		if value := r.URL.Query().Get("depth"); value != "" {
+			d, err := strconv.Atoi(value)
+			if err != nil || d < 1 || d > maxThreadDepth {
+				writeProblem(http.StatusBadRequest,
+					fmt.Sprintf("depth must be between 1 and %d", maxThreadDepth), w, r)
+				return
+			}
+			depth = d
+		}
+		options := ListOptionsFromRequest(r)
+		if marker := options.Marker.(string); marker != "" {
+			replyID, err := strconv.ParseInt(marker, 10, 64)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			options.Marker = replyID
+		}
+
+		viewer := viewerID(r)
+		post, err := getVisiblePost(api.stores, id, viewer)
+		if err == data.ErrNoEnt {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		} else if err != nil {
+			writeError(err, w, r, api.debug)
+			return
+		}
+		ancestors, err := api.stores.Ancestors(id, viewer, maxThreadAncestors)
+		if err != nil {
+			writeError(err, w, r, api.debug)
+			return
+		}
+		descendants, err := api.stores.Descendants(id, viewer, depth, maxThreadReplies, options)
+		if err != nil {
+			writeError(err, w, r, api.debug)
+			return
+		}
+		if ancestors == nil {
+			ancestors = []data.Post{}
+		}
+		writeJSON(&ThreadResponse{
+			Ancestors: ancestors,
+			Post:      post,
+			Replies:   replyTree(id, descendants),
+		}, w)
+	}
+}
+
+// replyTree nests descendants of the post with the given id under
+// their parents. Descendants are ordered by depth, so parents always
+// precede their replies, which keep their order
+func replyTree(id int64, descendants []data.Post) []ReplyNode {
+	children := make(map[int64][]int)
+	for i, post := range descendants {
+		children[post.ParentID] = append(children[post.ParentID], i)
+	}
+	var build func(parentID int64) []ReplyNode
+	build = func(parentID int64) []ReplyNode {
+		nodes := make([]ReplyNode, 0, len(children[parentID]))
+		for _, i := range children[parentID] {
+			nodes = append(nodes, ReplyNode{
+				Post:     descendants[i],
+				Children: build(descendants[i].ID),
+			})
+		}
+		return nodes
+	}
+	return build(id)
+}
+
+// Explore returns an http handler that handles explore API requests,
+// listing the recent posts of every user newest first unless desc is
+// given. Unlike feeds, explore is never empty for users without follows
+func (api PostAPI) Explore() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		options := ListOptionsFromRequest(r)
+		if r.URL.Query().Get("desc") == "" {
+			options.Desc = true
+		}
+		if marker := options.Marker.(string); marker != "" {
+			m, err := parseTimeMarker(marker)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			options.Marker = m
+		}
+		posts, err := api.stores.PostStore.Recent(options)
+		if err != nil {
+			writeError(err, w, r, api.debug)
+			return
+		}
+		if posts == nil {
+			posts = []data.Post{}
+		}
+		writeJSON(posts, w)
+	}
+}
+
+// Trending returns an http handler that handles trending API requests,
+// listing the posts with the most keks per hour over the window given
+// by the window query parameter, 24h by default
+func (api PostAPI) Trending() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		window := data.TrendingDay
+		if value := r.URL.Query().Get("window"); value != "" {
+			window = data.TrendingWindow(value)
+		}
+		if window.Duration() == 0 {
+			writeProblem(http.StatusBadRequest, fmt.Sprintf("unknown trending window %q", window), w, r)
+			return
+		}
+		options := ListOptionsFromRequest(r)
+		if marker := options.Marker.(string); marker != "" {
+			rank, err := strconv.ParseInt(marker, 10, 64)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			options.Marker = rank
+		}
+		posts, err := api.stores.PostStore.Trending(window, options)
+		if err != nil {
+			writeError(err, w, r, api.debug)
+			return
+		}
+		if posts == nil {
+			posts = []data.Post{}
+		}
+		writeJSON(posts, w)
+	}
+}
+
+// React returns an http handler that handles API requests reacting
+// to a post with the given reaction as the authenticated user
+func (api PostAPI) React(reaction data.Reaction) http.HandlerFunc {
+	return api.updateReaction(reaction, data.PostStore.React, true)
+}
+
+// UnReact returns an http handler that handles API requests removing
+// the given reaction to a post by the authenticated user
+func (api PostAPI) UnReact(reaction data.Reaction) http.HandlerFunc {
+	return api.updateReaction(reaction, data.PostStore.UnReact, false)
+}
+
+func (api PostAPI) updateReaction(
+	reaction data.Reaction,
+	update func(store data.PostStore, postID, userID int64, reaction data.Reaction) error,
+	notifyAuthor bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := claimsID(r)
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		postID := contextID(r)
+		post, err := getVisiblePost(api.stores, postID, userID)
+		if err == data.ErrNoEnt {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		} else if err != nil {
+			writeError(err, w, r, api.debug)
+			return
+		}
+		err = update(api.stores.PostStore, postID, userID, reaction)
+		if err != nil {
+			writeError(err, w, r, api.debug)
+			return
+		}
+		if notifyAuthor {
+			notificationType := data.NotificationKek
+			if reaction == data.ReactionNo {
+				notificationType = data.NotificationNo
+			}
+			notify(api.stores, api.events, post.AuthorID, userID, notificationType, postID, r)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
+
+// TaggedPosts returns an http handler that handles API requests
+// listing the posts tagged with the hashtag in the path, regardless
+// of case. Posts are listed newest or best first unless desc is given
+func (api PostAPI) TaggedPosts() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tag := strings.ToLower(mux.Vars(r)["tag"])
+		options, sort, ok := newestListFromRequest(r)
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		posts, err := api.stores.PostStore.TaggedPosts(tag, options, sort)
+		if err != nil {
+			writeError(err, w, r, api.debug)
+			return
+		}
+		if posts == nil {
+			posts = []data.Post{}
+		}
+		writeJSON(posts, w)
+	}
+}
+
+// Mentions returns an http handler that handles API requests listing
+// the posts mentioning the authenticated user, newest or best first
+// unless desc is given
+func (api PostAPI) Mentions() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := claimsID(r)
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		options, sort, ok := newestListFromRequest(r)
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		posts, err := api.stores.PostStore.Mentions(userID, options, sort)
+		if err != nil {
+			writeError(err, w, r, api.debug)
+			return
+		}
+		if posts == nil {
+			posts = []data.Post{}
+		}
+		writeJSON(posts, w)
+	}
+}
+
+// Repost returns an http handler that handles API requests reposting
+// a post to the followers of the authenticated user
+func (api PostAPI) Repost() http.HandlerFunc {
+	return api.updateRepost(data.PostStore.Repost)
+}
+
+// UnRepost returns an http handler that handles API requests undoing
+// the repost of a post by the authenticated user
+func (api PostAPI) UnRepost() http.HandlerFunc {
+	return api.updateRepost(data.PostStore.UnRepost)
+}
+
+func (api PostAPI) updateRepost(update func(store data.PostStore, postID, userID int64) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := claimsID(r)
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		postID := contextID(r)
+		_, err := getVisiblePost(api.stores, postID, userID)
+		if err == data.ErrNoEnt {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		} else if err != nil {
+			writeError(err, w, r, api.debug)
+			return
+		}
+		err = update(api.stores.PostStore, postID, userID)
+		if err != nil {
+			writeError(err, w, r, api.debug)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
+
+// Notify the users mentioned in a post once each
+func (api PostAPI) notifyMentions(authorID, postID int64, entities []data.PostEntity, r *http.Request) {
+	notified := make(map[int64]bool)
+	for _, entity := range entities {
+		if entity.Type != data.EntityMention || notified[entity.UserID] {
+			continue
+		}
+		notified[entity.UserID] = true
+		notify(api.stores, api.events, entity.UserID, authorID, data.NotificationMention, postID, r)
+	}
+}
+
+// maxMentions is the maximum number of users mentioned
+// and maxHashtags the maximum number of tags in a single post
+const (
+	maxMentions = 10
+	maxHashtags = 10
+)
+
+var mentionRegex = regexp.MustCompile(`(?:^|[^0-9a-zA-Z_])@([0-9a-zA-Z_]+)`)
+var hashtagRegex = regexp.MustCompile(`(?:^|[^0-9a-zA-Z_])#([0-9a-zA-Z_]+)`)
+
+// postEntities parses the mentions and hashtags in the contents of a
+// post, resolving mentions to users. Mentions of users that do not
+// exist, mentions of users beyond the first maxMentions and tags beyond
+// the first maxHashtags are ignored
+func postEntities(users data.UserStore, contents []byte) ([]data.PostEntity, error) {
+	userIDs := make(map[string]int64)
+	for _, username := range mentionedUsernames(contents) {
+		user, err := users.GetByUsername(username)
+		if err == data.ErrNoEnt {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		userIDs[username] = user.ID
+	}
+	var entities []data.PostEntity
+	for _, match := range mentionRegex.FindAllSubmatchIndex(contents, -1) {
+		username := string(contents[match[2]:match[3]])
+		if userID, ok := userIDs[username]; ok {
+			entities = append(entities, data.PostEntity{
+				Type:   data.EntityMention,
+				Start:  match[2] - 1,
+				End:    match[3],
+				Text:   username,
+				UserID: userID,
+			})
+		}
+	}
+	tags := make(map[string]bool)
+	for _, match := range hashtagRegex.FindAllSubmatchIndex(contents, -1) {
+		tag := strings.ToLower(string(contents[match[2]:match[3]]))
+		if !tags[tag] {
+			if len(tags) == maxHashtags {
+				continue
+			}
+			tags[tag] = true
+		}
+		entities = append(entities, data.PostEntity{
+			Type:  data.EntityHashtag,
+			Start: match[2] - 1,
+			End:   match[3],
+			Text:  tag,
+		})
+	}
+	sort.Slice(entities, func(i, j int) bool {
+		return entities[i].Start < entities[j].Start
+	})
+	return entities, nil
+}
+
+// mentionedUsernames returns the distinct usernames
+// mentioned with an @ in the contents of a post
+func mentionedUsernames(contents []byte) []string {
+	var usernames []string
+	seen := make(map[string]bool)
+	for _, match := range mentionRegex.FindAllSubmatch(contents, -1) {
+		username := string(match[1])
+		if seen[username] {
+			continue
+		}
+		seen[username] = true
+		usernames = append(usernames, username)
+		if len(usernames) == maxMentions {
+			break
+		}
+	}
+	return usernames
+}
@@ -1,9 +1,11 @@
 package api
 
 import (
+	"encoding/json"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"net/http"
 	"net/http/httptest"
@@ -11,7 +13,9 @@ import (
 	"github.com/boxtown/meirl/api/apitest"
 	"github.com/boxtown/meirl/data"
 	"github.com/boxtown/meirl/data/datatest"
+	"github.com/boxtown/meirl/realtime"
 	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/gorilla/mux"
 )
 
 func TestCreatePost(t *testing.T) {
@@ -23,6 +27,7 @@ func TestCreatePost(t *testing.T) {
 				},
 			},
 		},
+		realtime.Discard,
 		false,
 	)
 
@@ -68,7 +73,13 @@ func TestGetPost(t *testing.T) {
 					return stored, nil
 				},
 			},
+			UserStore: mockUserStore{
+				OnGet: func(id int64) (*data.User, error) {
+					return datatest.ExampleUser(), nil
+				},
+			},
 		},
+		realtime.Discard,
 		false,
 	)
 
@@ -91,3 +102,798 @@ func TestGetPost(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestExplore(t *testing.T) {
+	var options data.ListOptions
+	api := NewPostAPI(
+		data.Stores{
+			PostStore: mockPostStore{
+				OnRecent: func(o data.ListOptions) ([]data.Post, error) {
+					options = o
+					return nil, nil
+				},
+			},
+		},
+		realtime.Discard,
+		false,
+	)
+
+	r, _ := http.NewRequest("", "?marker=1500000000", nil)
+	w := httptest.NewRecorder()
+	api.Explore()(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status code %d, received %d", http.StatusOK, w.Code)
+		t.FailNow()
+	}
+	if !options.Desc || options.Marker != time.Unix(1500000000, 0) {
+		t.Errorf("Expected newest posts before the marker, got %+v", options)
+		t.Fail()
+	}
+	if body := strings.TrimSpace(w.Body.String()); body != "[]" {
+		t.Errorf("Expected an empty list, got %s", body)
+		t.Fail()
+	}
+
+	// the marker of the last post of a page is precise
+	r, _ = http.NewRequest("", "?marker=1500000000123456:7", nil)
+	w = httptest.NewRecorder()
+	api.Explore()(w, r)
+
+	expected := data.TimeMarker{Time: time.Unix(1500000000, 123456000), ID: 7}
+	if w.Code != http.StatusOK || options.Marker != expected {
+		t.Errorf("Expected posts after %v, got %d and %+v", expected, w.Code, options)
+		t.Fail()
+	}
+}
+
+func TestTrending(t *testing.T) {
+	var window data.TrendingWindow
+	var options data.ListOptions
+	api := NewPostAPI(
+		data.Stores{
+			PostStore: mockPostStore{
+				OnTrending: func(tw data.TrendingWindow, o data.ListOptions) ([]data.Post, error) {
+					window, options = tw, o
+					return []data.Post{*datatest.ExamplePost(1)}, nil
+				},
+			},
+		},
+		realtime.Discard,
+		false,
+	)
+
+	r, _ := http.NewRequest("", "?window=1h&marker=10", nil)
+	w := httptest.NewRecorder()
+	api.Trending()(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status code %d, received %d", http.StatusOK, w.Code)
+		t.FailNow()
+	}
+	if window != data.TrendingHour || options.Marker != int64(10) {
+		t.Errorf("Expected the hourly window after rank 10, got %s and %v", window, options.Marker)
+		t.Fail()
+	}
+
+	r, _ = http.NewRequest("", "", nil)
+	w = httptest.NewRecorder()
+	api.Trending()(w, r)
+	if window != data.TrendingDay {
+		t.Errorf("Expected the daily window by default, got %s", window)
+		t.Fail()
+	}
+
+	r, _ = http.NewRequest("", "?window=1y", nil)
+	w = httptest.NewRecorder()
+	api.Trending()(w, r)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d, received %d", http.StatusBadRequest, w.Code)
+		t.Fail()
+	}
+}
+
+func TestGetThread(t *testing.T) {
+	post := func(id, parentID int64) data.Post {
+		p := *datatest.ExamplePost(1)
+		p.ID, p.ParentID, p.ConversationID = id, parentID, 1
+		return p
+	}
+	var depth int
+	var options data.ListOptions
+	api := NewPostAPI(
+		data.Stores{
+			PostStore: mockPostStore{
+				OnGet: func(id int64) (*data.Post, error) {
+					if id != 2 {
+						return nil, data.ErrNoEnt
+					}
+					p := post(2, 1)
+					return &p, nil
+				},
+				OnAncestors: func(id, viewerID int64, limit int) ([]data.Post, error) {
+					return []data.Post{post(1, 0)}, nil
+				},
+				OnDescendants: func(id, viewerID int64, d, maxNodes int, o data.ListOptions) ([]data.Post, error) {
+					depth, options = d, o
+					return []data.Post{post(3, 2), post(4, 2), post(5, 3), post(6, 5)}, nil
+				},
+			},
+			UserStore: mockUserStore{
+				OnGet: func(id int64) (*data.User, error) {
+					return datatest.ExampleUser(), nil
+				},
+			},
+		},
+		realtime.Discard,
+		false,
+	)
+
+	r, _ := http.NewRequest("", "?depth=3&marker=2", nil)
+	r = apitest.RequestWithContextID(r, idContextKey, int64(2))
+	w := httptest.NewRecorder()
+	api.GetThread()(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status code %d, received %d", http.StatusOK, w.Code)
+		t.FailNow()
+	}
+	if depth != 3 || options.Marker != int64(2) {
+		t.Errorf("Expected depth 3 after reply 2, got %d and %v", depth, options.Marker)
+		t.Fail()
+	}
+	var thread ThreadResponse
+	if err := json.NewDecoder(w.Body).Decode(&thread); err != nil {
+		t.Error(err.Error())
+		t.FailNow()
+	}
+	if len(thread.Ancestors) != 1 || thread.Post.ID != 2 || len(thread.Replies) != 2 {
+		t.Errorf("Expected an ancestor and 2 replies, got %+v", thread)
+		t.FailNow()
+	}
+	replies := thread.Replies
+	if replies[0].ID != 3 || replies[1].ID != 4 || len(replies[1].Children) != 0 ||
+		len(replies[0].Children) != 1 || replies[0].Children[0].ID != 5 ||
+		len(replies[0].Children[0].Children) != 1 || replies[0].Children[0].Children[0].ID != 6 {
+		t.Errorf("Expected replies nested under their parents, got %+v", replies)
+		t.Fail()
+	}
+
+	for _, c := range []struct {
+		query  string
+		id     int64
+		status int
+	}{
+		{"?depth=11", 2, http.StatusBadRequest},
+		{"?marker=first", 2, http.StatusBadRequest},
+		{"", 7, http.StatusNotFound},
+	} {
+		r, _ = http.NewRequest("", c.query, nil)
+		r = apitest.RequestWithContextID(r, idContextKey, c.id)
+		w = httptest.NewRecorder()
+		api.GetThread()(w, r)
+		if w.Code != c.status {
+			t.Errorf("Expected status code %d for %s, received %d", c.status, c.query, w.Code)
+			t.Fail()
+		}
+	}
+}
+
+func TestReactToPost(t *testing.T) {
+	var reacted data.Reaction
+	var notified data.NotificationType
+	bus := realtime.NewBus()
+	sub := bus.Subscribe()
+	defer sub.Close()
+	api := NewPostAPI(
+		data.Stores{
+			PostStore: mockPostStore{
+				OnGet: func(id int64) (*data.Post, error) {
+					return datatest.ExamplePost(2), nil
+				},
+				OnReact: func(postID, userID int64, reaction data.Reaction) error {
+					reacted = reaction
+					return nil
+				},
+			},
+			UserStore: mockUserStore{
+				OnGet: func(id int64) (*data.User, error) {
+					user := datatest.ExampleUser()
+					user.ID = id
+					return user, nil
+				},
+			},
+			BlockStore: mockBlockStore{
+				OnIsBlocked: func(userID, otherID int64) (bool, error) {
+					return false, nil
+				},
+			},
+			NotificationStore: mockNotificationStore{
+				OnNotify: func(
+					userID, actorID int64,
+					notificationType data.NotificationType,
+					postID int64) (int64, error) {
+					if userID != 2 || actorID != 1 || postID != 3 {
+						t.Errorf("Unexpected notification of %d by %d about %d", userID, actorID, postID)
+						t.Fail()
+					}
+					notified = notificationType
+					return 4, nil
+				},
+			},
+		},
+		bus,
+		false,
+	)
+
+	r, _ := http.NewRequest("", "", nil)
+	r = apitest.RequestWithContextID(r, idContextKey, 3)
+	r = apitest.RequestWithClaims(r, claimsContextKey, jwt.MapClaims{
+		"sub": int64(1),
+	})
+	w := httptest.NewRecorder()
+	api.React(data.ReactionNo)(w, r)
+
+	if w.Code != http.StatusAccepted {
+		t.Errorf("Expected status code %d, received %d", http.StatusAccepted, w.Code)
+		t.Fail()
+	}
+	if reacted != data.ReactionNo {
+		t.Error("Expected a no reaction to be stored")
+		t.Fail()
+	}
+	if notified != data.NotificationNo {
+		t.Error("Expected the author to be notified of the no")
+		t.Fail()
+	}
+	// the reaction itself is published by the outbox relay
+	e := <-sub.Events()
+	if e.Type != realtime.NotificationCreated || e.ID != 4 || e.UserID != 2 {
+		t.Errorf("Unexpected event %v", e)
+		t.Fail()
+	}
+}
+
+func TestCreatePostRelatedToHiddenPost(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		post    string
+		blocked bool
+		private bool
+	}{
+		{"reply to a blocking author", `{"contents": "dGVzdA==", "parentID": 3}`, true, false},
+		{"reply to a private author", `{"contents": "dGVzdA==", "parentID": 3}`, false, true},
+		{"quote of a blocking author", `{"contents": "dGVzdA==", "quoteID": 3}`, true, false},
+		{"quote of a private author", `{"contents": "dGVzdA==", "quoteID": 3}`, false, true},
+	} {
+		api := NewPostAPI(
+			data.Stores{
+				PostStore: mockPostStore{
+					OnGet: func(id int64) (*data.Post, error) {
+						return datatest.ExamplePost(2), nil
+					},
+					OnCreate: func(post *data.Post) (int64, error) {
+						t.Errorf("Expected no %s to be created", test.name)
+						t.Fail()
+						return 1, nil
+					},
+				},
+				UserStore: mockUserStore{
+					OnGet: func(id int64) (*data.User, error) {
+						user := datatest.ExampleUser()
+						user.ID, user.Private = id, test.private
+						return user, nil
+					},
+					OnIsFollowing: func(followerID, followeeID int64) (bool, error) {
+						return false, nil
+					},
+				},
+				BlockStore: mockBlockStore{
+					OnIsBlocked: func(userID, otherID int64) (bool, error) {
+						return test.blocked, nil
+					},
+				},
+			},
+			realtime.Discard,
+			false,
+		)
+
+		r, _ := http.NewRequest("", "", strings.NewReader(test.post))
+		r = apitest.RequestWithClaims(r, claimsContextKey, jwt.MapClaims{
+			"sub": int64(1),
+		})
+		w := httptest.NewRecorder()
+		api.CreatePost()(w, r)
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status code %d for %s, received %d", http.StatusNotFound, test.name, w.Code)
+			t.Fail()
+		}
+	}
+}
+
+func TestCreatePostNotifiesMentions(t *testing.T) {
+	var mentioned []int64
+	api := NewPostAPI(
+		data.Stores{
+			UserStore: mockUserStore{
+				OnGetByUsername: func(username string) (*data.User, error) {
+					if username == "nobody" {
+						return nil, data.ErrNoEnt
+					}
+					user := datatest.ExampleUser()
+					user.ID = int64(len(username))
+					return user, nil
+				},
+			},
+			PostStore: mockPostStore{
+				OnCreate: func(post *data.Post) (int64, error) {
+					return 5, nil
+				},
+			},
+			NotificationStore: mockNotificationStore{
+				OnNotify: func(
+					userID, actorID int64,
+					notificationType data.NotificationType,
+					postID int64) (int64, error) {
+					if notificationType != data.NotificationMention || postID != 5 {
+						t.Errorf("Unexpected %s notification about %d", notificationType, postID)
+						t.Fail()
+					}
+					mentioned = append(mentioned, userID)
+					return 1, nil
+				},
+			},
+			BlockStore: mockBlockStore{
+				OnIsBlocked: func(userID, otherID int64) (bool, error) {
+					return false, nil
+				},
+			},
+		},
+		realtime.Discard,
+		false,
+	)
+
+	body := `{"contents": "aGV5IEBib2IsIEBhbGljZUB4IEBib2IgQG5vYm9keSBtZUBleGFtcGxlLmNvbQ=="}`
+	r, _ := http.NewRequest("", "", strings.NewReader(body))
+	r = apitest.RequestWithClaims(r, claimsContextKey, jwt.MapClaims{
+		"sub": int64(1),
+	})
+	w := httptest.NewRecorder()
+	api.CreatePost()(w, r)
+
+	if w.Code != http.StatusCreated {
+		t.Errorf("Expected status code %d, received %d", http.StatusCreated, w.Code)
+		t.Fail()
+	}
+	// "hey @bob, @alice@x @bob @nobody me@example.com"
+	if len(mentioned) != 2 || mentioned[0] != 3 || mentioned[1] != 5 {
+		t.Errorf("Expected bob and alice to be notified, got %v", mentioned)
+		t.Fail()
+	}
+}
+
+func TestNotifySkipsBlockedUsers(t *testing.T) {
+	stores := data.Stores{
+		BlockStore: mockBlockStore{
+			OnIsBlocked: func(userID, otherID int64) (bool, error) {
+				return userID == 2 && otherID == 1, nil
+			},
+		},
+		NotificationStore: mockNotificationStore{
+			OnNotify: func(
+				userID, actorID int64,
+				notificationType data.NotificationType,
+				postID int64) (int64, error) {
+				t.Error("Expected blocked users not to be notified")
+				t.Fail()
+				return 0, nil
+			},
+		},
+	}
+	r, _ := http.NewRequest("", "", nil)
+	notify(stores, realtime.Discard, 2, 1, data.NotificationMention, 3, r)
+}
+
+func TestMentionedUsernames(t *testing.T) {
+	usernames := mentionedUsernames([]byte("@a (@b_2) x@c @a @d-e"))
+	expected := []string{"a", "b_2", "d"}
+	if len(usernames) != len(expected) {
+		t.Errorf("Expected %v, got %v", expected, usernames)
+		t.FailNow()
+	}
+	for i := range expected {
+		if usernames[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected, usernames)
+			t.Fail()
+		}
+	}
+}
+
+func TestReactToMissingPost(t *testing.T) {
+	api := NewPostAPI(
+		data.Stores{
+			PostStore: mockPostStore{
+				OnGet: func(id int64) (*data.Post, error) {
+					return nil, data.ErrNoEnt
+				},
+			},
+		},
+		realtime.Discard,
+		false,
+	)
+
+	r, _ := http.NewRequest("", "", nil)
+	r = apitest.RequestWithContextID(r, idContextKey, 3)
+	r = apitest.RequestWithClaims(r, claimsContextKey, jwt.MapClaims{
+		"sub": int64(1),
+	})
+	w := httptest.NewRecorder()
+	api.UnReact(data.ReactionKek)(w, r)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d, received %d", http.StatusNotFound, w.Code)
+		t.Fail()
+	}
+}
+
+func TestReactToPostOfBlockingAuthor(t *testing.T) {
+	api := NewPostAPI(
+		data.Stores{
+			PostStore: mockPostStore{
+				OnGet: func(id int64) (*data.Post, error) {
+					return datatest.ExamplePost(2), nil
+				},
+				OnReact: func(postID, userID int64, reaction data.Reaction) error {
+					t.Error("Expected blocked users not to react")
+					t.Fail()
+					return nil
+				},
+			},
+			UserStore: mockUserStore{
+				OnGet: func(id int64) (*data.User, error) {
+					user := datatest.ExampleUser()
+					user.ID = id
+					return user, nil
+				},
+			},
+			BlockStore: mockBlockStore{
+				OnIsBlocked: func(userID, otherID int64) (bool, error) {
+					return true, nil
+				},
+			},
+		},
+		realtime.Discard,
+		false,
+	)
+
+	r, _ := http.NewRequest("", "", nil)
+	r = apitest.RequestWithContextID(r, idContextKey, 3)
+	r = apitest.RequestWithClaims(r, claimsContextKey, jwt.MapClaims{
+		"sub": int64(1),
+	})
+	w := httptest.NewRecorder()
+	api.React(data.ReactionKek)(w, r)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d, received %d", http.StatusNotFound, w.Code)
+		t.Fail()
+	}
+}
+
+func TestRepost(t *testing.T) {
+	var reposted, unreposted bool
+	api := NewPostAPI(
+		data.Stores{
+			PostStore: mockPostStore{
+				OnGet: func(id int64) (*data.Post, error) {
+					if id != 3 {
+						return nil, data.ErrNoEnt
+					}
+					return datatest.ExamplePost(2), nil
+				},
+				OnRepost: func(postID, userID int64) error {
+					reposted = postID == 3 && userID == 1
+					return nil
+				},
+				OnUnRepost: func(postID, userID int64) error {
+					unreposted = postID == 3 && userID == 1
+					return nil
+				},
+			},
+			UserStore: mockUserStore{
+				OnGet: func(id int64) (*data.User, error) {
+					user := datatest.ExampleUser()
+					user.ID = id
+					return user, nil
+				},
+			},
+			BlockStore: mockBlockStore{
+				OnIsBlocked: func(userID, otherID int64) (bool, error) {
+					return false, nil
+				},
+			},
+		},
+		realtime.Discard,
+		false,
+	)
+
+	for _, handler := range []http.HandlerFunc{api.Repost(), api.UnRepost()} {
+		r, _ := http.NewRequest("", "", nil)
+		r = apitest.RequestWithContextID(r, idContextKey, 3)
+		r = apitest.RequestWithClaims(r, claimsContextKey, jwt.MapClaims{
+			"sub": int64(1),
+		})
+		w := httptest.NewRecorder()
+		handler(w, r)
+
+		if w.Code != http.StatusAccepted {
+			t.Errorf("Expected status code %d, received %d", http.StatusAccepted, w.Code)
+			t.Fail()
+		}
+	}
+	if !reposted || !unreposted {
+		t.Error("Expected the post to be reposted by the user and the repost undone")
+		t.Fail()
+	}
+
+	r, _ := http.NewRequest("", "", nil)
+	r = apitest.RequestWithContextID(r, idContextKey, 4)
+	r = apitest.RequestWithClaims(r, claimsContextKey, jwt.MapClaims{
+		"sub": int64(1),
+	})
+	w := httptest.NewRecorder()
+	api.Repost()(w, r)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d, received %d", http.StatusNotFound, w.Code)
+		t.Fail()
+	}
+}
+
+func TestPostEntities(t *testing.T) {
+	users := mockUserStore{
+		OnGetByUsername: func(username string) (*data.User, error) {
+			if username == "nobody" {
+				return nil, data.ErrNoEnt
+			}
+			user := datatest.ExampleUser()
+			user.ID = int64(len(username))
+			return user, nil
+		},
+	}
+	entities, err := postEntities(users, []byte("#Go @bob #go x#no @nobody #meirl_2"))
+	if err != nil {
+		t.Error(err.Error())
+		t.FailNow()
+	}
+	expected := []data.PostEntity{
+		{Type: data.EntityHashtag, Start: 0, End: 3, Text: "go"},
+		{Type: data.EntityMention, Start: 4, End: 8, Text: "bob", UserID: 3},
+		{Type: data.EntityHashtag, Start: 9, End: 12, Text: "go"},
+		{Type: data.EntityHashtag, Start: 26, End: 34, Text: "meirl_2"},
+	}
+	if len(entities) != len(expected) {
+		t.Errorf("Expected %v, got %v", expected, entities)
+		t.FailNow()
+	}
+	for i := range expected {
+		if entities[i] != expected[i] {
+			t.Errorf("Expected %v at %d, got %v", expected[i], i, entities[i])
+			t.Fail()
+		}
+	}
+}
+
+func TestTaggedPosts(t *testing.T) {
+	api := NewPostAPI(
+		data.Stores{
+			PostStore: mockPostStore{
+				OnTaggedPosts: func(
+					tag string,
+					options data.ListOptions,
+					sort data.PostSortMethod) ([]data.Post, error) {
+					if tag != "meirl" || !options.Desc || sort != data.PostSortByDate {
+						t.Errorf("Unexpected list of %q with %+v sorted by %v", tag, options, sort)
+						t.Fail()
+					}
+					return []data.Post{*datatest.ExamplePost(1)}, nil
+				},
+			},
+		},
+		realtime.Discard,
+		false,
+	)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/tag/{tag}/posts", api.TaggedPosts())
+	r, _ := http.NewRequest("GET", "/tag/MeIRL/posts", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status code %d, received %d", http.StatusOK, w.Code)
+		t.Fail()
+	}
+	var posts []data.Post
+	if err := json.NewDecoder(w.Body).Decode(&posts); err != nil || len(posts) != 1 {
+		t.Errorf("Expected a tagged post, got %v and %v", posts, err)
+		t.Fail()
+	}
+}
+
+func TestMentions(t *testing.T) {
+	api := NewPostAPI(
+		data.Stores{
+			PostStore: mockPostStore{
+				OnMentions: func(
+					userID int64,
+					options data.ListOptions,
+					sort data.PostSortMethod) ([]data.Post, error) {
+					if userID != 1 || !options.Desc {
+						t.Errorf("Unexpected list of mentions of %d with %+v", userID, options)
+						t.Fail()
+					}
+					return nil, nil
+				},
+			},
+		},
+		realtime.Discard,
+		false,
+	)
+
+	r, _ := http.NewRequest("GET", "/user/me/mentions", nil)
+	r = apitest.RequestWithClaims(r, claimsContextKey, jwt.MapClaims{
+		"sub": int64(1),
+	})
+	w := httptest.NewRecorder()
+	api.Mentions()(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status code %d, received %d", http.StatusOK, w.Code)
+		t.Fail()
+	}
+	if body := strings.TrimSpace(w.Body.String()); body != "[]" {
+		t.Errorf("Expected an empty list, got %s", body)
+		t.Fail()
+	}
+
+	r, _ = http.NewRequest("GET", "/user/me/mentions?marker=soon", nil)
+	r = apitest.RequestWithClaims(r, claimsContextKey, jwt.MapClaims{
+		"sub": int64(1),
+	})
+	w = httptest.NewRecorder()
+	api.Mentions()(w, r)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d, received %d", http.StatusBadRequest, w.Code)
+		t.Fail()
+	}
+}
+
+func TestGetPostOfBlockedAuthor(t *testing.T) {
+	api := NewPostAPI(
+		data.Stores{
+			PostStore: mockPostStore{
+				OnGet: func(id int64) (*data.Post, error) {
+					return datatest.ExamplePost(2), nil
+				},
+			},
+			UserStore: mockUserStore{
+				OnGet: func(id int64) (*data.User, error) {
+					user := datatest.ExampleUser()
+					user.ID = id
+					return user, nil
+				},
+			},
+			BlockStore: mockBlockStore{
+				OnIsBlocked: func(userID, otherID int64) (bool, error) {
+					return userID == 1 && otherID == 2, nil
+				},
+			},
+		},
+		realtime.Discard,
+		false,
+	)
+
+	r, _ := http.NewRequest("", "", nil)
+	r = apitest.RequestWithContextID(r, idContextKey, 3)
+	r = apitest.RequestWithClaims(r, claimsContextKey, jwt.MapClaims{
+		"sub": int64(1),
+	})
+	w := httptest.NewRecorder()
+	api.GetPost()(w, r)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d, received %d", http.StatusNotFound, w.Code)
+		t.Fail()
+	}
+}
+
+func TestGetPostOfPrivateAuthor(t *testing.T) {
+	api := NewPostAPI(
+		data.Stores{
+			PostStore: mockPostStore{
+				OnGet: func(id int64) (*data.Post, error) {
+					return datatest.ExamplePost(2), nil
+				},
+			},
+			UserStore: mockUserStore{
+				OnGet: func(id int64) (*data.User, error) {
+					user := datatest.ExampleUser()
+					user.ID, user.Private = id, true
+					return user, nil
+				},
+				OnIsFollowing: func(followerID, followeeID int64) (bool, error) {
+					return followerID == 1 && followeeID == 2, nil
+				},
+			},
+			BlockStore: mockBlockStore{
+				OnIsBlocked: func(userID, otherID int64) (bool, error) {
+					return false, nil
+				},
+			},
+		},
+		realtime.Discard,
+		false,
+	)
+
+	// only the author and their followers see the post
+	for viewer, expected := range map[int64]int{
+		0: http.StatusNotFound,
+		1: http.StatusOK,
+		2: http.StatusOK,
+		3: http.StatusNotFound,
+	} {
+		r, _ := http.NewRequest("", "", nil)
+		r = apitest.RequestWithContextID(r, idContextKey, 4)
+		if viewer != 0 {
+			r = apitest.RequestWithClaims(r, claimsContextKey, jwt.MapClaims{
+				"sub": viewer,
+			})
+		}
+		w := httptest.NewRecorder()
+		api.GetPost()(w, r)
+
+		if w.Code != expected {
+			t.Errorf("Expected status code %d for %d, received %d", expected, viewer, w.Code)
+			t.Fail()
+		}
+	}
+}
+
+func TestGetThreadOfPrivateAuthor(t *testing.T) {
+	api := NewPostAPI(
+		data.Stores{
+			PostStore: mockPostStore{
+				OnGet: func(id int64) (*data.Post, error) {
+					return datatest.ExamplePost(2), nil
+				},
+				OnAncestors: func(id, viewerID int64, limit int) ([]data.Post, error) {
+					t.Error("Expected the thread not to be listed")
+					t.Fail()
+					return nil, nil
+				},
+			},
+			UserStore: mockUserStore{
+				OnGet: func(id int64) (*data.User, error) {
+					user := datatest.ExampleUser()
+					user.ID, user.Private = id, true
+					return user, nil
+				},
+			},
+		},
+		realtime.Discard,
+		false,
+	)
+
+	r, _ := http.NewRequest("", "", nil)
+	r = apitest.RequestWithContextID(r, idContextKey, int64(3))
+	w := httptest.NewRecorder()
+	api.GetThread()(w, r)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d, received %d", http.StatusNotFound, w.Code)
+		t.Fail()
+	}
+}
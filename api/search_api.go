@@ -0,0 +1,186 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/boxtown/meirl/data"
+)
+
+// Limits of search queries and results
+const (
+	maxSearchQueryLength = 256
+	maxUserResults       = 50
+	defaultSuggestions   = 5
+	maxSuggestions       = 20
+)
+
+// Search sorts accepted by the sort query parameter
+const (
+	searchSortRelevance = "relevance"
+	searchSortDate      = "date"
+)
+
+// SearchAPI contains state information for executing
+// MeIRL Search API route handlers
+type SearchAPI struct {
+	stores data.Stores
+	debug  bool
+}
+
+// NewSearchAPI returns an instance of the SearchAPI struct
+func NewSearchAPI(stores data.Stores, debug bool) SearchAPI {
+	return SearchAPI{
+		stores: stores,
+		debug:  debug,
+	}
+}
+
+// SearchPosts returns an http handler that handles post search API
+// requests. Posts matching the q query parameter are optionally
+// filtered by the username of their author and by creation time in
+// seconds since the epoch, and sorted by relevance or date. Posts the
+// viewer may not see are excluded
+func (api SearchAPI) SearchPosts() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		values := r.URL.Query()
+		query := data.PostQuery{Text: values.Get("q"), ViewerID: viewerID(r)}
+		if query.Text == "" || len(query.Text) > maxSearchQueryLength {
+			writeProblem(http.StatusBadRequest,
+				fmt.Sprintf("q must be between 1 and %d characters", maxSearchQueryLength), w, r)
+			return
+		}
+		options := ListOptionsFromRequest(r)
+		switch values.Get("sort") {
+		case "", searchSortRelevance:
+			query.Sort = data.SearchByRelevance
+		case searchSortDate:
+			query.Sort = data.SearchByDate
+			if values.Get("desc") == "" {
+				options.Desc = true
+			}
+		default:
+			writeProblem(http.StatusBadRequest, "sort must be relevance or date", w, r)
+			return
+		}
+		var ok bool
+		if query.Since, ok = unixFromQuery(values.Get("since")); !ok {
+			writeProblem(http.StatusBadRequest, "since must be seconds since the epoch", w, r)
+			return
+		}
+		if query.Until, ok = unixFromQuery(values.Get("until")); !ok {
+			writeProblem(http.StatusBadRequest, "until must be seconds since the epoch", w, r)
+			return
+		}
+		if marker := options.Marker.(string); marker != "" {
+			m, err := data.ParseSearchMarker(marker, query.Sort)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			options.Marker = m
+		}
+		if username := values.Get("author"); username != "" {
+			author, err := api.stores.GetByUsername(username)
+			if err == data.ErrNoEnt {
+				writeJSON([]data.PostSearchResult{}, w)
+				return
+			} else if err != nil {
+				writeError(err, w, r, api.debug)
+				return
+			}
+			query.AuthorID = author.ID
+		}
+
+		results, err := api.stores.SearchPosts(query, options)
+		if err != nil {
+			writeError(err, w, r, api.debug)
+			return
+		}
+		if results == nil {
+			results = []data.PostSearchResult{}
+		}
+		writeJSON(results, w)
+	}
+}
+
+// SearchUsers returns an http handler that handles user search API
+// requests, listing users whose username the q query parameter
+// prefixes or whose actual name resembles it, boosted by their
+// follower counts. Emails are not returned
+func (api SearchAPI) SearchUsers() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := strings.TrimSpace(r.URL.Query().Get("q"))
+		if q == "" || len(q) > maxSearchQueryLength {
+			writeProblem(http.StatusBadRequest,
+				fmt.Sprintf("q must be between 1 and %d characters", maxSearchQueryLength), w, r)
+			return
+		}
+		limit := ListOptionsFromRequest(r).Limit
+		if limit > maxUserResults {
+			limit = maxUserResults
+		}
+		users, err := api.stores.SearchUsers(q, limit)
+		if err != nil {
+			writeError(err, w, r, api.debug)
+			return
+		}
+		if users == nil {
+			users = []data.User{}
+		}
+		for i := range users {
+			users[i].Email, users[i].Password = "", ""
+		}
+		writeJSON(users, w)
+	}
+}
+
+// AutocompleteUsers returns an http handler that handles username
+// autocomplete API requests while composing mentions, suggesting the
+// most followed users whose username the q query parameter prefixes.
+// A leading @ is ignored
+func (api SearchAPI) AutocompleteUsers() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		prefix := strings.TrimPrefix(r.URL.Query().Get("q"), "@")
+		if prefix == "" || !usernameRegex.MatchString(prefix) {
+			writeProblem(http.StatusBadRequest, "q must be the start of a username", w, r)
+			return
+		}
+		limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+		if err != nil || limit <= 0 {
+			limit = defaultSuggestions
+		} else if limit > maxSuggestions {
+			limit = maxSuggestions
+		}
+		users, err := api.stores.CompleteUsernames(prefix, limit)
+		if err != nil {
+			writeError(err, w, r, api.debug)
+			return
+		}
+		suggestions := make([]UserSuggestion, len(users))
+		for i, user := range users {
+			suggestions[i] = UserSuggestion{
+				ID:         user.ID,
+				Username:   user.Username,
+				ActualName: user.ActualName,
+			}
+		}
+		writeJSON(suggestions, w)
+	}
+}
+
+// unixFromQuery parses a time in seconds since the epoch,
+// returning the zero time if the value is empty
+func unixFromQuery(value string) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, true
+	}
+	unix, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(unix, 0), true
+}
@@ -0,0 +1,185 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/boxtown/meirl/data"
+	"github.com/boxtown/meirl/data/datatest"
+)
+
+func TestSearchPosts(t *testing.T) {
+	var query data.PostQuery
+	var options data.ListOptions
+	api := NewSearchAPI(
+		data.Stores{
+			UserStore: mockUserStore{
+				OnGetByUsername: func(username string) (*data.User, error) {
+					if username != "test" {
+						return nil, data.ErrNoEnt
+					}
+					return &data.User{Mutable: data.Mutable{AutoIncr: data.AutoIncr{ID: 2}}}, nil
+				},
+			},
+			SearchStore: mockSearchStore{
+				OnSearchPosts: func(q data.PostQuery, o data.ListOptions) ([]data.PostSearchResult, error) {
+					query, options = q, o
+					return []data.PostSearchResult{
+						{Post: *datatest.ExamplePost(2), Snippet: "a <mark>match</mark>"},
+					}, nil
+				},
+			},
+		},
+		false,
+	)
+
+	marker := data.SearchMarker{CreatedAt: time.Unix(1500000000, 0), ID: 3}.String(data.SearchByDate)
+	r, _ := http.NewRequest("GET", `/?q="a+match"&author=test&since=1400000000&sort=date&marker=`+marker, nil)
+	w := httptest.NewRecorder()
+	api.SearchPosts()(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status code %d, received %d", http.StatusOK, w.Code)
+		t.FailNow()
+	}
+	if query.Text != `"a match"` || query.AuthorID != 2 || query.Sort != data.SearchByDate {
+		t.Errorf("Expected a phrase by the author sorted by date, got %+v", query)
+		t.Fail()
+	}
+	if !query.Since.Equal(time.Unix(1400000000, 0)) || !query.Until.IsZero() {
+		t.Errorf("Expected results since the given time, got %+v", query)
+		t.Fail()
+	}
+	m, ok := options.Marker.(data.SearchMarker)
+	if !ok || m.ID != 3 || !m.CreatedAt.Equal(time.Unix(1500000000, 0)) || !options.Desc {
+		t.Errorf("Expected newest results after the marker, got %+v", options)
+		t.Fail()
+	}
+	var results []data.PostSearchResult
+	if err := json.NewDecoder(w.Body).Decode(&results); err != nil {
+		t.Error(err.Error())
+		t.FailNow()
+	}
+	if len(results) != 1 || results[0].Snippet != "a <mark>match</mark>" {
+		t.Errorf("Expected a highlighted result, got %v", results)
+		t.Fail()
+	}
+
+	// posts by unknown authors match nothing
+	query = data.PostQuery{}
+	r, _ = http.NewRequest("GET", "/?q=match&author=unknown", nil)
+	w = httptest.NewRecorder()
+	api.SearchPosts()(w, r)
+	if w.Code != http.StatusOK || query.Text != "" {
+		t.Errorf("Expected no search for an unknown author, got %d", w.Code)
+		t.Fail()
+	}
+
+	for _, q := range []string{"/", "/?q=match&sort=best", "/?q=match&until=yesterday", "/?q=match&marker=1"} {
+		r, _ = http.NewRequest("GET", q, nil)
+		w = httptest.NewRecorder()
+		api.SearchPosts()(w, r)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status code %d for %s, received %d", http.StatusBadRequest, q, w.Code)
+			t.Fail()
+		}
+	}
+}
+
+func TestSearchUsers(t *testing.T) {
+	var searched string
+	var limit int
+	api := NewSearchAPI(
+		data.Stores{
+			SearchStore: mockSearchStore{
+				OnSearchUsers: func(query string, l int) ([]data.User, error) {
+					searched, limit = query, l
+					user := datatest.ExampleUser()
+					return []data.User{*user}, nil
+				},
+			},
+		},
+		false,
+	)
+
+	r, _ := http.NewRequest("GET", "/?q=+jon+smith+&limit=100", nil)
+	w := httptest.NewRecorder()
+	api.SearchUsers()(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status code %d, received %d", http.StatusOK, w.Code)
+		t.FailNow()
+	}
+	if searched != "jon smith" || limit != maxUserResults {
+		t.Errorf("Expected a trimmed query limited to %d, got %q and %d", maxUserResults, searched, limit)
+		t.Fail()
+	}
+	var users []data.User
+	if err := json.NewDecoder(w.Body).Decode(&users); err != nil {
+		t.Error(err.Error())
+		t.FailNow()
+	}
+	if len(users) != 1 || users[0].Email != "" || users[0].Password != "" {
+		t.Errorf("Expected a user without email or password, got %v", users)
+		t.Fail()
+	}
+
+	r, _ = http.NewRequest("GET", "/?q=+", nil)
+	w = httptest.NewRecorder()
+	api.SearchUsers()(w, r)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d, received %d", http.StatusBadRequest, w.Code)
+		t.Fail()
+	}
+}
+
+func TestAutocompleteUsers(t *testing.T) {
+	var prefix string
+	var limit int
+	api := NewSearchAPI(
+		data.Stores{
+			SearchStore: mockSearchStore{
+				OnCompleteUsernames: func(p string, l int) ([]data.User, error) {
+					prefix, limit = p, l
+					return []data.User{*datatest.ExampleUser()}, nil
+				},
+			},
+		},
+		false,
+	)
+
+	r, _ := http.NewRequest("GET", "/?q=@te", nil)
+	w := httptest.NewRecorder()
+	api.AutocompleteUsers()(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status code %d, received %d", http.StatusOK, w.Code)
+		t.FailNow()
+	}
+	if prefix != "te" || limit != defaultSuggestions {
+		t.Errorf("Expected prefix te limited to %d, got %q and %d", defaultSuggestions, prefix, limit)
+		t.Fail()
+	}
+	var suggestions []UserSuggestion
+	if err := json.NewDecoder(w.Body).Decode(&suggestions); err != nil {
+		t.Error(err.Error())
+		t.FailNow()
+	}
+	if len(suggestions) != 1 || suggestions[0].Username != datatest.ExampleUser().Username {
+		t.Errorf("Expected the suggested user, got %v", suggestions)
+		t.Fail()
+	}
+
+	for _, q := range []string{"/?q=@", "/?q=te%25"} {
+		r, _ = http.NewRequest("GET", q, nil)
+		w = httptest.NewRecorder()
+		api.AutocompleteUsers()(w, r)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status code %d for %s, received %d", http.StatusBadRequest, q, w.Code)
+			t.Fail()
+		}
+	}
+}
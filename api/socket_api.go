@@ -0,0 +1,402 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/boxtown/meirl/data"
+	"github.com/boxtown/meirl/realtime"
+	"github.com/gorilla/websocket"
+	"github.com/uber-go/zap"
+)
+
+// Socket message types
+const (
+	// SocketSubscribe is sent by clients to subscribe to a topic
+	SocketSubscribe = "subscribe"
+
+	// SocketUnsubscribe is sent by clients to unsubscribe from a topic
+	SocketUnsubscribe = "unsubscribe"
+
+	// SocketSubscribed acknowledges a subscribe message
+	SocketSubscribed = "subscribed"
+
+	// SocketUnsubscribed acknowledges an unsubscribe message
+	SocketUnsubscribed = "unsubscribed"
+
+	// SocketPost carries a post created in the feed
+	SocketPost = "post"
+
+	// SocketReactions carries the reaction counts of a post
+	SocketReactions = "reactions"
+
+	// SocketNotification carries a notification created
+	// or updated for the authenticated user
+	SocketNotification = "notification"
+
+	// SocketError reports a message that could not be handled
+	SocketError = "error"
+)
+
+// Socket topics
+const (
+	// TopicFeed streams posts created by the authenticated
+	// user and the users they follow
+	TopicFeed = "feed"
+
+	// TopicPost streams the reaction counts of the post with
+	// the ID given in the subscribe message
+	TopicPost = "post"
+
+	// TopicNotifications streams notifications
+	// created for the authenticated user
+	TopicNotifications = "notifications"
+)
+
+const (
+	// socketSendBuffer is the number of messages buffered for each
+	// connection. Connections that fall further behind are closed
+	socketSendBuffer = 64
+
+	// socketReadLimit is the maximum size of a client message
+	socketReadLimit = 4096
+
+	// maxSocketPostSubscriptions is the maximum number of
+	// posts a connection may subscribe to
+	maxSocketPostSubscriptions = 100
+)
+
+// SocketAPI contains state information for executing
+// MeIRL WebSocket gateway route handlers
+type SocketAPI struct {
+	stores    data.Stores
+	bus       *realtime.Bus
+	upgrader  websocket.Upgrader
+	pingEvery time.Duration
+	pongWait  time.Duration
+	writeWait time.Duration
+	done      chan struct{}
+	once      sync.Once
+	debug     bool
+}
+
+// NewSocketAPI returns an instance of the SocketAPI struct.
+// Connections receive the events published on bus
+func NewSocketAPI(stores data.Stores, bus *realtime.Bus, debug bool) *SocketAPI {
+	return &SocketAPI{
+		stores: stores,
+		bus:    bus,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			// clients authenticate with a token rather than
+			// cookies, so any origin may connect
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+		pingEvery: 30 * time.Second,
+		pongWait:  60 * time.Second,
+		writeWait: 10 * time.Second,
+		done:      make(chan struct{}),
+		debug:     debug,
+	}
+}
+
+// Shutdown closes every open connection so that graceful
+// shutdown does not wait on them
+func (api *SocketAPI) Shutdown() {
+	api.once.Do(func() {
+		close(api.done)
+	})
+}
+
+// Connect returns an http handler that upgrades the request to a
+// WebSocket connection for the authenticated user. Over a single
+// connection the client may subscribe to its feed, the reaction
+// counts of individual posts and its notifications
+func (api *SocketAPI) Connect() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := claimsID(r)
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		conn, err := api.upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			// the upgrader has responded with an error
+			return
+		}
+		s := &socketSession{
+			api:    api,
+			conn:   conn,
+			userID: id,
+			logger: requestLogger(r),
+			send:   make(chan SocketMessage, socketSendBuffer),
+			posts:  make(map[int64]bool),
+		}
+		s.run()
+	}
+}
+
+// socketSession is the state of a single WebSocket connection.
+// Subscriptions are only accessed by the goroutine running the session
+type socketSession struct {
+	api    *SocketAPI
+	conn   *websocket.Conn
+	userID int64
+	logger zap.Logger
+
+	send        chan SocketMessage
+	closeCode   int
+	closeReason string
+
+	feed          *feedFilter
+	posts         map[int64]bool
+	notifications bool
+}
+
+// run serves the connection until either side closes it
+func (s *socketSession) run() {
+	sub := s.api.bus.Subscribe()
+	defer sub.Close()
+
+	requests := make(chan SocketMessage)
+	quit := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		s.read(requests, quit)
+	}()
+	go func() {
+		defer wg.Done()
+		s.write()
+	}()
+
+	s.closeCode = websocket.CloseNormalClosure
+	s.serve(sub, requests)
+	// the writer sends the close message and closes the
+	// connection, which in turn stops the reader
+	close(quit)
+	close(s.send)
+	wg.Wait()
+}
+
+// serve dispatches client requests and bus events until
+// the client disconnects or the connection must be closed
+func (s *socketSession) serve(sub *realtime.Subscription, requests <-chan SocketMessage) {
+	for {
+		select {
+		case <-s.api.done:
+			s.closeCode, s.closeReason = websocket.CloseGoingAway, "server shutting down"
+			return
+		case msg, ok := <-requests:
+			if !ok {
+				return
+			}
+			if !s.handle(msg) {
+				return
+			}
+		case e, ok := <-sub.Events():
+			if !ok {
+				s.closeCode, s.closeReason = websocket.CloseTryAgainLater, "fell behind"
+				return
+			}
+			if !s.dispatch(e) {
+				return
+			}
+		}
+	}
+}
+
+// enqueue queues a message for the writer without blocking. Returns
+// false, marking the connection for closing, if the buffer is full
+func (s *socketSession) enqueue(msg SocketMessage) bool {
+	select {
+	case s.send <- msg:
+		return true
+	default:
+		s.closeCode, s.closeReason = websocket.CloseTryAgainLater, "send buffer full"
+		return false
+	}
+}
+
+// handle applies a subscribe or unsubscribe request
+func (s *socketSession) handle(msg SocketMessage) bool {
+	reply := SocketMessage{Topic: msg.Topic, ID: msg.ID}
+	switch msg.Type {
+	case SocketSubscribe:
+		reply.Type = SocketSubscribed
+		if detail := s.subscribe(msg); detail != "" {
+			reply.Type, reply.Error = SocketError, detail
+		}
+	case SocketUnsubscribe:
+		reply.Type = SocketUnsubscribed
+		switch msg.Topic {
+		case TopicFeed:
+			s.feed = nil
+		case TopicPost:
+			delete(s.posts, msg.ID)
+		case TopicNotifications:
+			s.notifications = false
+		default:
+			reply.Type, reply.Error = SocketError, "unknown topic"
+		}
+	default:
+		reply.Type, reply.Error = SocketError, "unknown message type"
+	}
+	if !s.enqueue(reply) {
+		return false
+	}
+	if reply.Type == SocketSubscribed && msg.Topic == TopicPost {
+		// start the client off with the current counts
+		return s.sendReactions(msg.ID)
+	}
+	return true
+}
+
+// subscribe subscribes to the requested topic, returning
+// an error detail for the client if it cannot
+func (s *socketSession) subscribe(msg SocketMessage) string {
+	switch msg.Topic {
+	case TopicFeed:
+		feed, err := newFeedFilter(s.api.stores, s.userID)
+		if err != nil {
+			s.logger.Error("could not load follows", zap.Error(err))
+			return "could not subscribe to feed"
+		}
+		s.feed = feed
+	case TopicPost:
+		if s.posts[msg.ID] {
+			return ""
+		}
+		if len(s.posts) >= maxSocketPostSubscriptions {
+			return "too many post subscriptions"
+		}
+		// posts the user may not see are not found, as by GetPost
+		_, err := getVisiblePost(s.api.stores, msg.ID, s.userID)
+		if err == data.ErrNoEnt {
+			return "post not found"
+		} else if err != nil {
+			s.logger.Error("could not load post", zap.Int64("post_id", msg.ID), zap.Error(err))
+			return "could not subscribe to post"
+		}
+		s.posts[msg.ID] = true
+	case TopicNotifications:
+		s.notifications = true
+	default:
+		return "unknown topic"
+	}
+	return ""
+}
+
+// dispatch forwards an event to the client if it is subscribed to it
+func (s *socketSession) dispatch(e realtime.Event) bool {
+	switch {
+	case s.feed != nil && s.feed.accepts(e):
+		post, err := s.api.stores.PostStore.Get(e.ID)
+		if err == data.ErrNoEnt {
+			return true
+		} else if err != nil {
+			s.logger.Error("could not load streamed post", zap.Int64("post_id", e.ID), zap.Error(err))
+			return true
+		}
+		return s.enqueue(SocketMessage{Type: SocketPost, Topic: TopicFeed, ID: post.ID, Post: post})
+	case e.Type == realtime.PostReactionsChanged && s.posts[e.ID]:
+		return s.sendReactions(e.ID)
+	case e.Type == realtime.NotificationCreated && s.notifications && e.UserID == s.userID:
+		notification, err := s.api.stores.NotificationStore.Get(e.ID)
+		if err == data.ErrNoEnt {
+			return true
+		} else if err != nil {
+			s.logger.Error("could not load notification", zap.Int64("notification_id", e.ID), zap.Error(err))
+			return true
+		}
+		return s.enqueue(SocketMessage{
+			Type:         SocketNotification,
+			Topic:        TopicNotifications,
+			ID:           e.ID,
+			Notification: notification,
+		})
+	}
+	return true
+}
+
+// sendReactions loads and sends the reaction counts of a post
+func (s *socketSession) sendReactions(postID int64) bool {
+	post, err := s.api.stores.PostStore.Get(postID)
+	if err == data.ErrNoEnt {
+		delete(s.posts, postID)
+		return true
+	} else if err != nil {
+		s.logger.Error("could not load post reactions", zap.Int64("post_id", postID), zap.Error(err))
+		return true
+	}
+	return s.enqueue(SocketMessage{
+		Type:  SocketReactions,
+		Topic: TopicPost,
+		ID:    postID,
+		Keks:  &post.Keks,
+		Nos:   &post.Nos,
+	})
+}
+
+// read reads client messages into requests until the connection
+// fails or quit is closed. Pongs extend the read deadline
+func (s *socketSession) read(requests chan<- SocketMessage, quit <-chan struct{}) {
+	defer close(requests)
+	s.conn.SetReadLimit(socketReadLimit)
+	s.conn.SetReadDeadline(time.Now().Add(s.api.pongWait))
+	s.conn.SetPongHandler(func(string) error {
+		return s.conn.SetReadDeadline(time.Now().Add(s.api.pongWait))
+	})
+	for {
+		var msg SocketMessage
+		if err := s.conn.ReadJSON(&msg); err != nil {
+			var syntaxErr *json.SyntaxError
+			var typeErr *json.UnmarshalTypeError
+			if err != io.ErrUnexpectedEOF && !errors.As(err, &syntaxErr) && !errors.As(err, &typeErr) {
+				return
+			}
+			// malformed messages are reported as unknown rather
+			// than closing the connection
+			msg = SocketMessage{}
+		}
+		select {
+		case requests <- msg:
+		case <-quit:
+			return
+		}
+	}
+}
+
+// write writes queued messages and pings until the send channel
+// is closed, then closes the connection
+func (s *socketSession) write() {
+	ping := time.NewTicker(s.api.pingEvery)
+	defer func() {
+		ping.Stop()
+		s.conn.Close()
+	}()
+	for {
+		select {
+		case msg, ok := <-s.send:
+			s.conn.SetWriteDeadline(time.Now().Add(s.api.writeWait))
+			if !ok {
+				s.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(s.closeCode, s.closeReason))
+				return
+			}
+			if err := s.conn.WriteJSON(msg); err != nil {
+				return
+			}
+		case <-ping.C:
+			s.conn.SetWriteDeadline(time.Now().Add(s.api.writeWait))
+			if err := s.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
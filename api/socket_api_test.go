@@ -0,0 +1,229 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/boxtown/meirl/data"
+	"github.com/boxtown/meirl/data/datatest"
+	"github.com/boxtown/meirl/realtime"
+	"github.com/gorilla/websocket"
+)
+
+var socketSigningKey = []byte("test")
+
+// dialSocket opens a socket to the API as user 1, passing
+// the access token in the query string
+func dialSocket(t *testing.T, api *SocketAPI) (*websocket.Conn, func()) {
+	server := httptest.NewServer(GetSocketClaimsMiddleware(socketSigningKey, api.stores.UserStore, api.Connect()))
+	user := datatest.ExampleUser()
+	user.ID = 1
+	token, err := NewAuth().GenerateAccessToken(user, socketSigningKey)
+	if err != nil {
+		t.Error(err.Error())
+		t.FailNow()
+	}
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "?access_token=" + token
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		server.Close()
+		t.Error(err.Error())
+		t.FailNow()
+	}
+	return conn, func() {
+		conn.Close()
+		server.Close()
+	}
+}
+
+// readSocketMessage reads the next message of the given type,
+// skipping any others
+func readSocketMessage(t *testing.T, conn *websocket.Conn, msgType string) SocketMessage {
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		var msg SocketMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			t.Error(err.Error())
+			t.FailNow()
+		}
+		if msg.Type == msgType {
+			return msg
+		}
+	}
+}
+
+// socketStores returns stores where user 1 follows users 2 and 4,
+// muted user 4 and does not follow private user 3, the author of post 403
+func socketStores() data.Stores {
+	return data.Stores{
+		UserStore: mockUserStore{
+			OnGet: func(id int64) (*data.User, error) {
+				user := datatest.ExampleUser()
+				user.ID, user.Private = id, id == 3
+				return user, nil
+			},
+			OnFollowingIDs: func(id int64) ([]int64, error) {
+				return []int64{2, 4}, nil
+			},
+			OnIsFollowing: func(followerID, followeeID int64) (bool, error) {
+				return followerID == 1 && (followeeID == 2 || followeeID == 4), nil
+			},
+		},
+		BlockStore: mockBlockStore{
+			OnIsBlocked: func(userID, otherID int64) (bool, error) {
+				return false, nil
+			},
+			OnMutedIDs: func(id int64) ([]int64, error) {
+				return []int64{4}, nil
+			},
+		},
+		PostStore: mockPostStore{
+			OnGet: func(id int64) (*data.Post, error) {
+				if id == 404 {
+					return nil, data.ErrNoEnt
+				}
+				authorID := int64(2)
+				if id == 403 {
+					authorID = 3
+				}
+				post := datatest.ExamplePost(authorID)
+				post.ID = id
+				post.Keks = 3
+				post.Nos = 1
+				return post, nil
+			},
+		},
+		NotificationStore: mockNotificationStore{
+			OnGet: func(id int64) (*data.Notification, error) {
+				notification := &data.Notification{UserID: 1, Type: data.NotificationFollow}
+				notification.ID = id
+				return notification, nil
+			},
+		},
+	}
+}
+
+func TestSocketSubscriptions(t *testing.T) {
+	bus := realtime.NewBus()
+	api := NewSocketAPI(socketStores(), bus, false)
+	conn, closeSocket := dialSocket(t, api)
+	defer closeSocket()
+
+	for _, msg := range []SocketMessage{
+		{Type: SocketSubscribe, Topic: TopicFeed},
+		{Type: SocketSubscribe, Topic: TopicNotifications},
+		{Type: SocketSubscribe, Topic: TopicPost, ID: 7},
+	} {
+		if err := conn.WriteJSON(msg); err != nil {
+			t.Error(err.Error())
+			t.FailNow()
+		}
+		reply := readSocketMessage(t, conn, SocketSubscribed)
+		if reply.Topic != msg.Topic || reply.ID != msg.ID {
+			t.Errorf("Expected subscription to %s %d, got %s %d", msg.Topic, msg.ID, reply.Topic, reply.ID)
+			t.Fail()
+		}
+	}
+	reactions := readSocketMessage(t, conn, SocketReactions)
+	if reactions.ID != 7 || reactions.Keks == nil || *reactions.Keks != 3 || *reactions.Nos != 1 {
+		t.Errorf("Expected current reaction counts of post 7, got %+v", reactions)
+		t.Fail()
+	}
+
+	bus.Publish(realtime.Event{Type: realtime.PostCreated, ID: 8, UserID: 3})
+	bus.Publish(realtime.Event{Type: realtime.PostCreated, ID: 8, UserID: 4})
+	bus.Publish(realtime.Event{Type: realtime.PostCreated, ID: 9, UserID: 2})
+	post := readSocketMessage(t, conn, SocketPost)
+	if post.ID != 9 || post.Post == nil || post.Post.ID != 9 {
+		t.Errorf("Expected post 9 from a followed and unmuted user, got %+v", post)
+		t.Fail()
+	}
+
+	bus.Publish(realtime.Event{Type: realtime.PostReactionsChanged, ID: 6, UserID: 2})
+	bus.Publish(realtime.Event{Type: realtime.PostReactionsChanged, ID: 7, UserID: 2})
+	reactions = readSocketMessage(t, conn, SocketReactions)
+	if reactions.ID != 7 {
+		t.Errorf("Expected reactions of post 7, got %d", reactions.ID)
+		t.Fail()
+	}
+
+	bus.Publish(realtime.Event{Type: realtime.NotificationCreated, ID: 10, UserID: 2})
+	bus.Publish(realtime.Event{Type: realtime.NotificationCreated, ID: 11, UserID: 1})
+	notification := readSocketMessage(t, conn, SocketNotification)
+	if notification.ID != 11 || notification.Notification == nil || notification.Notification.Type != data.NotificationFollow {
+		t.Errorf("Expected notification 11 for user 1, got %d", notification.ID)
+		t.Fail()
+	}
+}
+
+func TestSocketErrors(t *testing.T) {
+	api := NewSocketAPI(socketStores(), realtime.NewBus(), false)
+	conn, closeSocket := dialSocket(t, api)
+	defer closeSocket()
+
+	for _, msg := range []SocketMessage{
+		{Type: SocketSubscribe, Topic: "everything"},
+		{Type: SocketSubscribe, Topic: TopicPost, ID: 404},
+		{Type: SocketSubscribe, Topic: TopicPost, ID: 403},
+		{Type: "shout"},
+	} {
+		if err := conn.WriteJSON(msg); err != nil {
+			t.Error(err.Error())
+			t.FailNow()
+		}
+		reply := readSocketMessage(t, conn, SocketError)
+		if reply.Error == "" {
+			t.Errorf("Expected an error for %+v", msg)
+			t.Fail()
+		}
+	}
+	if err := conn.WriteMessage(websocket.TextMessage, []byte("{")); err != nil {
+		t.Error(err.Error())
+		t.FailNow()
+	}
+	readSocketMessage(t, conn, SocketError)
+}
+
+func TestSocketShutdown(t *testing.T) {
+	api := NewSocketAPI(socketStores(), realtime.NewBus(), false)
+	conn, closeSocket := dialSocket(t, api)
+	defer closeSocket()
+
+	api.Shutdown()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, _, err := conn.ReadMessage()
+	if !websocket.IsCloseError(err, websocket.CloseGoingAway) {
+		t.Errorf("Expected a going away close, got %v", err)
+		t.Fail()
+	}
+}
+
+func TestSocketSendBufferFull(t *testing.T) {
+	s := &socketSession{send: make(chan SocketMessage, 1)}
+	if !s.enqueue(SocketMessage{Type: SocketPost}) {
+		t.Errorf("Expected message to be queued")
+		t.Fail()
+	}
+	if s.enqueue(SocketMessage{Type: SocketPost}) {
+		t.Errorf("Expected full buffer to reject message")
+		t.Fail()
+	}
+	if s.closeCode != websocket.CloseTryAgainLater {
+		t.Errorf("Expected try again later close code, got %d", s.closeCode)
+		t.Fail()
+	}
+}
+
+func TestSocketRequiresClaims(t *testing.T) {
+	api := NewSocketAPI(socketStores(), realtime.NewBus(), false)
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest("GET", "/", nil)
+	GetSocketClaimsMiddleware(socketSigningKey, api.stores.UserStore, api.Connect())(w, r)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+		t.Fail()
+	}
+}
@@ -0,0 +1,220 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/boxtown/meirl/data"
+	"github.com/boxtown/meirl/realtime"
+	"github.com/uber-go/zap"
+)
+
+const (
+	// maxStreamBacklog is the maximum number of posts sent
+	// when resuming a stream from a Last-Event-ID
+	maxStreamBacklog = 1000
+
+	// streamRetry is how long clients wait before
+	// reconnecting a dropped stream
+	streamRetry = 3 * time.Second
+)
+
+// StreamAPI contains state information for executing
+// MeIRL streaming API route handlers
+type StreamAPI struct {
+	stores    data.Stores
+	bus       *realtime.Bus
+	heartbeat time.Duration
+	done      chan struct{}
+	once      sync.Once
+	debug     bool
+}
+
+// NewStreamAPI returns an instance of the StreamAPI struct.
+// Streams receive the events published on bus
+func NewStreamAPI(stores data.Stores, bus *realtime.Bus, debug bool) *StreamAPI {
+	return &StreamAPI{
+		stores:    stores,
+		bus:       bus,
+		heartbeat: 15 * time.Second,
+		done:      make(chan struct{}),
+		debug:     debug,
+	}
+}
+
+// Shutdown ends every open stream so that graceful shutdown
+// does not wait on them. Clients reconnect to another instance
+// and resume from their last event
+func (api *StreamAPI) Shutdown() {
+	api.once.Do(func() {
+		close(api.done)
+	})
+}
+
+// FeedStream returns an http handler that streams the posts created by
+// the authenticated user and the users they follow as server-sent events.
+// Event IDs are post IDs, so a reconnecting client sending Last-Event-ID
+// first receives the posts it missed
+func (api *StreamAPI) FeedStream() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := claimsID(r)
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			writeProblem(http.StatusInternalServerError, "Streaming is not supported", w, r)
+			return
+		}
+
+		// subscribe before loading follows and the backlog
+		// so that no event is missed in between
+		sub := api.bus.Subscribe()
+		defer sub.Close()
+
+		feed, err := newFeedFilter(api.stores, id)
+		if err != nil {
+			writeError(err, w, r, api.debug)
+			return
+		}
+
+		lastID, resume := lastEventID(r)
+		var backlog []data.Post
+		if resume {
+			options := data.ListOptions{Marker: lastID, Limit: maxStreamBacklog}
+			backlog, err = api.stores.PostStore.Feed(id, options, data.PostSortByID)
+			if err != nil {
+				writeError(err, w, r, api.debug)
+				return
+			}
+		}
+
+		// streams outlive the server write timeout
+		http.NewResponseController(w).SetWriteDeadline(time.Time{})
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, "retry: %d\n\n", streamRetry/time.Millisecond)
+		for i := range backlog {
+			if err := writePostEvent(w, &backlog[i]); err != nil {
+				return
+			}
+			lastID = backlog[i].ID
+		}
+		flusher.Flush()
+
+		heartbeat := time.NewTicker(api.heartbeat)
+		defer heartbeat.Stop()
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-api.done:
+				return
+			case <-heartbeat.C:
+				if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+					return
+				}
+			case e, ok := <-sub.Events():
+				if !ok {
+					// fell behind, the client resumes from its last event
+					return
+				}
+				if !feed.accepts(e) || e.ID <= lastID {
+					continue
+				}
+				post, err := api.stores.PostStore.Get(e.ID)
+				if err == data.ErrNoEnt {
+					continue
+				} else if err != nil {
+					requestLogger(r).Error("could not load streamed post", zap.Int64("post_id", e.ID), zap.Error(err))
+					return
+				}
+				if err := writePostEvent(w, post); err != nil {
+					return
+				}
+				lastID = post.ID
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// feedFilter tracks which users' posts belong in the feed of a user
+// as they follow and unfollow other users. The posts of users muted
+// when the filter was created are hidden, as they are from the feed
+type feedFilter struct {
+	userID    int64
+	following map[int64]bool
+	muted     map[int64]bool
+}
+
+func newFeedFilter(stores data.Stores, userID int64) (*feedFilter, error) {
+	ids, err := stores.FollowingIDs(userID)
+	if err != nil {
+		return nil, err
+	}
+	following := make(map[int64]bool, len(ids)+1)
+	following[userID] = true
+	for _, id := range ids {
+		following[id] = true
+	}
+	ids, err = stores.MutedIDs(userID)
+	if err != nil {
+		return nil, err
+	}
+	muted := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		muted[id] = true
+	}
+	return &feedFilter{userID: userID, following: following, muted: muted}, nil
+}
+
+// accepts returns true if the event is a post created in the feed.
+// Follow events of the user update the filter
+func (f *feedFilter) accepts(e realtime.Event) bool {
+	switch e.Type {
+	case realtime.UserFollowed:
+		if e.UserID == f.userID {
+			f.following[e.ID] = true
+		}
+	case realtime.UserUnFollowed:
+		if e.UserID == f.userID && e.ID != f.userID {
+			delete(f.following, e.ID)
+		}
+	case realtime.PostCreated:
+		return f.following[e.UserID] && !f.muted[e.UserID]
+	}
+	return false
+}
+
+// lastEventID returns the ID of the last event received by a
+// reconnecting client. Browsers send the Last-Event-ID header when
+// reconnecting, while other clients may use the lastEventId param
+func lastEventID(r *http.Request) (int64, bool) {
+	value := r.Header.Get("Last-Event-ID")
+	if value == "" {
+		value = r.URL.Query().Get("lastEventId")
+	}
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id < 0 {
+		return 0, false
+	}
+	return id, true
+}
+
+// Write the post as a server-sent event
+func writePostEvent(w http.ResponseWriter, post *data.Post) error {
+	b, err := json.Marshal(post)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: post\ndata: %s\n\n", post.ID, b)
+	return err
+}
@@ -0,0 +1,123 @@
+package api
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/boxtown/meirl/api/apitest"
+	"github.com/boxtown/meirl/data"
+	"github.com/boxtown/meirl/data/datatest"
+	"github.com/boxtown/meirl/realtime"
+	jwt "github.com/dgrijalva/jwt-go"
+)
+
+// readEventIDs reads server-sent events from the stream until
+// n post event IDs have been read or the stream ends
+func readEventIDs(t *testing.T, scanner *bufio.Scanner, n int) []string {
+	var ids []string
+	for len(ids) < n && scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "id: ") {
+			ids = append(ids, strings.TrimPrefix(line, "id: "))
+		}
+	}
+	return ids
+}
+
+func TestFeedStream(t *testing.T) {
+	var feedOptions data.ListOptions
+	bus := realtime.NewBus()
+	api := NewStreamAPI(
+		data.Stores{
+			UserStore: mockUserStore{
+				OnFollowingIDs: func(id int64) ([]int64, error) {
+					return []int64{2, 4}, nil
+				},
+			},
+			BlockStore: mockBlockStore{
+				OnMutedIDs: func(id int64) ([]int64, error) {
+					return []int64{4}, nil
+				},
+			},
+			PostStore: mockPostStore{
+				OnGet: func(id int64) (*data.Post, error) {
+					post := datatest.ExamplePost(1)
+					post.ID = id
+					return post, nil
+				},
+				OnFeed: func(
+					userID int64,
+					options data.ListOptions,
+					sort data.PostSortMethod) ([]data.Post, error) {
+					feedOptions = options
+					a, b := datatest.ExamplePost(2), datatest.ExamplePost(1)
+					a.ID, b.ID = 6, 7
+					return []data.Post{*a, *b}, nil
+				},
+			},
+		},
+		bus,
+		false,
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r = apitest.RequestWithClaims(r, claimsContextKey, jwt.MapClaims{"sub": int64(1)})
+		api.FeedStream()(w, r)
+	}))
+	defer server.Close()
+
+	r, _ := http.NewRequest("GET", server.URL, nil)
+	r.Header.Set("Last-Event-ID", "5")
+	resp, err := http.DefaultClient.Do(r)
+	if err != nil {
+		t.Error(err.Error())
+		t.FailNow()
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Expected event stream content type, got %s", ct)
+		t.FailNow()
+	}
+	scanner := bufio.NewScanner(resp.Body)
+	ids := readEventIDs(t, scanner, 2)
+	if strings.Join(ids, ",") != "6,7" {
+		t.Errorf("Expected backlog of posts 6 and 7, got %v", ids)
+		t.FailNow()
+	}
+	if feedOptions.Marker != int64(5) {
+		t.Errorf("Expected backlog after post 5, got marker %v", feedOptions.Marker)
+		t.Fail()
+	}
+
+	// posts by unfollowed or muted users, and posts already sent, are skipped
+	bus.Publish(realtime.Event{Type: realtime.PostCreated, ID: 8, UserID: 3})
+	bus.Publish(realtime.Event{Type: realtime.PostCreated, ID: 8, UserID: 4})
+	bus.Publish(realtime.Event{Type: realtime.PostCreated, ID: 7, UserID: 2})
+	bus.Publish(realtime.Event{Type: realtime.UserFollowed, ID: 3, UserID: 1})
+	bus.Publish(realtime.Event{Type: realtime.PostCreated, ID: 9, UserID: 3})
+	bus.Publish(realtime.Event{Type: realtime.UserUnFollowed, ID: 2, UserID: 1})
+	bus.Publish(realtime.Event{Type: realtime.PostCreated, ID: 10, UserID: 2})
+	bus.Publish(realtime.Event{Type: realtime.PostCreated, ID: 11, UserID: 1})
+	ids = readEventIDs(t, scanner, 2)
+	if strings.Join(ids, ",") != "9,11" {
+		t.Errorf("Expected posts 9 and 11, got %v", ids)
+		t.Fail()
+	}
+
+	api.Shutdown()
+	done := make(chan bool)
+	go func() {
+		for scanner.Scan() {
+		}
+		done <- true
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("Expected shutdown to end the stream")
+		t.Fail()
+	}
+}
@@ -61,6 +61,15 @@ func tokenResponseFromJSON(r io.Reader) (*TokenResponse, error) {
 	return &t, nil
 }
 
+func followResponseFromJSON(r io.Reader) (*FollowResponse, error) {
+	var f FollowResponse
+	err := json.NewDecoder(r).Decode(&f)
+	if err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
 func postFromJSON(r io.Reader) (*data.Post, error) {
 	var p data.Post
 	err := json.NewDecoder(r).Decode(&p)
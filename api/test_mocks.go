@@ -1,6 +1,10 @@
 package api
 
-import "github.com/boxtown/meirl/data"
+import (
+	"time"
+
+	"github.com/boxtown/meirl/data"
+)
 
 /* *************** *
  * Mock User Store *
@@ -25,6 +29,15 @@ type mockUserStore struct {
 		id int64,
 		options data.ListOptions,
 		sort data.UserSortMethod) ([]data.User, error)
+
+	OnFollowingIDs func(id int64) ([]int64, error)
+
+	OnSetPrivate          func(id int64, private bool) error
+	OnIsFollowing         func(followerID, followeeID int64) (bool, error)
+	OnRequestFollow       func(followerID, followeeID int64) error
+	OnApproveFollow       func(followerID, followeeID int64) error
+	OnDeleteFollowRequest func(followerID, followeeID int64) error
+	OnFollowRequests      func(id int64, options data.ListOptions) ([]data.User, error)
 }
 
 func (store mockUserStore) Create(user *data.User) (int64, error) {
@@ -73,6 +86,34 @@ func (store mockUserStore) Following(
 	return store.OnFollowing(id, options, sort)
 }
 
+func (store mockUserStore) FollowingIDs(id int64) ([]int64, error) {
+	return store.OnFollowingIDs(id)
+}
+
+func (store mockUserStore) SetPrivate(id int64, private bool) error {
+	return store.OnSetPrivate(id, private)
+}
+
+func (store mockUserStore) IsFollowing(followerID, followeeID int64) (bool, error) {
+	return store.OnIsFollowing(followerID, followeeID)
+}
+
+func (store mockUserStore) RequestFollow(followerID, followeeID int64) error {
+	return store.OnRequestFollow(followerID, followeeID)
+}
+
+func (store mockUserStore) ApproveFollow(followerID, followeeID int64) error {
+	return store.OnApproveFollow(followerID, followeeID)
+}
+
+func (store mockUserStore) DeleteFollowRequest(followerID, followeeID int64) error {
+	return store.OnDeleteFollowRequest(followerID, followeeID)
+}
+
+func (store mockUserStore) FollowRequests(id int64, options data.ListOptions) ([]data.User, error) {
+	return store.OnFollowRequests(id, options)
+}
+
 /* *************** *
  * Mock Post Store *
  * *************** */
@@ -80,7 +121,7 @@ func (store mockUserStore) Following(
 type mockPostStore struct {
 	OnCreate func(post *data.Post) (int64, error)
 	OnGet    func(id int64) (*data.Post, error)
-	OnUpdate func(id int64, contents []byte) error
+	OnUpdate func(id int64, contents []byte, entities []data.PostEntity) error
 	OnDelete func(id int64) error
 
 	OnUserPosts func(
@@ -92,6 +133,28 @@ type mockPostStore struct {
 		userID int64,
 		options data.ListOptions,
 		sort data.PostSortMethod) ([]data.Post, error)
+
+	OnMentions func(
+		userID int64,
+		options data.ListOptions,
+		sort data.PostSortMethod) ([]data.Post, error)
+
+	OnTaggedPosts func(
+		tag string,
+		options data.ListOptions,
+		sort data.PostSortMethod) ([]data.Post, error)
+
+	OnAncestors   func(id, viewerID int64, limit int) ([]data.Post, error)
+	OnDescendants func(id, viewerID int64, depth, maxNodes int, options data.ListOptions) ([]data.Post, error)
+
+	OnRecent   func(options data.ListOptions) ([]data.Post, error)
+	OnTrending func(window data.TrendingWindow, options data.ListOptions) ([]data.Post, error)
+
+	OnReact   func(postID, userID int64, reaction data.Reaction) error
+	OnUnReact func(postID, userID int64, reaction data.Reaction) error
+
+	OnRepost   func(postID, userID int64) error
+	OnUnRepost func(postID, userID int64) error
 }
 
 func (store mockPostStore) Create(post *data.Post) (int64, error) {
@@ -102,8 +165,8 @@ func (store mockPostStore) Get(id int64) (*data.Post, error) {
 	return store.OnGet(id)
 }
 
-func (store mockPostStore) Update(id int64, contents []byte) error {
-	return store.OnUpdate(id, contents)
+func (store mockPostStore) Update(id int64, contents []byte, entities []data.PostEntity) error {
+	return store.OnUpdate(id, contents, entities)
 }
 
 func (store mockPostStore) Delete(id int64) error {
@@ -124,6 +187,169 @@ func (store mockPostStore) Feed(
 	return store.OnFeed(userID, options, sort)
 }
 
+func (store mockPostStore) Mentions(
+	userID int64,
+	options data.ListOptions,
+	sort data.PostSortMethod) ([]data.Post, error) {
+	return store.OnMentions(userID, options, sort)
+}
+
+func (store mockPostStore) TaggedPosts(
+	tag string,
+	options data.ListOptions,
+	sort data.PostSortMethod) ([]data.Post, error) {
+	return store.OnTaggedPosts(tag, options, sort)
+}
+
+func (store mockPostStore) Ancestors(id, viewerID int64, limit int) ([]data.Post, error) {
+	return store.OnAncestors(id, viewerID, limit)
+}
+
+func (store mockPostStore) Descendants(
+	id, viewerID int64,
+	depth, maxNodes int,
+	options data.ListOptions) ([]data.Post, error) {
+	return store.OnDescendants(id, viewerID, depth, maxNodes, options)
+}
+
+func (store mockPostStore) Recent(options data.ListOptions) ([]data.Post, error) {
+	return store.OnRecent(options)
+}
+
+func (store mockPostStore) Trending(window data.TrendingWindow, options data.ListOptions) ([]data.Post, error) {
+	return store.OnTrending(window, options)
+}
+
+func (store mockPostStore) React(postID, userID int64, reaction data.Reaction) error {
+	return store.OnReact(postID, userID, reaction)
+}
+
+func (store mockPostStore) UnReact(postID, userID int64, reaction data.Reaction) error {
+	return store.OnUnReact(postID, userID, reaction)
+}
+
+func (store mockPostStore) Repost(postID, userID int64) error {
+	return store.OnRepost(postID, userID)
+}
+
+func (store mockPostStore) UnRepost(postID, userID int64) error {
+	return store.OnUnRepost(postID, userID)
+}
+
+/* *********************** *
+ * Mock Notification Store *
+ * *********************** */
+
+type mockNotificationStore struct {
+	OnNotify func(
+		userID, actorID int64,
+		notificationType data.NotificationType,
+		postID int64) (int64, error)
+
+	OnGet            func(id int64) (*data.Notification, error)
+	OnList           func(userID int64, options data.ListOptions, unreadOnly bool) ([]data.Notification, error)
+	OnUnreadCount    func(userID int64) (int, error)
+	OnMarkRead       func(userID int64, ids []int64) error
+	OnMarkAllRead    func(userID int64) error
+	OnPreferences    func(userID int64) (*data.NotificationPreferences, error)
+	OnSetPreferences func(userID int64, preferences *data.NotificationPreferences) error
+}
+
+func (store mockNotificationStore) Notify(
+	userID, actorID int64,
+	notificationType data.NotificationType,
+	postID int64) (int64, error) {
+	return store.OnNotify(userID, actorID, notificationType, postID)
+}
+
+func (store mockNotificationStore) Get(id int64) (*data.Notification, error) {
+	return store.OnGet(id)
+}
+
+func (store mockNotificationStore) List(
+	userID int64,
+	options data.ListOptions,
+	unreadOnly bool) ([]data.Notification, error) {
+	return store.OnList(userID, options, unreadOnly)
+}
+
+func (store mockNotificationStore) UnreadCount(userID int64) (int, error) {
+	return store.OnUnreadCount(userID)
+}
+
+func (store mockNotificationStore) MarkRead(userID int64, ids []int64) error {
+	return store.OnMarkRead(userID, ids)
+}
+
+func (store mockNotificationStore) MarkAllRead(userID int64) error {
+	return store.OnMarkAllRead(userID)
+}
+
+func (store mockNotificationStore) Preferences(userID int64) (*data.NotificationPreferences, error) {
+	return store.OnPreferences(userID)
+}
+
+func (store mockNotificationStore) SetPreferences(userID int64, preferences *data.NotificationPreferences) error {
+	return store.OnSetPreferences(userID, preferences)
+}
+
+/* ****************** *
+ * Mock Webhook Store *
+ * ****************** */
+
+type mockWebhookStore struct {
+	OnCreate          func(webhook *data.Webhook) (int64, error)
+	OnGet             func(id int64) (*data.Webhook, error)
+	OnList            func(userID int64) ([]data.Webhook, error)
+	OnDelete          func(id int64) error
+	OnSubscribed      func(event string, userIDs []int64) ([]data.Webhook, error)
+	OnCreateDelivery  func(delivery *data.WebhookDelivery) (int64, error)
+	OnGetDelivery     func(id int64) (*data.WebhookDelivery, error)
+	OnDeliveries      func(webhookID int64, options data.ListOptions) ([]data.WebhookDelivery, error)
+	OnClaimDeliveries func(limit int, lease time.Duration) ([]data.WebhookDelivery, error)
+	OnUpdateDelivery  func(delivery *data.WebhookDelivery) error
+}
+
+func (store mockWebhookStore) Create(webhook *data.Webhook) (int64, error) {
+	return store.OnCreate(webhook)
+}
+
+func (store mockWebhookStore) Get(id int64) (*data.Webhook, error) {
+	return store.OnGet(id)
+}
+
+func (store mockWebhookStore) List(userID int64) ([]data.Webhook, error) {
+	return store.OnList(userID)
+}
+
+func (store mockWebhookStore) Delete(id int64) error {
+	return store.OnDelete(id)
+}
+
+func (store mockWebhookStore) Subscribed(event string, userIDs []int64) ([]data.Webhook, error) {
+	return store.OnSubscribed(event, userIDs)
+}
+
+func (store mockWebhookStore) CreateDelivery(delivery *data.WebhookDelivery) (int64, error) {
+	return store.OnCreateDelivery(delivery)
+}
+
+func (store mockWebhookStore) GetDelivery(id int64) (*data.WebhookDelivery, error) {
+	return store.OnGetDelivery(id)
+}
+
+func (store mockWebhookStore) Deliveries(webhookID int64, options data.ListOptions) ([]data.WebhookDelivery, error) {
+	return store.OnDeliveries(webhookID, options)
+}
+
+func (store mockWebhookStore) ClaimDeliveries(limit int, lease time.Duration) ([]data.WebhookDelivery, error) {
+	return store.OnClaimDeliveries(limit, lease)
+}
+
+func (store mockWebhookStore) UpdateDelivery(delivery *data.WebhookDelivery) error {
+	return store.OnUpdateDelivery(delivery)
+}
+
 /* ************** *
  * Mock Auth Impl *
  * ************** */
@@ -145,3 +371,74 @@ func (auth mockAuth) CheckPassword(password, storedPassword string) bool {
 func (auth mockAuth) GenerateAccessToken(user *data.User, signingKey []byte) (string, error) {
 	return auth.OnGenerateAccessToken(user, signingKey)
 }
+
+/* ***************** *
+ * Mock Search Store *
+ * ***************** */
+
+type mockSearchStore struct {
+	OnSearchPosts       func(query data.PostQuery, options data.ListOptions) ([]data.PostSearchResult, error)
+	OnSearchUsers       func(query string, limit int) ([]data.User, error)
+	OnCompleteUsernames func(prefix string, limit int) ([]data.User, error)
+}
+
+func (store mockSearchStore) SearchPosts(
+	query data.PostQuery,
+	options data.ListOptions) ([]data.PostSearchResult, error) {
+	return store.OnSearchPosts(query, options)
+}
+
+func (store mockSearchStore) SearchUsers(query string, limit int) ([]data.User, error) {
+	return store.OnSearchUsers(query, limit)
+}
+
+func (store mockSearchStore) CompleteUsernames(prefix string, limit int) ([]data.User, error) {
+	return store.OnCompleteUsernames(prefix, limit)
+}
+
+/* **************** *
+ * Mock Block Store *
+ * **************** */
+
+type mockBlockStore struct {
+	OnBlock     func(blockerID, blockedID int64) error
+	OnUnBlock   func(blockerID, blockedID int64) error
+	OnMute      func(muterID, mutedID int64) error
+	OnUnMute    func(muterID, mutedID int64) error
+	OnIsBlocked func(userID, otherID int64) (bool, error)
+	OnBlocked   func(userID int64, options data.ListOptions) ([]data.User, error)
+	OnMuted     func(userID int64, options data.ListOptions) ([]data.User, error)
+	OnMutedIDs  func(userID int64) ([]int64, error)
+}
+
+func (store mockBlockStore) Block(blockerID, blockedID int64) error {
+	return store.OnBlock(blockerID, blockedID)
+}
+
+func (store mockBlockStore) UnBlock(blockerID, blockedID int64) error {
+	return store.OnUnBlock(blockerID, blockedID)
+}
+
+func (store mockBlockStore) Mute(muterID, mutedID int64) error {
+	return store.OnMute(muterID, mutedID)
+}
+
+func (store mockBlockStore) UnMute(muterID, mutedID int64) error {
+	return store.OnUnMute(muterID, mutedID)
+}
+
+func (store mockBlockStore) IsBlocked(userID, otherID int64) (bool, error) {
+	return store.OnIsBlocked(userID, otherID)
+}
+
+func (store mockBlockStore) Blocked(userID int64, options data.ListOptions) ([]data.User, error) {
+	return store.OnBlocked(userID, options)
+}
+
+func (store mockBlockStore) Muted(userID int64, options data.ListOptions) ([]data.User, error) {
+	return store.OnMuted(userID, options)
+}
+
+func (store mockBlockStore) MutedIDs(userID int64) ([]int64, error) {
+	return store.OnMutedIDs(userID)
+}
@@ -5,12 +5,17 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-
 	"regexp"
+	"strconv"
 
 	"github.com/boxtown/meirl/data"
+	"github.com/boxtown/meirl/metrics"
+	"github.com/boxtown/meirl/realtime"
 )
 
+// usernameRegex matches valid usernames
+var usernameRegex = regexp.MustCompile("^[0-9a-zA-Z_]+$")
+
 var errBadUsername = errors.New("Username may only contain [0-9], [a-z], and [A-Z]")
 var errTakenUsername = errors.New("Username is taken")
 var errBadEmail = errors.New("Email must be of the format [example@example]")
@@ -21,14 +26,16 @@ var errTakenEmail = errors.New("Email is taken")
 type UserAPI struct {
 	stores data.Stores
 	auth   Auth
+	events realtime.Publisher
 	debug  bool
 }
 
 // NewUserAPI returns an instance of the UserAPI struct
-func NewUserAPI(stores data.Stores, auth Auth, debug bool) UserAPI {
+func NewUserAPI(stores data.Stores, auth Auth, events realtime.Publisher, debug bool) UserAPI {
 	return UserAPI{
 		stores: stores,
 		auth:   auth,
+		events: events,
 		debug:  debug,
 	}
 }
@@ -49,20 +56,21 @@ func (api UserAPI) CreateUser() http.HandlerFunc {
 				w.WriteHeader(http.StatusBadRequest)
 				fmt.Fprintf(w, err.Error())
 			} else {
-				writeError(err, w, api.debug)
+				writeError(err, w, r, api.debug)
 			}
 			return
 		}
 		u.Password, err = api.auth.SecurePassword(u.Password)
 		if err != nil {
-			writeError(err, w, api.debug)
+			writeError(err, w, r, api.debug)
 			return
 		}
 		id, err := api.stores.UserStore.Create(&u)
 		if err != nil {
-			writeError(err, w, api.debug)
+			writeError(err, w, r, api.debug)
 			return
 		}
+		metrics.Signups.Inc()
 		w.WriteHeader(http.StatusCreated)
 		w.Header().Add("Location", fmt.Sprintf("/%s/user/%d", apiVersion, id))
 		writeJSON(IDResponse{ID: id}, w)
@@ -79,7 +87,7 @@ func (api UserAPI) GetUser() http.HandlerFunc {
 			w.WriteHeader(http.StatusNotFound)
 			return
 		} else if err != nil {
-			writeError(err, w, api.debug)
+			writeError(err, w, r, api.debug)
 			return
 		}
 		user.Password = ""
@@ -101,7 +109,7 @@ func (api UserAPI) GetMe() http.HandlerFunc {
 			w.WriteHeader(http.StatusNotFound)
 			return
 		} else if err != nil {
-			writeError(err, w, api.debug)
+			writeError(err, w, r, api.debug)
 			return
 		}
 		user.Password = ""
@@ -110,30 +118,86 @@ func (api UserAPI) GetMe() http.HandlerFunc {
 }
 
 // GetFeed returns an http handler that handles get user feed
-// API requests
+// API requests. Users may only read their own feed, which holds
+// the posts of the private users they follow
 func (api UserAPI) GetFeed() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		claimed, ok := claimsID(r)
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
 		id := contextID(r)
+		if id != claimed {
+			writeProblem(http.StatusForbidden, "Users may only read their own feed", w, r)
+			return
+		}
 		_, err := api.stores.UserStore.Get(id)
 		if err == data.ErrNoEnt {
 			w.WriteHeader(http.StatusNotFound)
 			return
 		} else if err != nil {
-			writeError(err, w, api.debug)
+			writeError(err, w, r, api.debug)
 			return
 		}
 		options := ListOptionsFromRequest(r)
-		posts, err := api.stores.PostStore.Feed(id, options, data.PostSortByDate)
+		sort, ok := postSortFromRequest(r, &options)
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		posts, err := api.stores.PostStore.Feed(id, options, sort)
+		if err != nil {
+			writeError(err, w, r, api.debug)
+			return
+		}
+		writeJSON(posts, w)
+	}
+}
+
+// GetUserPosts returns an http handler that handles get user posts
+// API requests. The posts of users the viewer may not see are not found
+func (api UserAPI) GetUserPosts() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := contextID(r)
+		user, err := api.stores.UserStore.Get(id)
+		if err == data.ErrNoEnt {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		} else if err != nil {
+			writeError(err, w, r, api.debug)
+			return
+		}
+		visible, err := canView(api.stores, viewerID(r), user)
 		if err != nil {
-			writeError(err, w, api.debug)
+			writeError(err, w, r, api.debug)
 			return
 		}
+		if !visible {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		options, sort, ok := newestListFromRequest(r)
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		posts, err := api.stores.PostStore.UserPosts(id, options, sort)
+		if err != nil {
+			writeError(err, w, r, api.debug)
+			return
+		}
+		if posts == nil {
+			posts = []data.Post{}
+		}
 		writeJSON(posts, w)
 	}
 }
 
 // FollowUser returns an http handler that handles follower user
-// API requests
+// API requests. Following a private user the authenticated user does
+// not already follow requests to follow them instead, pending their
+// approval
 func (api UserAPI) FollowUser() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		id, ok := claimsID(r)
@@ -142,13 +206,15 @@ func (api UserAPI) FollowUser() http.HandlerFunc {
 			return
 		}
 		followeeID := contextID(r)
+		var followee *data.User
 		errc := make(chan error, 2)
 		go func() {
 			_, err := api.stores.UserStore.Get(id)
 			errc <- err
 		}()
 		go func() {
-			_, err := api.stores.UserStore.Get(followeeID)
+			var err error
+			followee, err = api.stores.UserStore.Get(followeeID)
 			errc <- err
 		}()
 		for i := 0; i < 2; i++ {
@@ -156,22 +222,244 @@ func (api UserAPI) FollowUser() http.HandlerFunc {
 			if err == data.ErrNoEnt {
 				w.WriteHeader(http.StatusBadRequest)
 				return
+			} else if err != nil {
+				writeError(err, w, r, api.debug)
+				return
+			}
+		}
+		blocked, err := api.stores.IsBlocked(id, followeeID)
+		if err != nil {
+			writeError(err, w, r, api.debug)
+			return
+		}
+		if blocked {
+			writeProblem(http.StatusForbidden, "Blocked users may not follow each other", w, r)
+			return
+		}
+		if followee.Private {
+			following, err := api.stores.IsFollowing(id, followeeID)
+			if err != nil {
+				writeError(err, w, r, api.debug)
+				return
+			}
+			if !following {
+				err = api.stores.RequestFollow(id, followeeID)
+				if err != nil {
+					writeError(err, w, r, api.debug)
+					return
+				}
+				w.WriteHeader(http.StatusAccepted)
+				writeJSON(FollowResponse{Pending: true}, w)
+				return
 			}
 		}
-		err := api.stores.Follow(id, followeeID)
+		err = api.stores.Follow(id, followeeID)
 		if err != nil {
-			writeError(err, w, api.debug)
+			writeError(err, w, r, api.debug)
 			return
 		}
+		metrics.Follows.Inc()
+		notify(api.stores, api.events, followeeID, id, data.NotificationFollow, 0, r)
 		w.WriteHeader(http.StatusAccepted)
+		writeJSON(FollowResponse{}, w)
 	}
 }
 
 // UnFollowUser returns an http handler that handles unfollowing user
-// API requests
+// API requests, which also withdraw pending requests to follow
 func (api UserAPI) UnFollowUser() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		// TODO
+		id, ok := claimsID(r)
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		followeeID := contextID(r)
+		err := api.stores.UnFollow(id, followeeID)
+		if err != nil {
+			writeError(err, w, r, api.debug)
+			return
+		}
+		err = api.stores.DeleteFollowRequest(id, followeeID)
+		if err != nil {
+			writeError(err, w, r, api.debug)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
+
+// SetPrivacy returns an http handler that handles API requests making
+// the authenticated user private or public. Making a user public
+// approves their pending follow requests
+func (api UserAPI) SetPrivacy() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := claimsID(r)
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var request PrivacyRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		err := api.stores.SetPrivate(id, request.Private)
+		if err == data.ErrNoEnt {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		} else if err != nil {
+			writeError(err, w, r, api.debug)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
+
+// ApproveFollowRequest returns an http handler that handles API
+// requests approving the request of a user to follow the
+// authenticated user
+func (api UserAPI) ApproveFollowRequest() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := claimsID(r)
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		err := api.stores.ApproveFollow(contextID(r), id)
+		if err == data.ErrNoEnt {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		} else if err != nil {
+			writeError(err, w, r, api.debug)
+			return
+		}
+		metrics.Follows.Inc()
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
+
+// RejectFollowRequest returns an http handler that handles API
+// requests rejecting the request of a user to follow the
+// authenticated user
+func (api UserAPI) RejectFollowRequest() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := claimsID(r)
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		err := api.stores.DeleteFollowRequest(contextID(r), id)
+		if err != nil {
+			writeError(err, w, r, api.debug)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
+
+// BlockUser returns an http handler that handles API requests blocking
+// a user as the authenticated user, which removes the follows between
+// them and hides their posts from each other
+func (api UserAPI) BlockUser() http.HandlerFunc {
+	return api.updateRelationship(data.BlockStore.Block)
+}
+
+// UnBlockUser returns an http handler that handles API requests
+// removing a block by the authenticated user
+func (api UserAPI) UnBlockUser() http.HandlerFunc {
+	return api.updateRelationship(data.BlockStore.UnBlock)
+}
+
+// MuteUser returns an http handler that handles API requests muting a
+// user as the authenticated user, which hides their posts and reposts
+// from the feed of the authenticated user
+func (api UserAPI) MuteUser() http.HandlerFunc {
+	return api.updateRelationship(data.BlockStore.Mute)
+}
+
+// UnMuteUser returns an http handler that handles API requests
+// removing a mute by the authenticated user
+func (api UserAPI) UnMuteUser() http.HandlerFunc {
+	return api.updateRelationship(data.BlockStore.UnMute)
+}
+
+func (api UserAPI) updateRelationship(update func(store data.BlockStore, userID, otherID int64) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := claimsID(r)
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		otherID := contextID(r)
+		if otherID == id {
+			writeProblem(http.StatusBadRequest, "Users may not block or mute themselves", w, r)
+			return
+		}
+		_, err := api.stores.UserStore.Get(otherID)
+		if err == data.ErrNoEnt {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		} else if err != nil {
+			writeError(err, w, r, api.debug)
+			return
+		}
+		err = update(api.stores.BlockStore, id, otherID)
+		if err != nil {
+			writeError(err, w, r, api.debug)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
+
+// ListBlocked returns an http handler that handles API requests
+// listing the users blocked by the authenticated user
+func (api UserAPI) ListBlocked() http.HandlerFunc {
+	return api.listRelationships(data.Stores.Blocked)
+}
+
+// ListMuted returns an http handler that handles API requests
+// listing the users muted by the authenticated user
+func (api UserAPI) ListMuted() http.HandlerFunc {
+	return api.listRelationships(data.Stores.Muted)
+}
+
+// ListFollowRequests returns an http handler that handles API requests
+// listing the users pending approval to follow the authenticated user
+func (api UserAPI) ListFollowRequests() http.HandlerFunc {
+	return api.listRelationships(data.Stores.FollowRequests)
+}
+
+func (api UserAPI) listRelationships(
+	list func(stores data.Stores, userID int64, options data.ListOptions) ([]data.User, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := claimsID(r)
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		options := ListOptionsFromRequest(r)
+		if marker := options.Marker.(string); marker != "" {
+			markerID, err := strconv.ParseInt(marker, 10, 64)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			options.Marker = markerID
+		}
+		users, err := list(api.stores, id, options)
+		if err != nil {
+			writeError(err, w, r, api.debug)
+			return
+		}
+		if users == nil {
+			users = []data.User{}
+		}
+		for i := range users {
+			users[i].Email, users[i].Password = "", ""
+		}
+		writeJSON(users, w)
 	}
 }
 
@@ -182,7 +470,7 @@ func (api UserAPI) DeleteUser() http.HandlerFunc {
 		id := contextID(r)
 		err := api.stores.UserStore.Delete(id)
 		if err != nil {
-			writeError(err, w, api.debug)
+			writeError(err, w, r, api.debug)
 			return
 		}
 		w.WriteHeader(http.StatusAccepted)
@@ -196,7 +484,7 @@ func (api UserAPI) Login(signingKey []byte) http.HandlerFunc {
 		var u data.User
 		err := json.NewDecoder(r.Body).Decode(&u)
 		if err != nil {
-			writeError(err, w, api.debug)
+			writeError(err, w, r, api.debug)
 			return
 		}
 		stored, err := api.getStoredUser(&u)
@@ -205,7 +493,7 @@ func (api UserAPI) Login(signingKey []byte) http.HandlerFunc {
 				w.WriteHeader(http.StatusBadRequest)
 				return
 			}
-			writeError(err, w, api.debug)
+			writeError(err, w, r, api.debug)
 			return
 		}
 		valid := api.auth.CheckPassword(u.Password, stored.Password)
@@ -213,11 +501,16 @@ func (api UserAPI) Login(signingKey []byte) http.HandlerFunc {
 			w.WriteHeader(http.StatusBadRequest)
 			return
 		}
+		if stored.Disabled {
+			writeProblem(http.StatusForbidden, "User is disabled", w, r)
+			return
+		}
 		accessToken, err := api.auth.GenerateAccessToken(stored, signingKey)
 		if err != nil {
-			writeError(err, w, api.debug)
+			writeError(err, w, r, api.debug)
 			return
 		}
+		metrics.Logins.Inc()
 		writeJSON(TokenResponse{AccessToken: accessToken}, w)
 	}
 }
@@ -230,7 +523,6 @@ func (api UserAPI) getStoredUser(user *data.User) (*data.User, error) {
 }
 
 func (api UserAPI) isCreateRequestValid(user *data.User) error {
-	usernameRegex := regexp.MustCompile("^[0-9a-zA-Z_]+$")
 	emailRegex := regexp.MustCompile("^.+@.+$")
 	if !usernameRegex.MatchString(user.Username) {
 		return errBadUsername
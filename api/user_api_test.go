@@ -10,10 +10,12 @@ import (
 	"net/http/httptest"
 
 	"strings"
+	"time"
 
 	"github.com/boxtown/meirl/api/apitest"
 	"github.com/boxtown/meirl/data"
 	"github.com/boxtown/meirl/data/datatest"
+	"github.com/boxtown/meirl/realtime"
 	jwt "github.com/dgrijalva/jwt-go"
 )
 
@@ -37,6 +39,7 @@ func TestCreateUser(t *testing.T) {
 				return password, nil
 			},
 		},
+		realtime.Discard,
 		false,
 	)
 
@@ -71,7 +74,7 @@ func TestCreateUser(t *testing.T) {
 }
 
 func TestBadCreateUserJSON(t *testing.T) {
-	api := NewUserAPI(data.Stores{}, nil, false)
+	api := NewUserAPI(data.Stores{}, nil, realtime.Discard, false)
 	r, _ := http.NewRequest("", "", bytes.NewBufferString("{"))
 	w := httptest.NewRecorder()
 	api.CreateUser()(w, r)
@@ -83,7 +86,7 @@ func TestBadCreateUserJSON(t *testing.T) {
 }
 
 func TestBadCreateUserUsername(t *testing.T) {
-	api := NewUserAPI(data.Stores{}, nil, false)
+	api := NewUserAPI(data.Stores{}, nil, realtime.Discard, false)
 	user := datatest.ExampleUser()
 	user.Username = ""
 	json, _ := userToJSON(user)
@@ -109,7 +112,7 @@ func TestBadCreateUserUsername(t *testing.T) {
 }
 
 func TestBadCreateUserEmail(t *testing.T) {
-	api := NewUserAPI(data.Stores{}, nil, false)
+	api := NewUserAPI(data.Stores{}, nil, realtime.Discard, false)
 	user := datatest.ExampleUser()
 	user.Email = ""
 	json, _ := userToJSON(user)
@@ -147,6 +150,7 @@ func TestTakenCreateUserUsernameOrEmail(t *testing.T) {
 			},
 		},
 		nil,
+		realtime.Discard,
 		false,
 	)
 	json, _ := userToJSON(datatest.ExampleUser())
@@ -171,6 +175,7 @@ func TestGetUser(t *testing.T) {
 			},
 		},
 		nil,
+		realtime.Discard,
 		false,
 	)
 
@@ -205,6 +210,7 @@ func TestGetMe(t *testing.T) {
 			},
 		},
 		nil,
+		realtime.Discard,
 		false,
 	)
 	r, _ := http.NewRequest("", "", nil)
@@ -250,10 +256,14 @@ func TestGetFeed(t *testing.T) {
 			},
 		},
 		nil,
+		realtime.Discard,
 		false,
 	)
 	r, _ := http.NewRequest("", "", nil)
 	r = apitest.RequestWithContextID(r, idContextKey, int64(1))
+	r = apitest.RequestWithClaims(r, claimsContextKey, jwt.MapClaims{
+		"sub": int64(1),
+	})
 	w := httptest.NewRecorder()
 	api.GetFeed()(w, r)
 
@@ -272,19 +282,119 @@ func TestGetFeed(t *testing.T) {
 	}
 }
 
-func TestFollowerUser(t *testing.T) {
+func TestGetFeedOfOtherUser(t *testing.T) {
+	api := NewUserAPI(
+		data.Stores{
+			PostStore: mockPostStore{
+				OnFeed: func(
+					userID int64,
+					options data.ListOptions,
+					sort data.PostSortMethod) ([]data.Post, error) {
+					t.Error("Expected the feed of another user not to be listed")
+					t.Fail()
+					return nil, nil
+				},
+			},
+		},
+		nil,
+		realtime.Discard,
+		false,
+	)
+	r, _ := http.NewRequest("", "", nil)
+	r = apitest.RequestWithContextID(r, idContextKey, int64(1))
+	r = apitest.RequestWithClaims(r, claimsContextKey, jwt.MapClaims{
+		"sub": int64(2),
+	})
+	w := httptest.NewRecorder()
+	api.GetFeed()(w, r)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Expected status code %d, received %d", http.StatusForbidden, w.Code)
+		t.Fail()
+	}
+}
+
+func TestGetTopFeed(t *testing.T) {
+	marker := data.ScoreMarker{AsOf: time.Unix(1500000000, 0), Score: 0.25, ID: 3}
+	var sort data.PostSortMethod
+	var options data.ListOptions
 	api := NewUserAPI(
 		data.Stores{
 			UserStore: mockUserStore{
 				OnGet: func(id int64) (*data.User, error) {
 					return nil, nil
 				},
+			},
+			PostStore: mockPostStore{
+				OnFeed: func(
+					userID int64,
+					o data.ListOptions,
+					s data.PostSortMethod) ([]data.Post, error) {
+					options, sort = o, s
+					return []data.Post{*datatest.ExamplePost(1)}, nil
+				},
+			},
+		},
+		nil,
+		realtime.Discard,
+		false,
+	)
+	r, _ := http.NewRequest("", "?sort=top&marker="+marker.String(), nil)
+	r = apitest.RequestWithContextID(r, idContextKey, int64(1))
+	r = apitest.RequestWithClaims(r, claimsContextKey, jwt.MapClaims{
+		"sub": int64(1),
+	})
+	w := httptest.NewRecorder()
+	api.GetFeed()(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status code %d, received %d", http.StatusOK, w.Code)
+		t.FailNow()
+	}
+	if sort != data.PostSortByScore || !options.Desc {
+		t.Errorf("Expected a descending score sort, got %d desc %t", sort, options.Desc)
+		t.Fail()
+	}
+	m, ok := options.Marker.(data.ScoreMarker)
+	if !ok || !m.AsOf.Equal(marker.AsOf) || m.Score != marker.Score || m.ID != marker.ID {
+		t.Errorf("Expected marker %v, got %v", marker, options.Marker)
+		t.Fail()
+	}
+
+	for _, query := range []string{"?sort=top&marker=12345", "?sort=best"} {
+		r, _ = http.NewRequest("", query, nil)
+		r = apitest.RequestWithContextID(r, idContextKey, int64(1))
+		r = apitest.RequestWithClaims(r, claimsContextKey, jwt.MapClaims{
+			"sub": int64(1),
+		})
+		w = httptest.NewRecorder()
+		api.GetFeed()(w, r)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status code %d for %s, received %d", http.StatusBadRequest, query, w.Code)
+			t.Fail()
+		}
+	}
+}
+
+func TestFollowerUser(t *testing.T) {
+	api := NewUserAPI(
+		data.Stores{
+			UserStore: mockUserStore{
+				OnGet: func(id int64) (*data.User, error) {
+					return datatest.ExampleUser(), nil
+				},
 				OnFollow: func(followerID, followeeID int64) error {
 					return nil
 				},
 			},
+			BlockStore: mockBlockStore{
+				OnIsBlocked: func(userID, otherID int64) (bool, error) {
+					return false, nil
+				},
+			},
 		},
 		nil,
+		realtime.Discard,
 		false,
 	)
 
@@ -302,6 +412,393 @@ func TestFollowerUser(t *testing.T) {
 	}
 }
 
+func TestFollowUserNotifiesFollowee(t *testing.T) {
+	var notified bool
+	api := NewUserAPI(
+		data.Stores{
+			UserStore: mockUserStore{
+				OnGet: func(id int64) (*data.User, error) {
+					return datatest.ExampleUser(), nil
+				},
+				OnFollow: func(followerID, followeeID int64) error {
+					return nil
+				},
+			},
+			BlockStore: mockBlockStore{
+				OnIsBlocked: func(userID, otherID int64) (bool, error) {
+					return false, nil
+				},
+			},
+			NotificationStore: mockNotificationStore{
+				OnNotify: func(
+					userID, actorID int64,
+					notificationType data.NotificationType,
+					postID int64) (int64, error) {
+					notified = userID == 2 && actorID == 1 &&
+						notificationType == data.NotificationFollow && postID == 0
+					return 0, nil
+				},
+			},
+		},
+		nil,
+		realtime.Discard,
+		false,
+	)
+
+	r, _ := http.NewRequest("", "", nil)
+	r = apitest.RequestWithContextID(r, idContextKey, int64(2))
+	r = apitest.RequestWithClaims(r, claimsContextKey, jwt.MapClaims{
+		"sub": int64(1),
+	})
+	w := httptest.NewRecorder()
+	api.FollowUser()(w, r)
+
+	if w.Code != http.StatusAccepted {
+		t.Errorf("Expected status code %d, received %d", http.StatusAccepted, w.Code)
+		t.Fail()
+	}
+	if !notified {
+		t.Error("Expected the followee to be notified")
+		t.Fail()
+	}
+}
+
+func TestFollowBlockedUser(t *testing.T) {
+	api := NewUserAPI(
+		data.Stores{
+			UserStore: mockUserStore{
+				OnGet: func(id int64) (*data.User, error) {
+					return datatest.ExampleUser(), nil
+				},
+				OnFollow: func(followerID, followeeID int64) error {
+					t.Error("Expected blocked users not to be followed")
+					t.Fail()
+					return nil
+				},
+			},
+			BlockStore: mockBlockStore{
+				OnIsBlocked: func(userID, otherID int64) (bool, error) {
+					return userID == 1 && otherID == 2, nil
+				},
+			},
+		},
+		nil,
+		realtime.Discard,
+		false,
+	)
+
+	r, _ := http.NewRequest("", "", nil)
+	r = apitest.RequestWithContextID(r, idContextKey, int64(2))
+	r = apitest.RequestWithClaims(r, claimsContextKey, jwt.MapClaims{
+		"sub": int64(1),
+	})
+	w := httptest.NewRecorder()
+	api.FollowUser()(w, r)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Expected status code %d, received %d", http.StatusForbidden, w.Code)
+		t.Fail()
+	}
+}
+
+func TestBlockUser(t *testing.T) {
+	var blocked, unmuted bool
+	api := NewUserAPI(
+		data.Stores{
+			UserStore: mockUserStore{
+				OnGet: func(id int64) (*data.User, error) {
+					return datatest.ExampleUser(), nil
+				},
+			},
+			BlockStore: mockBlockStore{
+				OnBlock: func(blockerID, blockedID int64) error {
+					blocked = blockerID == 1 && blockedID == 2
+					return nil
+				},
+				OnUnMute: func(muterID, mutedID int64) error {
+					unmuted = muterID == 1 && mutedID == 2
+					return nil
+				},
+			},
+		},
+		nil,
+		realtime.Discard,
+		false,
+	)
+
+	tests := []struct {
+		handler  http.HandlerFunc
+		otherID  int64
+		expected int
+	}{
+		{api.BlockUser(), 2, http.StatusAccepted},
+		{api.UnMuteUser(), 2, http.StatusAccepted},
+		{api.BlockUser(), 1, http.StatusBadRequest},
+	}
+	for _, test := range tests {
+		r, _ := http.NewRequest("", "", nil)
+		r = apitest.RequestWithContextID(r, idContextKey, test.otherID)
+		r = apitest.RequestWithClaims(r, claimsContextKey, jwt.MapClaims{
+			"sub": int64(1),
+		})
+		w := httptest.NewRecorder()
+		test.handler(w, r)
+
+		if w.Code != test.expected {
+			t.Errorf("Expected status code %d, received %d", test.expected, w.Code)
+			t.Fail()
+		}
+	}
+	if !blocked || !unmuted {
+		t.Error("Expected the user to be blocked and unmuted")
+		t.Fail()
+	}
+}
+
+func TestGetUserPostsOfBlockedUser(t *testing.T) {
+	api := NewUserAPI(
+		data.Stores{
+			UserStore: mockUserStore{
+				OnGet: func(id int64) (*data.User, error) {
+					return datatest.ExampleUser(), nil
+				},
+			},
+			PostStore: mockPostStore{
+				OnUserPosts: func(
+					userID int64,
+					options data.ListOptions,
+					sort data.PostSortMethod) ([]data.Post, error) {
+					return []data.Post{*datatest.ExamplePost(userID)}, nil
+				},
+			},
+			BlockStore: mockBlockStore{
+				OnIsBlocked: func(userID, otherID int64) (bool, error) {
+					return userID == 3, nil
+				},
+			},
+		},
+		nil,
+		realtime.Discard,
+		false,
+	)
+
+	// anonymous viewers and viewers not blocked see the posts
+	for viewer, expected := range map[int64]int{0: http.StatusOK, 1: http.StatusOK, 3: http.StatusNotFound} {
+		r, _ := http.NewRequest("GET", "", nil)
+		r = apitest.RequestWithContextID(r, idContextKey, int64(2))
+		if viewer != 0 {
+			r = apitest.RequestWithClaims(r, claimsContextKey, jwt.MapClaims{
+				"sub": viewer,
+			})
+		}
+		w := httptest.NewRecorder()
+		api.GetUserPosts()(w, r)
+
+		if w.Code != expected {
+			t.Errorf("Expected status code %d for %d, received %d", expected, viewer, w.Code)
+			t.Fail()
+		}
+	}
+}
+
+func TestFollowPrivateUser(t *testing.T) {
+	var requested bool
+	api := NewUserAPI(
+		data.Stores{
+			UserStore: mockUserStore{
+				OnGet: func(id int64) (*data.User, error) {
+					user := datatest.ExampleUser()
+					user.Private = id == 2
+					return user, nil
+				},
+				OnIsFollowing: func(followerID, followeeID int64) (bool, error) {
+					return false, nil
+				},
+				OnRequestFollow: func(followerID, followeeID int64) error {
+					requested = followerID == 1 && followeeID == 2
+					return nil
+				},
+				OnFollow: func(followerID, followeeID int64) error {
+					t.Error("Expected private users not to be followed before approving")
+					t.Fail()
+					return nil
+				},
+			},
+			BlockStore: mockBlockStore{
+				OnIsBlocked: func(userID, otherID int64) (bool, error) {
+					return false, nil
+				},
+			},
+		},
+		nil,
+		realtime.Discard,
+		false,
+	)
+
+	r, _ := http.NewRequest("", "", nil)
+	r = apitest.RequestWithContextID(r, idContextKey, int64(2))
+	r = apitest.RequestWithClaims(r, claimsContextKey, jwt.MapClaims{
+		"sub": int64(1),
+	})
+	w := httptest.NewRecorder()
+	api.FollowUser()(w, r)
+
+	if w.Code != http.StatusAccepted {
+		t.Errorf("Expected status code %d, received %d", http.StatusAccepted, w.Code)
+		t.FailNow()
+	}
+	resp, err := followResponseFromJSON(w.Body)
+	if err != nil {
+		t.Error(err.Error())
+		t.FailNow()
+	}
+	if !resp.Pending || !requested {
+		t.Error("Expected a pending request to follow the user")
+		t.Fail()
+	}
+}
+
+func TestFollowRequests(t *testing.T) {
+	var approved, rejected bool
+	api := NewUserAPI(
+		data.Stores{
+			UserStore: mockUserStore{
+				OnApproveFollow: func(followerID, followeeID int64) error {
+					if followerID != 2 {
+						return data.ErrNoEnt
+					}
+					approved = followeeID == 1
+					return nil
+				},
+				OnDeleteFollowRequest: func(followerID, followeeID int64) error {
+					rejected = followerID == 3 && followeeID == 1
+					return nil
+				},
+			},
+		},
+		nil,
+		realtime.Discard,
+		false,
+	)
+
+	tests := []struct {
+		handler     http.HandlerFunc
+		requesterID int64
+		expected    int
+	}{
+		{api.ApproveFollowRequest(), 2, http.StatusAccepted},
+		{api.ApproveFollowRequest(), 3, http.StatusNotFound},
+		{api.RejectFollowRequest(), 3, http.StatusAccepted},
+	}
+	for _, test := range tests {
+		r, _ := http.NewRequest("", "", nil)
+		r = apitest.RequestWithContextID(r, idContextKey, test.requesterID)
+		r = apitest.RequestWithClaims(r, claimsContextKey, jwt.MapClaims{
+			"sub": int64(1),
+		})
+		w := httptest.NewRecorder()
+		test.handler(w, r)
+
+		if w.Code != test.expected {
+			t.Errorf("Expected status code %d, received %d", test.expected, w.Code)
+			t.Fail()
+		}
+	}
+	if !approved || !rejected {
+		t.Error("Expected one request to be approved and another rejected")
+		t.Fail()
+	}
+}
+
+func TestGetFeedMarker(t *testing.T) {
+	var marker interface{}
+	api := NewUserAPI(
+		data.Stores{
+			UserStore: mockUserStore{
+				OnGet: func(id int64) (*data.User, error) {
+					return nil, nil
+				},
+			},
+			PostStore: mockPostStore{
+				OnFeed: func(
+					userID int64,
+					options data.ListOptions,
+					sort data.PostSortMethod) ([]data.Post, error) {
+					marker = options.Marker
+					return nil, nil
+				},
+			},
+		},
+		nil,
+		realtime.Discard,
+		false,
+	)
+	r, _ := http.NewRequest("GET", "/?marker=1500000000", nil)
+	r = apitest.RequestWithContextID(r, idContextKey, int64(1))
+	r = apitest.RequestWithClaims(r, claimsContextKey, jwt.MapClaims{
+		"sub": int64(1),
+	})
+	w := httptest.NewRecorder()
+	api.GetFeed()(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status code %d, received %d", http.StatusOK, w.Code)
+		t.Fail()
+	}
+	if ts, ok := marker.(time.Time); !ok || ts.Unix() != 1500000000 {
+		t.Errorf("Expected marker to be converted to a time, got %v", marker)
+		t.Fail()
+	}
+
+	r, _ = http.NewRequest("GET", "/?marker=yesterday", nil)
+	r = apitest.RequestWithContextID(r, idContextKey, int64(1))
+	r = apitest.RequestWithClaims(r, claimsContextKey, jwt.MapClaims{
+		"sub": int64(1),
+	})
+	w = httptest.NewRecorder()
+	api.GetFeed()(w, r)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d, received %d", http.StatusBadRequest, w.Code)
+		t.Fail()
+	}
+}
+
+func TestUnFollowUser(t *testing.T) {
+	api := NewUserAPI(
+		data.Stores{
+			UserStore: mockUserStore{
+				OnUnFollow: func(followerID, followeeID int64) error {
+					if followerID != 1 || followeeID != 2 {
+						t.Errorf("Expected 1 to unfollow 2, got %d and %d", followerID, followeeID)
+						t.Fail()
+					}
+					return nil
+				},
+				OnDeleteFollowRequest: func(followerID, followeeID int64) error {
+					return nil
+				},
+			},
+		},
+		nil,
+		realtime.Discard,
+		false,
+	)
+
+	r, _ := http.NewRequest("", "", nil)
+	r = apitest.RequestWithContextID(r, idContextKey, int64(2))
+	r = apitest.RequestWithClaims(r, claimsContextKey, jwt.MapClaims{
+		"sub": int64(1),
+	})
+	w := httptest.NewRecorder()
+	api.UnFollowUser()(w, r)
+
+	if w.Code != http.StatusAccepted {
+		t.Errorf("Expected status code %d, received %d", http.StatusAccepted, w.Code)
+		t.Fail()
+	}
+}
+
 func TestDeleteUser(t *testing.T) {
 	api := NewUserAPI(
 		data.Stores{
@@ -312,6 +809,7 @@ func TestDeleteUser(t *testing.T) {
 			},
 		},
 		nil,
+		realtime.Discard,
 		false,
 	)
 
@@ -343,6 +841,7 @@ func TestLoginUser(t *testing.T) {
 				return "test-token", nil
 			},
 		},
+		realtime.Discard,
 		false,
 	)
 
@@ -365,3 +864,34 @@ func TestLoginUser(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestLoginDisabledUser(t *testing.T) {
+	api := NewUserAPI(
+		data.Stores{
+			UserStore: mockUserStore{
+				OnGetByUsername: func(username string) (*data.User, error) {
+					user := datatest.ExampleUser()
+					user.Disabled = true
+					return user, nil
+				},
+			},
+		},
+		mockAuth{
+			OnCheckPassword: func(password, storedPassword string) bool {
+				return password == storedPassword
+			},
+		},
+		realtime.Discard,
+		false,
+	)
+
+	json, _ := userToJSON(datatest.ExampleUser())
+	r, _ := http.NewRequest("", "", json)
+	w := httptest.NewRecorder()
+	api.Login([]byte("test"))(w, r)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Expected status code %d, received %d", http.StatusForbidden, w.Code)
+		t.Fail()
+	}
+}
@@ -0,0 +1,195 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/boxtown/meirl/data"
+	"github.com/boxtown/meirl/webhook"
+)
+
+// WebhookAPI contains state information for executing
+// MeIRL Webhook API route handlers
+type WebhookAPI struct {
+	stores data.Stores
+	debug  bool
+}
+
+// NewWebhookAPI returns an instance of the WebhookAPI struct
+func NewWebhookAPI(stores data.Stores, debug bool) WebhookAPI {
+	return WebhookAPI{
+		stores: stores,
+		debug:  debug,
+	}
+}
+
+// CreateWebhook returns an http handler that handles API requests
+// registering a webhook for the authenticated user. The secret used
+// to sign deliveries is generated and only returned in the response
+func (api WebhookAPI) CreateWebhook() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := claimsID(r)
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var hook data.Webhook
+		if err := json.NewDecoder(r.Body).Decode(&hook); err != nil {
+			writeProblem(http.StatusBadRequest, "Malformed request body", w, r)
+			return
+		}
+		if err := webhook.ValidateURL(hook.URL); err != nil {
+			writeProblem(http.StatusBadRequest, "Webhook URL must be an absolute http or https URL", w, r)
+			return
+		}
+		if len(hook.Events) == 0 {
+			writeProblem(http.StatusBadRequest, "Webhook must subscribe to at least one event", w, r)
+			return
+		}
+		for _, event := range hook.Events {
+			if !webhook.IsEvent(event) {
+				writeProblem(http.StatusBadRequest, fmt.Sprintf("Unknown webhook event %q", event), w, r)
+				return
+			}
+		}
+		secret, err := webhook.NewSecret()
+		if err != nil {
+			writeError(err, w, r, api.debug)
+			return
+		}
+		hook.UserID, hook.Secret = &userID, secret
+
+		id, err := api.stores.WebhookStore.Create(&hook)
+		if err != nil {
+			writeError(err, w, r, api.debug)
+			return
+		}
+		hook.ID = id
+		w.Header().Add("Location", fmt.Sprintf("/%s/webhook/%d", apiVersion, id))
+		w.WriteHeader(http.StatusCreated)
+		writeJSON(&hook, w)
+	}
+}
+
+// ListWebhooks returns an http handler that handles API requests
+// listing the webhooks of the authenticated user. Secrets are omitted
+func (api WebhookAPI) ListWebhooks() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := claimsID(r)
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		webhooks, err := api.stores.WebhookStore.List(userID)
+		if err != nil {
+			writeError(err, w, r, api.debug)
+			return
+		}
+		if webhooks == nil {
+			webhooks = []data.Webhook{}
+		}
+		for i := range webhooks {
+			webhooks[i].Secret = ""
+		}
+		writeJSON(webhooks, w)
+	}
+}
+
+// DeleteWebhook returns an http handler that handles API requests
+// deleting a webhook of the authenticated user
+func (api WebhookAPI) DeleteWebhook() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !api.ownsWebhook(w, r, contextID(r)) {
+			return
+		}
+		if err := api.stores.WebhookStore.Delete(contextID(r)); err != nil {
+			writeError(err, w, r, api.debug)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
+
+// ListDeliveries returns an http handler that handles API requests
+// listing the delivery log of a webhook of the authenticated user.
+// Deliveries are listed most recent first unless desc is false
+func (api WebhookAPI) ListDeliveries() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !api.ownsWebhook(w, r, contextID(r)) {
+			return
+		}
+		options := ListOptionsFromRequest(r)
+		if r.URL.Query().Get("desc") == "" {
+			options.Desc = true
+		}
+		if marker := options.Marker.(string); marker != "" {
+			id, err := strconv.ParseInt(marker, 10, 64)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			options.Marker = id
+		}
+		deliveries, err := api.stores.WebhookStore.Deliveries(contextID(r), options)
+		if err != nil {
+			writeError(err, w, r, api.debug)
+			return
+		}
+		if deliveries == nil {
+			deliveries = []data.WebhookDelivery{}
+		}
+		writeJSON(deliveries, w)
+	}
+}
+
+// Redeliver returns an http handler that handles API requests queueing
+// a new delivery of the payload of a previous delivery, regardless of
+// whether the previous delivery succeeded
+func (api WebhookAPI) Redeliver() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		delivery, err := api.stores.WebhookStore.GetDelivery(contextID(r))
+		if err == data.ErrNoEnt {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		} else if err != nil {
+			writeError(err, w, r, api.debug)
+			return
+		}
+		if !api.ownsWebhook(w, r, delivery.WebhookID) {
+			return
+		}
+		id, err := api.stores.WebhookStore.CreateDelivery(&data.WebhookDelivery{
+			WebhookID: delivery.WebhookID,
+			Event:     delivery.Event,
+			Payload:   delivery.Payload,
+		})
+		if err != nil {
+			writeError(err, w, r, api.debug)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+		writeJSON(IDResponse{ID: id}, w)
+	}
+}
+
+// Return true if the webhook with the given id belongs to the
+// authenticated user, otherwise write a 404 so that the webhooks
+// of other users are not revealed
+func (api WebhookAPI) ownsWebhook(w http.ResponseWriter, r *http.Request, id int64) bool {
+	userID, ok := claimsID(r)
+	if !ok {
+		w.WriteHeader(http.StatusBadRequest)
+		return false
+	}
+	hook, err := api.stores.WebhookStore.Get(id)
+	if err == data.ErrNoEnt || (err == nil && (hook.UserID == nil || *hook.UserID != userID)) {
+		w.WriteHeader(http.StatusNotFound)
+		return false
+	} else if err != nil {
+		writeError(err, w, r, api.debug)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,236 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/boxtown/meirl/api/apitest"
+	"github.com/boxtown/meirl/data"
+	"github.com/boxtown/meirl/webhook"
+	jwt "github.com/dgrijalva/jwt-go"
+)
+
+func TestCreateWebhook(t *testing.T) {
+	var created *data.Webhook
+	api := NewWebhookAPI(
+		data.Stores{
+			WebhookStore: mockWebhookStore{
+				OnCreate: func(hook *data.Webhook) (int64, error) {
+					created = hook
+					return 4, nil
+				},
+			},
+		},
+		false,
+	)
+
+	body := `{"url":"https://example.com/hook","events":["post.created"],"userID":2,"secret":"mine"}`
+	r, _ := http.NewRequest("POST", "/", strings.NewReader(body))
+	r = apitest.RequestWithClaims(r, claimsContextKey, jwt.MapClaims{
+		"sub": int64(1),
+	})
+	w := httptest.NewRecorder()
+	api.CreateWebhook()(w, r)
+
+	if w.Code != http.StatusCreated {
+		t.Errorf("Expected status code %d, received %d", http.StatusCreated, w.Code)
+		t.FailNow()
+	}
+	if created.UserID == nil || *created.UserID != 1 {
+		t.Error("Expected webhook to belong to the authenticated user")
+		t.Fail()
+	}
+	if created.Secret == "" || created.Secret == "mine" {
+		t.Error("Expected a generated secret")
+		t.Fail()
+	}
+	var response data.Webhook
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Error(err.Error())
+		t.FailNow()
+	}
+	if response.ID != 4 || response.Secret != created.Secret {
+		t.Errorf("Expected webhook 4 with its secret, got %v", response)
+		t.Fail()
+	}
+}
+
+func TestCreateWebhookValidation(t *testing.T) {
+	api := NewWebhookAPI(data.Stores{}, false)
+	bodies := []string{
+		`{"url":"example.com","events":["post.created"]}`,
+		`{"url":"https://example.com","events":[]}`,
+		`{"url":"https://example.com","events":["post.deleted"]}`,
+		`{"url":`,
+	}
+	for _, body := range bodies {
+		r, _ := http.NewRequest("POST", "/", strings.NewReader(body))
+		r = apitest.RequestWithClaims(r, claimsContextKey, jwt.MapClaims{
+			"sub": int64(1),
+		})
+		w := httptest.NewRecorder()
+		api.CreateWebhook()(w, r)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status code %d for %s, received %d", http.StatusBadRequest, body, w.Code)
+			t.Fail()
+		}
+	}
+}
+
+func TestListWebhooksOmitsSecrets(t *testing.T) {
+	api := NewWebhookAPI(
+		data.Stores{
+			WebhookStore: mockWebhookStore{
+				OnList: func(userID int64) ([]data.Webhook, error) {
+					return []data.Webhook{{UserID: &userID, URL: "https://example.com", Secret: "secret"}}, nil
+				},
+			},
+		},
+		false,
+	)
+
+	r, _ := http.NewRequest("GET", "/", nil)
+	r = apitest.RequestWithClaims(r, claimsContextKey, jwt.MapClaims{
+		"sub": int64(1),
+	})
+	w := httptest.NewRecorder()
+	api.ListWebhooks()(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status code %d, received %d", http.StatusOK, w.Code)
+		t.FailNow()
+	}
+	if strings.Contains(w.Body.String(), "secret") {
+		t.Errorf("Expected secrets to be omitted, got %s", w.Body.String())
+		t.Fail()
+	}
+}
+
+func TestDeleteWebhookOfOtherUser(t *testing.T) {
+	owner := int64(2)
+	api := NewWebhookAPI(
+		data.Stores{
+			WebhookStore: mockWebhookStore{
+				OnGet: func(id int64) (*data.Webhook, error) {
+					return &data.Webhook{UserID: &owner}, nil
+				},
+			},
+		},
+		false,
+	)
+
+	r, _ := http.NewRequest("DELETE", "/", nil)
+	r = apitest.RequestWithContextID(r, idContextKey, 3)
+	r = apitest.RequestWithClaims(r, claimsContextKey, jwt.MapClaims{
+		"sub": int64(1),
+	})
+	w := httptest.NewRecorder()
+	api.DeleteWebhook()(w, r)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d, received %d", http.StatusNotFound, w.Code)
+		t.Fail()
+	}
+}
+
+func TestListDeliveries(t *testing.T) {
+	owner := int64(1)
+	var listOptions data.ListOptions
+	api := NewWebhookAPI(
+		data.Stores{
+			WebhookStore: mockWebhookStore{
+				OnGet: func(id int64) (*data.Webhook, error) {
+					return &data.Webhook{UserID: &owner}, nil
+				},
+				OnDeliveries: func(webhookID int64, options data.ListOptions) ([]data.WebhookDelivery, error) {
+					listOptions = options
+					return nil, nil
+				},
+			},
+		},
+		false,
+	)
+
+	r, _ := http.NewRequest("GET", "/?marker=20", nil)
+	r = apitest.RequestWithContextID(r, idContextKey, 3)
+	r = apitest.RequestWithClaims(r, claimsContextKey, jwt.MapClaims{
+		"sub": owner,
+	})
+	w := httptest.NewRecorder()
+	api.ListDeliveries()(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status code %d, received %d", http.StatusOK, w.Code)
+		t.FailNow()
+	}
+	if marker, ok := listOptions.Marker.(int64); !ok || marker != 20 || !listOptions.Desc {
+		t.Errorf("Expected most recent deliveries before 20, got %v", listOptions)
+		t.Fail()
+	}
+	if strings.TrimSpace(w.Body.String()) != "[]" {
+		t.Errorf("Expected an empty list, got %s", w.Body.String())
+		t.Fail()
+	}
+}
+
+func TestRedeliver(t *testing.T) {
+	owner := int64(1)
+	payload := json.RawMessage(`{"event":"post.created"}`)
+	var redelivered *data.WebhookDelivery
+	api := NewWebhookAPI(
+		data.Stores{
+			WebhookStore: mockWebhookStore{
+				OnGetDelivery: func(id int64) (*data.WebhookDelivery, error) {
+					return &data.WebhookDelivery{
+						WebhookID: 2,
+						Event:     webhook.EventPostCreated,
+						Payload:   payload,
+						Status:    data.DeliveryFailed,
+						Attempts:  webhook.MaxAttempts,
+					}, nil
+				},
+				OnGet: func(id int64) (*data.Webhook, error) {
+					if id != 2 {
+						return nil, data.ErrNoEnt
+					}
+					return &data.Webhook{UserID: &owner}, nil
+				},
+				OnCreateDelivery: func(delivery *data.WebhookDelivery) (int64, error) {
+					redelivered = delivery
+					return 8, nil
+				},
+			},
+		},
+		false,
+	)
+
+	r, _ := http.NewRequest("POST", "/", nil)
+	r = apitest.RequestWithContextID(r, idContextKey, 5)
+	r = apitest.RequestWithClaims(r, claimsContextKey, jwt.MapClaims{
+		"sub": owner,
+	})
+	w := httptest.NewRecorder()
+	api.Redeliver()(w, r)
+
+	if w.Code != http.StatusAccepted {
+		t.Errorf("Expected status code %d, received %d", http.StatusAccepted, w.Code)
+		t.FailNow()
+	}
+	if redelivered.WebhookID != 2 || string(redelivered.Payload) != string(payload) || redelivered.Attempts != 0 {
+		t.Errorf("Expected a fresh delivery of the same payload, got %v", redelivered)
+		t.Fail()
+	}
+	var response IDResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Error(err.Error())
+		t.FailNow()
+	}
+	if response.ID != 8 {
+		t.Errorf("Expected new delivery 8, got %d", response.ID)
+		t.Fail()
+	}
+}
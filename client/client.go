@@ -0,0 +1,218 @@
+// Package client provides a typed Go client for the MeIRL API
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const apiVersion = "1.0"
+
+// refreshLeeway is how long before its expiry an access
+// token is refreshed
+const refreshLeeway = time.Minute
+
+// Credentials identify a user by username or email
+// when logging in
+type Credentials struct {
+	Username string `json:"username,omitempty"`
+	Email    string `json:"email,omitempty"`
+	Password string `json:"password"`
+}
+
+// Client is a client for the MeIRL API. A Client is safe
+// for concurrent use. After Login, the Client authenticates
+// requests and logs in again when its access token is about
+// to expire
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+
+	mu          sync.Mutex
+	token       string
+	expires     time.Time
+	credentials *Credentials
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sets the http.Client used to make
+// requests. Defaults to http.DefaultClient
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithToken sets an access token to authenticate requests with.
+// A token set this way is not refreshed
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+		c.expires = tokenExpiry(token)
+	}
+}
+
+// New returns a Client for the MeIRL API served at baseURL,
+// e.g. https://api.meirl.example
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("base URL %q must be absolute", baseURL)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	c := &Client{
+		baseURL:    u,
+		httpClient: http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// Token returns the current access token, or an empty
+// string if the Client is not authenticated
+func (c *Client) Token() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.token
+}
+
+// Login logs in with the given credentials and authenticates
+// subsequent requests with the returned access token. The
+// credentials are kept to refresh the token
+func (c *Client) Login(ctx context.Context, credentials Credentials) error {
+	token, err := c.login(ctx, credentials)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.setToken(token)
+	c.credentials = &credentials
+	return nil
+}
+
+func (c *Client) login(ctx context.Context, credentials Credentials) (string, error) {
+	var resp tokenResponse
+	err := c.do(ctx, "POST", "user/login", nil, credentials, &resp, false)
+	if err != nil {
+		return "", err
+	}
+	return resp.AccessToken, nil
+}
+
+func (c *Client) setToken(token string) {
+	c.token = token
+	c.expires = tokenExpiry(token)
+}
+
+// accessToken returns a valid access token, logging in again
+// if the current token is about to expire and credentials are known
+func (c *Client) accessToken(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token == "" {
+		return "", ErrNotAuthenticated
+	}
+	if c.credentials == nil || c.expires.IsZero() || time.Until(c.expires) > refreshLeeway {
+		return c.token, nil
+	}
+	token, err := c.login(ctx, *c.credentials)
+	if err != nil {
+		return "", err
+	}
+	c.setToken(token)
+	return token, nil
+}
+
+// do sends a request to the API path with the given query and JSON body,
+// decoding a JSON response into out if it is non-nil. Returns an *Error
+// if the API responds with an error status
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out interface{}, auth bool) error {
+	u := *c.baseURL
+	u.Path = fmt.Sprintf("%s/%s/%s", u.Path, apiVersion, path)
+	u.RawQuery = query.Encode()
+
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(b)
+	}
+	req, err := http.NewRequest(method, u.String(), reader)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	if auth {
+		token, err := c.accessToken(ctx)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return newError(resp)
+	}
+	if out == nil {
+		io.Copy(ioutil.Discard, resp.Body)
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// tokenExpiry returns the expiry of the JWT without verifying it,
+// or the zero time if the token has no readable expiry
+func tokenExpiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}
+	}
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
+		return time.Time{}
+	}
+	return time.Unix(claims.Exp, 0)
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"accessToken"`
+}
+
+type idResponse struct {
+	ID int64 `json:"id"`
+}
@@ -0,0 +1,200 @@
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/boxtown/meirl/data"
+)
+
+// testToken returns an unsigned JWT expiring at exp. The client
+// never verifies tokens so a signature is not needed
+func testToken(exp time.Time) string {
+	claims, _ := json.Marshal(map[string]int64{"exp": exp.Unix()})
+	return "e30." + base64.RawURLEncoding.EncodeToString(claims) + ".sig"
+}
+
+// testPost returns a post created half way through the given second,
+// as Postgres timestamps are more precise than those returned by the API
+func testPost(id int64, createdAt int64) data.Post {
+	var p data.Post
+	p.ID = id
+	p.CreatedAt = data.Time{Time: time.Unix(createdAt, int64(time.Second/2))}
+	return p
+}
+
+func TestLoginRefreshesToken(t *testing.T) {
+	logins := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/1.0/user/login", func(w http.ResponseWriter, r *http.Request) {
+		var creds Credentials
+		json.NewDecoder(r.Body).Decode(&creds)
+		if creds.Username != "tester" || creds.Password != "pass" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		logins++
+		// the first token is about to expire
+		exp := time.Now().Add(time.Hour)
+		if logins == 1 {
+			exp = time.Now().Add(time.Second)
+		}
+		json.NewEncoder(w).Encode(tokenResponse{AccessToken: testToken(exp)})
+	})
+	var auth string
+	mux.HandleFunc("/1.0/user/me", func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		json.NewEncoder(w).Encode(&data.User{Username: "tester"})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c, err := New(server.URL)
+	if err != nil {
+		t.Error(err.Error())
+		t.FailNow()
+	}
+	ctx := context.Background()
+	if _, err := c.Me(ctx); err != ErrNotAuthenticated {
+		t.Errorf("Expected ErrNotAuthenticated before login, got %v", err)
+		t.Fail()
+	}
+
+	err = c.Login(ctx, Credentials{Username: "tester", Password: "pass"})
+	if err != nil {
+		t.Error(err.Error())
+		t.FailNow()
+	}
+	first := c.Token()
+	user, err := c.Me(ctx)
+	if err != nil {
+		t.Error(err.Error())
+		t.FailNow()
+	}
+	if user.Username != "tester" {
+		t.Errorf("Expected user tester, got %s", user.Username)
+		t.Fail()
+	}
+	if logins != 2 || c.Token() == first {
+		t.Error("Expected expiring token to be refreshed")
+		t.Fail()
+	}
+	if auth != "Bearer "+c.Token() {
+		t.Errorf("Expected request to use refreshed token, got %s", auth)
+		t.Fail()
+	}
+
+	err = c.Login(ctx, Credentials{Username: "tester", Password: "wrong"})
+	if !IsBadRequest(err) {
+		t.Errorf("Expected bad request error for wrong password, got %v", err)
+		t.Fail()
+	}
+}
+
+func TestErrors(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/1.0/post/1", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc("/1.0/post/new", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprint(w, `{"title":"Too Many Requests","status":429,"requestId":"abc"}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c, _ := New(server.URL, WithToken(testToken(time.Now().Add(time.Hour))))
+	ctx := context.Background()
+
+	_, err := c.Post(ctx, 1)
+	if !IsNotFound(err) {
+		t.Errorf("Expected not found error, got %v", err)
+		t.Fail()
+	}
+
+	_, err = c.CreatePost(ctx, []byte("hello"))
+	if !IsRateLimited(err) {
+		t.Errorf("Expected rate limited error, got %v", err)
+		t.FailNow()
+	}
+	apiErr := err.(*Error)
+	if apiErr.RetryAfter != 30*time.Second || apiErr.RequestID != "abc" {
+		t.Errorf("Expected Retry-After and request ID in error, got %+v", apiErr)
+		t.Fail()
+	}
+}
+
+func TestFeedIterator(t *testing.T) {
+	// posts 10 to 12 share a time across the first page boundary
+	feed := []data.Post{}
+	for i := int64(1); i <= 25; i++ {
+		feed = append(feed, testPost(i, 100+i))
+	}
+	feed[10], feed[11] = testPost(11, 110), testPost(12, 110)
+
+	requests := 0
+	token := testToken(time.Now().Add(time.Hour))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("Authorization") != "Bearer "+token {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		q := r.URL.Query()
+		limit, _ := strconv.Atoi(q.Get("limit"))
+		page := []data.Post{}
+		for _, p := range feed {
+			if marker := q.Get("marker"); marker != "" {
+				m, err := data.ParseTimeMarker(marker)
+				if err != nil {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				if p.CreatedAt.Before(m.Time) || (p.CreatedAt.Time.Equal(m.Time) && p.ID <= m.ID) {
+					continue
+				}
+			}
+			if len(page) < limit {
+				p.Marker = data.TimeMarker{Time: p.CreatedAt.Time, ID: p.ID}.String()
+				page = append(page, p)
+			}
+		}
+		json.NewEncoder(w).Encode(page)
+	}))
+	defer server.Close()
+
+	c, _ := New(server.URL, WithToken(token))
+	it := c.Feed(1, FeedOptions{PageSize: 10})
+	var ids []int64
+	ctx := context.Background()
+	for it.Next(ctx) {
+		ids = append(ids, it.Post().ID)
+	}
+	if err := it.Err(); err != nil {
+		t.Error(err.Error())
+		t.FailNow()
+	}
+	if len(ids) != 25 {
+		t.Errorf("Expected 25 posts, got %d: %v", len(ids), ids)
+		t.FailNow()
+	}
+	for i, id := range ids {
+		if id != int64(i+1) {
+			t.Errorf("Expected posts in order without duplicates, got %v", ids)
+			t.FailNow()
+		}
+	}
+	if requests != 3 {
+		t.Errorf("Expected 3 page requests, got %d", requests)
+		t.Fail()
+	}
+}
@@ -0,0 +1,94 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNotAuthenticated is returned when calling an authenticated
+// endpoint before logging in
+var ErrNotAuthenticated = errors.New("client is not authenticated")
+
+// Error is returned when the API responds with an error status
+type Error struct {
+	StatusCode int
+	Message    string
+	RequestID  string
+
+	// RetryAfter is how long to wait before retrying a
+	// rate limited request
+	RetryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	msg := strconv.Itoa(e.StatusCode) + " " + http.StatusText(e.StatusCode)
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	if e.RequestID != "" {
+		msg += " (request " + e.RequestID + ")"
+	}
+	return msg
+}
+
+// IsNotFound returns true if err is an API error
+// for an entity that does not exist
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsBadRequest returns true if err is an API error for an
+// invalid request, such as a validation error or bad credentials
+func IsBadRequest(err error) bool {
+	return hasStatus(err, http.StatusBadRequest)
+}
+
+// IsRateLimited returns true if err is an API error
+// for a rate limited request
+func IsRateLimited(err error) bool {
+	return hasStatus(err, http.StatusTooManyRequests)
+}
+
+func hasStatus(err error, status int) bool {
+	var e *Error
+	return errors.As(err, &e) && e.StatusCode == status
+}
+
+type problemResponse struct {
+	Title     string `json:"title"`
+	Detail    string `json:"detail"`
+	RequestID string `json:"requestId"`
+}
+
+// newError builds an *Error from an error response. The body is
+// either a problem+json document or a plain text message
+func newError(resp *http.Response) *Error {
+	e := &Error{
+		StatusCode: resp.StatusCode,
+		RequestID:  resp.Header.Get("X-Request-ID"),
+	}
+	if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		e.RetryAfter = time.Duration(secs) * time.Second
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "application/problem+json") {
+		var problem problemResponse
+		if json.Unmarshal(body, &problem) == nil {
+			e.Message = problem.Detail
+			if e.Message == "" {
+				e.Message = problem.Title
+			}
+			if problem.RequestID != "" {
+				e.RequestID = problem.RequestID
+			}
+			return e
+		}
+	}
+	e.Message = strings.TrimSpace(string(body))
+	return e
+}
@@ -0,0 +1,134 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/boxtown/meirl/data"
+)
+
+// page sizes accepted by the API
+const (
+	defaultPageSize = 10
+	maxPageSize     = 1000
+)
+
+// FeedOptions configures iteration over a feed
+type FeedOptions struct {
+	// PageSize is the number of posts fetched per request,
+	// between 10 and 1000. Defaults to 10
+	PageSize int
+
+	// Desc iterates from the newest post to the oldest
+	Desc bool
+
+	// Since starts iteration after this time, or
+	// before it if Desc is set
+	Since time.Time
+}
+
+// Feed returns an iterator over the feed of the user with the
+// given id, which must be the user the client is logged in as
+func (c *Client) Feed(userID int64, options FeedOptions) *PostIterator {
+	size := options.PageSize
+	if size < defaultPageSize {
+		size = defaultPageSize
+	} else if size > maxPageSize {
+		size = maxPageSize
+	}
+	it := &PostIterator{
+		path: fmt.Sprintf("user/%d/feed", userID),
+		size: size,
+		desc: options.Desc,
+		auth: true,
+		get:  c.do,
+	}
+	if !options.Since.IsZero() {
+		it.marker = strconv.FormatInt(options.Since.Unix(), 10)
+	}
+	return it
+}
+
+// PostIterator iterates over a paginated list of posts:
+//
+//	it := c.Feed(id, client.FeedOptions{Desc: true})
+//	for it.Next(ctx) {
+//		post := it.Post()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+//
+// Pages are seeked with the marker of the last post of the
+// previous page, so posts sharing a time are neither skipped
+// nor fetched again
+type PostIterator struct {
+	path string
+	size int
+	desc bool
+	auth bool
+	get  func(ctx context.Context, method, path string, query url.Values, body, out interface{}, auth bool) error
+
+	marker string
+
+	page []data.Post
+	post data.Post
+	done bool
+	err  error
+}
+
+// Next advances the iterator to the next post, fetching the next
+// page if needed. Returns false when iteration is finished or an
+// error occurred
+func (it *PostIterator) Next(ctx context.Context) bool {
+	for len(it.page) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+		it.fetch(ctx)
+	}
+	it.post = it.page[0]
+	it.page = it.page[1:]
+	return true
+}
+
+// Post returns the current post
+func (it *PostIterator) Post() data.Post {
+	return it.post
+}
+
+// Err returns the error that stopped iteration, if any
+func (it *PostIterator) Err() error {
+	return it.err
+}
+
+func (it *PostIterator) fetch(ctx context.Context) {
+	query := url.Values{}
+	query.Set("limit", strconv.Itoa(it.size))
+	query.Set("desc", strconv.FormatBool(it.desc))
+	if it.marker != "" {
+		query.Set("marker", it.marker)
+	}
+	var posts []data.Post
+	err := it.get(ctx, "GET", it.path, query, nil, &posts, it.auth)
+	if err != nil {
+		it.err = err
+		return
+	}
+	if len(posts) < it.size {
+		it.done = true
+	}
+	if len(posts) == 0 {
+		return
+	}
+	it.page = posts
+	it.marker = posts[len(posts)-1].Marker
+	if it.marker == "" {
+		it.err = errors.New("listed posts have no marker")
+	}
+}
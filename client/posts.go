@@ -0,0 +1,29 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/boxtown/meirl/data"
+)
+
+// Post retrieves a post by id
+func (c *Client) Post(ctx context.Context, id int64) (*data.Post, error) {
+	var post data.Post
+	err := c.do(ctx, "GET", fmt.Sprintf("post/%d", id), nil, nil, &post, false)
+	if err != nil {
+		return nil, err
+	}
+	return &post, nil
+}
+
+// CreatePost creates a post with the given contents as the
+// authenticated user, returning the ID of the created post
+func (c *Client) CreatePost(ctx context.Context, contents []byte) (int64, error) {
+	var resp idResponse
+	err := c.do(ctx, "POST", "post/new", nil, data.Post{Contents: contents}, &resp, true)
+	if err != nil {
+		return 0, err
+	}
+	return resp.ID, nil
+}
@@ -0,0 +1,51 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/boxtown/meirl/data"
+)
+
+// Signup creates a new user, returning the ID of
+// the created user
+func (c *Client) Signup(ctx context.Context, user *data.User) (int64, error) {
+	var resp idResponse
+	err := c.do(ctx, "POST", "user/new", nil, user, &resp, false)
+	if err != nil {
+		return 0, err
+	}
+	return resp.ID, nil
+}
+
+// User retrieves a user by id
+func (c *Client) User(ctx context.Context, id int64) (*data.User, error) {
+	var user data.User
+	err := c.do(ctx, "GET", fmt.Sprintf("user/%d", id), nil, nil, &user, false)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// Me retrieves the authenticated user
+func (c *Client) Me(ctx context.Context) (*data.User, error) {
+	var user data.User
+	err := c.do(ctx, "GET", "user/me", nil, nil, &user, true)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// Follow follows the user with the given id as
+// the authenticated user
+func (c *Client) Follow(ctx context.Context, id int64) error {
+	return c.do(ctx, "POST", fmt.Sprintf("user/%d/followers", id), nil, nil, nil, true)
+}
+
+// UnFollow unfollows the user with the given id as
+// the authenticated user
+func (c *Client) UnFollow(ctx context.Context, id int64) error {
+	return c.do(ctx, "DELETE", fmt.Sprintf("user/%d/followers", id), nil, nil, nil, true)
+}
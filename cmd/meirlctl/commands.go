@@ -0,0 +1,271 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/boxtown/meirl/api"
+	"github.com/boxtown/meirl/data"
+	"github.com/boxtown/meirl/data/postgres"
+)
+
+func createUser(e *env, args []string) error {
+	var u data.User
+	var dob string
+	fs := flag.NewFlagSet("user create", flag.ContinueOnError)
+	fs.StringVar(&u.Username, "username", "", "username")
+	fs.StringVar(&u.Email, "email", "", "email")
+	fs.StringVar(&u.ActualName, "name", "", "actual name")
+	fs.StringVar(&dob, "dob", "", "date of birth as YYYY-MM-DD")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if u.Username == "" || u.Email == "" || u.ActualName == "" || dob == "" {
+		return errors.New("-username, -email, -name and -dob are required")
+	}
+	t, err := time.Parse("2006-01-02", dob)
+	if err != nil {
+		return fmt.Errorf("invalid -dob: %s", err)
+	}
+	u.DOB = data.Time{Time: t}
+	u.Password, err = readPassword(e.in)
+	if err != nil {
+		return err
+	}
+	id, err := e.users.Create(&u)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(e.out, "created user %d\n", id)
+	return nil
+}
+
+func disableUser(e *env, args []string) error {
+	return setDisabled(e, args, true)
+}
+
+func enableUser(e *env, args []string) error {
+	return setDisabled(e, args, false)
+}
+
+func setDisabled(e *env, args []string, disabled bool) error {
+	id, err := parseID(args)
+	if err != nil {
+		return err
+	}
+	err = e.users.SetDisabled(id, disabled)
+	if err != nil {
+		return err
+	}
+	if disabled {
+		fmt.Fprintf(e.out, "disabled user %d\n", id)
+	} else {
+		fmt.Fprintf(e.out, "enabled user %d\n", id)
+	}
+	return nil
+}
+
+func deleteUser(e *env, args []string) error {
+	id, err := parseID(args)
+	if err != nil {
+		return err
+	}
+	// the store deletes idempotently, so check the user exists
+	// to report a mistyped id
+	if _, err = e.users.Get(id); err != nil {
+		return err
+	}
+	err = e.users.Delete(id)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(e.out, "deleted user %d\n", id)
+	return nil
+}
+
+func resetPassword(e *env, args []string) error {
+	id, err := parseID(args)
+	if err != nil {
+		return err
+	}
+	password, err := readPassword(e.in)
+	if err != nil {
+		return err
+	}
+	err = e.users.SetPassword(id, password)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(e.out, "reset password of user %d\n", id)
+	return nil
+}
+
+func showUser(e *env, args []string) error {
+	id, err := parseID(args)
+	if err != nil {
+		return err
+	}
+	u, err := e.users.Get(id)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(e.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "id\t%d\n", u.ID)
+	fmt.Fprintf(w, "username\t%s\n", u.Username)
+	fmt.Fprintf(w, "email\t%s\n", u.Email)
+	fmt.Fprintf(w, "name\t%s\n", u.ActualName)
+	fmt.Fprintf(w, "dob\t%s\n", u.DOB.Format("2006-01-02"))
+	fmt.Fprintf(w, "created\t%s\n", u.CreatedAt.Format(time.RFC3339))
+	fmt.Fprintf(w, "disabled\t%t\n", u.Disabled)
+	fmt.Fprintf(w, "following\t%d\n", u.NumFollowing)
+	fmt.Fprintf(w, "followers\t%d\n", u.NumFollowers)
+	return w.Flush()
+}
+
+func listFollowers(e *env, args []string) error {
+	id, options, err := parseListArgs("user followers", args)
+	if err != nil {
+		return err
+	}
+	followers, err := e.users.Followers(id, options, data.UserSortByID)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(e.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tUSERNAME\tEMAIL\tDISABLED")
+	for _, u := range followers {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%t\n", u.ID, u.Username, u.Email, u.Disabled)
+	}
+	return w.Flush()
+}
+
+func listFeed(e *env, args []string) error {
+	id, options, err := parseListArgs("user feed", args)
+	if err != nil {
+		return err
+	}
+	options.Desc = true
+	posts, err := e.posts.Feed(id, options, data.PostSortByDate)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(e.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tAUTHOR\tCREATED\tKEKS\tNOS\tCONTENTS")
+	for _, p := range posts {
+		fmt.Fprintf(w, "%d\t%d\t%s\t%d\t%d\t%s\n",
+			p.ID, p.AuthorID, p.CreatedAt.Format(time.RFC3339), p.Keks, p.Nos, preview(p.Contents))
+	}
+	return w.Flush()
+}
+
+func deletePost(e *env, args []string) error {
+	id, err := parseID(args)
+	if err != nil {
+		return err
+	}
+	if _, err = e.posts.Get(id); err != nil {
+		return err
+	}
+	err = e.posts.Delete(id)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(e.out, "deleted post %d\n", id)
+	return nil
+}
+
+func migrate(e *env, args []string) error {
+	migrations, err := postgres.LoadMigrations(e.cfg.Postgres.MigrationsDir)
+	if err != nil {
+		return err
+	}
+	applied, err := postgres.Migrate(e.db, migrations)
+	for _, migration := range applied {
+		fmt.Fprintf(e.out, "applied %04d %s\n", migration.Version, migration.Name)
+	}
+	if err != nil {
+		return err
+	}
+	if len(applied) == 0 {
+		fmt.Fprintf(e.out, "schema is up to date at version %d\n", postgres.LatestVersion(migrations))
+	}
+	return nil
+}
+
+func counts(e *env, args []string) error {
+	c, err := postgres.CountRows(e.db)
+	if err != nil {
+		return err
+	}
+	version, err := postgres.SchemaVersion(e.db)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(e.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "users\t%d\n", c.Users)
+	fmt.Fprintf(w, "disabled users\t%d\n", c.DisabledUsers)
+	fmt.Fprintf(w, "follows\t%d\n", c.Follows)
+	fmt.Fprintf(w, "posts\t%d\n", c.Posts)
+	fmt.Fprintf(w, "keks\t%d\n", c.Keks)
+	fmt.Fprintf(w, "nos\t%d\n", c.Nos)
+	fmt.Fprintf(w, "schema version\t%d\n", version)
+	return w.Flush()
+}
+
+func parseID(args []string) (int64, error) {
+	if len(args) != 1 {
+		return 0, errors.New("expected a single id argument")
+	}
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil || id < 1 {
+		return 0, fmt.Errorf("invalid id %q", args[0])
+	}
+	return id, nil
+}
+
+func parseListArgs(name string, args []string) (int64, data.ListOptions, error) {
+	options := data.ListOptions{}
+	if len(args) == 0 {
+		return 0, options, errors.New("expected an id argument")
+	}
+	id, err := parseID(args[:1])
+	if err != nil {
+		return 0, options, err
+	}
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.IntVar(&options.Limit, "limit", 10, "maximum number of entities to list, at most 1000")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 0, options, err
+	}
+	return id, options, nil
+}
+
+// readPassword reads a password from the first line of r
+// and secures it the same way the API does
+func readPassword(r io.Reader) (string, error) {
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	password := strings.TrimRight(line, "\r\n")
+	if password == "" {
+		return "", errors.New("expected a password on stdin")
+	}
+	return api.NewAuth().SecurePassword(password)
+}
+
+// preview returns the start of the post contents on a single line
+func preview(contents []byte) string {
+	runes := []rune(strings.Join(strings.Fields(string(contents)), " "))
+	if len(runes) > 40 {
+		return string(runes[:40]) + "..."
+	}
+	return string(runes)
+}
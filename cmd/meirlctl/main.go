@@ -0,0 +1,141 @@
+// Command meirlctl performs operator tasks directly against
+// the MeIRL data layer
+//
+//	meirlctl [config flags] <command> [arguments]
+//
+// Config flags are those accepted by the MeIRL server, e.g. -config
+// and -pgDSN, and the configuration is otherwise loaded from the
+// environment just as it is for the server
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/boxtown/meirl/config"
+	"github.com/boxtown/meirl/data/postgres"
+	"github.com/jmoiron/sqlx"
+)
+
+// env holds the dependencies shared by commands
+type env struct {
+	cfg      *config.Config
+	db       *sqlx.DB
+	users    *postgres.UserStore
+	posts    *postgres.PostStore
+	webhooks *postgres.WebhookStore
+	in       io.Reader
+	out      io.Writer
+}
+
+// command is a meirlctl subcommand
+type command struct {
+	usage string
+	run   func(e *env, args []string) error
+}
+
+var commands = map[string]command{
+	"user create":         {"-username <name> -email <email> -name <actual name> -dob <YYYY-MM-DD>, reads the password from stdin", createUser},
+	"user disable":        {"<id>", disableUser},
+	"user enable":         {"<id>", enableUser},
+	"user delete":         {"<id>", deleteUser},
+	"user reset-password": {"<id>, reads the new password from stdin", resetPassword},
+	"user show":           {"<id>", showUser},
+	"user followers":      {"<id> [-limit n]", listFollowers},
+	"user feed":           {"<id> [-limit n]", listFeed},
+	"post delete":         {"<id>", deletePost},
+	"webhook create":      {"-url <url> [-events e1,e2], registers an admin webhook receiving every event", createWebhook},
+	"webhook list":        {"[user id], lists admin webhooks unless a user is given", listWebhooks},
+	"webhook delete":      {"<id>", deleteWebhook},
+	"webhook deliveries":  {"<webhook id> [-limit n]", listDeliveries},
+	"webhook redeliver":   {"<delivery id>", redeliver},
+	"migrate":             {"applies pending migrations", migrate},
+	"seed":                {"[-seed n] [-users n] [-follows n] [-posts n] [-from date] [-to date] [-sql path], loads a generated dataset", seedData},
+	"counts":              {"prints row counts", counts},
+}
+
+func main() {
+	configArgs, args := splitConfigArgs(os.Args[1:])
+	name, cmd, args, ok := lookup(args)
+	if !ok {
+		usage(os.Stderr)
+		os.Exit(2)
+	}
+
+	cfg, err := config.Load(configArgs)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	db, err := postgres.OpenDB(cfg.Postgres.ConnectionString())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	e := &env{
+		cfg:      cfg,
+		db:       db,
+		users:    postgres.NewUserStore(db),
+		posts:    postgres.NewPostStore(db),
+		webhooks: postgres.NewWebhookStore(db),
+		in:       os.Stdin,
+		out:      os.Stdout,
+	}
+	if err := cmd.run(e, args); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", name, err)
+		os.Exit(1)
+	}
+}
+
+// splitConfigArgs splits the arguments into the config flags preceding
+// the command and the command with its arguments. Every config flag
+// takes a value
+func splitConfigArgs(args []string) ([]string, []string) {
+	i := 0
+	for i < len(args) && strings.HasPrefix(args[i], "-") {
+		if strings.Contains(args[i], "=") {
+			i++
+		} else {
+			i += 2
+		}
+	}
+	if i > len(args) {
+		i = len(args)
+	}
+	return args[:i], args[i:]
+}
+
+// lookup finds the command named by the leading one or two
+// arguments, returning the remaining arguments
+func lookup(args []string) (string, command, []string, bool) {
+	if len(args) >= 2 {
+		name := args[0] + " " + args[1]
+		if cmd, ok := commands[name]; ok {
+			return name, cmd, args[2:], true
+		}
+	}
+	if len(args) >= 1 {
+		if cmd, ok := commands[args[0]]; ok {
+			return args[0], cmd, args[1:], true
+		}
+	}
+	return "", command{}, nil, false
+}
+
+func usage(w io.Writer) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Fprintln(w, "usage: meirlctl [config flags] <command> [arguments]")
+	fmt.Fprintln(w, "\ncommands:")
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-20s %s\n", name, commands[name].usage)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/boxtown/meirl/api"
+	"github.com/boxtown/meirl/config"
+	"github.com/boxtown/meirl/data/seed"
+)
+
+func seedData(e *env, args []string) error {
+	var options seed.Options
+	var from, to, password, sqlPath string
+	fs := flag.NewFlagSet("seed", flag.ContinueOnError)
+	fs.Int64Var(&options.Seed, "seed", 1, "random seed, equal seeds and options generate equal datasets")
+	fs.IntVar(&options.Users, "users", 1000, "number of users")
+	fs.IntVar(&options.MeanFollows, "follows", 20, "mean number of users followed per user")
+	fs.IntVar(&options.MeanPosts, "posts", 10, "mean number of posts per user")
+	fs.StringVar(&from, "from", "", "posts are created from this date, YYYY-MM-DD, defaults to a year before -to")
+	fs.StringVar(&to, "to", "2017-01-01", "posts are created until this date, YYYY-MM-DD")
+	fs.StringVar(&password, "password", "password", "password of every user")
+	fs.StringVar(&sqlPath, "sql", "", "write the dataset as a psql script to this path instead of loading it")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	var err error
+	if options.To, err = parseDate(to); err != nil {
+		return fmt.Errorf("invalid -to: %s", err)
+	}
+	if options.From, err = parseDate(from); err != nil {
+		return fmt.Errorf("invalid -from: %s", err)
+	}
+	if !options.From.IsZero() && !options.From.Before(options.To) {
+		return errors.New("-from must be before -to")
+	}
+	options.Password, err = api.NewAuth().SecurePassword(password)
+	if err != nil {
+		return err
+	}
+
+	ds := seed.Generate(options)
+	if sqlPath != "" {
+		f, err := os.Create(sqlPath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		err = seed.WriteSQL(f, ds)
+		if err != nil {
+			return err
+		}
+	} else {
+		if e.cfg.Env != config.Dev {
+			return fmt.Errorf("refusing to seed a %s database", e.cfg.Env)
+		}
+		err = seed.Load(e.db, ds)
+		if err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(e.out, "seeded %d users, %d follows, %d posts, %d keks and %d nos\n",
+		len(ds.Users), len(ds.Follows), len(ds.Posts), len(ds.Keks), len(ds.Nos))
+	return nil
+}
+
+func parseDate(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse("2006-01-02", s)
+}
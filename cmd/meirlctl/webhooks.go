@@ -0,0 +1,127 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/boxtown/meirl/data"
+	"github.com/boxtown/meirl/webhook"
+)
+
+func createWebhook(e *env, args []string) error {
+	var w data.Webhook
+	var events string
+	fs := flag.NewFlagSet("webhook create", flag.ContinueOnError)
+	fs.StringVar(&w.URL, "url", "", "URL deliveries are posted to")
+	fs.StringVar(&events, "events", strings.Join(webhook.Events, ","), "comma separated events to deliver")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := webhook.ValidateURL(w.URL); err != nil {
+		return err
+	}
+	for _, event := range strings.Split(events, ",") {
+		event = strings.TrimSpace(event)
+		if !webhook.IsEvent(event) {
+			return fmt.Errorf("unknown event %q, expected one of %s", event, strings.Join(webhook.Events, ", "))
+		}
+		w.Events = append(w.Events, event)
+	}
+	secret, err := webhook.NewSecret()
+	if err != nil {
+		return err
+	}
+	w.Secret = secret
+	id, err := e.webhooks.Create(&w)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(e.out, "created webhook %d with secret %s\n", id, secret)
+	return nil
+}
+
+func listWebhooks(e *env, args []string) error {
+	// admin webhooks are listed unless a user is given
+	var userID int64
+	if len(args) > 0 {
+		id, err := parseID(args)
+		if err != nil {
+			return err
+		}
+		userID = id
+	}
+	webhooks, err := e.webhooks.List(userID)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(e.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tCREATED\tEVENTS\tURL")
+	for _, hook := range webhooks {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n",
+			hook.ID, hook.CreatedAt.Format(time.RFC3339), strings.Join(hook.Events, ","), hook.URL)
+	}
+	return w.Flush()
+}
+
+func deleteWebhook(e *env, args []string) error {
+	id, err := parseID(args)
+	if err != nil {
+		return err
+	}
+	if _, err = e.webhooks.Get(id); err != nil {
+		return err
+	}
+	err = e.webhooks.Delete(id)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(e.out, "deleted webhook %d\n", id)
+	return nil
+}
+
+func listDeliveries(e *env, args []string) error {
+	id, options, err := parseListArgs("webhook deliveries", args)
+	if err != nil {
+		return err
+	}
+	options.Desc = true
+	deliveries, err := e.webhooks.Deliveries(id, options)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(e.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tEVENT\tSTATUS\tATTEMPTS\tRESPONSE\tNEXT ATTEMPT\tERROR")
+	for _, d := range deliveries {
+		next := "-"
+		if d.Status == data.DeliveryPending {
+			next = d.NextAttemptAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%d\t%d\t%s\t%s\n",
+			d.ID, d.Event, d.Status, d.Attempts, d.ResponseStatus, next, d.LastError)
+	}
+	return w.Flush()
+}
+
+func redeliver(e *env, args []string) error {
+	id, err := parseID(args)
+	if err != nil {
+		return err
+	}
+	d, err := e.webhooks.GetDelivery(id)
+	if err != nil {
+		return err
+	}
+	newID, err := e.webhooks.CreateDelivery(&data.WebhookDelivery{
+		WebhookID: d.WebhookID,
+		Event:     d.Event,
+		Payload:   d.Payload,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(e.out, "queued delivery %d\n", newID)
+	return nil
+}
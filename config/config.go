@@ -0,0 +1,404 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Environment is the type of environment the application
+// is running in
+type Environment int
+
+const (
+	// Unset designates an environment that was not configured. It
+	// is not a development environment, so no secrets are defaulted
+	Unset Environment = iota
+
+	// Dev designates a development environment
+	Dev
+
+	// Prod designates a production environment
+	Prod
+)
+
+func (e Environment) String() string {
+	switch e {
+	case Dev:
+		return "dev"
+	case Prod:
+		return "prod"
+	default:
+		return "unset"
+	}
+}
+
+// Set parses an environment name into the Environment instance.
+// Implements flag.Value
+func (e *Environment) Set(s string) error {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "prod":
+		*e = Prod
+	case "dev":
+		*e = Dev
+	default:
+		return fmt.Errorf("unknown environment %q, expected dev or prod", s)
+	}
+	return nil
+}
+
+// UnmarshalYAML unmarshals an environment name into the
+// Environment instance
+func (e *Environment) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+	return e.Set(s)
+}
+
+// Environment variables read by Load
+const (
+	ConfigFileVar = "MEIRL_CONFIG"
+	EnvVar        = "MEIRL_ENV"
+	SigningKeyVar = "MEIRL_KEY"
+	ListenAddrVar = "MEIRL_LISTEN_ADDR"
+	PGDSNVar      = "MEIRL_PG_DSN"
+	PGUserVar     = "MEIRL_PG_USER"
+	PGPassVar     = "MEIRL_PG_PASS"
+	PGHostVar     = "MEIRL_PG_HOST"
+	PGPortVar     = "MEIRL_PG_PORT"
+	PGNameVar     = "MEIRL_PG_NAME"
+	RedisAddrVar  = "MEIRL_REDIS_ADDR"
+)
+
+// Config is the complete configuration for a MeIRL server
+type Config struct {
+	Env        Environment     `yaml:"env"`
+	SigningKey string          `yaml:"signingKey"`
+	Server     ServerConfig    `yaml:"server"`
+	Postgres   PostgresConfig  `yaml:"postgres"`
+	RateLimit  RateLimitConfig `yaml:"rateLimit"`
+	Realtime   RealtimeConfig  `yaml:"realtime"`
+	Feed       FeedConfig      `yaml:"feed"`
+	Webhooks   WebhookConfig   `yaml:"webhooks"`
+}
+
+// ServerConfig configures the HTTP server
+type ServerConfig struct {
+	ListenAddr          string        `yaml:"listenAddr"`
+	ReadTimeout         time.Duration `yaml:"readTimeout"`
+	WriteTimeout        time.Duration `yaml:"writeTimeout"`
+	IdleTimeout         time.Duration `yaml:"idleTimeout"`
+	ShutdownTimeout     time.Duration `yaml:"shutdownTimeout"`
+	RequestBodyMaxBytes int64         `yaml:"requestBodyMaxBytes"`
+}
+
+// PostgresConfig configures the Postgres connection and pool.
+// If DSN is set, it is used verbatim and the individual connection
+// fields are ignored
+type PostgresConfig struct {
+	DSN      string `yaml:"dsn"`
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	Host     string `yaml:"host"`
+	Port     string `yaml:"port"`
+	Database string `yaml:"database"`
+	SSLMode  string `yaml:"sslMode"`
+
+	ConnectTimeout   time.Duration `yaml:"connectTimeout"`
+	StatementTimeout time.Duration `yaml:"statementTimeout"`
+
+	MaxOpenConns    int           `yaml:"maxOpenConns"`
+	MaxIdleConns    int           `yaml:"maxIdleConns"`
+	ConnMaxLifetime time.Duration `yaml:"connMaxLifetime"`
+
+	MigrationsDir string `yaml:"migrationsDir"`
+}
+
+// Rate limit backends
+const (
+	RateLimitMemory = "memory"
+	RateLimitRedis  = "redis"
+)
+
+// RateLimitConfig configures the rate limiter backend. The memory
+// backend limits per instance while the redis backend shares limits
+// between instances
+type RateLimitConfig struct {
+	Backend   string `yaml:"backend"`
+	RedisAddr string `yaml:"redisAddr"`
+}
+
+// Realtime event backends
+const (
+	RealtimeMemory   = "memory"
+	RealtimePostgres = "postgres"
+)
+
+// RealtimeConfig configures how realtime events are distributed. The
+// memory backend only reaches streams connected to the same instance
+// while the postgres backend relays events between instances using
+// LISTEN/NOTIFY. If LogEvents is true, every domain event relayed
+// from the outbox is logged
+type RealtimeConfig struct {
+	Backend   string `yaml:"backend"`
+	LogEvents bool   `yaml:"logEvents"`
+}
+
+// Feed strategies
+const (
+	FeedQuery    = "query"
+	FeedTimeline = "timeline"
+)
+
+// FeedConfig configures how feeds are retrieved. The query strategy
+// joins the posts of every followee when a feed is retrieved while the
+// timeline strategy reads timelines materialized as posts are created.
+// Posts by authors with more than FanoutLimit followers are not
+// materialized and are read with the query strategy instead. Timelines
+// are maintained with either strategy so that it may be changed
+type FeedConfig struct {
+	Strategy    string `yaml:"strategy"`
+	FanoutLimit int    `yaml:"fanoutLimit"`
+}
+
+// WebhookConfig configures webhook delivery. Deliveries are only
+// made to public addresses unless AllowPrivateAddrs is true, which
+// should only be the case when testing against a local receiver
+type WebhookConfig struct {
+	AllowPrivateAddrs bool `yaml:"allowPrivateAddrs"`
+}
+
+// ConnectionString returns the DSN used to connect to Postgres
+func (c PostgresConfig) ConnectionString() string {
+	if c.DSN != "" {
+		return c.DSN
+	}
+	u := url.URL{
+		Scheme: "postgres",
+		User:   url.UserPassword(c.User, c.Password),
+		Host:   c.Host + ":" + c.Port,
+		Path:   "/" + c.Database,
+	}
+	q := url.Values{}
+	if c.SSLMode != "" {
+		q.Set("sslmode", c.SSLMode)
+	}
+	if c.ConnectTimeout > 0 {
+		q.Set("connect_timeout", strconv.Itoa(int(c.ConnectTimeout/time.Second)))
+	}
+	if c.StatementTimeout > 0 {
+		q.Set("statement_timeout", strconv.FormatInt(int64(c.StatementTimeout/time.Millisecond), 10))
+	}
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// Debug returns true if the configuration is for a
+// development environment
+func (c *Config) Debug() bool {
+	return c.Env == Dev
+}
+
+// Load loads the configuration from, in increasing order of precedence,
+// built-in defaults, the YAML config file, environment variables and the
+// given command line arguments. The config file is named by the -config flag
+// or the MEIRL_CONFIG environment variable. Returns an error if the resulting
+// configuration is invalid
+func Load(args []string) (*Config, error) {
+	return load(args, os.Getenv)
+}
+
+func load(args []string, getenv func(string) string) (*Config, error) {
+	var flags Config
+	var path string
+	fs := flag.NewFlagSet("meirl", flag.ContinueOnError)
+	fs.StringVar(&path, "config", "", "path to a YAML config file")
+	fs.Var(&flags.Env, "env", "application environment type, dev or prod")
+	fs.StringVar(&flags.Server.ListenAddr, "listenAddr", "", "address to listen on, defaults to :8080")
+	fs.StringVar(&flags.Postgres.DSN, "pgDSN", "", "Postgres connection string")
+	fs.Int64Var(&flags.Server.RequestBodyMaxBytes, "requestBodyMaxBytes", 0, "max request body bytes, defaults to 5mb")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if path == "" {
+		path = getenv(ConfigFileVar)
+	}
+
+	var c Config
+	if path != "" {
+		if err := c.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.loadEnv(getenv); err != nil {
+		return nil, err
+	}
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "env":
+			c.Env = flags.Env
+		case "listenAddr":
+			c.Server.ListenAddr = flags.Server.ListenAddr
+		case "pgDSN":
+			c.Postgres.DSN = flags.Postgres.DSN
+		case "requestBodyMaxBytes":
+			c.Server.RequestBodyMaxBytes = flags.Server.RequestBodyMaxBytes
+		}
+	})
+	c.setDefaults()
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (c *Config) loadFile(path string) error {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("could not read config file: %s", err)
+	}
+	if err := yaml.UnmarshalStrict(b, c); err != nil {
+		return fmt.Errorf("could not parse config file %s: %s", path, err)
+	}
+	return nil
+}
+
+func (c *Config) loadEnv(getenv func(string) string) error {
+	if v := getenv(EnvVar); v != "" {
+		if err := c.Env.Set(v); err != nil {
+			return err
+		}
+	}
+	setFromEnv(&c.SigningKey, getenv(SigningKeyVar))
+	setFromEnv(&c.Server.ListenAddr, getenv(ListenAddrVar))
+	setFromEnv(&c.Postgres.DSN, getenv(PGDSNVar))
+	setFromEnv(&c.Postgres.User, getenv(PGUserVar))
+	setFromEnv(&c.Postgres.Password, getenv(PGPassVar))
+	setFromEnv(&c.Postgres.Host, getenv(PGHostVar))
+	setFromEnv(&c.Postgres.Port, getenv(PGPortVar))
+	setFromEnv(&c.Postgres.Database, getenv(PGNameVar))
+	setFromEnv(&c.RateLimit.RedisAddr, getenv(RedisAddrVar))
+	return nil
+}
+
+func setFromEnv(dst *string, val string) {
+	if val != "" {
+		*dst = val
+	}
+}
+
+// setDefaults fills in any unset values. Secrets are only
+// defaulted in an environment explicitly configured as dev
+func (c *Config) setDefaults() {
+	if c.Env == Dev {
+		defaultString(&c.SigningKey, "dev-key")
+		defaultString(&c.Postgres.User, "tester")
+		defaultString(&c.Postgres.Password, "test")
+	}
+	defaultString(&c.Server.ListenAddr, ":8080")
+	defaultDuration(&c.Server.ReadTimeout, 10*time.Second)
+	defaultDuration(&c.Server.WriteTimeout, 30*time.Second)
+	defaultDuration(&c.Server.IdleTimeout, 2*time.Minute)
+	defaultDuration(&c.Server.ShutdownTimeout, 10*time.Second)
+	if c.Server.RequestBodyMaxBytes == 0 {
+		c.Server.RequestBodyMaxBytes = 5 * (1 << 20)
+	}
+
+	defaultString(&c.Postgres.Host, "localhost")
+	defaultString(&c.Postgres.Port, "5432")
+	defaultString(&c.Postgres.Database, "meirldb")
+	defaultDuration(&c.Postgres.ConnectTimeout, 5*time.Second)
+	if c.Postgres.MaxOpenConns == 0 {
+		c.Postgres.MaxOpenConns = 20
+	}
+	if c.Postgres.MaxIdleConns == 0 {
+		c.Postgres.MaxIdleConns = 5
+	}
+	defaultDuration(&c.Postgres.ConnMaxLifetime, 30*time.Minute)
+	defaultString(&c.Postgres.MigrationsDir, "resources/sql/migrations")
+	defaultString(&c.RateLimit.Backend, RateLimitMemory)
+	defaultString(&c.Realtime.Backend, RealtimeMemory)
+	defaultString(&c.Feed.Strategy, FeedTimeline)
+	if c.Feed.FanoutLimit == 0 {
+		c.Feed.FanoutLimit = 10000
+	}
+}
+
+func defaultString(dst *string, val string) {
+	if *dst == "" {
+		*dst = val
+	}
+}
+
+func defaultDuration(dst *time.Duration, val time.Duration) {
+	if *dst == 0 {
+		*dst = val
+	}
+}
+
+// Validate returns an error describing every problem
+// with the configuration, or nil if it is valid
+func (c *Config) Validate() error {
+	var problems []string
+	if c.SigningKey == "" {
+		problems = append(problems, fmt.Sprintf("signing key is required (set %s)", SigningKeyVar))
+	}
+	if c.Postgres.DSN == "" {
+		if c.Postgres.User == "" {
+			problems = append(problems, fmt.Sprintf("postgres user is required (set %s or %s)", PGUserVar, PGDSNVar))
+		}
+		if c.Postgres.Password == "" {
+			problems = append(problems, fmt.Sprintf("postgres password is required (set %s or %s)", PGPassVar, PGDSNVar))
+		}
+	}
+	if c.Server.RequestBodyMaxBytes < 0 {
+		problems = append(problems, "requestBodyMaxBytes must not be negative")
+	}
+	if c.Postgres.MaxOpenConns < 0 || c.Postgres.MaxIdleConns < 0 {
+		problems = append(problems, "postgres pool sizes must not be negative")
+	}
+	if c.Postgres.MaxOpenConns > 0 && c.Postgres.MaxIdleConns > c.Postgres.MaxOpenConns {
+		problems = append(problems, "postgres maxIdleConns must not exceed maxOpenConns")
+	}
+	switch c.RateLimit.Backend {
+	case RateLimitMemory:
+	case RateLimitRedis:
+		if c.RateLimit.RedisAddr == "" {
+			problems = append(problems, fmt.Sprintf("redis address is required for the redis rate limit backend (set %s)", RedisAddrVar))
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("unknown rate limit backend %q", c.RateLimit.Backend))
+	}
+	if c.Realtime.Backend != RealtimeMemory && c.Realtime.Backend != RealtimePostgres {
+		problems = append(problems, fmt.Sprintf("unknown realtime backend %q", c.Realtime.Backend))
+	}
+	if c.Feed.Strategy != FeedQuery && c.Feed.Strategy != FeedTimeline {
+		problems = append(problems, fmt.Sprintf("unknown feed strategy %q", c.Feed.Strategy))
+	}
+	if c.Feed.FanoutLimit < 0 {
+		problems = append(problems, "feed fanoutLimit must not be negative")
+	}
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// ValidationError is returned when a loaded configuration
+// is invalid
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration: " + strings.Join(e.Problems, "; ")
+}
@@ -1,6 +1,7 @@
 package data
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"time"
@@ -73,6 +74,11 @@ type User struct {
 
 	NumFollowing int `json:"numFollowing"`
 	NumFollowers int `json:"numFollowers"`
+
+	// The posts of private users are only visible to their followers,
+	// who must have their requests to follow approved
+	Private  bool `json:"private"`
+	Disabled bool `json:"-"`
 }
 
 // Post is the data model for a MeIRL post
@@ -82,6 +88,140 @@ type Post struct {
 	Contents []byte `json:"contents"`
 	Keks     int    `json:"keks"`
 	Nos      int    `json:"nos"`
+
+	// ParentID is the ID of the post this post replies to, if any.
+	// ConversationID is the ID of the post that started the thread,
+	// which is the ID of the post itself if it is not a reply
+	ParentID       int64 `json:"parentID,omitempty"`
+	ConversationID int64 `json:"conversationID"`
+	Replies        int   `json:"replies"`
+
+	// QuoteID is the ID of the post this post quotes, if any
+	QuoteID int64 `json:"quoteID,omitempty"`
+	Reposts int   `json:"reposts"`
+	Quotes  int   `json:"quotes"`
+
+	// RepostedBy is the ID of the user whose repost put the post in
+	// a feed, at RepostedAt. Both are only set in feeds
+	RepostedBy int64 `json:"repostedBy,omitempty"`
+	RepostedAt *Time `json:"repostedAt,omitempty"`
+
+	// Entities are the mentions and hashtags in the contents,
+	// by offset. Mentions of users that do not exist are omitted
+	Entities PostEntities `json:"entities"`
+
+	// Marker is the marker of the next page after this post in
+	// lists sorted by date, score, trending rank or search relevance
+	Marker string `json:"marker,omitempty" db:"-"`
+}
+
+// EntityType is the type of an entity in the contents of a post
+type EntityType string
+
+// Entity types
+const (
+	EntityMention EntityType = "mention"
+	EntityHashtag EntityType = "hashtag"
+)
+
+// PostEntity is a mention or hashtag in the contents of a post from
+// the byte offset Start, at the leading @ or #, up to End. Text is the
+// mentioned username or the lowercase tag, and UserID the ID of the
+// mentioned user
+type PostEntity struct {
+	Type   EntityType `json:"type"`
+	Start  int        `json:"start"`
+	End    int        `json:"end"`
+	Text   string     `json:"text"`
+	UserID int64      `json:"userID,omitempty"`
+}
+
+// PostEntities are the entities of a post
+type PostEntities []PostEntity
+
+// Scan scans a JSON array of entities into the PostEntities instance
+func (e *PostEntities) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case []byte:
+		return json.Unmarshal(src, e)
+	case string:
+		return json.Unmarshal([]byte(src), e)
+	case nil:
+		*e = nil
+		return nil
+	}
+	return fmt.Errorf("Could not scan %v into data.PostEntities", src)
+}
+
+// PostSearchResult is a post matching a search query, with
+// a snippet of its contents in which matches are highlighted
+// by <mark> tags and which is otherwise HTML escaped
+type PostSearchResult struct {
+	Post
+	Rank    float64 `json:"rank"`
+	Snippet string  `json:"snippet"`
+}
+
+// Notification is the data model for a notification of activity
+// concerning a user. Unread notifications of the same type about the
+// same post are grouped, with ActorID the most recent of ActorCount
+// users to act
+type Notification struct {
+	Mutable
+	UserID     int64            `json:"userID"`
+	Type       NotificationType `json:"type"`
+	PostID     *int64           `json:"postID,omitempty"`
+	ActorID    int64            `json:"actorID"`
+	ActorCount int              `json:"actorCount"`
+	Read       bool             `json:"read"`
+
+	// Marker is the marker of the next page after this
+	// notification when listed by latest activity
+	Marker string `json:"marker,omitempty" db:"-"`
+}
+
+// NotificationPreferences are the types of
+// notifications a user receives
+type NotificationPreferences struct {
+	Follow  bool `json:"follow"`
+	Kek     bool `json:"kek"`
+	No      bool `json:"no"`
+	Mention bool `json:"mention"`
+}
+
+// Webhook is the data model for an endpoint receiving signed
+// deliveries of events. Webhooks registered by users receive the
+// events the user is involved in, while webhooks without a user
+// are registered by admins and receive every event
+type Webhook struct {
+	Mutable
+	UserID *int64   `json:"userID,omitempty"`
+	URL    string   `json:"url"`
+	Secret string   `json:"secret,omitempty"`
+	Events []string `json:"events" db:"-"`
+}
+
+// Webhook delivery statuses
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+// WebhookDelivery is the data model for the delivery of an event
+// to a webhook, recording the outcome of the latest attempt. EventID
+// is the ID of the outbox event delivered, or zero if unknown
+type WebhookDelivery struct {
+	Mutable
+	WebhookID      int64           `json:"webhookID"`
+	EventID        int64           `json:"eventID,omitempty"`
+	Event          string          `json:"event"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	ResponseStatus int             `json:"responseStatus,omitempty"`
+	LastError      string          `json:"lastError,omitempty"`
+	NextAttemptAt  Time            `json:"nextAttemptAt"`
 }
 
 type errCouldNotUnmarshalTime struct {
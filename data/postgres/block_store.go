@@ -0,0 +1,121 @@
+package postgres
+
+import (
+	"github.com/boxtown/meirl/data"
+	"github.com/boxtown/meirl/realtime"
+	"github.com/jmoiron/sqlx"
+)
+
+// BlockStore is a PostgreSQL specific implementation
+// of data.BlockStore
+type BlockStore struct {
+	db *sqlx.DB
+}
+
+// NewBlockStore returns a newly constructed BlockStore
+// with the given database reference
+func NewBlockStore(db *sqlx.DB) *BlockStore {
+	return &BlockStore{db}
+}
+
+// follow is a follow relationship removed by a block
+type follow struct {
+	FollowerID int64
+	FolloweeID int64
+}
+
+// Block idempotently records that the blocker blocked the blocked
+// user, removing the follows and follow requests between them in
+// either direction
+func (store *BlockStore) Block(blockerID, blockedID int64) error {
+	return inTx(store.db, func(tx *sqlx.Tx) error {
+		if _, err := tx.Exec(blockUserSQL, blockerID, blockedID); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(deleteFollowRequestsBetweenSQL, blockerID, blockedID); err != nil {
+			return err
+		}
+		var follows []follow
+		if err := tx.Select(&follows, deleteFollowsBetweenSQL, blockerID, blockedID); err != nil {
+			return err
+		}
+		for _, f := range follows {
+			err := recordEvent(tx, realtime.Event{Type: realtime.UserUnFollowed, ID: f.FolloweeID, UserID: f.FollowerID})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// UnBlock idempotently removes the block of the blocked user by
+// the blocker. Removed follows are not restored
+func (store *BlockStore) UnBlock(blockerID, blockedID int64) error {
+	return store.exec(unBlockUserSQL, blockerID, blockedID)
+}
+
+// Mute idempotently records that the muter muted the muted user
+func (store *BlockStore) Mute(muterID, mutedID int64) error {
+	return store.exec(muteUserSQL, muterID, mutedID)
+}
+
+// UnMute idempotently removes the mute of the muted user by the muter
+func (store *BlockStore) UnMute(muterID, mutedID int64) error {
+	return store.exec(unMuteUserSQL, muterID, mutedID)
+}
+
+// exec executes a query that changes a block or mute
+func (store *BlockStore) exec(query string, args ...interface{}) error {
+	_, err := store.db.Exec(query, args...)
+	if err != nil {
+		return data.NewError(err)
+	}
+	return nil
+}
+
+// IsBlocked returns true if either user blocked the other
+func (store *BlockStore) IsBlocked(userID, otherID int64) (bool, error) {
+	var blocked bool
+	err := store.db.Get(&blocked, isBlockedSQL, userID, otherID)
+	if err != nil {
+		return false, data.NewError(err)
+	}
+	return blocked, nil
+}
+
+// Blocked returns the users blocked by the user with the given id
+func (store *BlockStore) Blocked(userID int64, options data.ListOptions) ([]data.User, error) {
+	return store.list(getBlockedByIDSQL, userID, options)
+}
+
+// Muted returns the users muted by the user with the given id
+func (store *BlockStore) Muted(userID int64, options data.ListOptions) ([]data.User, error) {
+	return store.list(getMutedByIDSQL, userID, options)
+}
+
+// MutedIDs returns the ids of every user muted
+// by the user with the given id
+func (store *BlockStore) MutedIDs(userID int64) ([]int64, error) {
+	var ids []int64
+	err := store.db.Select(&ids, getMutedIDsSQL, userID)
+	if err != nil {
+		return nil, data.NewError(err)
+	}
+	return ids, nil
+}
+
+// list lists the users selected by the query by id
+func (store *BlockStore) list(query string, userID int64, options data.ListOptions) ([]data.User, error) {
+	if options.Limit <= 0 || options.Limit > 1000 {
+		options.Limit = 10
+	}
+	paginator := createUserPaginator(options, data.UserSortByID)
+	q, args := paginator.paginate(query, true, options.Marker, userID)
+	var users []data.User
+	err := store.db.Select(&users, q, args...)
+	if err != nil {
+		return nil, data.NewError(err)
+	}
+	return users, nil
+}
@@ -0,0 +1,156 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/boxtown/gotag"
+	"github.com/boxtown/meirl/data"
+	"github.com/boxtown/meirl/data/datatest"
+	"github.com/jmoiron/sqlx"
+)
+
+func TestBlockUser(t *testing.T) {
+	gotag.Test(gotag.Integration, t, func(t gotag.T) {
+		whileConnectedToTestDb(testDbName, func(db *sqlx.DB) error {
+			defer cleanupBlockStoreTest(t, db)
+
+			ids, err := populateNotificationUsers(t, db, 3)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			blocker, blocked, other := ids[0], ids[1], ids[2]
+			users, posts, blocks := NewUserStore(db), NewPostStore(db), NewBlockStore(db)
+			for _, f := range [][2]int64{{blocker, blocked}, {blocked, blocker}, {blocked, other}} {
+				if err := users.Follow(f[0], f[1]); err != nil {
+					t.Error(err.Error())
+					t.FailNow()
+				}
+			}
+			postID, err := posts.Create(datatest.ExamplePost(blocker))
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			expectTimeline(t, posts, blocked, postID)
+
+			// blocking twice is harmless
+			for i := 0; i < 2; i++ {
+				if err := blocks.Block(blocker, blocked); err != nil {
+					t.Error(err.Error())
+					t.FailNow()
+				}
+			}
+			for _, id := range []int64{blocker, blocked} {
+				following, err := users.FollowingIDs(id)
+				if err != nil {
+					t.Error(err.Error())
+					t.FailNow()
+				}
+				for _, followee := range following {
+					if followee == blocker || followee == blocked {
+						t.Errorf("Expected the follows between %d and %d to be removed", blocker, blocked)
+						t.Fail()
+					}
+				}
+			}
+			isBlocked, err := blocks.IsBlocked(blocked, blocker)
+			if err != nil || !isBlocked {
+				t.Errorf("Expected blocks to apply both ways, got %v and %v", isBlocked, err)
+				t.Fail()
+			}
+
+			// the blocked user may not follow the blocker again
+			if err := users.Follow(blocked, blocker); err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			expectTimeline(t, posts, blocked)
+
+			// not even through reposts
+			if err := posts.Repost(postID, other); err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			expectTimeline(t, posts, blocked)
+
+			listed, err := blocks.Blocked(blocker, data.ListOptions{})
+			if err != nil || len(listed) != 1 || listed[0].ID != blocked {
+				t.Errorf("Expected %d to be blocked, got %v and %v", blocked, listed, err)
+				t.Fail()
+			}
+			if err := blocks.UnBlock(blocker, blocked); err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			isBlocked, err = blocks.IsBlocked(blocker, blocked)
+			if err != nil || isBlocked {
+				t.Errorf("Expected the block to be removed, got %v and %v", isBlocked, err)
+				t.Fail()
+			}
+			return nil
+		})
+	})
+}
+
+func TestMuteUser(t *testing.T) {
+	gotag.Test(gotag.Integration, t, func(t gotag.T) {
+		whileConnectedToTestDb(testDbName, func(db *sqlx.DB) error {
+			defer cleanupBlockStoreTest(t, db)
+
+			ids, err := populateNotificationUsers(t, db, 2)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			muter, muted := ids[0], ids[1]
+			users, posts, blocks := NewUserStore(db), NewPostStore(db), NewBlockStore(db)
+			if err := users.Follow(muter, muted); err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			postID, err := posts.Create(datatest.ExamplePost(muted))
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			if err := blocks.Mute(muter, muted); err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			expectTimeline(t, posts, muter)
+			muting, err := blocks.MutedIDs(muter)
+			if err != nil || len(muting) != 1 || muting[0] != muted {
+				t.Errorf("Expected %d to be muted, got %v and %v", muted, muting, err)
+				t.Fail()
+			}
+
+			// muting keeps the follow and hides nothing from the muted user
+			following, err := users.FollowingIDs(muter)
+			if err != nil || len(following) != 1 {
+				t.Errorf("Expected the follow to be kept, got %v and %v", following, err)
+				t.Fail()
+			}
+			expectTimeline(t, posts, muted, postID)
+
+			if err := blocks.UnMute(muter, muted); err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			expectTimeline(t, posts, muter, postID)
+			return nil
+		})
+	})
+}
+
+func cleanupBlockStoreTest(t gotag.T, db *sqlx.DB) {
+	_, err := db.Exec("DELETE FROM blocks")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	_, err = db.Exec("DELETE FROM mutes")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	cleanupOutboxTest(t, db)
+}
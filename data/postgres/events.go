@@ -0,0 +1,97 @@
+package postgres
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/boxtown/meirl/data"
+	"github.com/boxtown/meirl/realtime"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// eventsChannel is the channel realtime events are
+// notified on
+const eventsChannel = "meirl_events"
+
+const notifyEventSQL = `SELECT pg_notify($1, $2)`
+
+// Notifier is a realtime.Publisher that notifies events
+// to every listening instance using Postgres NOTIFY
+type Notifier struct {
+	db *sqlx.DB
+}
+
+// NewNotifier returns a newly constructed Notifier
+// with the given database reference
+func NewNotifier(db *sqlx.DB) *Notifier {
+	return &Notifier{db}
+}
+
+// Publish notifies the event on the events channel
+func (n *Notifier) Publish(e realtime.Event) error {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = n.db.Exec(notifyEventSQL, eventsChannel, string(payload))
+	if err != nil {
+		return data.NewError(err)
+	}
+	return nil
+}
+
+// EventListener relays events notified on the events
+// channel to a local publisher
+type EventListener struct {
+	listener *pq.Listener
+	done     chan struct{}
+}
+
+// ListenEvents listens for events notified by any instance, including
+// this one, and publishes them to pub. Reconnects automatically, calling
+// onError with connection errors. Events notified while disconnected are
+// lost, so streams rely on clients resuming from their last event
+func ListenEvents(dsn string, pub realtime.Publisher, onError func(error)) (*EventListener, error) {
+	listener := pq.NewListener(dsn, time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			onError(err)
+		}
+	})
+	err := listener.Listen(eventsChannel)
+	if err != nil {
+		listener.Close()
+		return nil, err
+	}
+	l := &EventListener{
+		listener: listener,
+		done:     make(chan struct{}),
+	}
+	go l.relay(pub, onError)
+	return l, nil
+}
+
+func (l *EventListener) relay(pub realtime.Publisher, onError func(error)) {
+	defer close(l.done)
+	for n := range l.listener.Notify {
+		// a nil notification signals a reconnection
+		if n == nil {
+			continue
+		}
+		var e realtime.Event
+		if err := json.Unmarshal([]byte(n.Extra), &e); err != nil {
+			onError(err)
+			continue
+		}
+		if err := pub.Publish(e); err != nil {
+			onError(err)
+		}
+	}
+}
+
+// Close stops listening for events
+func (l *EventListener) Close() error {
+	err := l.listener.Close()
+	<-l.done
+	return err
+}
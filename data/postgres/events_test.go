@@ -0,0 +1,48 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/boxtown/gotag"
+	"github.com/boxtown/meirl/realtime"
+	"github.com/jmoiron/sqlx"
+)
+
+func TestNotifyEvents(t *testing.T) {
+	gotag.Test(gotag.Integration, t, func(t gotag.T) {
+		whileConnectedToTestDb(testDbName, func(db *sqlx.DB) error {
+			bus := realtime.NewBus()
+			sub := bus.Subscribe()
+			defer sub.Close()
+
+			dsn := "postgres://postgres:@localhost:5432/" + testDbName + "?sslmode=disable"
+			listener, err := ListenEvents(dsn, bus, func(err error) {
+				t.Error(err.Error())
+			})
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			defer listener.Close()
+
+			e := realtime.Event{Type: realtime.PostCreated, ID: 1, UserID: 2}
+			err = NewNotifier(db).Publish(e)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			select {
+			case received := <-sub.Events():
+				if received != e {
+					t.Errorf("Expected %v, received %v", e, received)
+					t.Fail()
+				}
+			case <-time.After(5 * time.Second):
+				t.Error("Expected notified event to be relayed")
+				t.Fail()
+			}
+			return nil
+		})
+	})
+}
@@ -8,10 +8,21 @@ import (
 
 var testDbName = "meirltest"
 
+var testMigrationsDir = "../../resources/sql/migrations"
+
 func TestMain(m *testing.M) {
+	migrations, err := LoadMigrations(testMigrationsDir)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(exitFail)
+	}
+	scripts := make([]string, len(migrations))
+	for i, migration := range migrations {
+		scripts[i] = migration.Path
+	}
 	result, err := RunWithTestDB(testDbName, false, func() int {
 		return m.Run()
-	}, "../../resources/sql/create_schema.sql")
+	}, scripts...)
 	if err != nil {
 		fmt.Println(err)
 	}
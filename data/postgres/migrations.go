@@ -0,0 +1,143 @@
+package postgres
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	getSchemaVersionSQL = `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`
+
+	hasSchemaMigrationsSQL = `SELECT to_regclass('public.schema_migrations') IS NOT NULL`
+)
+
+// migration file names are of the form 0001_description.sql
+var migrationFileRegex = regexp.MustCompile(`^(\d+)_([0-9a-zA-Z_]+)\.sql$`)
+
+// Migration is a versioned SQL script that changes the schema.
+// Each script records its own version in the schema_migrations table
+type Migration struct {
+	Version int
+	Name    string
+	Path    string
+}
+
+// LoadMigrations loads the migrations within dir, ordered
+// by version. Returns an error if two migrations share a version
+func LoadMigrations(dir string) ([]Migration, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var migrations []Migration
+	for _, file := range files {
+		match := migrationFileRegex.FindStringSubmatch(file.Name())
+		if file.IsDir() || match == nil {
+			continue
+		}
+		version, _ := strconv.Atoi(match[1])
+		migrations = append(migrations, Migration{
+			Version: version,
+			Name:    match[2],
+			Path:    filepath.Join(dir, file.Name()),
+		})
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	for i := 1; i < len(migrations); i++ {
+		if migrations[i].Version == migrations[i-1].Version {
+			return nil, fmt.Errorf("duplicate migration version %d", migrations[i].Version)
+		}
+	}
+	return migrations, nil
+}
+
+// LatestVersion returns the highest version within
+// the given migrations, or 0 if there are none
+func LatestVersion(migrations []Migration) int {
+	if len(migrations) == 0 {
+		return 0
+	}
+	return migrations[len(migrations)-1].Version
+}
+
+// SchemaVersion returns the version of the most recently
+// applied migration
+func SchemaVersion(db *sqlx.DB) (int, error) {
+	var version int
+	err := db.Get(&version, getSchemaVersionSQL)
+	if err != nil {
+		return 0, err
+	}
+	return version, nil
+}
+
+// CheckSchemaVersion returns an error if the schema is not
+// at least at the given version
+func CheckSchemaVersion(db *sqlx.DB, version int) error {
+	current, err := SchemaVersion(db)
+	if err != nil {
+		return err
+	}
+	if current < version {
+		return fmt.Errorf("schema is at version %d, expected %d", current, version)
+	}
+	return nil
+}
+
+// Migrate applies, in order, the given migrations that are newer than
+// the schema version. Each migration is applied within its own transaction.
+// Returns the migrations that were applied, which on error are those
+// applied before the failing migration
+func Migrate(db *sqlx.DB, migrations []Migration) ([]Migration, error) {
+	var exists bool
+	err := db.Get(&exists, hasSchemaMigrationsSQL)
+	if err != nil {
+		return nil, err
+	}
+	current := 0
+	if exists {
+		current, err = SchemaVersion(db)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var applied []Migration
+	for _, migration := range migrations {
+		if migration.Version <= current {
+			continue
+		}
+		err = applyMigration(db, migration)
+		if err != nil {
+			return applied, fmt.Errorf("migration %d %s failed: %s", migration.Version, migration.Name, err)
+		}
+		applied = append(applied, migration)
+	}
+	return applied, nil
+}
+
+func applyMigration(db *sqlx.DB, migration Migration) error {
+	script, err := ioutil.ReadFile(migration.Path)
+	if err != nil {
+		return err
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	// without arguments the script is sent as a simple query,
+	// which may contain multiple statements
+	_, err = tx.Exec(string(script))
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/boxtown/gotag"
+	"github.com/jmoiron/sqlx"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	dir, err := ioutil.TempDir("", "meirlmigrations")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer os.RemoveAll(dir)
+	for _, name := range []string{"0010_add_index.sql", "0002_add_column.sql", "README.md"} {
+		ioutil.WriteFile(filepath.Join(dir, name), []byte(""), 0600)
+	}
+
+	migrations, err := LoadMigrations(dir)
+	if err != nil {
+		t.Error(err.Error())
+		t.FailNow()
+	}
+	if len(migrations) != 2 {
+		t.Errorf("Expected 2 migrations, loaded %d", len(migrations))
+		t.FailNow()
+	}
+	if migrations[0].Version != 2 || migrations[0].Name != "add_column" {
+		t.Errorf("Expected migration 2 add_column first, got %d %s", migrations[0].Version, migrations[0].Name)
+		t.Fail()
+	}
+	if LatestVersion(migrations) != 10 {
+		t.Errorf("Expected latest version 10, got %d", LatestVersion(migrations))
+		t.Fail()
+	}
+
+	ioutil.WriteFile(filepath.Join(dir, "010_duplicate.sql"), []byte(""), 0600)
+	_, err = LoadMigrations(dir)
+	if err == nil {
+		t.Error("Loading migrations with duplicate versions should have failed")
+		t.Fail()
+	}
+}
+
+func TestLoadRepositoryMigrations(t *testing.T) {
+	migrations, err := LoadMigrations(testMigrationsDir)
+	if err != nil {
+		t.Error(err.Error())
+		t.FailNow()
+	}
+	for i, migration := range migrations {
+		if migration.Version != i+1 {
+			t.Errorf("Expected contiguous migration versions, found %d at position %d", migration.Version, i)
+			t.Fail()
+		}
+	}
+}
+
+func TestMigrateSkipsAppliedMigrations(t *testing.T) {
+	gotag.Test(gotag.Integration, t, func(t gotag.T) {
+		whileConnectedToTestDb(testDbName, func(db *sqlx.DB) error {
+			migrations, err := LoadMigrations(testMigrationsDir)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			applied, err := Migrate(db, migrations)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			if len(applied) != 0 {
+				t.Errorf("Expected no migrations to apply to the test database, applied %d", len(applied))
+				t.Fail()
+			}
+			err = CheckSchemaVersion(db, LatestVersion(migrations))
+			if err != nil {
+				t.Error(err.Error())
+				t.Fail()
+			}
+			return nil
+		})
+	})
+}
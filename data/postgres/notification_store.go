@@ -0,0 +1,180 @@
+package postgres
+
+import (
+	"database/sql"
+
+	"github.com/boxtown/meirl/data"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// NotificationStore is a PostgreSQL specific implementation
+// of data.NotificationStore
+type NotificationStore struct {
+	db *sqlx.DB
+}
+
+// NewNotificationStore returns a newly constructed NotificationStore
+// with the given database reference
+func NewNotificationStore(db *sqlx.DB) *NotificationStore {
+	return &NotificationStore{db}
+}
+
+// Notify notifies the user of activity by the actor, grouping it with
+// the unread notification of the same type about the same post if there
+// is one. postID is 0 for notifications not about a post. Returns the ID
+// of the notification, or 0 if the user disabled the notification type
+func (store *NotificationStore) Notify(
+	userID, actorID int64,
+	notificationType data.NotificationType,
+	postID int64) (int64, error) {
+	post := sql.NullInt64{Int64: postID, Valid: postID != 0}
+	var id int64
+	err := store.db.Get(&id, notifySQL, userID, string(notificationType), actorID, post)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, nil
+		}
+		return 0, data.NewError(err)
+	}
+	return id, nil
+}
+
+// Get retrieves a notification by id
+func (store *NotificationStore) Get(id int64) (*data.Notification, error) {
+	var n data.Notification
+	err := store.db.Get(&n, getNotificationByIDSQL, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, data.ErrNoEnt
+		}
+		return nil, data.NewError(err)
+	}
+	return &n, nil
+}
+
+// List returns the notifications of the user with the given id
+// sorted by the time of their latest activity and then by id. The
+// marker is a data.TimeMarker or a time, and every notification
+// is given the marker of the page following it
+func (store *NotificationStore) List(userID int64, options data.ListOptions, unreadOnly bool) ([]data.Notification, error) {
+	if options.Limit <= 10 || options.Limit > 1000 {
+		options.Limit = 10
+	}
+	paginator := paginator{
+		field:    "notifications.updated_at",
+		tiebreak: "notifications.id",
+		limit:    options.Limit,
+		desc:     options.Desc,
+	}
+	query := getNotificationsByUserIDSQL
+	if unreadOnly {
+		query = getUnreadNotificationsByUserIDSQL
+	}
+	query, args := paginator.paginate(query, true, timeSeek(options.Marker, options.Desc), userID)
+	var notifications []data.Notification
+	err := store.db.Select(&notifications, query, args...)
+	if err != nil {
+		return nil, data.NewError(err)
+	}
+	for i, n := range notifications {
+		notifications[i].Marker = data.TimeMarker{Time: n.UpdatedAt.Time, ID: n.ID}.String()
+	}
+	return notifications, nil
+}
+
+// UnreadCount returns the number of unread notifications
+// of the user with the given id
+func (store *NotificationStore) UnreadCount(userID int64) (int, error) {
+	var count int
+	err := store.db.Get(&count, countUnreadNotificationsSQL, userID)
+	if err != nil {
+		return 0, data.NewError(err)
+	}
+	return count, nil
+}
+
+// MarkRead marks the notifications with the given ids as read.
+// Notifications of other users are ignored
+func (store *NotificationStore) MarkRead(userID int64, ids []int64) error {
+	_, err := store.db.Exec(markNotificationsReadSQL, userID, pq.Array(ids))
+	if err != nil {
+		return data.NewError(err)
+	}
+	return nil
+}
+
+// MarkAllRead marks every notification of the
+// user with the given id as read
+func (store *NotificationStore) MarkAllRead(userID int64) error {
+	_, err := store.db.Exec(markAllNotificationsReadSQL, userID)
+	if err != nil {
+		return data.NewError(err)
+	}
+	return nil
+}
+
+// Preferences returns the notification preferences of the user with
+// the given id. Notification types are enabled unless disabled
+func (store *NotificationStore) Preferences(userID int64) (*data.NotificationPreferences, error) {
+	var rows []struct {
+		Type    data.NotificationType
+		Enabled bool
+	}
+	err := store.db.Select(&rows, getNotificationPreferencesSQL, userID)
+	if err != nil {
+		return nil, data.NewError(err)
+	}
+	preferences := &data.NotificationPreferences{
+		Follow:  true,
+		Kek:     true,
+		No:      true,
+		Mention: true,
+	}
+	for _, row := range rows {
+		if enabled := preferenceField(preferences, row.Type); enabled != nil {
+			*enabled = row.Enabled
+		}
+	}
+	return preferences, nil
+}
+
+// SetPreferences replaces the notification preferences
+// of the user with the given id
+func (store *NotificationStore) SetPreferences(userID int64, preferences *data.NotificationPreferences) error {
+	tx, err := store.db.Begin()
+	if err != nil {
+		return data.NewError(err)
+	}
+	for _, t := range []data.NotificationType{
+		data.NotificationFollow,
+		data.NotificationKek,
+		data.NotificationNo,
+		data.NotificationMention,
+	} {
+		_, err = tx.Exec(setNotificationPreferenceSQL, userID, string(t), *preferenceField(preferences, t))
+		if err != nil {
+			tx.Rollback()
+			return data.NewError(err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return data.NewError(err)
+	}
+	return nil
+}
+
+// Get the preference field for the notification type
+func preferenceField(preferences *data.NotificationPreferences, t data.NotificationType) *bool {
+	switch t {
+	case data.NotificationFollow:
+		return &preferences.Follow
+	case data.NotificationKek:
+		return &preferences.Kek
+	case data.NotificationNo:
+		return &preferences.No
+	case data.NotificationMention:
+		return &preferences.Mention
+	}
+	return nil
+}
@@ -0,0 +1,254 @@
+package postgres
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/boxtown/gotag"
+	"github.com/boxtown/meirl/data"
+	"github.com/boxtown/meirl/data/datatest"
+	"github.com/jmoiron/sqlx"
+)
+
+func TestNotifyGroupsUnread(t *testing.T) {
+	gotag.Test(gotag.Integration, t, func(t gotag.T) {
+		whileConnectedToTestDb(testDbName, func(db *sqlx.DB) error {
+			defer cleanupNotificationStoreTest(t, db)
+
+			ids, err := populateNotificationUsers(t, db, 4)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			postID, err := NewPostStore(db).Create(datatest.ExamplePost(ids[0]))
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			store := NewNotificationStore(db)
+			var notificationIDs []int64
+			// the second kek by ids[1] must not be counted twice
+			for _, actorID := range []int64{ids[1], ids[2], ids[1], ids[3]} {
+				id, err := store.Notify(ids[0], actorID, data.NotificationKek, postID)
+				if err != nil {
+					t.Error(err.Error())
+					t.FailNow()
+				}
+				notificationIDs = append(notificationIDs, id)
+			}
+			for _, id := range notificationIDs[1:] {
+				if id != notificationIDs[0] {
+					t.Errorf("Expected keks to be grouped, got notifications %v", notificationIDs)
+					t.FailNow()
+				}
+			}
+			n, err := store.Get(notificationIDs[0])
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			if n.ActorCount != 3 || n.ActorID != ids[3] || n.PostID == nil || *n.PostID != postID {
+				t.Errorf("Expected 3 actors, most recently %d, got %d, most recently %d", ids[3], n.ActorCount, n.ActorID)
+				t.Fail()
+			}
+
+			// a follow is not grouped with keks
+			followID, err := store.Notify(ids[0], ids[1], data.NotificationFollow, 0)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			if followID == notificationIDs[0] {
+				t.Error("Expected a follow to be a separate notification")
+				t.Fail()
+			}
+			count, err := store.UnreadCount(ids[0])
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			if count != 2 {
+				t.Errorf("Expected 2 unread notifications, got %d", count)
+				t.Fail()
+			}
+
+			// once read, new keks start a new group
+			err = store.MarkRead(ids[0], []int64{notificationIDs[0]})
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			id, err := store.Notify(ids[0], ids[2], data.NotificationKek, postID)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			if id == notificationIDs[0] {
+				t.Error("Expected a read notification not to be grouped with")
+				t.Fail()
+			}
+			unread, err := store.List(ids[0], data.ListOptions{Desc: true}, true)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			if len(unread) != 2 || unread[0].ID != id {
+				t.Errorf("Expected 2 unread notifications, most recently %d", id)
+				t.Fail()
+			}
+
+			err = store.MarkAllRead(ids[0])
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			count, err = store.UnreadCount(ids[0])
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			if count != 0 {
+				t.Errorf("Expected no unread notifications, got %d", count)
+				t.Fail()
+			}
+			return nil
+		})
+	})
+}
+
+func TestListNotificationsSharingATime(t *testing.T) {
+	gotag.Test(gotag.Integration, t, func(t gotag.T) {
+		whileConnectedToTestDb(testDbName, func(db *sqlx.DB) error {
+			defer cleanupNotificationStoreTest(t, db)
+
+			ids, err := populateNotificationUsers(t, db, 2)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			posts, store := NewPostStore(db), NewNotificationStore(db)
+			for i := 0; i < 15; i++ {
+				postID, err := posts.Create(datatest.ExamplePost(ids[0]))
+				if err != nil {
+					t.Error(err.Error())
+					t.FailNow()
+				}
+				if _, err := store.Notify(ids[0], ids[1], data.NotificationKek, postID); err != nil {
+					t.Error(err.Error())
+					t.FailNow()
+				}
+			}
+			if _, err := db.Exec("UPDATE notifications SET updated_at='2017-01-01 00:00:00.5+00'"); err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+
+			seen := make(map[int64]bool)
+			options := data.ListOptions{Desc: true}
+			for page := 0; page < 2; page++ {
+				notifications, err := store.List(ids[0], options, false)
+				if err != nil {
+					t.Error(err.Error())
+					t.FailNow()
+				}
+				for _, n := range notifications {
+					if seen[n.ID] {
+						t.Errorf("Expected notification %d once", n.ID)
+						t.Fail()
+					}
+					seen[n.ID] = true
+				}
+				if len(notifications) == 0 {
+					break
+				}
+				options.Marker, err = data.ParseTimeMarker(notifications[len(notifications)-1].Marker)
+				if err != nil {
+					t.Error(err.Error())
+					t.FailNow()
+				}
+			}
+			if len(seen) != 15 {
+				t.Errorf("Expected 15 notifications over two pages, got %d", len(seen))
+				t.Fail()
+			}
+			return nil
+		})
+	})
+}
+
+func TestNotificationPreferences(t *testing.T) {
+	gotag.Test(gotag.Integration, t, func(t gotag.T) {
+		whileConnectedToTestDb(testDbName, func(db *sqlx.DB) error {
+			defer cleanupNotificationStoreTest(t, db)
+
+			ids, err := populateNotificationUsers(t, db, 2)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			store := NewNotificationStore(db)
+			preferences, err := store.Preferences(ids[0])
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			if !preferences.Follow || !preferences.Kek || !preferences.No || !preferences.Mention {
+				t.Errorf("Expected every notification type to be enabled, got %v", preferences)
+				t.Fail()
+			}
+
+			preferences.Follow = false
+			err = store.SetPreferences(ids[0], preferences)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			stored, err := store.Preferences(ids[0])
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			if *stored != *preferences {
+				t.Errorf("Expected preferences %v, got %v", preferences, stored)
+				t.Fail()
+			}
+			id, err := store.Notify(ids[0], ids[1], data.NotificationFollow, 0)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			if id != 0 {
+				t.Error("Expected disabled notification type not to be notified")
+				t.Fail()
+			}
+			return nil
+		})
+	})
+}
+
+func populateNotificationUsers(t gotag.T, db *sqlx.DB, n int) ([]int64, error) {
+	ids := make([]int64, n)
+	for i := range ids {
+		user := datatest.ExampleUser()
+		user.Username = fmt.Sprintf("test%d", i)
+		user.Email = fmt.Sprintf("test%d@test.com", i)
+		id, err := populateUsersTable(t, db, user)
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+func cleanupNotificationStoreTest(t gotag.T, db *sqlx.DB) {
+	_, err := db.Exec("DELETE FROM notification_preferences")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	_, err = db.Exec("DELETE FROM notifications")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	cleanupPostStoreTest(t, db)
+}
@@ -0,0 +1,205 @@
+package postgres
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/boxtown/meirl/data"
+	"github.com/boxtown/meirl/realtime"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// inTx runs fn within a transaction, committing if fn succeeds.
+// data.ErrNoEnt is returned as is, other errors are wrapped
+func inTx(db *sqlx.DB, fn func(tx *sqlx.Tx) error) error {
+	tx, err := db.Beginx()
+	if err != nil {
+		return data.NewError(err)
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		if err == data.ErrNoEnt {
+			return err
+		}
+		return data.NewError(err)
+	}
+	if err := tx.Commit(); err != nil {
+		return data.NewError(err)
+	}
+	return nil
+}
+
+// recordEvent records the event in the outbox. The event is
+// relayed once the transaction commits, and never if it does not
+func recordEvent(tx *sqlx.Tx, e realtime.Event) error {
+	_, err := tx.Exec(recordEventSQL, e.Type, e.ID, e.UserID)
+	return err
+}
+
+// outboxEvent is an event recorded in the outbox
+type outboxEvent struct {
+	ID       int64
+	TxID     int64
+	Type     string
+	EntityID int64
+	UserID   int64
+}
+
+// OutboxRelay publishes the events recorded in the outbox to each of
+// its sinks. Every sink has its own cursor, so a failing sink neither
+// holds back the others nor causes them to see an event twice. Events
+// are published to a sink in order and at least once; an event is only
+// published again if the relay stops after publishing it but before
+// recording that it did. Any number of relays may run against the same
+// database, as only one at a time publishes to each sink
+type OutboxRelay struct {
+	db        *sqlx.DB
+	sinks     map[string]realtime.Publisher
+	onError   func(error)
+	interval  time.Duration
+	batch     int
+	retention time.Duration
+
+	done    chan struct{}
+	stopped chan struct{}
+	once    sync.Once
+}
+
+// NewOutboxRelay returns a newly constructed OutboxRelay that publishes
+// to the named sinks, calling onError with errors publishing to a sink.
+// Names identify the cursor of a sink and must not change across restarts
+func NewOutboxRelay(db *sqlx.DB, sinks map[string]realtime.Publisher, onError func(error)) *OutboxRelay {
+	return &OutboxRelay{
+		db:        db,
+		sinks:     sinks,
+		onError:   onError,
+		interval:  250 * time.Millisecond,
+		batch:     100,
+		retention: 24 * time.Hour,
+		done:      make(chan struct{}),
+		stopped:   make(chan struct{}),
+	}
+}
+
+// Run relays events until Stop is called, pruning events
+// relayed to every sink once they are older than a day
+func (r *OutboxRelay) Run() {
+	defer close(r.stopped)
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	var pruned time.Time
+	for {
+		select {
+		case <-r.done:
+			return
+		case <-ticker.C:
+			for sink := range r.sinks {
+				// keep going while there is a backlog
+				for {
+					n, err := r.Relay(sink)
+					if err != nil {
+						r.onError(fmt.Errorf("relaying events to %s: %s", sink, err))
+					}
+					if n < r.batch || r.stopping() {
+						break
+					}
+				}
+			}
+			if time.Since(pruned) > time.Minute {
+				if err := r.Prune(); err != nil {
+					r.onError(err)
+				}
+				pruned = time.Now()
+			}
+		}
+	}
+}
+
+// Stop signals Run to return once the events
+// being relayed are published
+func (r *OutboxRelay) Stop() {
+	r.once.Do(func() {
+		close(r.done)
+	})
+}
+
+func (r *OutboxRelay) stopping() bool {
+	select {
+	case <-r.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Wait waits for Run to return after Stop is called
+func (r *OutboxRelay) Wait() {
+	<-r.stopped
+}
+
+// Relay publishes the next batch of events to the named sink, returning
+// the number of events published. Publishing stops at the first event
+// the sink fails to publish, which is retried by the next call. No
+// events are published if another relay is publishing to the sink
+func (r *OutboxRelay) Relay(sink string) (int, error) {
+	pub, ok := r.sinks[sink]
+	if !ok {
+		return 0, nil
+	}
+	if _, err := r.db.Exec(createOutboxCursorSQL, sink); err != nil {
+		return 0, data.NewError(err)
+	}
+
+	published := 0
+	var pubErr error
+	err := inTx(r.db, func(tx *sqlx.Tx) error {
+		var locked bool
+		if err := tx.Get(&locked, lockOutboxCursorSQL, sink); err != nil || !locked {
+			return err
+		}
+		var cursor struct {
+			TxID    int64
+			EventID int64
+		}
+		if err := tx.Get(&cursor, getOutboxCursorSQL, sink); err != nil {
+			return err
+		}
+		var events []outboxEvent
+		if err := tx.Select(&events, getEventsAfterSQL, cursor.TxID, cursor.EventID, r.batch); err != nil {
+			return err
+		}
+		for _, e := range events {
+			pubErr = pub.Publish(realtime.Event{Type: e.Type, ID: e.EntityID, UserID: e.UserID, EventID: e.ID})
+			if pubErr != nil {
+				break
+			}
+			cursor.TxID, cursor.EventID = e.TxID, e.ID
+			published++
+		}
+		if published == 0 {
+			return nil
+		}
+		_, err := tx.Exec(updateOutboxCursorSQL, cursor.TxID, cursor.EventID, sink)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return published, pubErr
+}
+
+// Prune deletes events older than the retention period
+// that have been relayed to every sink
+func (r *OutboxRelay) Prune() error {
+	sinks := make([]string, 0, len(r.sinks))
+	for sink := range r.sinks {
+		sinks = append(sinks, sink)
+	}
+	_, err := r.db.Exec(pruneOutboxSQL, int64(r.retention/time.Millisecond), pq.Array(sinks))
+	if err != nil {
+		return data.NewError(err)
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package postgres
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/boxtown/gotag"
+	"github.com/boxtown/meirl/data"
+	"github.com/boxtown/meirl/data/datatest"
+	"github.com/boxtown/meirl/realtime"
+	"github.com/jmoiron/sqlx"
+)
+
+// recordingPublisher records published events, failing
+// once err is set
+type recordingPublisher struct {
+	events []realtime.Event
+	err    error
+}
+
+func (p *recordingPublisher) Publish(e realtime.Event) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.events = append(p.events, e)
+	return nil
+}
+
+func TestOutboxRelaysStoreEvents(t *testing.T) {
+	gotag.Test(gotag.Integration, t, func(t gotag.T) {
+		whileConnectedToTestDb(testDbName, func(db *sqlx.DB) error {
+			defer cleanupOutboxTest(t, db)
+
+			ok, failing := &recordingPublisher{}, &recordingPublisher{err: errors.New("unavailable")}
+			relay := NewOutboxRelay(db, map[string]realtime.Publisher{"ok": ok, "failing": failing}, nil)
+			// sinks start after the latest event
+			for _, sink := range []string{"ok", "failing"} {
+				if _, err := relay.Relay(sink); err != nil {
+					t.Error(err.Error())
+					t.FailNow()
+				}
+			}
+
+			ids, err := populateNotificationUsers(t, db, 2)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			users, posts := NewUserStore(db), NewPostStore(db)
+			postID, err := posts.Create(datatest.ExamplePost(ids[0]))
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			steps := []error{
+				users.Follow(ids[1], ids[0]),
+				posts.React(postID, ids[1], data.ReactionKek),
+				// reacting again changes nothing and records no event
+				posts.React(postID, ids[1], data.ReactionKek),
+				posts.Delete(postID),
+				// nor does deleting a post that no longer exists
+				posts.Delete(postID),
+			}
+			for _, err := range steps {
+				if err != nil {
+					t.Error(err.Error())
+					t.FailNow()
+				}
+			}
+
+			n, err := relay.Relay("ok")
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			expected := []realtime.Event{
+				{Type: realtime.UserCreated, ID: ids[0], UserID: ids[0]},
+				{Type: realtime.UserCreated, ID: ids[1], UserID: ids[1]},
+				{Type: realtime.PostCreated, ID: postID, UserID: ids[0]},
+				{Type: realtime.UserFollowed, ID: ids[0], UserID: ids[1]},
+				{Type: realtime.PostReactionsChanged, ID: postID, UserID: ids[1]},
+				{Type: realtime.PostDeleted, ID: postID, UserID: ids[0]},
+			}
+			if n != len(expected) || len(ok.events) != len(expected) {
+				t.Errorf("Expected %d events, got %v", len(expected), ok.events)
+				t.FailNow()
+			}
+			for i, e := range expected {
+				got := ok.events[i]
+				// events carry increasing outbox ids
+				if got.EventID == 0 || i > 0 && got.EventID <= ok.events[i-1].EventID {
+					t.Errorf("Expected event %d to carry an increasing outbox id, got %d", i, got.EventID)
+					t.Fail()
+				}
+				got.EventID = 0
+				if got != e {
+					t.Errorf("Expected event %d to be %v, got %v", i, e, got)
+					t.Fail()
+				}
+			}
+
+			// relayed events are not relayed again
+			n, err = relay.Relay("ok")
+			if err != nil || n != 0 {
+				t.Errorf("Expected no events to be relayed again, got %d and %v", n, err)
+				t.Fail()
+			}
+
+			// a failing sink keeps its place without affecting others
+			_, err = relay.Relay("failing")
+			if err == nil {
+				t.Error("Expected the failing sink to return an error")
+				t.Fail()
+			}
+			failing.err = nil
+			n, err = relay.Relay("failing")
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			if n != len(expected) || failing.events[0] != ok.events[0] {
+				t.Errorf("Expected the recovered sink to receive every event, got %v", failing.events)
+				t.Fail()
+			}
+			return nil
+		})
+	})
+}
+
+func TestFailedTransactionRecordsNoEvent(t *testing.T) {
+	gotag.Test(gotag.Integration, t, func(t gotag.T) {
+		whileConnectedToTestDb(testDbName, func(db *sqlx.DB) error {
+			defer cleanupOutboxTest(t, db)
+
+			pub := &recordingPublisher{}
+			relay := NewOutboxRelay(db, map[string]realtime.Publisher{"test": pub}, nil)
+			if _, err := relay.Relay("test"); err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			ids, err := populateNotificationUsers(t, db, 2)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			users := NewUserStore(db)
+			if err := users.Follow(ids[0], ids[1]); err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			// following twice violates the followers primary key
+			if err := users.Follow(ids[0], ids[1]); err == nil {
+				t.Error("Expected following twice to fail")
+				t.FailNow()
+			}
+
+			if _, err := relay.Relay("test"); err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			follows := 0
+			for _, e := range pub.events {
+				if e.Type == realtime.UserFollowed {
+					follows++
+				}
+			}
+			if follows != 1 {
+				t.Errorf("Expected a single follow event, got %d", follows)
+				t.Fail()
+			}
+			return nil
+		})
+	})
+}
+
+func cleanupOutboxTest(t gotag.T, db *sqlx.DB) {
+	_, err := db.Exec("DELETE FROM outbox_cursors")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	_, err = db.Exec("DELETE FROM outbox")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	cleanupPostStoreTest(t, db)
+}
@@ -2,14 +2,41 @@ package postgres
 
 import (
 	"bytes"
+	"math"
 	"strconv"
+	"time"
+
+	"github.com/boxtown/meirl/data"
 )
 
-// seek-based paginator
+// seek-based paginator. Fields that are not unique are
+// paginated together with a unique tiebreak field, in
+// which case markers are tiedMarkers
 type paginator struct {
-	field string
-	desc  bool
-	limit int
+	field    string
+	tiebreak string
+	desc     bool
+	limit    int
+}
+
+// tiedMarker is a marker of both the field
+// and the tiebreak field of a paginator
+type tiedMarker struct {
+	value    interface{}
+	tiebreak interface{}
+}
+
+// paginate builds the paginated query and its arguments from the given
+// query and arguments. Seeks past the marker unless the marker is empty,
+// in which case the query starts from the first page
+func (p *paginator) paginate(query string, skipWhere bool, marker interface{}, args ...interface{}) (string, []interface{}) {
+	if isEmptyMarker(marker) {
+		return p.orderedQuery(query), args
+	}
+	if m, ok := marker.(tiedMarker); ok {
+		return p.seekingQuery(query, len(args)+1, skipWhere), append(args, m.value, m.tiebreak)
+	}
+	return p.seekingQuery(query, len(args)+1, skipWhere), append(args, marker)
 }
 
 // Build builds the paginated query from the given query
@@ -20,21 +47,68 @@ func (p *paginator) seekingQuery(query string, pIdx int, skipWhere bool) string
 	} else {
 		buf.WriteString(" WHERE ")
 	}
-	buf.WriteString(p.field)
+	if p.tiebreak != "" {
+		buf.WriteString("(" + p.field + ", " + p.tiebreak + ")")
+	} else {
+		buf.WriteString(p.field)
+	}
 	if p.desc {
 		buf.WriteString(" < ")
 	} else {
 		buf.WriteString(" > ")
 	}
-	buf.WriteRune('$')
-	buf.WriteString(strconv.Itoa(pIdx))
+	if p.tiebreak != "" {
+		buf.WriteString("($" + strconv.Itoa(pIdx) + ", $" + strconv.Itoa(pIdx+1) + ")")
+	} else {
+		buf.WriteRune('$')
+		buf.WriteString(strconv.Itoa(pIdx))
+	}
+	p.writeOrder(buf)
+	return buf.String()
+}
+
+// orderedQuery builds the first page of the given query
+func (p *paginator) orderedQuery(query string) string {
+	buf := bytes.NewBufferString(query)
+	p.writeOrder(buf)
+	return buf.String()
+}
+
+func (p *paginator) writeOrder(buf *bytes.Buffer) {
+	direction := " ASC"
+	if p.desc {
+		direction = " DESC"
+	}
 	buf.WriteString(" ORDER BY ")
 	buf.WriteString(p.field)
-	if p.desc {
-		buf.WriteString(" DESC LIMIT ")
-	} else {
-		buf.WriteString(" ASC LIMIT ")
+	buf.WriteString(direction)
+	if p.tiebreak != "" {
+		buf.WriteString(", " + p.tiebreak + direction)
 	}
+	buf.WriteString(" LIMIT ")
 	buf.WriteString(strconv.Itoa(p.limit))
-	return buf.String()
+}
+
+// timeSeek returns the marker of a paginator by time with an id
+// tiebreak. A data.TimeMarker seeks past the entity it marks while
+// a time seeks past every entity at that time
+func timeSeek(marker interface{}, desc bool) interface{} {
+	switch m := marker.(type) {
+	case data.TimeMarker:
+		return tiedMarker{m.Time, m.ID}
+	case time.Time:
+		// ids are positive integer columns
+		if desc {
+			return tiedMarker{m, 0}
+		}
+		return tiedMarker{m, math.MaxInt32}
+	}
+	return marker
+}
+
+func isEmptyMarker(marker interface{}) bool {
+	if s, ok := marker.(string); ok {
+		return s == ""
+	}
+	return marker == nil
 }
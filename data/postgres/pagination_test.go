@@ -52,3 +52,62 @@ func TestSeekingPaginationBuild(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestPaginate(t *testing.T) {
+	p := paginator{field: "test", limit: 10}
+
+	query, args := p.paginate("SELECT FROM posts WHERE id=$1", true, "", 1)
+	expected := "SELECT FROM posts WHERE id=$1 ORDER BY test ASC LIMIT 10"
+	if query != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, query)
+		t.Fail()
+	}
+	if len(args) != 1 {
+		t.Errorf("Expected 1 argument without a marker, got %d", len(args))
+		t.Fail()
+	}
+
+	query, args = p.paginate("SELECT FROM posts WHERE id=$1", true, 5, 1)
+	expected = "SELECT FROM posts WHERE id=$1 AND test > $2 ORDER BY test ASC LIMIT 10"
+	if query != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, query)
+		t.Fail()
+	}
+	if len(args) != 2 || args[1] != 5 {
+		t.Errorf("Expected marker as the last argument, got %v", args)
+		t.Fail()
+	}
+
+	query, _ = p.paginate("SELECT FROM posts", false, nil)
+	expected = "SELECT FROM posts ORDER BY test ASC LIMIT 10"
+	if query != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, query)
+		t.Fail()
+	}
+}
+
+func TestPaginateWithTiebreak(t *testing.T) {
+	p := paginator{field: "score", tiebreak: "id", desc: true, limit: 10}
+
+	query, args := p.paginate("SELECT FROM scored", false, nil, 1)
+	expected := "SELECT FROM scored ORDER BY score DESC, id DESC LIMIT 10"
+	if query != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, query)
+		t.Fail()
+	}
+	if len(args) != 1 {
+		t.Errorf("Expected 1 argument without a marker, got %d", len(args))
+		t.Fail()
+	}
+
+	query, args = p.paginate("SELECT FROM scored", false, tiedMarker{0.5, int64(7)}, 1)
+	expected = "SELECT FROM scored WHERE (score, id) < ($2, $3) ORDER BY score DESC, id DESC LIMIT 10"
+	if query != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, query)
+		t.Fail()
+	}
+	if len(args) != 3 || args[1] != 0.5 || args[2] != int64(7) {
+		t.Errorf("Expected both markers as the last arguments, got %v", args)
+		t.Fail()
+	}
+}
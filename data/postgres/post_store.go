@@ -2,29 +2,72 @@ package postgres
 
 import (
 	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/boxtown/meirl/data"
+	"github.com/boxtown/meirl/realtime"
 	"github.com/jmoiron/sqlx"
 )
 
 // PostStore is a PostgreSQL specific implementation
 // of data.PostStore
 type PostStore struct {
-	db *sqlx.DB
+	db   *sqlx.DB
+	feed postList
 }
 
+// postList describes a list of posts: the columns selected in
+// addition to those of a post, the FROM clause selecting the posts,
+// which is parameterized by a user id or tag, and the time they are listed
+// by when sorted by date
+type postList struct {
+	columns   string
+	from      string
+	timeField string
+}
+
+// Lists of the posts of a user, mentioning a user and tagged with a tag
+var (
+	userPostsList    = postList{from: postsByUserIDSQL, timeField: "posts.created_at"}
+	userMentionsList = postList{from: mentionsByUserIDSQL + fmt.Sprintf(visiblePostsSQL, 1), timeField: "posts.created_at"}
+	taggedPostsList  = postList{from: postsByTagSQL, timeField: "posts.created_at"}
+)
+
 // NewPostStore returns a newly constructed PostStore
-// with the given database reference
+// with the given database reference. Feeds are retrieved
+// by joining the posts of every followee
 func NewPostStore(db *sqlx.DB) *PostStore {
-	return &PostStore{db}
+	return &PostStore{db, postList{feedEntryColumnsSQL, feedByUserIDSQL, feedEntryTimeField}}
 }
 
-// Create creates a record for the given post in Postgres
+// NewTimelinePostStore returns a newly constructed PostStore with the
+// given database reference that retrieves feeds from the timelines
+// maintained by a TimelineFanout. Posts appear in feeds once fanned out
+func NewTimelinePostStore(db *sqlx.DB) *PostStore {
+	return &PostStore{db, postList{feedEntryColumnsSQL, timelineByUserIDSQL, feedEntryTimeField}}
+}
+
+// Create creates a record for the given post and its entities
+// in Postgres. data.ErrNoEnt is returned if the post replies to
+// or quotes a post that does not exist
 func (store *PostStore) Create(post *data.Post) (int64, error) {
 	var id int64
-	err := store.db.Get(&id, createPostSQL, post.AuthorID, post.Contents)
+	err := inTx(store.db, func(tx *sqlx.Tx) error {
+		err := tx.Get(&id, createPostSQL, post.AuthorID, post.Contents, post.ParentID, post.QuoteID)
+		if err == sql.ErrNoRows {
+			return data.ErrNoEnt
+		} else if err != nil {
+			return err
+		}
+		if err := insertEntities(tx, id, post.Entities); err != nil {
+			return err
+		}
+		return recordEvent(tx, realtime.Event{Type: realtime.PostCreated, ID: id, UserID: post.AuthorID})
+	})
 	if err != nil {
-		return 0, data.NewError(err)
+		return 0, err
 	}
 	return id, nil
 }
@@ -42,20 +85,67 @@ func (store *PostStore) Get(id int64) (*data.Post, error) {
 	return &p, nil
 }
 
-// Update updates a post by id
-func (store *PostStore) Update(id int64, contents []byte) error {
-	_, err := store.db.Exec(updatePostSQL, contents, id)
-	if err != nil {
-		return data.NewError(err)
+// Update updates the contents of a post by id, replacing
+// its entities with the given entities of the new contents
+func (store *PostStore) Update(id int64, contents []byte, entities []data.PostEntity) error {
+	replace := func(tx *sqlx.Tx) error {
+		if _, err := tx.Exec(deleteMentionsSQL, id); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(deleteHashtagsSQL, id); err != nil {
+			return err
+		}
+		return insertEntities(tx, id, entities)
 	}
-	return nil
+	return store.changePost(realtime.PostUpdated, id, replace, updatePostSQL, contents, id)
 }
 
 // Delete deletes a post by id
 func (store *PostStore) Delete(id int64) error {
-	_, err := store.db.Exec(deletePostSQL, id)
-	if err != nil {
-		return data.NewError(err)
+	return store.changePost(realtime.PostDeleted, id, nil, deletePostSQL, id)
+}
+
+// changePost executes a query returning the author of the changed
+// post, then calls changed if it is not nil and records an event of
+// the given type in the same transaction if the post exists
+func (store *PostStore) changePost(
+	eventType string,
+	id int64,
+	changed func(tx *sqlx.Tx) error,
+	query string,
+	args ...interface{}) error {
+	return inTx(store.db, func(tx *sqlx.Tx) error {
+		var authorID int64
+		err := tx.Get(&authorID, query, args...)
+		if err == sql.ErrNoRows {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if changed != nil {
+			if err := changed(tx); err != nil {
+				return err
+			}
+		}
+		return recordEvent(tx, realtime.Event{Type: eventType, ID: id, UserID: authorID})
+	})
+}
+
+// insertEntities inserts the entities of the post with the given id
+func insertEntities(tx *sqlx.Tx, postID int64, entities []data.PostEntity) error {
+	for _, entity := range entities {
+		var err error
+		switch entity.Type {
+		case data.EntityMention:
+			_, err = tx.Exec(insertMentionSQL, postID, entity.Start, entity.End, entity.UserID)
+		case data.EntityHashtag:
+			_, err = tx.Exec(insertHashtagSQL, postID, entity.Start, entity.End, entity.Text)
+		default:
+			err = fmt.Errorf("unknown entity type %q", entity.Type)
+		}
+		if err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -63,36 +153,255 @@ func (store *PostStore) Delete(id int64) error {
 // UserPosts returns the posts for the user with
 // the given id
 func (store *PostStore) UserPosts(userID int64, options data.ListOptions, sort data.PostSortMethod) ([]data.Post, error) {
+	return store.list(userPostsList, userID, options, sort)
+}
+
+// Feed retrieves the post feed for the user with the given id,
+// including the posts reposted by the user and the users they follow.
+// Reposted posts are attributed to their latest repost and listed
+// by its time when sorted by date
+func (store *PostStore) Feed(userID int64, options data.ListOptions, sort data.PostSortMethod) ([]data.Post, error) {
+	return store.list(store.feed, userID, options, sort)
+}
+
+// Mentions retrieves the posts mentioning the user with the given id
+func (store *PostStore) Mentions(userID int64, options data.ListOptions, sort data.PostSortMethod) ([]data.Post, error) {
+	return store.list(userMentionsList, userID, options, sort)
+}
+
+// TaggedPosts retrieves the posts tagged with the given
+// lowercase tag
+func (store *PostStore) TaggedPosts(tag string, options data.ListOptions, sort data.PostSortMethod) ([]data.Post, error) {
+	return store.list(taggedPostsList, tag, options, sort)
+}
+
+// list lists the posts of the list for the given user id or tag
+func (store *PostStore) list(list postList, param interface{}, options data.ListOptions, sort data.PostSortMethod) ([]data.Post, error) {
 	if options.Limit <= 10 || options.Limit > 1000 {
 		options.Limit = 10
 	}
-	paginator := createPostsPaginator(options, sort)
-	query := paginator.seekingQuery(getPostsByUserIDSQL, 2, true)
+	if sort == data.PostSortByScore {
+		return store.listByScore(list, param, options)
+	}
+	paginator := createPostsPaginator(options, sort, list.timeField)
+	marker := options.Marker
+	if sort == data.PostSortByDate {
+		marker = timeSeek(marker, options.Desc)
+	}
+	query, args := paginator.paginate(selectPostSQL+list.columns+list.from, true, marker, param)
 	var posts []data.Post
-	err := store.db.Select(&posts, query, userID, options.Marker)
+	err := store.db.Select(&posts, query, args...)
 	if err != nil {
 		return nil, data.NewError(err)
 	}
+	if sort == data.PostSortByDate {
+		markByTime(posts)
+	}
 	return posts, nil
 }
 
-// Feed retrieves the post feed for the user with
-// the given id
-func (store *PostStore) Feed(userID int64, options data.ListOptions, sort data.PostSortMethod) ([]data.Post, error) {
+// markByTime gives every post listed by date the marker of the
+// page following it, at the time of the repost that put the post
+// in a feed if any
+func markByTime(posts []data.Post) {
+	for i, p := range posts {
+		at := p.CreatedAt.Time
+		if p.RepostedAt != nil {
+			at = p.RepostedAt.Time
+		}
+		posts[i].Marker = data.TimeMarker{Time: at, ID: p.ID}.String()
+	}
+}
+
+// scoredPost is a post and its score
+type scoredPost struct {
+	data.Post
+	Score float64
+}
+
+// listByScore lists the posts of the list by score as of the time of
+// the marker, or now if the marker is empty. Every post is given the
+// marker of the page following it
+func (store *PostStore) listByScore(list postList, param interface{}, options data.ListOptions) ([]data.Post, error) {
+	marker := data.ScoreMarker{AsOf: time.Now()}
+	var seek interface{}
+	if !isEmptyMarker(options.Marker) {
+		var ok bool
+		marker, ok = options.Marker.(data.ScoreMarker)
+		if !ok {
+			return nil, data.NewError(fmt.Errorf("posts sorted by score require a score marker, got %v", options.Marker))
+		}
+		seek = tiedMarker{marker.Score, marker.ID}
+	}
+	// the marker is encoded in microseconds, as stored by Postgres
+	asOf := marker.AsOf.Truncate(time.Microsecond)
+	paginator := paginator{
+		field:    "score",
+		tiebreak: "id",
+		desc:     options.Desc,
+		limit:    options.Limit,
+	}
+	asOfSQL := selectPostAsOfSQL + list.columns + list.from + " AND " + list.timeField + " <= $2"
+	query, args := paginator.paginate(fmt.Sprintf(scoredPostsSQL, asOfSQL), false, seek, param, asOf)
+	var scored []scoredPost
+	err := store.db.Select(&scored, query, args...)
+	if err != nil {
+		return nil, data.NewError(err)
+	}
+	posts := make([]data.Post, len(scored))
+	for i, p := range scored {
+		posts[i] = p.Post
+		posts[i].Marker = data.ScoreMarker{AsOf: asOf, Score: p.Score, ID: p.ID}.String()
+	}
+	return posts, nil
+}
+
+// Ancestors retrieves at most limit of the nearest ancestors of the
+// post with the given id, starting from the post that started the
+// thread. Ancestors that were deleted end the thread early, and
+// ancestors the viewer may not see are omitted
+func (store *PostStore) Ancestors(id, viewerID int64, limit int) ([]data.Post, error) {
+	var posts []data.Post
+	query := fmt.Sprintf(getAncestorsSQL, fmt.Sprintf(visiblePostsSQL, 3))
+	err := store.db.Select(&posts, query, id, limit, viewerID)
+	if err != nil {
+		return nil, data.NewError(err)
+	}
+	for i, j := 0, len(posts)-1; i < j; i, j = i+1, j-1 {
+		posts[i], posts[j] = posts[j], posts[i]
+	}
+	return posts, nil
+}
+
+// Descendants retrieves a page of the replies to the post with the
+// given id, by id, and their replies down to the given depth, by depth
+// and then id in the same order. At most maxNodes posts are returned, so replies deeper
+// in large threads may be missing. Replies the viewer may not see are
+// omitted along with their replies
+func (store *PostStore) Descendants(id, viewerID int64, depth, maxNodes int, options data.ListOptions) ([]data.Post, error) {
 	if options.Limit <= 10 || options.Limit > 1000 {
 		options.Limit = 10
 	}
-	paginator := createPostsPaginator(options, sort)
-	query := paginator.seekingQuery(getFeedByUserIDSQL, 2, true)
+	paginator := paginator{
+		field: "posts.id",
+		desc:  options.Desc,
+		limit: options.Limit,
+	}
+	visible := fmt.Sprintf(visiblePostsSQL, 2)
+	page, args := paginator.paginate(getRepliesSQL+" AND "+visible, true, options.Marker, id, viewerID)
+	args = append(args, depth)
+	direction := "ASC"
+	if options.Desc {
+		direction = "DESC"
+	}
+	query := fmt.Sprintf(getDescendantsSQL, page, len(args), visible, direction, maxNodes)
 	var posts []data.Post
-	err := store.db.Select(&posts, query, userID, options.Marker)
+	err := store.db.Select(&posts, query, args...)
 	if err != nil {
 		return nil, data.NewError(err)
 	}
 	return posts, nil
 }
 
-func createPostsPaginator(options data.ListOptions, sort data.PostSortMethod) *paginator {
+// Recent retrieves the posts of every public user by date. Every
+// post is given the marker of the page following it
+func (store *PostStore) Recent(options data.ListOptions) ([]data.Post, error) {
+	if options.Limit <= 10 || options.Limit > 1000 {
+		options.Limit = 10
+	}
+	paginator := createPostsPaginator(options, data.PostSortByDate, "posts.created_at")
+	query, args := paginator.paginate(getRecentPostsSQL, true, timeSeek(options.Marker, options.Desc))
+	var posts []data.Post
+	err := store.db.Select(&posts, query, args...)
+	if err != nil {
+		return nil, data.NewError(err)
+	}
+	markByTime(posts)
+	return posts, nil
+}
+
+// rankedPost is a post and its trending rank
+type rankedPost struct {
+	data.Post
+	Rank int64
+}
+
+// Trending retrieves the trending posts of the window as of the last
+// aggregation by a TrendingAggregator, by rank. The marker is the rank
+// after which to start, and every post is given its rank as the marker
+// of the following page
+func (store *PostStore) Trending(window data.TrendingWindow, options data.ListOptions) ([]data.Post, error) {
+	if options.Limit <= 10 || options.Limit > 1000 {
+		options.Limit = 10
+	}
+	paginator := paginator{
+		field: "trending_posts.rank",
+		limit: options.Limit,
+	}
+	query, args := paginator.paginate(getTrendingPostsSQL, true, options.Marker, string(window))
+	var ranked []rankedPost
+	err := store.db.Select(&ranked, query, args...)
+	if err != nil {
+		return nil, data.NewError(err)
+	}
+	posts := make([]data.Post, len(ranked))
+	for i, p := range ranked {
+		posts[i] = p.Post
+		posts[i].Marker = strconv.FormatInt(p.Rank, 10)
+	}
+	return posts, nil
+}
+
+// React idempotently records the reaction of the user
+// to the post
+func (store *PostStore) React(postID, userID int64, reaction data.Reaction) error {
+	query := kekPostSQL
+	if reaction == data.ReactionNo {
+		query = noPostSQL
+	}
+	return store.changeByUser(realtime.PostReactionsChanged, postID, userID, query)
+}
+
+// UnReact idempotently removes the reaction of the user
+// to the post
+func (store *PostStore) UnReact(postID, userID int64, reaction data.Reaction) error {
+	query := unKekPostSQL
+	if reaction == data.ReactionNo {
+		query = unNoPostSQL
+	}
+	return store.changeByUser(realtime.PostReactionsChanged, postID, userID, query)
+}
+
+// Repost idempotently records the repost of the post by the user
+func (store *PostStore) Repost(postID, userID int64) error {
+	return store.changeByUser(realtime.PostReposted, postID, userID, repostSQL)
+}
+
+// UnRepost idempotently removes the repost of the post by the user
+func (store *PostStore) UnRepost(postID, userID int64) error {
+	return store.changeByUser(realtime.PostUnReposted, postID, userID, unRepostSQL)
+}
+
+// changeByUser executes a query parameterized by the post and user
+// ids, recording an event of the given type in the same transaction
+// if any rows changed
+func (store *PostStore) changeByUser(eventType string, postID, userID int64, query string) error {
+	return inTx(store.db, func(tx *sqlx.Tx) error {
+		result, err := tx.Exec(query, postID, userID)
+		if err != nil {
+			return err
+		}
+		n, err := result.RowsAffected()
+		if err != nil || n == 0 {
+			return err
+		}
+		return recordEvent(tx, realtime.Event{Type: eventType, ID: postID, UserID: userID})
+	})
+}
+
+// createPostsPaginator returns a paginator of posts in the given sort,
+// listing them by the time field when sorted by date
+func createPostsPaginator(options data.ListOptions, sort data.PostSortMethod, timeField string) *paginator {
 	paginator := paginator{
 		limit: options.Limit,
 		desc:  options.Desc,
@@ -102,8 +411,11 @@ func createPostsPaginator(options data.ListOptions, sort data.PostSortMethod) *p
 		paginator.field = "keks"
 	case data.PostSortByNos:
 		paginator.field = "nos"
+	case data.PostSortByID:
+		paginator.field = "posts.id"
 	default:
-		paginator.field = "posts.created_at"
+		paginator.field = timeField
+		paginator.tiebreak = "posts.id"
 	}
 	return &paginator
 }
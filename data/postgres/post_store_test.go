@@ -131,7 +131,7 @@ func TestUpdatePost(t *testing.T) {
 				t.FailNow()
 			}
 			check.Contents = []byte("updated")
-			err = store.Update(id, check.Contents)
+			err = store.Update(id, check.Contents, nil)
 			if err != nil {
 				t.Error(err.Error())
 				t.FailNow()
@@ -156,7 +156,7 @@ func TestUpdatePostIsIdempotent(t *testing.T) {
 			defer cleanupPostStoreTest(t, db)
 
 			store := NewPostStore(db)
-			err := store.Update(3, []byte("updated"))
+			err := store.Update(3, []byte("updated"), nil)
 			if err != nil {
 				t.Error(err.Error())
 				t.Fail()
@@ -213,6 +213,63 @@ func TestDeletePostIsIdempotent(t *testing.T) {
 	})
 }
 
+func TestReactToPost(t *testing.T) {
+	gotag.Test(gotag.Integration, t, func(t gotag.T) {
+		whileConnectedToTestDb(testDbName, func(db *sqlx.DB) error {
+			defer cleanupPostStoreTest(t, db)
+
+			userID, err := populateUsersTable(t, db, datatest.ExampleUser())
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			store := NewPostStore(db)
+			postID, err := store.Create(datatest.ExamplePost(userID))
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			for i := 0; i < 2; i++ {
+				err = store.React(postID, userID, data.ReactionKek)
+				if err != nil {
+					t.Error(err.Error())
+					t.FailNow()
+				}
+			}
+			err = store.React(postID, userID, data.ReactionNo)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			post, err := store.Get(postID)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			if post.Keks != 1 || post.Nos != 1 {
+				t.Errorf("Expected 1 kek and 1 no, got %d and %d", post.Keks, post.Nos)
+				t.Fail()
+			}
+
+			err = store.UnReact(postID, userID, data.ReactionKek)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			post, err = store.Get(postID)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			if post.Keks != 0 || post.Nos != 1 {
+				t.Errorf("Expected 0 keks and 1 no, got %d and %d", post.Keks, post.Nos)
+				t.Fail()
+			}
+			return nil
+		})
+	})
+}
+
 func TestGetUserPosts(t *testing.T) {
 	gotag.Test(gotag.Integration, t, func(t gotag.T) {
 		whileConnectedToTestDb(testDbName, func(db *sqlx.DB) error {
@@ -303,6 +360,61 @@ func TestGetPostFeed(t *testing.T) {
 	})
 }
 
+func TestPagePostsSharingATime(t *testing.T) {
+	gotag.Test(gotag.Integration, t, func(t gotag.T) {
+		whileConnectedToTestDb(testDbName, func(db *sqlx.DB) error {
+			defer cleanupPostStoreTest(t, db)
+
+			authorID, err := populateUsersTable(t, db, datatest.ExampleUser())
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			store := NewPostStore(db)
+			for i := 0; i < 15; i++ {
+				if _, err := store.Create(datatest.ExamplePost(authorID)); err != nil {
+					t.Error(err.Error())
+					t.FailNow()
+				}
+			}
+			if _, err := db.Exec("UPDATE posts SET created_at='2017-01-01 00:00:00.5+00'"); err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+
+			seen := make(map[int64]bool)
+			options := data.ListOptions{Desc: true}
+			for page := 0; page < 2; page++ {
+				posts, err := store.UserPosts(authorID, options, data.PostSortByDate)
+				if err != nil {
+					t.Error(err.Error())
+					t.FailNow()
+				}
+				for _, p := range posts {
+					if seen[p.ID] {
+						t.Errorf("Expected post %d once", p.ID)
+						t.Fail()
+					}
+					seen[p.ID] = true
+				}
+				if len(posts) == 0 {
+					break
+				}
+				options.Marker, err = data.ParseTimeMarker(posts[len(posts)-1].Marker)
+				if err != nil {
+					t.Error(err.Error())
+					t.FailNow()
+				}
+			}
+			if len(seen) != 15 {
+				t.Errorf("Expected 15 posts over two pages, got %d", len(seen))
+				t.Fail()
+			}
+			return nil
+		})
+	})
+}
+
 func populateUsersTable(t gotag.T, db *sqlx.DB, user *data.User) (int64, error) {
 	store := NewUserStore(db)
 	return store.Create(user)
@@ -337,6 +449,390 @@ func populateNosTable(t gotag.T, db *sqlx.DB, nos int, authorID, postID int64) e
 	return nil
 }
 
+func TestGetPostFeedByScore(t *testing.T) {
+	gotag.Test(gotag.Integration, t, func(t gotag.T) {
+		whileConnectedToTestDb(testDbName, func(db *sqlx.DB) error {
+			defer cleanupPostStoreTest(t, db)
+
+			ids, err := populateNotificationUsers(t, db, 2)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			store := NewPostStore(db)
+			// an old post with keks, a newer post with more nos than
+			// keks and a newer post without reactions
+			postIDs := make([]int64, 3)
+			for i := range postIDs {
+				postIDs[i], err = store.Create(datatest.ExamplePost(ids[0]))
+				if err != nil {
+					t.Error(err.Error())
+					t.FailNow()
+				}
+			}
+			_, err = db.Exec("UPDATE posts SET created_at=now() - interval '2 days' WHERE id=$1", postIDs[0])
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			reactions := []error{
+				store.React(postIDs[0], ids[0], data.ReactionKek),
+				store.React(postIDs[0], ids[1], data.ReactionKek),
+				store.React(postIDs[1], ids[1], data.ReactionNo),
+			}
+			for _, err := range reactions {
+				if err != nil {
+					t.Error(err.Error())
+					t.FailNow()
+				}
+			}
+
+			options := data.ListOptions{Desc: true, Limit: 10}
+			all, err := store.Feed(ids[0], options, data.PostSortByScore)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			// the post with nos scores as if unreacted and the old post decays
+			expected := []int64{postIDs[2], postIDs[1], postIDs[0]}
+			if len(all) != len(expected) {
+				t.Errorf("Expected %d posts, got %d", len(expected), len(all))
+				t.FailNow()
+			}
+			for i, id := range expected {
+				if all[i].ID != id {
+					t.Errorf("Expected post %d at %d, got %d", id, i, all[i].ID)
+					t.Fail()
+				}
+			}
+
+			// later reactions do not affect the next page
+			if err := store.React(postIDs[0], ids[1], data.ReactionNo); err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			marker, err := data.ParseScoreMarker(all[0].Marker)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			options.Marker = marker
+			rest, err := store.Feed(ids[0], options, data.PostSortByScore)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			if len(rest) != 2 || rest[0].ID != postIDs[1] || rest[1].ID != postIDs[0] {
+				t.Errorf("Expected the remaining posts after the marker, got %v", rest)
+				t.Fail()
+			}
+			if len(rest) == 2 && rest[1].Keks != 2 {
+				t.Errorf("Expected reactions as of the marker, got %d keks", rest[1].Keks)
+				t.Fail()
+			}
+			return nil
+		})
+	})
+}
+
+func TestPostThreads(t *testing.T) {
+	gotag.Test(gotag.Integration, t, func(t gotag.T) {
+		whileConnectedToTestDb(testDbName, func(db *sqlx.DB) error {
+			defer cleanupPostStoreTest(t, db)
+
+			authorID, err := populateUsersTable(t, db, datatest.ExampleUser())
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			store := NewPostStore(db)
+			reply := func(parentID int64) int64 {
+				post := datatest.ExamplePost(authorID)
+				post.ParentID = parentID
+				id, err := store.Create(post)
+				if err != nil {
+					t.Error(err.Error())
+					t.FailNow()
+				}
+				return id
+			}
+			// root <- a <- b <- c, root <- d
+			root := reply(0)
+			a := reply(root)
+			b := reply(a)
+			c := reply(b)
+			d := reply(root)
+
+			post, err := store.Get(b)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			if post.ParentID != a || post.ConversationID != root || post.Replies != 1 {
+				t.Errorf("Expected a reply to %d in conversation %d with a reply, got %+v", a, root, post)
+				t.Fail()
+			}
+			if _, err := store.Create(&data.Post{AuthorID: authorID, Contents: []byte("test"), ParentID: -1}); err != data.ErrNoEnt {
+				t.Errorf("Expected replying to a missing post to fail with ErrNoEnt, got %v", err)
+				t.Fail()
+			}
+
+			ancestors, err := store.Ancestors(c, authorID, 10)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			if len(ancestors) != 3 || ancestors[0].ID != root || ancestors[2].ID != b {
+				t.Errorf("Expected root, a and b, got %v", ancestors)
+				t.Fail()
+			}
+			ancestors, err = store.Ancestors(c, authorID, 1)
+			if err != nil || len(ancestors) != 1 || ancestors[0].ID != b {
+				t.Errorf("Expected only the nearest ancestor, got %v and %v", ancestors, err)
+				t.Fail()
+			}
+
+			descendants, err := store.Descendants(root, authorID, 2, 100, data.ListOptions{})
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			expected := []int64{a, d, b}
+			if len(descendants) != len(expected) {
+				t.Errorf("Expected %d descendants down to depth 2, got %v", len(expected), descendants)
+				t.FailNow()
+			}
+			for i, id := range expected {
+				if descendants[i].ID != id {
+					t.Errorf("Expected descendant %d at %d, got %d", id, i, descendants[i].ID)
+					t.Fail()
+				}
+			}
+			descendants, err = store.Descendants(root, authorID, 3, 100, data.ListOptions{Marker: a})
+			if err != nil || len(descendants) != 1 || descendants[0].ID != d {
+				t.Errorf("Expected only the replies after the marker, got %v and %v", descendants, err)
+				t.Fail()
+			}
+
+			// replies outlive the posts they reply to
+			if err := store.Delete(a); err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			post, err = store.Get(b)
+			if err != nil || post.ParentID != 0 || post.ConversationID != root {
+				t.Errorf("Expected an orphaned reply in the same conversation, got %+v and %v", post, err)
+				t.Fail()
+			}
+			return nil
+		})
+	})
+}
+
+func TestReposts(t *testing.T) {
+	gotag.Test(gotag.Integration, t, func(t gotag.T) {
+		whileConnectedToTestDb(testDbName, func(db *sqlx.DB) error {
+			defer cleanupOutboxTest(t, db)
+
+			ids, err := populateNotificationUsers(t, db, 4)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			author, first, second, reader := ids[0], ids[1], ids[2], ids[3]
+			for _, followee := range []int64{first, second} {
+				if err := populateFollowersTable(t, db, reader, followee); err != nil {
+					t.Error(err.Error())
+					t.FailNow()
+				}
+			}
+			store := NewPostStore(db)
+			postID, err := store.Create(datatest.ExamplePost(author))
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			expectRepost := func(reposters ...int64) {
+				feed, err := store.Feed(reader, data.ListOptions{Desc: true}, data.PostSortByDate)
+				if err != nil {
+					t.Error(err.Error())
+					t.FailNow()
+				}
+				if len(reposters) == 0 {
+					if len(feed) != 0 {
+						t.Errorf("Expected an empty feed, got %v", feed)
+						t.Fail()
+					}
+					return
+				}
+				if len(feed) != 1 || feed[0].ID != postID || feed[0].Reposts != len(reposters) {
+					t.Errorf("Expected the post once with %d reposts, got %+v", len(reposters), feed)
+					t.FailNow()
+				}
+				if feed[0].RepostedBy != reposters[len(reposters)-1] || feed[0].RepostedAt == nil {
+					t.Errorf("Expected the post attributed to its latest repost by %d, got %+v",
+						reposters[len(reposters)-1], feed[0])
+					t.Fail()
+				}
+			}
+
+			// reposting twice is harmless
+			for _, reposter := range []int64{first, first, second} {
+				if err := store.Repost(postID, reposter); err != nil {
+					t.Error(err.Error())
+					t.FailNow()
+				}
+			}
+			expectRepost(first, second)
+			if err := store.UnRepost(postID, second); err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			expectRepost(first)
+			if err := store.UnRepost(postID, first); err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			expectRepost()
+
+			quote := &data.Post{AuthorID: reader, Contents: []byte("quoted"), QuoteID: postID}
+			quoteID, err := store.Create(quote)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			post, err := store.Get(postID)
+			if err != nil || post.Quotes != 1 {
+				t.Errorf("Expected a quote of the post, got %+v and %v", post, err)
+				t.Fail()
+			}
+			post, err = store.Get(quoteID)
+			if err != nil || post.QuoteID != postID || post.RepostedBy != 0 {
+				t.Errorf("Expected a quote of %d, got %+v and %v", postID, post, err)
+				t.Fail()
+			}
+			quote.QuoteID = -1
+			if _, err := store.Create(quote); err != data.ErrNoEnt {
+				t.Errorf("Expected quoting a missing post to fail with ErrNoEnt, got %v", err)
+				t.Fail()
+			}
+			return nil
+		})
+	})
+}
+
+func TestPostEntities(t *testing.T) {
+	gotag.Test(gotag.Integration, t, func(t gotag.T) {
+		whileConnectedToTestDb(testDbName, func(db *sqlx.DB) error {
+			defer cleanupBlockStoreTest(t, db)
+
+			ids, err := populateNotificationUsers(t, db, 2)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			author, mentioned := ids[0], ids[1]
+			store := NewPostStore(db)
+			post := &data.Post{
+				AuthorID: author,
+				Contents: []byte("@test1 #meirl"),
+				Entities: []data.PostEntity{
+					{Type: data.EntityMention, Start: 0, End: 6, Text: "test1", UserID: mentioned},
+					{Type: data.EntityHashtag, Start: 7, End: 13, Text: "meirl"},
+				},
+			}
+			postID, err := store.Create(post)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			created, err := store.Get(postID)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			if len(created.Entities) != 2 {
+				t.Errorf("Expected 2 entities, got %v", created.Entities)
+				t.FailNow()
+			}
+			for i, entity := range post.Entities {
+				if created.Entities[i] != entity {
+					t.Errorf("Expected entity %v, got %v", entity, created.Entities[i])
+					t.Fail()
+				}
+			}
+
+			options := data.ListOptions{Desc: true}
+			mentions, err := store.Mentions(mentioned, options, data.PostSortByDate)
+			if err != nil || len(mentions) != 1 || mentions[0].ID != postID {
+				t.Errorf("Expected the post to mention %d, got %v and %v", mentioned, mentions, err)
+				t.Fail()
+			}
+
+			// mentions by blocked users are hidden
+			if err := NewBlockStore(db).Block(mentioned, author); err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			mentions, err = store.Mentions(mentioned, options, data.PostSortByDate)
+			if err != nil || len(mentions) != 0 {
+				t.Errorf("Expected mentions by blocked users to be hidden, got %v and %v", mentions, err)
+				t.Fail()
+			}
+			if err := NewBlockStore(db).UnBlock(mentioned, author); err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+
+			// mentions by private users are hidden from non-followers
+			users := NewUserStore(db)
+			if err := users.SetPrivate(author, true); err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			mentions, err = store.Mentions(mentioned, options, data.PostSortByDate)
+			if err != nil || len(mentions) != 0 {
+				t.Errorf("Expected mentions by private users to be hidden, got %v and %v", mentions, err)
+				t.Fail()
+			}
+			if err := users.Follow(mentioned, author); err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			mentions, err = store.Mentions(mentioned, options, data.PostSortByDate)
+			if err != nil || len(mentions) != 1 {
+				t.Errorf("Expected mentions by followed private users, got %v and %v", mentions, err)
+				t.Fail()
+			}
+			tagged, err := store.TaggedPosts("meirl", options, data.PostSortByDate)
+			if err != nil || len(tagged) != 1 || tagged[0].ID != postID {
+				t.Errorf("Expected the post to be tagged, got %v and %v", tagged, err)
+				t.Fail()
+			}
+
+			// updates replace the entities
+			err = store.Update(postID, []byte("#other"), []data.PostEntity{
+				{Type: data.EntityHashtag, Start: 0, End: 6, Text: "other"},
+			})
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			mentions, err = store.Mentions(mentioned, options, data.PostSortByDate)
+			if err != nil || len(mentions) != 0 {
+				t.Errorf("Expected no mentions after the update, got %v and %v", mentions, err)
+				t.Fail()
+			}
+			tagged, err = store.TaggedPosts("other", options, data.PostSortByDate)
+			if err != nil || len(tagged) != 1 || len(tagged[0].Entities) != 1 {
+				t.Errorf("Expected the post to be retagged, got %v and %v", tagged, err)
+				t.Fail()
+			}
+			return nil
+		})
+	})
+}
+
 func cleanupPostStoreTest(t gotag.T, db *sqlx.DB) {
 	_, err := db.Exec("DELETE FROM post_keks")
 	if err != nil {
@@ -0,0 +1,134 @@
+package postgres
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+
+	"github.com/boxtown/meirl/data"
+	"github.com/jmoiron/sqlx"
+)
+
+// Delimiters of matches in snippets, which are
+// replaced by <mark> tags once snippets are escaped
+const (
+	searchMarkStart = "\x01"
+	searchMarkStop  = "\x02"
+)
+
+// SearchStore is a PostgreSQL specific implementation
+// of data.SearchStore
+type SearchStore struct {
+	db *sqlx.DB
+}
+
+// NewSearchStore returns a newly constructed SearchStore
+// with the given database reference
+func NewSearchStore(db *sqlx.DB) *SearchStore {
+	return &SearchStore{db}
+}
+
+// SearchPosts returns the posts matching the query. Results sorted by
+// relevance are listed best first and results sorted by date are listed
+// newest first unless options.Desc is false. The marker is a
+// data.SearchMarker of the sort of the query, and every result is given
+// the marker of the page following it
+func (store *SearchStore) SearchPosts(query data.PostQuery, options data.ListOptions) ([]data.PostSearchResult, error) {
+	if options.Limit <= 10 || options.Limit > 1000 {
+		options.Limit = 10
+	}
+	buf := bytes.NewBufferString(searchPostsSQL)
+	args := []interface{}{query.Text}
+	if query.AuthorID != 0 {
+		args = append(args, query.AuthorID)
+		buf.WriteString(" AND posts.author_id=$" + strconv.Itoa(len(args)))
+	}
+	args = append(args, query.ViewerID)
+	buf.WriteString(" AND " + fmt.Sprintf(visiblePostsSQL, len(args)))
+	if !query.Since.IsZero() {
+		args = append(args, query.Since)
+		buf.WriteString(" AND posts.created_at >= $" + strconv.Itoa(len(args)))
+	}
+	if !query.Until.IsZero() {
+		args = append(args, query.Until)
+		buf.WriteString(" AND posts.created_at < $" + strconv.Itoa(len(args)))
+	}
+
+	paginator := paginator{
+		field:    searchRankField,
+		tiebreak: "posts.id",
+		desc:     true,
+		limit:    options.Limit,
+	}
+	if query.Sort == data.SearchByDate {
+		paginator.field = "posts.created_at"
+		paginator.desc = options.Desc
+	}
+	var seek interface{}
+	if marker, ok := options.Marker.(data.SearchMarker); ok {
+		if query.Sort == data.SearchByDate {
+			seek = tiedMarker{marker.CreatedAt, marker.ID}
+		} else {
+			seek = tiedMarker{marker.Rank, marker.ID}
+		}
+	}
+	q, args := paginator.paginate(buf.String(), true, seek, args...)
+	var results []data.PostSearchResult
+	err := store.db.Select(&results, q, args...)
+	if err != nil {
+		return nil, data.NewError(err)
+	}
+	for i := range results {
+		result := &results[i]
+		result.Snippet = highlight(result.Snippet)
+		result.Marker = data.SearchMarker{
+			Rank:      result.Rank,
+			CreatedAt: result.CreatedAt.Time,
+			ID:        result.ID,
+		}.String(query.Sort)
+	}
+	return results, nil
+}
+
+// SearchUsers returns at most limit enabled users whose username
+// the query prefixes or whose actual name resembles the query,
+// with follower counts but without emails
+func (store *SearchStore) SearchUsers(query string, limit int) ([]data.User, error) {
+	var users []data.User
+	err := store.db.Select(&users, searchUsersSQL, query, prefixPattern(query), limit)
+	if err != nil {
+		return nil, data.NewError(err)
+	}
+	return users, nil
+}
+
+// CompleteUsernames returns at most limit enabled users whose username
+// the prefix prefixes, most followed first, with follower counts but
+// without emails
+func (store *SearchStore) CompleteUsernames(prefix string, limit int) ([]data.User, error) {
+	var users []data.User
+	err := store.db.Select(&users, completeUsernamesSQL, prefixPattern(prefix), limit)
+	if err != nil {
+		return nil, data.NewError(err)
+	}
+	return users, nil
+}
+
+// prefixPattern returns the case insensitive
+// LIKE pattern of strings with the prefix
+func prefixPattern(prefix string) string {
+	prefix = strings.Replace(prefix, `\`, `\\`, -1)
+	prefix = strings.Replace(prefix, "%", `\%`, -1)
+	prefix = strings.Replace(prefix, "_", `\_`, -1)
+	return strings.ToLower(prefix) + "%"
+}
+
+// highlight escapes the snippet and replaces
+// match delimiters with <mark> tags
+func highlight(snippet string) string {
+	snippet = html.EscapeString(snippet)
+	snippet = strings.Replace(snippet, searchMarkStart, "<mark>", -1)
+	return strings.Replace(snippet, searchMarkStop, "</mark>", -1)
+}
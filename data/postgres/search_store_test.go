@@ -0,0 +1,230 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/boxtown/gotag"
+	"github.com/boxtown/meirl/data"
+	"github.com/boxtown/meirl/data/datatest"
+	"github.com/jmoiron/sqlx"
+)
+
+func TestHighlight(t *testing.T) {
+	snippet := highlight("<b>" + searchMarkStart + "kek" + searchMarkStop + "</b>")
+	expected := "&lt;b&gt;<mark>kek</mark>&lt;/b&gt;"
+	if snippet != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, snippet)
+		t.Fail()
+	}
+}
+
+func TestSearchPosts(t *testing.T) {
+	gotag.Test(gotag.Integration, t, func(t gotag.T) {
+		whileConnectedToTestDb(testDbName, func(db *sqlx.DB) error {
+			defer cleanupBlockStoreTest(t, db)
+
+			ids, err := populateNotificationUsers(t, db, 2)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			posts, search := NewPostStore(db), NewSearchStore(db)
+			contents := []struct {
+				authorID int64
+				contents string
+			}{
+				{ids[0], "the quick brown fox jumps over the lazy dog"},
+				{ids[0], "a brown dog and a quick fox, quick quick"},
+				{ids[1], "the fox is quick"},
+				{ids[1], "nothing to see here"},
+			}
+			postIDs := make([]int64, len(contents))
+			for i, c := range contents {
+				postIDs[i], err = posts.Create(&data.Post{AuthorID: c.authorID, Contents: []byte(c.contents)})
+				if err != nil {
+					t.Error(err.Error())
+					t.FailNow()
+				}
+			}
+
+			results, err := search.SearchPosts(data.PostQuery{Text: "quick fox"}, data.ListOptions{})
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			if len(results) != 3 {
+				t.Errorf("Expected 3 matching posts, got %d", len(results))
+				t.FailNow()
+			}
+			for i := 1; i < len(results); i++ {
+				if results[i].Rank > results[i-1].Rank {
+					t.Errorf("Expected results by relevance, got %v", results)
+					t.Fail()
+				}
+			}
+			if results[0].Snippet == "" || results[0].Marker == "" {
+				t.Errorf("Expected a snippet and marker, got %+v", results[0])
+				t.Fail()
+			}
+
+			// a phrase only matches words in order
+			results, err = search.SearchPosts(data.PostQuery{Text: `"quick brown fox"`}, data.ListOptions{})
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			if len(results) != 1 || results[0].ID != postIDs[0] {
+				t.Errorf("Expected the phrase to match the first post, got %v", results)
+				t.Fail()
+			}
+
+			// edits are searchable
+			if err := posts.Update(postIDs[3], []byte("a quick fox after all"), nil); err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			query := data.PostQuery{Text: "fox", AuthorID: ids[1], Sort: data.SearchByDate}
+			results, err = search.SearchPosts(query, data.ListOptions{Desc: true})
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			if len(results) != 2 || results[0].ID != postIDs[3] || results[1].ID != postIDs[2] {
+				t.Errorf("Expected posts by the author newest first, got %v", results)
+				t.FailNow()
+			}
+			marker, err := data.ParseSearchMarker(results[0].Marker, data.SearchByDate)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			results, err = search.SearchPosts(query, data.ListOptions{Desc: true, Marker: marker})
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			if len(results) != 1 || results[0].ID != postIDs[2] {
+				t.Errorf("Expected the next page after the marker, got %v", results)
+				t.Fail()
+			}
+
+			query = data.PostQuery{Text: "fox", Until: time.Now().Add(-time.Hour)}
+			results, err = search.SearchPosts(query, data.ListOptions{})
+			if err != nil || len(results) != 0 {
+				t.Errorf("Expected no posts before an hour ago, got %v and %v", results, err)
+				t.Fail()
+			}
+
+			// blocks hide the posts of either user from the other
+			if err := NewBlockStore(db).Block(ids[1], ids[0]); err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			query = data.PostQuery{Text: "fox", ViewerID: ids[0]}
+			results, err = search.SearchPosts(query, data.ListOptions{})
+			if err != nil || len(results) != 2 {
+				t.Errorf("Expected only the posts of the viewer, got %v and %v", results, err)
+				t.FailNow()
+			}
+			for _, result := range results {
+				if result.AuthorID != ids[0] {
+					t.Errorf("Expected the posts of the blocker to be hidden, got %v", result)
+					t.Fail()
+				}
+			}
+			return nil
+		})
+	})
+}
+
+func TestSearchUsers(t *testing.T) {
+	gotag.Test(gotag.Integration, t, func(t gotag.T) {
+		whileConnectedToTestDb(testDbName, func(db *sqlx.DB) error {
+			defer cleanupPostStoreTest(t, db)
+
+			users := []struct {
+				username   string
+				actualName string
+			}{
+				{"jsmith", "Jonathan Smith"},
+				{"jsmithers", "Jane Smithers"},
+				{"kek_lord", "John Smyth"},
+				{"someone", "Someone Else"},
+			}
+			ids := make([]int64, len(users))
+			for i, u := range users {
+				user := datatest.ExampleUser()
+				user.Username, user.ActualName = u.username, u.actualName
+				user.Email = u.username + "@test.com"
+				id, err := populateUsersTable(t, db, user)
+				if err != nil {
+					t.Error(err.Error())
+					t.FailNow()
+				}
+				ids[i] = id
+			}
+			// jsmithers is followed by everyone else
+			for _, id := range []int64{ids[0], ids[2], ids[3]} {
+				if err := populateFollowersTable(t, db, id, ids[1]); err != nil {
+					t.Error(err.Error())
+					t.FailNow()
+				}
+			}
+			store := NewSearchStore(db)
+
+			// the exact username outranks the followed prefix match
+			found, err := store.SearchUsers("JSmith", 10)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			if len(found) < 2 || found[0].ID != ids[0] || found[1].ID != ids[1] {
+				t.Errorf("Expected jsmith then jsmithers, got %v", found)
+				t.FailNow()
+			}
+			if found[1].NumFollowers != 3 || found[0].Email != "" {
+				t.Errorf("Expected follower counts without emails, got %+v", found[1])
+				t.Fail()
+			}
+
+			// actual names match approximately
+			found, err = store.SearchUsers("jon smyth", 10)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			if len(found) == 0 || found[0].ID != ids[2] {
+				t.Errorf("Expected kek_lord to match by actual name, got %v", found)
+				t.Fail()
+			}
+
+			// suggestions are the most followed first
+			found, err = store.CompleteUsernames("js", 5)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			if len(found) != 2 || found[0].ID != ids[1] || found[1].ID != ids[0] {
+				t.Errorf("Expected jsmithers then jsmith, got %v", found)
+				t.Fail()
+			}
+			// underscores are not wildcards
+			found, err = store.CompleteUsernames("kek_", 5)
+			if err != nil || len(found) != 1 {
+				t.Errorf("Expected a single suggestion, got %v and %v", found, err)
+				t.Fail()
+			}
+			return nil
+		})
+	})
+}
+
+func TestPrefixPattern(t *testing.T) {
+	pattern := prefixPattern(`Kek_100%\`)
+	expected := `kek\_100\%\\%`
+	if pattern != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, pattern)
+		t.Fail()
+	}
+}
@@ -0,0 +1,110 @@
+package postgres
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/boxtown/gotag"
+	"github.com/boxtown/meirl/data"
+	"github.com/boxtown/meirl/data/seed"
+	"github.com/jmoiron/sqlx"
+)
+
+func TestLoadSeed(t *testing.T) {
+	gotag.Test(gotag.Integration, t, func(t gotag.T) {
+		whileConnectedToTestDb(testDbName, func(db *sqlx.DB) error {
+			defer cleanupBlockStoreTest(t, db)
+
+			ds := seed.Generate(seed.Options{Seed: 1, Users: 20, MeanFollows: 3, MeanPosts: 3})
+			if len(ds.Posts) == 0 {
+				t.Error("Expected posts to be generated")
+				t.FailNow()
+			}
+			if err := seed.Load(db, ds); err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+
+			var posts int
+			if err := db.Get(&posts, "SELECT count(*) FROM posts"); err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			if posts != len(ds.Posts) {
+				t.Errorf("Expected %d posts, got %d", len(ds.Posts), posts)
+				t.Fail()
+			}
+
+			// seeded posts start their own conversations
+			var strays int
+			if err := db.Get(&strays, "SELECT count(*) FROM posts WHERE conversation_id<>id"); err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			if strays != 0 {
+				t.Errorf("Expected every seeded post to start a conversation, %d do not", strays)
+				t.Fail()
+			}
+
+			// seeded reactions follow the posts reacted to
+			var early int
+			err := db.Get(&early, `SELECT count(*) FROM posts
+				INNER JOIN post_keks ON post_keks.post_id=posts.id
+				WHERE post_keks.created_at < posts.created_at`)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			if early != 0 {
+				t.Errorf("Expected keks after the posts reacted to, %d precede them", early)
+				t.Fail()
+			}
+
+			// seeded posts are fanned out to the timelines of followers
+			var missing int
+			err = db.Get(&missing, `SELECT count(*) FROM posts
+				INNER JOIN followers ON followers.followee_id=posts.author_id
+				LEFT JOIN timelines ON timelines.user_id=followers.follower_id AND timelines.post_id=posts.id
+				WHERE timelines.post_id IS NULL`)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			if missing != 0 {
+				t.Errorf("Expected seeded posts in the timelines of followers, %d are missing", missing)
+				t.Fail()
+			}
+			feed, err := NewTimelinePostStore(db).Feed(ds.Follows[0].FollowerID, data.ListOptions{Limit: 1000}, data.PostSortByDate)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			if len(feed) == 0 {
+				t.Errorf("Expected a feed for user %d", ds.Follows[0].FollowerID)
+				t.Fail()
+			}
+
+			// seeded posts are searchable
+			var word string
+			for _, w := range strings.Fields(string(ds.Posts[0].Contents)) {
+				if len(w) > len(word) {
+					word = w
+				}
+			}
+			results, err := NewSearchStore(db).SearchPosts(data.PostQuery{Text: word}, data.ListOptions{Limit: 1000})
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			found := false
+			for _, result := range results {
+				found = found || result.ID == ds.Posts[0].ID
+			}
+			if !found {
+				t.Errorf("Expected '%s' to match post %d", word, ds.Posts[0].ID)
+				t.Fail()
+			}
+			return nil
+		})
+	})
+}
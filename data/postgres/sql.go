@@ -11,12 +11,13 @@ import (
 // User SQL queries
 const (
 	createUserSQL = `INSERT INTO 
-        users (username, email, password, actual_name, dob) 
-		VALUES ($1, $2, $3, $4, $5) 
+        users (username, email, password, actual_name, dob, private) 
+		VALUES ($1, $2, $3, $4, $5, $6) 
         RETURNING id`
 
 	selectUserSQL = `SELECT users.id, users.created_at, users.updated_at,
-		users.username, users.email, users.password, users.actual_name, dob`
+		users.username, users.email, users.password, users.actual_name, dob,
+		users.disabled, users.private`
 
 	getUserByIDSQL = selectUserSQL + ", " +
 		`(SELECT COUNT(*) FROM followers WHERE followers.follower_id=users.id) AS num_following,
@@ -36,52 +37,513 @@ const (
 		` FROM users INNER JOIN followers 
 		  ON followers.followee_id=users.id WHERE followers.follower_id=$1`
 
+	getFollowingIDsSQL = `SELECT followee_id FROM followers WHERE follower_id=$1`
+
 	updateUserSQL = `UPDATE users SET 
 		username=$1, email=$2, actual_name=$3, dob=$4, updated_at=now() 
 		WHERE id=$5`
 
 	deleteUserSQL = `DELETE FROM users WHERE id=$1`
 
+	setUserDisabledSQL = `UPDATE users SET disabled=$1, updated_at=now() WHERE id=$2`
+
+	setUserPasswordSQL = `UPDATE users SET password=$1, updated_at=now() WHERE id=$2`
+
+	// Nothing is inserted if either user blocked the other
 	followUserSQL = `INSERT INTO 
 		followers (follower_id, followee_id) 
-		VALUES ($1, $2)`
+		SELECT $1, $2 WHERE NOT EXISTS (` + blockBetweenSQL + `)`
 
 	unFollowUserSQL = `DELETE FROM followers 
 		WHERE follower_id=$1 AND followee_id=$2`
+
+	isFollowingSQL = `SELECT EXISTS (SELECT 1 FROM followers 
+		WHERE follower_id=$1 AND followee_id=$2)`
+
+	setUserPrivateSQL = `UPDATE users SET private=$1, updated_at=now() WHERE id=$2`
+
+	// Nothing is requested if the requester already follows
+	// the target or if either user blocked the other
+	requestFollowSQL = `INSERT INTO 
+		follow_requests (requester_id, target_id) 
+		SELECT $1, $2 WHERE NOT EXISTS 
+		  (SELECT 1 FROM followers WHERE follower_id=$1 AND followee_id=$2)
+		  AND NOT EXISTS (` + blockBetweenSQL + `)
+		ON CONFLICT DO NOTHING`
+
+	deleteFollowRequestSQL = `DELETE FROM follow_requests 
+		WHERE requester_id=$1 AND target_id=$2`
+
+	// Turns the request of $2 to follow $1 into a follow,
+	// returning the new follower
+	approveFollowRequestSQL = `WITH approved AS (
+		  DELETE FROM follow_requests
+		  WHERE target_id=$1 AND requester_id=$2
+		  RETURNING requester_id)` + insertApprovedSQL
+
+	// Turns every request to follow $1 into a follow,
+	// returning the new followers
+	approveAllFollowRequestsSQL = `WITH approved AS (
+		  DELETE FROM follow_requests WHERE target_id=$1
+		  RETURNING requester_id)` + insertApprovedSQL
+
+	insertApprovedSQL = `
+		INSERT INTO followers (follower_id, followee_id)
+		SELECT requester_id, $1 FROM approved
+		ON CONFLICT DO NOTHING
+		RETURNING follower_id`
+
+	getFollowRequestsByIDSQL = selectUserSQL +
+		` FROM users INNER JOIN follow_requests 
+		  ON follow_requests.requester_id=users.id WHERE follow_requests.target_id=$1`
 )
 
 // Post SQL queries
 const (
-	createPostSQL = `INSERT INTO 
-		posts (author_id, contents) VALUES ($1, $2) RETURNING id`
+	// Replies join the conversation of their parent while other posts
+	// start their own. Nothing is inserted if the parent or the quoted
+	// post does not exist
+	createPostSQL = `WITH new AS (SELECT nextval('posts_id_seq') AS id)
+		INSERT INTO posts (id, author_id, contents, parent_id, conversation_id, quote_id)
+		SELECT new.id, $1, $2,
+		  parent.id, COALESCE(parent.conversation_id, new.id), quoted.id
+		FROM new
+		  LEFT JOIN posts AS parent ON parent.id=$3
+		  LEFT JOIN posts AS quoted ON quoted.id=$4
+		WHERE ($3=0 OR parent.id IS NOT NULL) AND ($4=0 OR quoted.id IS NOT NULL)
+		RETURNING id`
+
+	// The entities of a post as a JSON array sorted by offset
+	postEntitiesSQL = `(SELECT COALESCE(json_agg(entities ORDER BY entities.start), '[]')
+		  FROM (SELECT 'mention' AS type, post_mentions.start_offset AS start,
+		      post_mentions.end_offset AS "end", users.username AS text,
+		      post_mentions.user_id AS "userID"
+		    FROM post_mentions INNER JOIN users ON users.id=post_mentions.user_id
+		    WHERE post_mentions.post_id=posts.id
+		    UNION ALL
+		    SELECT 'hashtag', start_offset, end_offset, tag, NULL FROM post_hashtags
+		    WHERE post_hashtags.post_id=posts.id) AS entities) AS entities`
 
 	selectPostSQL = `SELECT posts.id, posts.created_at, 
 		posts.author_id, posts.contents,
+		COALESCE(posts.parent_id, 0) AS parent_id, posts.conversation_id,
+		COALESCE(posts.quote_id, 0) AS quote_id,
+		(SELECT COUNT(*) FROM posts AS replies WHERE replies.parent_id=posts.id) AS replies,
+		(SELECT COUNT(*) FROM reposts WHERE reposts.post_id=posts.id) AS reposts,
+		(SELECT COUNT(*) FROM posts AS quotes WHERE quotes.quote_id=posts.id) AS quotes,
 		(SELECT COUNT(*) FROM post_keks WHERE post_keks.post_id=posts.id) AS keks, 
-		(SELECT COUNT(*) FROM post_nos WHERE post_nos.post_id=posts.id) AS nos`
+		(SELECT COUNT(*) FROM post_nos WHERE post_nos.post_id=posts.id) AS nos,
+		` + postEntitiesSQL
 
-	getPostByIDSQL      = selectPostSQL + " FROM posts WHERE posts.id=$1"
-	getPostsByUserIDSQL = selectPostSQL +
-		` FROM posts
+	getPostByIDSQL = selectPostSQL + " FROM posts WHERE posts.id=$1"
+
+	postsByUserIDSQL = ` FROM posts
 		  INNER JOIN users ON posts.author_id=users.id
 		  WHERE users.id=$1`
-	getFeedByUserIDSQL = selectPostSQL +
-		` FROM posts 
-		  INNER JOIN users ON posts.author_id=users.id
-		  INNER JOIN followers ON followers.followee_id=users.id
-		  WHERE followers.follower_id=$1 OR users.id=$1`
 
-	updatePostSQL = `UPDATE posts SET contents=$1, updated_at=now() WHERE id=$2`
+	// Posts by the user and the users they follow
+	feedPostsSQL = `posts.author_id=$1 OR posts.author_id IN
+		    (SELECT followee_id FROM followers WHERE follower_id=$1)`
+
+	// Fanned out posts are read from the timeline of the user, while
+	// the posts of pull authors are read from the posts table
+	timelinePostsSQL = `posts.author_id=$1
+		    OR posts.id IN (SELECT post_id FROM timelines WHERE user_id=$1)
+		    OR posts.author_id IN (SELECT followers.followee_id FROM followers
+		      INNER JOIN timeline_pull_authors
+		        ON timeline_pull_authors.author_id=followers.followee_id
+		      WHERE followers.follower_id=$1)`
+
+	// Posts mentioning the user, to be followed by the
+	// posts the user may see
+	mentionsByUserIDSQL = ` FROM posts
+		  WHERE posts.id IN (SELECT post_id FROM post_mentions WHERE user_id=$1)
+		    AND `
+
+	// Posts tagged with the tag
+	postsByTagSQL = ` FROM posts
+		  WHERE posts.id IN (SELECT post_id FROM post_hashtags WHERE tag=$1)
+		    AND ` + publicPostsSQL
+
+	// Feeds are the posts above and the posts reposted by the user and
+	// the users they follow. Every post appears once, at the latest of
+	// its creation if included above and its reposts, which feeds are
+	// sorted by. Reposts are always read rather than fanned out. The
+	// posts and reposts of users the user blocked, was blocked by or
+	// muted are hidden
+	feedEntriesStartSQL = ` FROM posts, (SELECT DISTINCT ON (post_id) post_id, reposted_by, at
+		  FROM (SELECT posts.id AS post_id, NULL::integer AS reposted_by, posts.created_at AS at
+		      FROM posts WHERE (`
+	feedEntriesEndSQL = `)
+		    UNION ALL
+		    SELECT reposts.post_id, reposts.user_id, reposts.created_at FROM reposts
+		    WHERE (reposts.user_id=$1 OR reposts.user_id IN
+		      (SELECT followee_id FROM followers WHERE follower_id=$1))
+		      AND reposts.user_id NOT IN (SELECT muted_id FROM mutes WHERE muter_id=$1)) AS appearances
+		  ORDER BY post_id, at DESC) AS entries
+		WHERE posts.id=entries.post_id
+		  AND posts.author_id NOT IN (` + hiddenAuthorsSQL + `)`
+	feedEntryColumnsSQL = `,
+		COALESCE(entries.reposted_by, 0) AS reposted_by,
+		CASE WHEN entries.reposted_by IS NOT NULL THEN entries.at END AS reposted_at`
+	feedEntryTimeField = "entries.at"
+
+	feedByUserIDSQL     = feedEntriesStartSQL + feedPostsSQL + feedEntriesEndSQL
+	timelineByUserIDSQL = feedEntriesStartSQL + timelinePostsSQL + feedEntriesEndSQL
+
+	getPostsByUserIDSQL = selectPostSQL + postsByUserIDSQL
+	getFeedByUserIDSQL  = selectPostSQL + feedEntryColumnsSQL + feedByUserIDSQL
+
+	// Posts and reactions as of $2, to be followed by one of the
+	// above FROM clauses and ranked by scoredPostsSQL. Reposts are
+	// counted as of now
+	selectPostAsOfSQL = `SELECT posts.id, posts.created_at,
+		posts.author_id, posts.contents,
+		COALESCE(posts.parent_id, 0) AS parent_id, posts.conversation_id,
+		COALESCE(posts.quote_id, 0) AS quote_id,
+		(SELECT COUNT(*) FROM posts AS replies WHERE replies.parent_id=posts.id) AS replies,
+		(SELECT COUNT(*) FROM reposts WHERE reposts.post_id=posts.id) AS reposts,
+		(SELECT COUNT(*) FROM posts AS quotes WHERE quotes.quote_id=posts.id) AS quotes,
+		(SELECT COUNT(*) FROM post_keks
+		  WHERE post_keks.post_id=posts.id AND post_keks.created_at <= $2) AS keks,
+		(SELECT COUNT(*) FROM post_nos
+		  WHERE post_nos.post_id=posts.id AND post_nos.created_at <= $2) AS nos,
+		` + postEntitiesSQL
+
+	// Scores are net keks decayed by age in hours, as ranked by Hacker
+	// News. Posts with more nos than keks score as if unreacted to, so
+	// that they sink with age like any other post rather than rising
+	// towards zero. Formatted with the posts as of $2
+	scoredPostsSQL = `SELECT * FROM (SELECT posts_as_of.*,
+		  (GREATEST(keks - nos, 0) + 1)::float8 /
+		  power(EXTRACT(EPOCH FROM $2::timestamptz - created_at)::float8 / 3600 + 2, 1.8) AS score
+		FROM (%s) AS posts_as_of) AS scored`
+
+	// Excludes the posts the viewer $%[1]d may not see: those of users
+	// the viewer blocked or was blocked by and of private users the
+	// viewer does not follow. Anonymous viewers have an ID of 0
+	visiblePostsSQL = `posts.author_id NOT IN
+		  (SELECT blocked_id FROM blocks WHERE blocker_id=$%[1]d
+		    UNION ALL SELECT blocker_id FROM blocks WHERE blocked_id=$%[1]d
+		    UNION ALL SELECT id FROM users WHERE private AND id<>$%[1]d
+		      AND id NOT IN (SELECT followee_id FROM followers WHERE follower_id=$%[1]d))`
+
+	// The nearest ancestors of $1, nearest first, formatted with
+	// the posts the viewer may see
+	getAncestorsSQL = `WITH RECURSIVE ancestors AS (
+		  SELECT parent_id AS id, 1 AS depth FROM posts
+		  WHERE id=$1 AND parent_id IS NOT NULL
+		  UNION ALL
+		  SELECT posts.parent_id, ancestors.depth + 1
+		  FROM posts INNER JOIN ancestors ON posts.id=ancestors.id
+		  WHERE posts.parent_id IS NOT NULL AND ancestors.depth < $2)
+		` + selectPostSQL + `
+		FROM posts INNER JOIN ancestors ON ancestors.id=posts.id
+		WHERE %s
+		ORDER BY ancestors.depth`
+
+	// Descendants of the page of replies to $1 returned by the
+	// paginated replies query, down to a depth of $n, by depth
+	// and then id in the order of the page. Replies the viewer may
+	// not see are skipped along with their replies
+	getDescendantsSQL = `WITH RECURSIVE page AS (%s),
+		  tree AS (
+		    SELECT id, 1 AS depth FROM page
+		    UNION ALL
+		    SELECT posts.id, tree.depth + 1
+		    FROM posts INNER JOIN tree ON posts.parent_id=tree.id
+		    WHERE tree.depth < $%d AND %s)
+		` + selectPostSQL + `
+		FROM posts INNER JOIN tree ON tree.id=posts.id
+		ORDER BY tree.depth, posts.id %s
+		LIMIT %d`
+
+	getRepliesSQL = `SELECT posts.id FROM posts WHERE posts.parent_id=$1`
+
+	// Lists of posts open to anyone exclude the posts of private users
+	publicPostsSQL = `posts.author_id NOT IN (SELECT id FROM users WHERE private)`
+
+	getRecentPostsSQL = selectPostSQL + " FROM posts WHERE " + publicPostsSQL
 
-	deletePostSQL = `DELETE FROM posts WHERE id=$1`
+	getTrendingPostsSQL = selectPostSQL + `, trending_posts.rank
+		FROM trending_posts INNER JOIN posts ON posts.id=trending_posts.post_id
+		WHERE trending_posts.span=$1 AND ` + publicPostsSQL
+
+	updatePostSQL = `UPDATE posts
+		SET contents=$1, updated_at=now()
+		WHERE id=$2 RETURNING author_id`
+
+	deletePostSQL = `DELETE FROM posts WHERE id=$1 RETURNING author_id`
+
+	insertMentionSQL = `INSERT INTO
+		post_mentions (post_id, start_offset, end_offset, user_id) VALUES ($1, $2, $3, $4)`
+
+	insertHashtagSQL = `INSERT INTO
+		post_hashtags (post_id, start_offset, end_offset, tag) VALUES ($1, $2, $3, $4)`
+
+	deleteMentionsSQL = `DELETE FROM post_mentions WHERE post_id=$1`
+
+	deleteHashtagsSQL = `DELETE FROM post_hashtags WHERE post_id=$1`
+
+	kekPostSQL = `INSERT INTO 
+		post_keks (post_id, author_id) VALUES ($1, $2) 
+		ON CONFLICT DO NOTHING`
+
+	unKekPostSQL = `DELETE FROM post_keks WHERE post_id=$1 AND author_id=$2`
+
+	noPostSQL = `INSERT INTO 
+		post_nos (post_id, author_id) VALUES ($1, $2) 
+		ON CONFLICT DO NOTHING`
+
+	unNoPostSQL = `DELETE FROM post_nos WHERE post_id=$1 AND author_id=$2`
+
+	repostSQL = `INSERT INTO 
+		reposts (post_id, user_id) VALUES ($1, $2) 
+		ON CONFLICT DO NOTHING`
+
+	unRepostSQL = `DELETE FROM reposts WHERE post_id=$1 AND user_id=$2`
+)
+
+// Block SQL queries
+const (
+	// Whether $1 or $2 blocked the other
+	blockBetweenSQL = `SELECT 1 FROM blocks
+		  WHERE (blocker_id=$1 AND blocked_id=$2) OR (blocker_id=$2 AND blocked_id=$1)`
+
+	isBlockedSQL = `SELECT EXISTS (` + blockBetweenSQL + `)`
+
+	// Users $1 blocked or was blocked by
+	blockedAuthorsSQL = `SELECT blocked_id FROM blocks WHERE blocker_id=$1
+		    UNION ALL SELECT blocker_id FROM blocks WHERE blocked_id=$1`
+
+	// Users whose posts are hidden from the feed of $1, including
+	// private users they do not follow whose posts were reposted
+	hiddenAuthorsSQL = blockedAuthorsSQL + `
+		    UNION ALL SELECT muted_id FROM mutes WHERE muter_id=$1
+		    UNION ALL SELECT id FROM users WHERE private AND id<>$1
+		      AND id NOT IN (SELECT followee_id FROM followers WHERE follower_id=$1)`
+
+	blockUserSQL = `INSERT INTO 
+		blocks (blocker_id, blocked_id) VALUES ($1, $2) 
+		ON CONFLICT DO NOTHING`
+
+	unBlockUserSQL = `DELETE FROM blocks WHERE blocker_id=$1 AND blocked_id=$2`
+
+	deleteFollowRequestsBetweenSQL = `DELETE FROM follow_requests
+		WHERE (requester_id=$1 AND target_id=$2) OR (requester_id=$2 AND target_id=$1)`
+
+	// Follows between $1 and $2 in either direction
+	deleteFollowsBetweenSQL = `DELETE FROM followers
+		WHERE (follower_id=$1 AND followee_id=$2) OR (follower_id=$2 AND followee_id=$1)
+		RETURNING follower_id, followee_id`
+
+	muteUserSQL = `INSERT INTO 
+		mutes (muter_id, muted_id) VALUES ($1, $2) 
+		ON CONFLICT DO NOTHING`
+
+	unMuteUserSQL = `DELETE FROM mutes WHERE muter_id=$1 AND muted_id=$2`
+
+	getBlockedByIDSQL = selectUserSQL +
+		` FROM users INNER JOIN blocks 
+		  ON blocks.blocked_id=users.id WHERE blocks.blocker_id=$1`
+
+	getMutedByIDSQL = selectUserSQL +
+		` FROM users INNER JOIN mutes 
+		  ON mutes.muted_id=users.id WHERE mutes.muter_id=$1`
+
+	getMutedIDsSQL = `SELECT muted_id FROM mutes WHERE muter_id=$1`
+)
+
+// Notification SQL queries
+const (
+	// Group with the unread notification of the same type about the same
+	// post if there is one, unless the user disabled the notification type
+	notifySQL = `WITH notification AS (
+			INSERT INTO notifications (user_id, type, post_id)
+			SELECT $1::integer, $2::text, $4::integer
+			WHERE NOT EXISTS (SELECT 1 FROM notification_preferences
+				WHERE user_id=$1 AND type=$2 AND NOT enabled)
+			ON CONFLICT (user_id, type, COALESCE(post_id, 0)) WHERE NOT read
+			DO UPDATE SET updated_at=now()
+			RETURNING id)
+		INSERT INTO notification_actors (notification_id, actor_id)
+		SELECT id, $3 FROM notification
+		ON CONFLICT (notification_id, actor_id) DO UPDATE SET created_at=now()
+		RETURNING notification_id`
+
+	selectNotificationSQL = `SELECT notifications.id, notifications.created_at,
+		notifications.updated_at, notifications.user_id, notifications.type,
+		notifications.post_id, notifications.read,
+		(SELECT actor_id FROM notification_actors
+		 WHERE notification_actors.notification_id=notifications.id
+		 ORDER BY notification_actors.created_at DESC LIMIT 1) AS actor_id,
+		(SELECT COUNT(*) FROM notification_actors
+		 WHERE notification_actors.notification_id=notifications.id) AS actor_count`
+
+	getNotificationByIDSQL = selectNotificationSQL + " FROM notifications WHERE notifications.id=$1"
+
+	getNotificationsByUserIDSQL = selectNotificationSQL +
+		" FROM notifications WHERE notifications.user_id=$1"
+
+	getUnreadNotificationsByUserIDSQL = getNotificationsByUserIDSQL + " AND NOT notifications.read"
+
+	countUnreadNotificationsSQL = `SELECT COUNT(*) FROM notifications WHERE user_id=$1 AND NOT read`
+
+	markNotificationsReadSQL = `UPDATE notifications SET read=true
+		WHERE user_id=$1 AND id=ANY($2) AND NOT read`
+
+	markAllNotificationsReadSQL = `UPDATE notifications SET read=true
+		WHERE user_id=$1 AND NOT read`
+
+	getNotificationPreferencesSQL = `SELECT type, enabled
+		FROM notification_preferences WHERE user_id=$1`
+
+	setNotificationPreferenceSQL = `INSERT INTO
+		notification_preferences (user_id, type, enabled) VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, type) DO UPDATE SET enabled=excluded.enabled`
+)
+
+// Webhook SQL queries
+const (
+	createWebhookSQL = `INSERT INTO
+		webhooks (user_id, url, secret, events) VALUES ($1, $2, $3, $4)
+		RETURNING id`
+
+	selectWebhookSQL = `SELECT webhooks.id, webhooks.created_at, webhooks.updated_at,
+		webhooks.user_id, webhooks.url, webhooks.secret, webhooks.events AS event_list
+		FROM webhooks`
+
+	getWebhookByIDSQL = selectWebhookSQL + " WHERE webhooks.id=$1"
+
+	getWebhooksByUserIDSQL = selectWebhookSQL + " WHERE webhooks.user_id=$1 ORDER BY webhooks.id"
+
+	getAdminWebhooksSQL = selectWebhookSQL + " WHERE webhooks.user_id IS NULL ORDER BY webhooks.id"
+
+	getSubscribedWebhooksSQL = selectWebhookSQL +
+		` WHERE $1=ANY(webhooks.events)
+		  AND (webhooks.user_id IS NULL OR webhooks.user_id=ANY($2))`
+
+	deleteWebhookSQL = `DELETE FROM webhooks WHERE id=$1`
+
+	// An event is delivered to a webhook at most once, returning
+	// the existing delivery if the event is relayed again
+	createDeliverySQL = `WITH inserted AS (
+			INSERT INTO webhook_deliveries (webhook_id, event_id, event, payload)
+			VALUES ($1, NULLIF($2::bigint, 0), $3, $4)
+			ON CONFLICT (webhook_id, event_id) DO NOTHING
+			RETURNING id)
+		SELECT id FROM inserted
+		UNION ALL
+		SELECT id FROM webhook_deliveries WHERE webhook_id=$1 AND event_id=$2
+		LIMIT 1`
+
+	deliveryColumns = `webhook_deliveries.id, webhook_deliveries.created_at,
+		webhook_deliveries.updated_at, webhook_deliveries.webhook_id,
+		COALESCE(webhook_deliveries.event_id, 0) AS event_id, webhook_deliveries.event, webhook_deliveries.payload,
+		webhook_deliveries.status, webhook_deliveries.attempts,
+		webhook_deliveries.response_status, webhook_deliveries.last_error,
+		webhook_deliveries.next_attempt_at`
+
+	getDeliveryByIDSQL = "SELECT " + deliveryColumns +
+		" FROM webhook_deliveries WHERE webhook_deliveries.id=$1"
+
+	getDeliveriesByWebhookIDSQL = "SELECT " + deliveryColumns +
+		" FROM webhook_deliveries WHERE webhook_deliveries.webhook_id=$1"
+
+	// Lease due deliveries by pushing back their next attempt so that
+	// deliveries claimed by a worker that dies are attempted again
+	claimDeliveriesSQL = `UPDATE webhook_deliveries
+		SET next_attempt_at=now() + $2 * interval '1 millisecond'
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status='pending' AND next_attempt_at <= now()
+			ORDER BY next_attempt_at LIMIT $1
+			FOR UPDATE SKIP LOCKED)
+		RETURNING ` + deliveryColumns
+
+	updateDeliverySQL = `UPDATE webhook_deliveries SET
+		status=$1, attempts=$2, response_status=$3, last_error=$4,
+		next_attempt_at=$5, updated_at=now()
+		WHERE id=$6`
+)
+
+// Outbox SQL queries
+const (
+	recordEventSQL = `INSERT INTO
+		outbox (type, entity_id, user_id) VALUES ($1, $2, $3)`
+
+	// New sinks start after the latest event rather than
+	// replaying the retained history
+	createOutboxCursorSQL = `INSERT INTO
+		outbox_cursors (sink, tx_id, event_id)
+		SELECT $1, COALESCE(MAX(tx_id), 0), COALESCE(MAX(id), 0) FROM outbox
+		ON CONFLICT DO NOTHING`
+
+	// Only one relay may publish to a sink at a time so that
+	// events are published in order
+	lockOutboxCursorSQL = `SELECT pg_try_advisory_xact_lock(hashtext('meirl_outbox:' || $1))`
+
+	getOutboxCursorSQL = `SELECT tx_id, event_id FROM outbox_cursors WHERE sink=$1`
+
+	// Events of transactions that may still be in progress are not
+	// visible yet, so only events of transactions older than every
+	// running transaction are relayed
+	getEventsAfterSQL = `SELECT id, tx_id, type, entity_id, user_id
+		FROM outbox
+		WHERE (tx_id, id) > ($1, $2)
+		  AND tx_id < txid_snapshot_xmin(txid_current_snapshot())
+		ORDER BY tx_id, id LIMIT $3`
+
+	updateOutboxCursorSQL = `UPDATE outbox_cursors
+		SET tx_id=$1, event_id=$2, updated_at=now() WHERE sink=$3`
+
+	// Events are only pruned once relayed to every sink
+	pruneOutboxSQL = `DELETE FROM outbox
+		WHERE created_at < now() - $1 * interval '1 millisecond'
+		  AND tx_id < (SELECT COALESCE(MIN(tx_id), 0)
+			FROM outbox_cursors WHERE sink=ANY($2))`
+)
+
+// Timeline SQL queries
+const (
+	countFollowersSQL = `SELECT COUNT(*) FROM followers WHERE followee_id=$1`
+
+	addPullAuthorSQL = `INSERT INTO timeline_pull_authors (author_id)
+		VALUES ($1) ON CONFLICT DO NOTHING`
+
+	fanOutPostSQL = `INSERT INTO timelines (user_id, post_id, author_id, created_at)
+		SELECT followers.follower_id, posts.id, posts.author_id, posts.created_at
+		FROM posts INNER JOIN followers ON followers.followee_id=posts.author_id
+		WHERE posts.id=$1 AND NOT EXISTS
+		  (SELECT 1 FROM timeline_pull_authors WHERE author_id=posts.author_id)
+		ON CONFLICT DO NOTHING`
+
+	// Backfill only if the follow still exists, as
+	// events may be relayed after an unfollow
+	backfillTimelineSQL = `INSERT INTO timelines (user_id, post_id, author_id, created_at)
+		SELECT followers.follower_id, posts.id, posts.author_id, posts.created_at
+		FROM posts INNER JOIN followers ON followers.followee_id=posts.author_id
+		WHERE followers.follower_id=$1 AND followers.followee_id=$2 AND NOT EXISTS
+		  (SELECT 1 FROM timeline_pull_authors WHERE author_id=posts.author_id)
+		ON CONFLICT DO NOTHING`
+
+	// Prune only if the follow no longer exists, as
+	// events may be relayed after a follow back
+	pruneTimelineSQL = `DELETE FROM timelines
+		WHERE user_id=$1 AND author_id=$2 AND NOT EXISTS
+		  (SELECT 1 FROM followers WHERE follower_id=$1 AND followee_id=$2)`
 )
 
 // InitDB creates a postgres database instance using the given connection
 // information
 func InitDB(user, pass, host, port, database string) (*sqlx.DB, error) {
-	db, err := sqlx.Open(
-		"postgres",
-		"postgres://"+user+":"+pass+"@"+host+":"+port+"/"+database)
+	return OpenDB("postgres://" + user + ":" + pass + "@" + host + ":" + port + "/" + database)
+}
+
+// OpenDB creates a postgres database instance using the given
+// connection string
+func OpenDB(dsn string) (*sqlx.DB, error) {
+	db, err := sqlx.Open("postgres", dsn)
 	if err != nil {
 		return nil, err
 	}
@@ -109,3 +571,70 @@ func mapperFunc(name string) string {
 	}
 	return buf.String()
 }
+
+// Trending SQL queries
+const (
+	lockTrendingSQL = `SELECT pg_try_advisory_xact_lock(hashtext('meirl_trending:' || $1))`
+
+	clearTrendingSQL = `DELETE FROM trending_posts WHERE span=$1`
+
+	// Velocity is keks per hour over the part of the window the post
+	// existed for, which is at least an hour so that the first kek of
+	// a brand-new post does not outrank everything
+	rankTrendingSQL = `INSERT INTO trending_posts (span, post_id, rank, velocity)
+		SELECT $1, post_id, row_number() OVER (ORDER BY velocity DESC, post_id DESC), velocity
+		FROM (SELECT post_keks.post_id, COUNT(*)::float8 / GREATEST(
+		    EXTRACT(EPOCH FROM now() - GREATEST(posts.created_at,
+		      now() - $2 * interval '1 millisecond'))::float8 / 3600, 1) AS velocity
+		  FROM post_keks INNER JOIN posts ON posts.id=post_keks.post_id
+		  WHERE post_keks.created_at > now() - $2 * interval '1 millisecond'
+		  GROUP BY post_keks.post_id, posts.created_at
+		  ORDER BY velocity DESC, post_keks.post_id DESC
+		  LIMIT $3) AS velocities`
+)
+
+// Search SQL queries
+const (
+	// Snippets are delimited by the searchMarkStart and searchMarkStop
+	// control characters, which are removed from contents so that only
+	// matches are delimited. Snippets of the results of a page are
+	// computed after sorting and limiting
+	searchPostsSQL = selectPostSQL + `,
+		ts_rank_cd(posts.search, query)::float8 AS rank,
+		ts_headline('english', translate(posts.contents, E'\x01\x02', ''), query,
+		  'StartSel=' || chr(1) || ', StopSel=' || chr(2) ||
+		  ', MaxFragments=2, MinWords=10, MaxWords=30') AS snippet
+		FROM posts, websearch_to_tsquery('english', $1) AS query
+		WHERE posts.search @@ query`
+
+	searchRankField = "ts_rank_cd(posts.search, query)::float8"
+
+	selectUserMatchSQL = `SELECT users.id, users.created_at, users.updated_at,
+		users.username, users.actual_name, users.dob,
+		(SELECT COUNT(*) FROM followers WHERE followers.followee_id=users.id) AS num_followers`
+
+	// Matches are ranked 2 for the exact username, 1 for a username
+	// prefix or by the similarity of the actual name otherwise, and
+	// boosted by the log of follower counts. $1 is the query and $2
+	// the lowercase LIKE pattern of usernames it prefixes
+	searchUsersSQL = `SELECT id, created_at, updated_at, username, actual_name, dob, num_followers
+		FROM (` + selectUserMatchSQL + `,
+		    GREATEST(CASE WHEN lower(users.username)=lower($1) THEN 2
+		      WHEN lower(users.username) LIKE $2 THEN 1 ELSE 0 END,
+		      similarity(users.actual_name, $1)) AS rank
+		  FROM users
+		  WHERE NOT users.disabled
+		    AND (lower(users.username) LIKE $2 OR users.actual_name % $1)) AS matches
+		ORDER BY rank * (1 + ln(1 + num_followers) / 10) DESC, id DESC
+		LIMIT $3`
+
+	// Only the first usernames with the prefix are candidates,
+	// so that completing short prefixes stays cheap
+	completeUsernamesSQL = `SELECT id, created_at, updated_at, username, actual_name, dob, num_followers
+		FROM (` + selectUserMatchSQL + `
+		  FROM users
+		  WHERE NOT users.disabled AND lower(users.username) LIKE $1
+		  ORDER BY lower(users.username) LIMIT 100) AS candidates
+		ORDER BY num_followers DESC, username
+		LIMIT $2`
+)
@@ -0,0 +1,31 @@
+package postgres
+
+import "github.com/jmoiron/sqlx"
+
+const countRowsSQL = `SELECT
+	(SELECT COUNT(*) FROM users) AS users,
+	(SELECT COUNT(*) FROM users WHERE disabled) AS disabled_users,
+	(SELECT COUNT(*) FROM followers) AS follows,
+	(SELECT COUNT(*) FROM posts) AS posts,
+	(SELECT COUNT(*) FROM post_keks) AS keks,
+	(SELECT COUNT(*) FROM post_nos) AS nos`
+
+// Counts holds the number of rows within the MeIRL tables
+type Counts struct {
+	Users         int64
+	DisabledUsers int64
+	Follows       int64
+	Posts         int64
+	Keks          int64
+	Nos           int64
+}
+
+// CountRows returns the number of rows within the MeIRL tables
+func CountRows(db *sqlx.DB) (*Counts, error) {
+	var c Counts
+	err := db.Get(&c, countRowsSQL)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
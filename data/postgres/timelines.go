@@ -0,0 +1,59 @@
+package postgres
+
+import (
+	"github.com/boxtown/meirl/data"
+	"github.com/boxtown/meirl/realtime"
+	"github.com/jmoiron/sqlx"
+)
+
+// TimelineFanout is a realtime.Publisher that maintains the
+// materialized timelines read by the PostStore returned by
+// NewTimelinePostStore. It is meant to be an outbox sink, so
+// that every post, follow and unfollow is eventually reflected.
+// Handling an event more than once is harmless
+type TimelineFanout struct {
+	db          *sqlx.DB
+	fanoutLimit int
+}
+
+// NewTimelineFanout returns a newly constructed TimelineFanout. Posts
+// by authors with more than fanoutLimit followers are not fanned out
+// and are instead read from the posts table when feeds are retrieved
+func NewTimelineFanout(db *sqlx.DB, fanoutLimit int) *TimelineFanout {
+	return &TimelineFanout{db, fanoutLimit}
+}
+
+// Publish updates the timelines affected by the event
+func (f *TimelineFanout) Publish(e realtime.Event) error {
+	var err error
+	switch e.Type {
+	case realtime.PostCreated:
+		err = f.fanOut(e.ID, e.UserID)
+	case realtime.UserFollowed:
+		_, err = f.db.Exec(backfillTimelineSQL, e.UserID, e.ID)
+	case realtime.UserUnFollowed:
+		_, err = f.db.Exec(pruneTimelineSQL, e.UserID, e.ID)
+	}
+	// deleted posts and users are removed from
+	// timelines by foreign key cascades
+	if err != nil {
+		return data.NewError(err)
+	}
+	return nil
+}
+
+// fanOut inserts the post into the timelines of the followers of its
+// author, unless the author has too many followers, in which case the
+// author becomes a pull author
+func (f *TimelineFanout) fanOut(postID, authorID int64) error {
+	var followers int
+	if err := f.db.Get(&followers, countFollowersSQL, authorID); err != nil {
+		return err
+	}
+	if followers > f.fanoutLimit {
+		_, err := f.db.Exec(addPullAuthorSQL, authorID)
+		return err
+	}
+	_, err := f.db.Exec(fanOutPostSQL, postID)
+	return err
+}
@@ -0,0 +1,151 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/boxtown/gotag"
+	"github.com/boxtown/meirl/data"
+	"github.com/boxtown/meirl/data/datatest"
+	"github.com/boxtown/meirl/realtime"
+	"github.com/jmoiron/sqlx"
+)
+
+func TestTimelineFanout(t *testing.T) {
+	gotag.Test(gotag.Integration, t, func(t gotag.T) {
+		whileConnectedToTestDb(testDbName, func(db *sqlx.DB) error {
+			defer cleanupOutboxTest(t, db)
+
+			ids, err := populateNotificationUsers(t, db, 3)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			author, follower, later := ids[0], ids[1], ids[2]
+			users, posts := NewUserStore(db), NewTimelinePostStore(db)
+			fanout := NewTimelineFanout(db, 10)
+
+			if err := users.Follow(follower, author); err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			postID, err := posts.Create(datatest.ExamplePost(author))
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			// publishing twice is harmless
+			for i := 0; i < 2; i++ {
+				err = fanout.Publish(realtime.Event{Type: realtime.PostCreated, ID: postID, UserID: author})
+				if err != nil {
+					t.Error(err.Error())
+					t.FailNow()
+				}
+			}
+			expectTimeline(t, posts, follower, postID)
+			expectTimeline(t, posts, later)
+
+			// following backfills the timeline
+			if err := users.Follow(later, author); err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			err = fanout.Publish(realtime.Event{Type: realtime.UserFollowed, ID: author, UserID: later})
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			expectTimeline(t, posts, later, postID)
+
+			// unfollowing prunes it
+			if err := users.UnFollow(later, author); err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			err = fanout.Publish(realtime.Event{Type: realtime.UserUnFollowed, ID: author, UserID: later})
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			expectTimeline(t, posts, later)
+			expectTimeline(t, posts, follower, postID)
+
+			// a follow relayed after the matching unfollow backfills nothing
+			err = fanout.Publish(realtime.Event{Type: realtime.UserFollowed, ID: author, UserID: later})
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			expectTimeline(t, posts, later)
+			return nil
+		})
+	})
+}
+
+func TestTimelineFanoutPullAuthors(t *testing.T) {
+	gotag.Test(gotag.Integration, t, func(t gotag.T) {
+		whileConnectedToTestDb(testDbName, func(db *sqlx.DB) error {
+			defer cleanupOutboxTest(t, db)
+
+			ids, err := populateNotificationUsers(t, db, 2)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			author, follower := ids[0], ids[1]
+			users, posts := NewUserStore(db), NewTimelinePostStore(db)
+			// every author with a follower is a pull author
+			fanout := NewTimelineFanout(db, 0)
+
+			if err := users.Follow(follower, author); err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			postID, err := posts.Create(datatest.ExamplePost(author))
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			err = fanout.Publish(realtime.Event{Type: realtime.PostCreated, ID: postID, UserID: author})
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			var materialized int
+			if err := db.Get(&materialized, "SELECT COUNT(*) FROM timelines"); err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			if materialized != 0 {
+				t.Errorf("Expected posts by pull authors not to be fanned out, got %d", materialized)
+				t.Fail()
+			}
+			// the post is read from the posts table instead
+			expectTimeline(t, posts, follower, postID)
+			expectTimeline(t, posts, author, postID)
+			return nil
+		})
+	})
+}
+
+func expectTimeline(t gotag.T, posts *PostStore, userID int64, postIDs ...int64) {
+	feed, err := posts.Feed(
+		userID,
+		data.ListOptions{Marker: time.Now().Add(time.Minute), Desc: true},
+		data.PostSortByDate,
+	)
+	if err != nil {
+		t.Error(err.Error())
+		t.FailNow()
+	}
+	if len(feed) != len(postIDs) {
+		t.Errorf("Expected %d posts in the feed of %d, got %d", len(postIDs), userID, len(feed))
+		t.FailNow()
+	}
+	for i, id := range postIDs {
+		if feed[i].ID != id {
+			t.Errorf("Expected post %d in the feed of %d, got %d", id, userID, feed[i].ID)
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package postgres
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/boxtown/meirl/data"
+	"github.com/jmoiron/sqlx"
+)
+
+// TrendingAggregator periodically ranks the posts with the most keks
+// per hour over each trending window, so that trending posts are read
+// rather than computed per request. Any number of aggregators may run
+// against the same database, as only one at a time ranks each window
+type TrendingAggregator struct {
+	db       *sqlx.DB
+	onError  func(error)
+	interval time.Duration
+	limit    int
+
+	done    chan struct{}
+	stopped chan struct{}
+	once    sync.Once
+}
+
+// NewTrendingAggregator returns a newly constructed TrendingAggregator,
+// calling onError with errors ranking a window
+func NewTrendingAggregator(db *sqlx.DB, onError func(error)) *TrendingAggregator {
+	return &TrendingAggregator{
+		db:       db,
+		onError:  onError,
+		interval: time.Minute,
+		limit:    1000,
+		done:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+}
+
+// Run ranks every window immediately and then
+// once a minute until Stop is called
+func (a *TrendingAggregator) Run() {
+	defer close(a.stopped)
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+	for {
+		for _, window := range data.TrendingWindows {
+			if err := a.Aggregate(window); err != nil {
+				a.onError(fmt.Errorf("ranking trending posts over %s: %s", window, err))
+			}
+		}
+		select {
+		case <-a.done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop signals Run to return once the
+// window being ranked is ranked
+func (a *TrendingAggregator) Stop() {
+	a.once.Do(func() {
+		close(a.done)
+	})
+}
+
+// Wait waits for Run to return after Stop is called
+func (a *TrendingAggregator) Wait() {
+	<-a.stopped
+}
+
+// Aggregate replaces the trending posts of the window with the posts
+// with the most keks per hour over it. The window is left as is if
+// another aggregator is ranking it
+func (a *TrendingAggregator) Aggregate(window data.TrendingWindow) error {
+	return inTx(a.db, func(tx *sqlx.Tx) error {
+		var locked bool
+		if err := tx.Get(&locked, lockTrendingSQL, string(window)); err != nil || !locked {
+			return err
+		}
+		if _, err := tx.Exec(clearTrendingSQL, string(window)); err != nil {
+			return err
+		}
+		millis := int64(window.Duration() / time.Millisecond)
+		_, err := tx.Exec(rankTrendingSQL, string(window), millis, a.limit)
+		return err
+	})
+}
@@ -0,0 +1,111 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/boxtown/gotag"
+	"github.com/boxtown/meirl/data"
+	"github.com/boxtown/meirl/data/datatest"
+	"github.com/jmoiron/sqlx"
+)
+
+func TestTrendingAggregator(t *testing.T) {
+	gotag.Test(gotag.Integration, t, func(t gotag.T) {
+		whileConnectedToTestDb(testDbName, func(db *sqlx.DB) error {
+			defer cleanupPostStoreTest(t, db)
+
+			ids, err := populateNotificationUsers(t, db, 3)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			store := NewPostStore(db)
+			postIDs := make([]int64, 3)
+			for i := range postIDs {
+				postIDs[i], err = store.Create(datatest.ExamplePost(ids[0]))
+				if err != nil {
+					t.Error(err.Error())
+					t.FailNow()
+				}
+			}
+			// the first post is kekked by everyone but only within the
+			// week, the second by one user within the hour and the
+			// third not at all
+			for _, id := range ids {
+				if err := store.React(postIDs[0], id, data.ReactionKek); err != nil {
+					t.Error(err.Error())
+					t.FailNow()
+				}
+			}
+			if err := store.React(postIDs[1], ids[1], data.ReactionKek); err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			_, err = db.Exec("UPDATE post_keks SET created_at=now() - interval '2 days' WHERE post_id=$1", postIDs[0])
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+
+			aggregator := NewTrendingAggregator(db, nil)
+			for _, window := range data.TrendingWindows {
+				if err := aggregator.Aggregate(window); err != nil {
+					t.Error(err.Error())
+					t.FailNow()
+				}
+			}
+			// aggregating again replaces the ranking
+			if err := aggregator.Aggregate(data.TrendingHour); err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+
+			expected := map[data.TrendingWindow][]int64{
+				data.TrendingHour: {postIDs[1]},
+				data.TrendingDay:  {postIDs[1]},
+				data.TrendingWeek: {postIDs[0], postIDs[1]},
+			}
+			for window, expectedIDs := range expected {
+				posts, err := store.Trending(window, data.ListOptions{})
+				if err != nil {
+					t.Error(err.Error())
+					t.FailNow()
+				}
+				if len(posts) != len(expectedIDs) {
+					t.Errorf("Expected %d posts trending over %s, got %d", len(expectedIDs), window, len(posts))
+					t.Fail()
+					continue
+				}
+				for i, id := range expectedIDs {
+					if posts[i].ID != id {
+						t.Errorf("Expected post %d at %d over %s, got %d", id, i, window, posts[i].ID)
+						t.Fail()
+					}
+				}
+			}
+
+			// pages continue after the rank of the marker
+			posts, err := store.Trending(data.TrendingWeek, data.ListOptions{Marker: int64(1)})
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			if len(posts) != 1 || posts[0].ID != postIDs[1] || posts[0].Marker != "2" {
+				t.Errorf("Expected the second ranked post after the marker, got %v", posts)
+				t.Fail()
+			}
+
+			recent, err := store.Recent(data.ListOptions{Marker: time.Now().Add(time.Minute), Desc: true})
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			if len(recent) != 3 || recent[0].ID != postIDs[2] {
+				t.Errorf("Expected every post newest first, got %v", recent)
+				t.Fail()
+			}
+			return nil
+		})
+	})
+}
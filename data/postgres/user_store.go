@@ -4,6 +4,7 @@ import (
 	"database/sql"
 
 	"github.com/boxtown/meirl/data"
+	"github.com/boxtown/meirl/realtime"
 	"github.com/jmoiron/sqlx"
 )
 
@@ -22,11 +23,17 @@ func NewUserStore(db *sqlx.DB) *UserStore {
 // Create creates a record for the given user in Postgres
 func (store *UserStore) Create(user *data.User) (int64, error) {
 	var id int64
-	err := store.db.Get(&id, createUserSQL,
-		user.Username, user.Email, user.Password,
-		user.ActualName, user.DOB.Time)
+	err := inTx(store.db, func(tx *sqlx.Tx) error {
+		err := tx.Get(&id, createUserSQL,
+			user.Username, user.Email, user.Password,
+			user.ActualName, user.DOB.Time, user.Private)
+		if err != nil {
+			return err
+		}
+		return recordEvent(tx, realtime.Event{Type: realtime.UserCreated, ID: id, UserID: id})
+	})
 	if err != nil {
-		return 0, data.NewError(err)
+		return 0, err
 	}
 	return id, nil
 }
@@ -72,44 +79,182 @@ func (store *UserStore) GetByEmail(email string) (*data.User, error) {
 
 // Update updates a user by id
 func (store *UserStore) Update(id int64, user *data.User) error {
-	_, err := store.db.Exec(updateUserSQL,
-		user.Username, user.Email, user.ActualName,
-		user.DOB.Time, id)
-	if err != nil {
-		return data.NewError(err)
-	}
-	return nil
+	return store.execRecordingEvent(realtime.Event{Type: realtime.UserUpdated, ID: id, UserID: id},
+		updateUserSQL, user.Username, user.Email, user.ActualName, user.DOB.Time, id)
 }
 
 // Delete deletes a given user by id
 func (store *UserStore) Delete(id int64) error {
-	_, err := store.db.Exec(deleteUserSQL, id)
-	if err != nil {
+	return store.execRecordingEvent(realtime.Event{Type: realtime.UserDeleted, ID: id, UserID: id},
+		deleteUserSQL, id)
+}
+
+// SetDisabled disables or re-enables the user with the given id.
+// Disabled users may not log in or use tokens issued to them. Returns
+// data.ErrNoEnt if the user does not exist
+func (store *UserStore) SetDisabled(id int64, disabled bool) error {
+	return inTx(store.db, func(tx *sqlx.Tx) error {
+		if err := execForUser(tx, setUserDisabledSQL, disabled, id); err != nil {
+			return err
+		}
+		return recordEvent(tx, realtime.Event{Type: realtime.UserUpdated, ID: id, UserID: id})
+	})
+}
+
+// SetPrivate makes the user with the given id private or public.
+// Making a user public approves their pending follow requests.
+// Returns data.ErrNoEnt if the user does not exist
+func (store *UserStore) SetPrivate(id int64, private bool) error {
+	return inTx(store.db, func(tx *sqlx.Tx) error {
+		if err := execForUser(tx, setUserPrivateSQL, private, id); err != nil {
+			return err
+		}
+		if !private {
+			if _, err := approveFollowRequests(tx, id, approveAllFollowRequestsSQL, id); err != nil {
+				return err
+			}
+		}
+		return recordEvent(tx, realtime.Event{Type: realtime.UserUpdated, ID: id, UserID: id})
+	})
+}
+
+// SetPassword replaces the stored password hash of the user with
+// the given id. Returns data.ErrNoEnt if the user does not exist
+func (store *UserStore) SetPassword(id int64, password string) error {
+	err := execForUser(store.db, setUserPasswordSQL, password, id)
+	if err != nil && err != data.ErrNoEnt {
 		return data.NewError(err)
 	}
+	return err
+}
+
+func execForUser(db sqlx.Execer, query string, args ...interface{}) error {
+	result, err := db.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return data.ErrNoEnt
+	}
 	return nil
 }
 
+// execRecordingEvent executes the query, recording the
+// event in the same transaction if any rows were affected
+func (store *UserStore) execRecordingEvent(e realtime.Event, query string, args ...interface{}) error {
+	return inTx(store.db, func(tx *sqlx.Tx) error {
+		result, err := tx.Exec(query, args...)
+		if err != nil {
+			return err
+		}
+		n, err := result.RowsAffected()
+		if err != nil || n == 0 {
+			return err
+		}
+		return recordEvent(tx, e)
+	})
+}
+
 // Follow creates a follow relationship between
 // the follower and followee
 func (store *UserStore) Follow(followerID, followeeID int64) error {
-	_, err := store.db.Exec(followUserSQL, followerID, followeeID)
+	return store.execRecordingEvent(
+		realtime.Event{Type: realtime.UserFollowed, ID: followeeID, UserID: followerID},
+		followUserSQL, followerID, followeeID)
+}
+
+// UnFollow idempotently deletes a follow relationship
+// between a follower and followee
+func (store *UserStore) UnFollow(followerID, followeeID int64) error {
+	return store.execRecordingEvent(
+		realtime.Event{Type: realtime.UserUnFollowed, ID: followeeID, UserID: followerID},
+		unFollowUserSQL, followerID, followeeID)
+}
+
+// IsFollowing returns true if the follower follows the followee
+func (store *UserStore) IsFollowing(followerID, followeeID int64) (bool, error) {
+	var following bool
+	err := store.db.Get(&following, isFollowingSQL, followerID, followeeID)
+	if err != nil {
+		return false, data.NewError(err)
+	}
+	return following, nil
+}
+
+// RequestFollow idempotently requests that the follower follow
+// the followee. Nothing is requested if the follower already
+// follows the followee or if either blocked the other
+func (store *UserStore) RequestFollow(followerID, followeeID int64) error {
+	_, err := store.db.Exec(requestFollowSQL, followerID, followeeID)
 	if err != nil {
 		return data.NewError(err)
 	}
 	return nil
 }
 
-// UnFollow idempotently deletes a follow relationship
-// between a follower and followee
-func (store *UserStore) UnFollow(followerID, followeeID int64) error {
-	_, err := store.db.Exec(unFollowUserSQL, followerID, followeeID)
+// ApproveFollow turns the request of the follower to follow the
+// followee into a follow relationship. Returns data.ErrNoEnt if
+// there is no such request
+func (store *UserStore) ApproveFollow(followerID, followeeID int64) error {
+	if followerID <= 0 {
+		return data.ErrNoEnt
+	}
+	return inTx(store.db, func(tx *sqlx.Tx) error {
+		n, err := approveFollowRequests(tx, followeeID, approveFollowRequestSQL, followeeID, followerID)
+		if err == nil && n == 0 {
+			return data.ErrNoEnt
+		}
+		return err
+	})
+}
+
+// DeleteFollowRequest idempotently deletes the request of
+// the follower to follow the followee
+func (store *UserStore) DeleteFollowRequest(followerID, followeeID int64) error {
+	_, err := store.db.Exec(deleteFollowRequestSQL, followerID, followeeID)
 	if err != nil {
 		return data.NewError(err)
 	}
 	return nil
 }
 
+// FollowRequests returns a slice of users that requested to
+// follow the user with the given id, by id
+func (store *UserStore) FollowRequests(id int64, options data.ListOptions) ([]data.User, error) {
+	if options.Limit <= 0 || options.Limit > 1000 {
+		options.Limit = 10
+	}
+	paginator := createUserPaginator(options, data.UserSortByID)
+	query, args := paginator.paginate(getFollowRequestsByIDSQL, true, options.Marker, id)
+	var requesters []data.User
+	err := store.db.Select(&requesters, query, args...)
+	if err != nil {
+		return nil, data.NewError(err)
+	}
+	return requesters, nil
+}
+
+// approveFollowRequests executes a query approving requests to
+// follow the followee, recording an event for each new follower.
+// Returns the number of new followers
+func approveFollowRequests(tx *sqlx.Tx, followeeID int64, query string, args ...interface{}) (int, error) {
+	var followers []int64
+	if err := tx.Select(&followers, query, args...); err != nil {
+		return 0, err
+	}
+	for _, id := range followers {
+		err := recordEvent(tx, realtime.Event{Type: realtime.UserFollowed, ID: followeeID, UserID: id})
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(followers), nil
+}
+
 // Followers returns a slice of users that are the Followers
 // of the user with the given id
 func (store *UserStore) Followers(id int64, options data.ListOptions, sort data.UserSortMethod) ([]data.User, error) {
@@ -117,9 +262,9 @@ func (store *UserStore) Followers(id int64, options data.ListOptions, sort data.
 		options.Limit = 10
 	}
 	paginator := createUserPaginator(options, sort)
-	query := paginator.seekingQuery(getFollowersByIDSQL, 2, true)
+	query, args := paginator.paginate(getFollowersByIDSQL, true, options.Marker, id)
 	var followers []data.User
-	err := store.db.Select(&followers, query, id, options.Marker)
+	err := store.db.Select(&followers, query, args...)
 	if err != nil {
 		return nil, data.NewError(err)
 	}
@@ -133,15 +278,26 @@ func (store *UserStore) Following(id int64, options data.ListOptions, sort data.
 		options.Limit = 10
 	}
 	paginator := createUserPaginator(options, sort)
-	query := paginator.seekingQuery(getFollowingByIDSQL, 2, true)
+	query, args := paginator.paginate(getFollowingByIDSQL, true, options.Marker, id)
 	var following []data.User
-	err := store.db.Select(&following, query, id, options.Marker)
+	err := store.db.Select(&following, query, args...)
 	if err != nil {
 		return nil, data.NewError(err)
 	}
 	return following, nil
 }
 
+// FollowingIDs returns the ids of every user the user
+// with the given id is following
+func (store *UserStore) FollowingIDs(id int64) ([]int64, error) {
+	var ids []int64
+	err := store.db.Select(&ids, getFollowingIDsSQL, id)
+	if err != nil {
+		return nil, data.NewError(err)
+	}
+	return ids, nil
+}
+
 func createUserPaginator(options data.ListOptions, sort data.UserSortMethod) *paginator {
 	paginator := paginator{
 		limit: options.Limit,
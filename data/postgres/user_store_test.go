@@ -415,6 +415,72 @@ func TestDeleteUserIsIdempotent(t *testing.T) {
 	})
 }
 
+func TestSetUserDisabled(t *testing.T) {
+	gotag.Test(gotag.Integration, t, func(t gotag.T) {
+		whileConnectedToTestDb(testDbName, func(db *sqlx.DB) error {
+			defer cleanUserStoreTest(t, db)
+
+			store := NewUserStore(db)
+			id, err := store.Create(datatest.ExampleUser())
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			err = store.SetDisabled(id, true)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			user, err := store.GetByUsername(datatest.ExampleUser().Username)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			if !user.Disabled {
+				t.Error("Expected user to be disabled")
+				t.Fail()
+			}
+
+			err = store.SetDisabled(id+1, true)
+			if err != data.ErrNoEnt {
+				t.Errorf("Expected ErrNoEnt disabling a missing user, got %v", err)
+				t.Fail()
+			}
+			return nil
+		})
+	})
+}
+
+func TestSetUserPassword(t *testing.T) {
+	gotag.Test(gotag.Integration, t, func(t gotag.T) {
+		whileConnectedToTestDb(testDbName, func(db *sqlx.DB) error {
+			defer cleanUserStoreTest(t, db)
+
+			store := NewUserStore(db)
+			id, err := store.Create(datatest.ExampleUser())
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			err = store.SetPassword(id, "new-hash")
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			user, err := store.Get(id)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			if user.Password != "new-hash" {
+				t.Errorf("Expected password new-hash, got %s", user.Password)
+				t.Fail()
+			}
+			return nil
+		})
+	})
+}
+
 func TestFollowUser(t *testing.T) {
 	gotag.Test(gotag.Integration, t, func(t gotag.T) {
 		whileConnectedToTestDb(testDbName, func(db *sqlx.DB) error {
@@ -570,6 +636,134 @@ func TestGetFollowing(t *testing.T) {
 	})
 }
 
+func TestFollowRequests(t *testing.T) {
+	gotag.Test(gotag.Integration, t, func(t gotag.T) {
+		whileConnectedToTestDb(testDbName, func(db *sqlx.DB) error {
+			defer cleanupBlockStoreTest(t, db)
+
+			ids, err := populateNotificationUsers(t, db, 3)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			private, requester, other := ids[0], ids[1], ids[2]
+			users, posts, search := NewUserStore(db), NewPostStore(db), NewSearchStore(db)
+			if err := users.SetPrivate(private, true); err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			postID, err := posts.Create(datatest.ExamplePost(private))
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+
+			// requesting twice is harmless
+			for i := 0; i < 2; i++ {
+				if err := users.RequestFollow(requester, private); err != nil {
+					t.Error(err.Error())
+					t.FailNow()
+				}
+			}
+			requests, err := users.FollowRequests(private, data.ListOptions{})
+			if err != nil || len(requests) != 1 || requests[0].ID != requester {
+				t.Errorf("Expected a request by %d, got %v and %v", requester, requests, err)
+				t.FailNow()
+			}
+			query := data.PostQuery{Text: "test", ViewerID: requester}
+			results, err := search.SearchPosts(query, data.ListOptions{})
+			if err != nil || len(results) != 0 {
+				t.Errorf("Expected the posts of the private user to be hidden, got %v and %v", results, err)
+				t.Fail()
+			}
+			recent, err := posts.Recent(data.ListOptions{})
+			if err != nil || len(recent) != 0 {
+				t.Errorf("Expected no recent public posts, got %v and %v", recent, err)
+				t.Fail()
+			}
+
+			// the replies of the private user are hidden from threads
+			// along with the replies to them
+			rootID, err := posts.Create(&data.Post{AuthorID: other, Contents: []byte("reply")})
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			reply := &data.Post{AuthorID: private, Contents: []byte("reply"), ParentID: rootID}
+			replyID, err := posts.Create(reply)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			nested := &data.Post{AuthorID: other, Contents: []byte("reply"), ParentID: replyID}
+			nestedID, err := posts.Create(nested)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			expectThread := func(ancestors, descendants int) {
+				a, err := posts.Ancestors(nestedID, requester, 10)
+				if err != nil || len(a) != ancestors {
+					t.Errorf("Expected %d ancestors, got %v and %v", ancestors, a, err)
+					t.Fail()
+				}
+				d, err := posts.Descendants(rootID, requester, 3, 100, data.ListOptions{})
+				if err != nil || len(d) != descendants {
+					t.Errorf("Expected %d descendants, got %v and %v", descendants, d, err)
+					t.Fail()
+				}
+			}
+			expectThread(1, 0)
+
+			if err := users.ApproveFollow(requester, private); err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			if err := users.ApproveFollow(requester, private); err != data.ErrNoEnt {
+				t.Errorf("Expected approving twice to return data.ErrNoEnt, got %v", err)
+				t.Fail()
+			}
+			following, err := users.IsFollowing(requester, private)
+			if err != nil || !following {
+				t.Errorf("Expected %d to follow %d, got %v and %v", requester, private, following, err)
+				t.FailNow()
+			}
+			expectTimeline(t, posts, requester, replyID, postID)
+			expectThread(2, 2)
+			results, err = search.SearchPosts(query, data.ListOptions{})
+			if err != nil || len(results) != 1 || results[0].ID != postID {
+				t.Errorf("Expected followers to find the post, got %v and %v", results, err)
+				t.Fail()
+			}
+
+			// making the user public approves pending requests
+			if err := users.RequestFollow(other, private); err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			if err := users.ApproveFollow(0, private); err != data.ErrNoEnt {
+				t.Errorf("Expected approving no requester to return data.ErrNoEnt, got %v", err)
+				t.Fail()
+			}
+			following, err = users.IsFollowing(other, private)
+			if err != nil || following {
+				t.Errorf("Expected %d not to follow %d yet, got %v and %v", other, private, following, err)
+				t.Fail()
+			}
+			if err := users.SetPrivate(private, false); err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			following, err = users.IsFollowing(other, private)
+			if err != nil || !following {
+				t.Errorf("Expected %d to follow %d, got %v and %v", other, private, following, err)
+				t.Fail()
+			}
+			return nil
+		})
+	})
+}
+
 func cleanUserStoreTest(t gotag.T, db *sqlx.DB) {
 	_, err := db.Exec("DELETE FROM followers")
 	if err != nil {
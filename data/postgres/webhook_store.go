@@ -0,0 +1,165 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/boxtown/meirl/data"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// WebhookStore is a PostgreSQL specific implementation
+// of data.WebhookStore
+type WebhookStore struct {
+	db *sqlx.DB
+}
+
+// NewWebhookStore returns a newly constructed WebhookStore
+// with the given database reference
+func NewWebhookStore(db *sqlx.DB) *WebhookStore {
+	return &WebhookStore{db}
+}
+
+// webhookRow scans a webhook, whose events are a Postgres array
+type webhookRow struct {
+	data.Webhook
+	EventList pq.StringArray
+}
+
+func (row *webhookRow) webhook() data.Webhook {
+	w := row.Webhook
+	w.Events = []string(row.EventList)
+	return w
+}
+
+// Create creates a record for the given webhook in Postgres
+func (store *WebhookStore) Create(webhook *data.Webhook) (int64, error) {
+	var id int64
+	err := store.db.Get(&id, createWebhookSQL,
+		webhook.UserID, webhook.URL, webhook.Secret, pq.StringArray(webhook.Events))
+	if err != nil {
+		return 0, data.NewError(err)
+	}
+	return id, nil
+}
+
+// Get retrieves a webhook by id
+func (store *WebhookStore) Get(id int64) (*data.Webhook, error) {
+	var row webhookRow
+	err := store.db.Get(&row, getWebhookByIDSQL, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, data.ErrNoEnt
+		}
+		return nil, data.NewError(err)
+	}
+	webhook := row.webhook()
+	return &webhook, nil
+}
+
+// List returns the webhooks registered by the user with
+// the given id, or the admin webhooks if the id is 0
+func (store *WebhookStore) List(userID int64) ([]data.Webhook, error) {
+	if userID == 0 {
+		return store.selectWebhooks(getAdminWebhooksSQL)
+	}
+	return store.selectWebhooks(getWebhooksByUserIDSQL, userID)
+}
+
+// Delete deletes a webhook and its deliveries by id
+func (store *WebhookStore) Delete(id int64) error {
+	_, err := store.db.Exec(deleteWebhookSQL, id)
+	if err != nil {
+		return data.NewError(err)
+	}
+	return nil
+}
+
+// Subscribed returns the webhooks subscribed to the event that
+// either belong to one of the given users or are admin webhooks
+func (store *WebhookStore) Subscribed(event string, userIDs []int64) ([]data.Webhook, error) {
+	return store.selectWebhooks(getSubscribedWebhooksSQL, event, pq.Array(userIDs))
+}
+
+func (store *WebhookStore) selectWebhooks(query string, args ...interface{}) ([]data.Webhook, error) {
+	var rows []webhookRow
+	err := store.db.Select(&rows, query, args...)
+	if err != nil {
+		return nil, data.NewError(err)
+	}
+	webhooks := make([]data.Webhook, len(rows))
+	for i := range rows {
+		webhooks[i] = rows[i].webhook()
+	}
+	return webhooks, nil
+}
+
+// CreateDelivery creates a pending delivery of the event to the webhook
+// that is due immediately. If the outbox event was already delivered to
+// the webhook, the ID of the existing delivery is returned instead
+func (store *WebhookStore) CreateDelivery(delivery *data.WebhookDelivery) (int64, error) {
+	var id int64
+	err := store.db.Get(&id, createDeliverySQL,
+		delivery.WebhookID, delivery.EventID, delivery.Event, []byte(delivery.Payload))
+	if err != nil {
+		return 0, data.NewError(err)
+	}
+	return id, nil
+}
+
+// GetDelivery retrieves a webhook delivery by id
+func (store *WebhookStore) GetDelivery(id int64) (*data.WebhookDelivery, error) {
+	var d data.WebhookDelivery
+	err := store.db.Get(&d, getDeliveryByIDSQL, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, data.ErrNoEnt
+		}
+		return nil, data.NewError(err)
+	}
+	return &d, nil
+}
+
+// Deliveries returns the delivery log of the webhook
+// with the given id, sorted by delivery id
+func (store *WebhookStore) Deliveries(webhookID int64, options data.ListOptions) ([]data.WebhookDelivery, error) {
+	if options.Limit <= 10 || options.Limit > 1000 {
+		options.Limit = 10
+	}
+	paginator := paginator{
+		field: "webhook_deliveries.id",
+		limit: options.Limit,
+		desc:  options.Desc,
+	}
+	query, args := paginator.paginate(getDeliveriesByWebhookIDSQL, true, options.Marker, webhookID)
+	var deliveries []data.WebhookDelivery
+	err := store.db.Select(&deliveries, query, args...)
+	if err != nil {
+		return nil, data.NewError(err)
+	}
+	return deliveries, nil
+}
+
+// ClaimDeliveries claims up to limit pending deliveries that are due.
+// Claimed deliveries are not due again until lease has passed, so
+// concurrent workers do not attempt the same delivery
+func (store *WebhookStore) ClaimDeliveries(limit int, lease time.Duration) ([]data.WebhookDelivery, error) {
+	var deliveries []data.WebhookDelivery
+	err := store.db.Select(&deliveries, claimDeliveriesSQL, limit, int64(lease/time.Millisecond))
+	if err != nil {
+		return nil, data.NewError(err)
+	}
+	return deliveries, nil
+}
+
+// UpdateDelivery records the outcome of an attempted delivery
+func (store *WebhookStore) UpdateDelivery(delivery *data.WebhookDelivery) error {
+	_, err := store.db.Exec(updateDeliverySQL,
+		delivery.Status, delivery.Attempts, delivery.ResponseStatus,
+		delivery.LastError, delivery.NextAttemptAt.Time, delivery.ID)
+	if err != nil {
+		return data.NewError(err)
+	}
+	return nil
+}
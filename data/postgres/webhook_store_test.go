@@ -0,0 +1,222 @@
+package postgres
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/boxtown/gotag"
+	"github.com/boxtown/meirl/data"
+	"github.com/jmoiron/sqlx"
+)
+
+func TestSubscribedWebhooks(t *testing.T) {
+	gotag.Test(gotag.Integration, t, func(t gotag.T) {
+		whileConnectedToTestDb(testDbName, func(db *sqlx.DB) error {
+			defer cleanupWebhookStoreTest(t, db)
+
+			ids, err := populateNotificationUsers(t, db, 2)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			store := NewWebhookStore(db)
+			webhooks := []data.Webhook{
+				{UserID: &ids[0], URL: "http://localhost/a", Secret: "a", Events: []string{"post.created"}},
+				{UserID: &ids[1], URL: "http://localhost/b", Secret: "b", Events: []string{"post.created"}},
+				{URL: "http://localhost/admin", Secret: "admin", Events: []string{"post.created", "user.followed"}},
+			}
+			for i := range webhooks {
+				id, err := store.Create(&webhooks[i])
+				if err != nil {
+					t.Error(err.Error())
+					t.FailNow()
+				}
+				webhooks[i].ID = id
+			}
+
+			stored, err := store.Get(webhooks[2].ID)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			if stored.UserID != nil || len(stored.Events) != 2 || stored.Secret != "admin" {
+				t.Errorf("Expected admin webhook %v, got %v", webhooks[2], stored)
+				t.Fail()
+			}
+
+			subscribed, err := store.Subscribed("post.created", []int64{ids[0]})
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			if len(subscribed) != 2 {
+				t.Errorf("Expected the user and admin webhooks, got %v", subscribed)
+				t.Fail()
+			}
+			for _, w := range subscribed {
+				if w.ID == webhooks[1].ID {
+					t.Error("Expected webhooks of uninvolved users not to be subscribed")
+					t.Fail()
+				}
+			}
+
+			admin, err := store.List(0)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			if len(admin) != 1 || admin[0].ID != webhooks[2].ID {
+				t.Errorf("Expected only the admin webhook, got %v", admin)
+				t.Fail()
+			}
+			return nil
+		})
+	})
+}
+
+func TestClaimAndUpdateDeliveries(t *testing.T) {
+	gotag.Test(gotag.Integration, t, func(t gotag.T) {
+		whileConnectedToTestDb(testDbName, func(db *sqlx.DB) error {
+			defer cleanupWebhookStoreTest(t, db)
+
+			store := NewWebhookStore(db)
+			webhookID, err := store.Create(&data.Webhook{
+				URL:    "http://localhost",
+				Secret: "secret",
+				Events: []string{"post.created"},
+			})
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			payload := json.RawMessage(`{"event": "post.created"}`)
+			id, err := store.CreateDelivery(&data.WebhookDelivery{
+				WebhookID: webhookID,
+				Event:     "post.created",
+				Payload:   payload,
+			})
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+
+			claimed, err := store.ClaimDeliveries(10, time.Minute)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			if len(claimed) != 1 || claimed[0].ID != id || claimed[0].Status != data.DeliveryPending {
+				t.Errorf("Expected pending delivery %d to be claimed, got %v", id, claimed)
+				t.FailNow()
+			}
+			// leased deliveries are not claimed twice
+			again, err := store.ClaimDeliveries(10, time.Minute)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			if len(again) != 0 {
+				t.Errorf("Expected leased delivery not to be claimed, got %v", again)
+				t.Fail()
+			}
+
+			d := claimed[0]
+			d.Status, d.Attempts, d.ResponseStatus = data.DeliverySucceeded, 1, 200
+			err = store.UpdateDelivery(&d)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			deliveries, err := store.Deliveries(webhookID, data.ListOptions{Desc: true})
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			if len(deliveries) != 1 || deliveries[0].Status != data.DeliverySucceeded || deliveries[0].ResponseStatus != 200 {
+				t.Errorf("Expected a succeeded delivery, got %v", deliveries)
+				t.Fail()
+			}
+
+			// deleting a webhook deletes its deliveries
+			err = store.Delete(webhookID)
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			_, err = store.GetDelivery(id)
+			if err != data.ErrNoEnt {
+				t.Errorf("Expected %v, got %v", data.ErrNoEnt, err)
+				t.Fail()
+			}
+			return nil
+		})
+	})
+}
+
+func TestCreateDeliveryOncePerEvent(t *testing.T) {
+	gotag.Test(gotag.Integration, t, func(t gotag.T) {
+		whileConnectedToTestDb(testDbName, func(db *sqlx.DB) error {
+			defer cleanupWebhookStoreTest(t, db)
+
+			store := NewWebhookStore(db)
+			webhookID, err := store.Create(&data.Webhook{
+				URL:    "http://localhost",
+				Secret: "secret",
+				Events: []string{"post.created"},
+			})
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			create := func(eventID int64) int64 {
+				id, err := store.CreateDelivery(&data.WebhookDelivery{
+					WebhookID: webhookID,
+					EventID:   eventID,
+					Event:     "post.created",
+					Payload:   json.RawMessage(`{"event": "post.created"}`),
+				})
+				if err != nil {
+					t.Error(err.Error())
+					t.FailNow()
+				}
+				return id
+			}
+
+			// a relayed event is delivered once
+			first := create(1)
+			if again := create(1); again != first {
+				t.Errorf("Expected event 1 to be delivered once, got deliveries %d and %d", first, again)
+				t.Fail()
+			}
+			if other := create(2); other == first {
+				t.Error("Expected event 2 to be delivered separately")
+				t.Fail()
+			}
+			// events without an outbox id are always delivered
+			if create(0) == create(0) {
+				t.Error("Expected separate deliveries of events without an id")
+				t.Fail()
+			}
+
+			deliveries, err := store.Deliveries(webhookID, data.ListOptions{Limit: 10})
+			if err != nil {
+				t.Error(err.Error())
+				t.FailNow()
+			}
+			if len(deliveries) != 4 || deliveries[0].EventID != 1 || deliveries[2].EventID != 0 {
+				t.Errorf("Expected 4 deliveries, got %v", deliveries)
+				t.Fail()
+			}
+			return nil
+		})
+	})
+}
+
+func cleanupWebhookStoreTest(t gotag.T, db *sqlx.DB) {
+	_, err := db.Exec("DELETE FROM webhooks")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	cleanupPostStoreTest(t, db)
+}
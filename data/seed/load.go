@@ -0,0 +1,184 @@
+package seed
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// ErrNotEmpty is returned when loading a dataset into
+// a database that already contains users or posts
+var ErrNotEmpty = errors.New("database already contains users or posts")
+
+const isEmptySQL = `SELECT NOT EXISTS (SELECT 1 FROM users) AND NOT EXISTS (SELECT 1 FROM posts)`
+
+// sequences are advanced past the explicit IDs of the dataset
+// so that rows created afterwards do not collide
+const setSequencesSQL = `SELECT
+	setval('users_id_seq', (SELECT COALESCE(MAX(id), 0) + 1 FROM users), false),
+	setval('posts_id_seq', (SELECT COALESCE(MAX(id), 0) + 1 FROM posts), false)`
+
+// seeded follows and posts bypass the outbox, so the timelines
+// they would have been fanned out to are filled in directly
+const backfillTimelinesSQL = `INSERT INTO timelines (user_id, post_id, author_id, created_at)
+	SELECT followers.follower_id, posts.id, posts.author_id, posts.created_at
+	FROM posts INNER JOIN followers ON followers.followee_id=posts.author_id
+	ON CONFLICT DO NOTHING`
+
+// table describes how to copy a slice of the dataset into a table
+type table struct {
+	name    string
+	columns []string
+	rows    func(ds *Dataset) [][]interface{}
+}
+
+var tables = []table{
+	{"users", []string{"id", "created_at", "updated_at", "username", "email", "password", "actual_name", "dob"}, userRows},
+	{"followers", []string{"follower_id", "followee_id", "created_at"}, followRows},
+	{"posts", []string{"id", "created_at", "updated_at", "author_id", "contents"}, postRows},
+	{"post_keks", []string{"author_id", "post_id", "created_at"}, kekRows},
+	{"post_nos", []string{"author_id", "post_id", "created_at"}, noRows},
+}
+
+// Load bulk loads the dataset into an empty database using COPY
+// within a single transaction, then backfills the timelines of the
+// seeded follows. Returns ErrNotEmpty if the database already
+// contains users or posts
+func Load(db *sqlx.DB, ds *Dataset) error {
+	tx, err := db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var empty bool
+	err = tx.Get(&empty, isEmptySQL)
+	if err != nil {
+		return err
+	}
+	if !empty {
+		return ErrNotEmpty
+	}
+	for _, t := range tables {
+		err = copyRows(tx, t.name, t.columns, t.rows(ds))
+		if err != nil {
+			return fmt.Errorf("could not copy %s: %s", t.name, err)
+		}
+	}
+	_, err = tx.Exec(setSequencesSQL)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(backfillTimelinesSQL)
+	if err != nil {
+		return fmt.Errorf("could not backfill timelines: %s", err)
+	}
+	return tx.Commit()
+}
+
+func copyRows(tx *sqlx.Tx, name string, columns []string, rows [][]interface{}) error {
+	stmt, err := tx.Prepare(pq.CopyIn(name, columns...))
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if _, err = stmt.Exec(row...); err != nil {
+			stmt.Close()
+			return err
+		}
+	}
+	// an empty Exec flushes the buffered rows
+	if _, err = stmt.Exec(); err != nil {
+		stmt.Close()
+		return err
+	}
+	return stmt.Close()
+}
+
+// WriteSQL writes the dataset as a psql script of COPY statements,
+// suitable for resources/sql/seed.sql
+func WriteSQL(w io.Writer, ds *Dataset) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "-- Generated by meirlctl seed, do not edit")
+	for _, t := range tables {
+		fmt.Fprintf(bw, "\nCOPY %s (%s) FROM stdin;\n", t.name, strings.Join(t.columns, ", "))
+		for _, row := range t.rows(ds) {
+			for i, value := range row {
+				if i > 0 {
+					bw.WriteByte('\t')
+				}
+				bw.WriteString(copyText(value))
+			}
+			bw.WriteByte('\n')
+		}
+		fmt.Fprintln(bw, `\.`)
+	}
+	for _, sql := range []string{setSequencesSQL, backfillTimelinesSQL} {
+		fmt.Fprintf(bw, "\n%s;\n", strings.Replace(sql, "\n\t", "\n    ", -1))
+	}
+	return bw.Flush()
+}
+
+// copyText formats a value in the COPY text format
+func copyText(value interface{}) string {
+	switch v := value.(type) {
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case time.Time:
+		return v.UTC().Format("2006-01-02 15:04:05.999999Z07:00")
+	case string:
+		return copyEscaper.Replace(v)
+	default:
+		return copyEscaper.Replace(fmt.Sprint(v))
+	}
+}
+
+var copyEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+func userRows(ds *Dataset) [][]interface{} {
+	rows := make([][]interface{}, len(ds.Users))
+	for i, u := range ds.Users {
+		rows[i] = []interface{}{u.ID, u.CreatedAt.Time, u.UpdatedAt.Time, u.Username, u.Email, u.Password, u.ActualName, u.DOB.Time}
+	}
+	return rows
+}
+
+func followRows(ds *Dataset) [][]interface{} {
+	rows := make([][]interface{}, len(ds.Follows))
+	for i, f := range ds.Follows {
+		rows[i] = []interface{}{f.FollowerID, f.FolloweeID, f.CreatedAt}
+	}
+	return rows
+}
+
+func postRows(ds *Dataset) [][]interface{} {
+	rows := make([][]interface{}, len(ds.Posts))
+	for i, p := range ds.Posts {
+		// contents are stored as text, so must not be sent as bytea
+		rows[i] = []interface{}{p.ID, p.CreatedAt.Time, p.UpdatedAt.Time, p.AuthorID, string(p.Contents)}
+	}
+	return rows
+}
+
+func kekRows(ds *Dataset) [][]interface{} {
+	return reactionRows(ds.Keks)
+}
+
+func noRows(ds *Dataset) [][]interface{} {
+	return reactionRows(ds.Nos)
+}
+
+func reactionRows(reactions []Reaction) [][]interface{} {
+	rows := make([][]interface{}, len(reactions))
+	for i, r := range reactions {
+		rows[i] = []interface{}{r.UserID, r.PostID, r.CreatedAt}
+	}
+	return rows
+}
@@ -0,0 +1,283 @@
+// Package seed generates reproducible MeIRL datasets for
+// development and benchmarking
+package seed
+
+import (
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/boxtown/meirl/data"
+)
+
+// Options configures a generated dataset. Datasets generated
+// with equal options are identical
+type Options struct {
+	// Seed seeds the random number generator
+	Seed int64
+
+	// Users is the number of users to generate
+	Users int
+
+	// MeanFollows is the mean number of users each user follows.
+	// Who is followed follows a power law, so a few users have
+	// most of the followers
+	MeanFollows int
+
+	// MeanPosts is the mean number of posts per user
+	MeanPosts int
+
+	// Posts are created between From and To
+	From time.Time
+	To   time.Time
+
+	// Password is the stored password of every user, which
+	// should already be secured
+	Password string
+}
+
+// Follow is a follow relationship between two users
+type Follow struct {
+	FollowerID int64
+	FolloweeID int64
+	CreatedAt  time.Time
+}
+
+// Reaction is a kek or no of a post by a user
+type Reaction struct {
+	UserID    int64
+	PostID    int64
+	CreatedAt time.Time
+}
+
+// Dataset is a generated dataset. Users and posts have IDs
+// starting from 1, and posts are ordered by creation time
+// so that IDs increase with time as they do in Postgres.
+// The whole dataset is held in memory
+type Dataset struct {
+	Users   []data.User
+	Follows []Follow
+	Posts   []data.Post
+	Keks    []Reaction
+	Nos     []Reaction
+}
+
+// followExponent is the exponent of the power law
+// of followers per user
+const followExponent = 1.2
+
+// meanReactionDelay is the mean time between a
+// post being created and it being reacted to
+const meanReactionDelay = 6 * time.Hour
+
+// Generate generates a dataset with the given options
+func Generate(options Options) *Dataset {
+	options = withDefaults(options)
+	r := rand.New(rand.NewSource(options.Seed))
+	ds := &Dataset{}
+	generateUsers(r, options, ds)
+	followers := generateFollows(r, options, ds)
+	generatePosts(r, options, ds)
+	generateReactions(r, options, followers, ds)
+	return ds
+}
+
+func withDefaults(options Options) Options {
+	if options.Users < 2 {
+		options.Users = 2
+	}
+	if options.MeanFollows < 0 {
+		options.MeanFollows = 0
+	}
+	if options.MeanPosts < 0 {
+		options.MeanPosts = 0
+	}
+	if options.To.IsZero() {
+		options.To = time.Date(2017, time.January, 1, 0, 0, 0, 0, time.UTC)
+	}
+	if options.From.IsZero() || !options.From.Before(options.To) {
+		options.From = options.To.AddDate(-1, 0, 0)
+	}
+	if options.Password == "" {
+		options.Password = "password"
+	}
+	return options
+}
+
+func generateUsers(r *rand.Rand, options Options, ds *Dataset) {
+	taken := make(map[string]bool, options.Users)
+	ds.Users = make([]data.User, options.Users)
+	for i := range ds.Users {
+		first := firstNames[r.Intn(len(firstNames))]
+		last := lastNames[r.Intn(len(lastNames))]
+		username := uniqueUsername(r, taken, first, last)
+
+		u := &ds.Users[i]
+		u.ID = int64(i + 1)
+		u.Username = username
+		u.Email = username + "@example.com"
+		u.Password = options.Password
+		u.ActualName = first + " " + last
+		u.DOB = data.Time{Time: randomDate(r, 1950, 2005)}
+		// users sign up during the month before posting starts
+		u.CreatedAt = data.Time{Time: options.From.Add(-time.Duration(r.Int63n(int64(30 * 24 * time.Hour))))}
+		u.UpdatedAt = u.CreatedAt
+	}
+}
+
+func uniqueUsername(r *rand.Rand, taken map[string]bool, first, last string) string {
+	first = strings.ToLower(first)
+	last = strings.ToLower(last)
+	var base string
+	switch r.Intn(3) {
+	case 0:
+		base = first + "_" + last
+	case 1:
+		base = first + last[:1]
+	default:
+		base = first[:1] + last
+	}
+	username := base
+	for taken[username] {
+		username = base + strconv.Itoa(r.Intn(10000))
+	}
+	taken[username] = true
+	return username
+}
+
+func randomDate(r *rand.Rand, fromYear, toYear int) time.Time {
+	from := time.Date(fromYear, time.January, 1, 0, 0, 0, 0, time.UTC)
+	days := int(time.Date(toYear, time.January, 1, 0, 0, 0, 0, time.UTC).Sub(from).Hours() / 24)
+	return from.AddDate(0, 0, r.Intn(days))
+}
+
+// generateFollows generates a follower graph where the number of followers
+// per user follows a power law, returning the follower count per user ID
+func generateFollows(r *rand.Rand, options Options, ds *Dataset) []int {
+	n := len(ds.Users)
+	followers := make([]int, n+1)
+	if options.MeanFollows == 0 {
+		return followers
+	}
+	// popularity ranks users randomly, with rank 0 the most followed
+	popularity := r.Perm(n)
+	zipf := rand.NewZipf(r, followExponent, 1, uint64(n-1))
+	for i := 0; i < n; i++ {
+		followerID := int64(i + 1)
+		k := int(r.ExpFloat64()*float64(options.MeanFollows)) + 1
+		if k > n-1 {
+			k = n - 1
+		}
+		followed := make(map[int64]bool, k)
+		for attempts := 0; len(followed) < k && attempts < 4*k; attempts++ {
+			followeeID := int64(popularity[zipf.Uint64()] + 1)
+			if followeeID == followerID || followed[followeeID] {
+				continue
+			}
+			followed[followeeID] = true
+		}
+		for _, followeeID := range sortedIDs(followed) {
+			ds.Follows = append(ds.Follows, Follow{
+				FollowerID: followerID,
+				FolloweeID: followeeID,
+				CreatedAt:  randomTime(r, options.From, options.To),
+			})
+			followers[followeeID]++
+		}
+	}
+	return followers
+}
+
+func generatePosts(r *rand.Rand, options Options, ds *Dataset) {
+	for _, u := range ds.Users {
+		n := int(r.ExpFloat64() * float64(options.MeanPosts))
+		for i := 0; i < n; i++ {
+			var p data.Post
+			p.AuthorID = u.ID
+			p.CreatedAt = data.Time{Time: randomTime(r, options.From, options.To)}
+			p.UpdatedAt = p.CreatedAt
+			p.Contents = []byte(sentence(r))
+			ds.Posts = append(ds.Posts, p)
+		}
+	}
+	sort.SliceStable(ds.Posts, func(i, j int) bool {
+		return ds.Posts[i].CreatedAt.Before(ds.Posts[j].CreatedAt.Time)
+	})
+	for i := range ds.Posts {
+		ds.Posts[i].ID = int64(i + 1)
+	}
+}
+
+// generateReactions generates keks and nos for every post, with
+// posts by more followed users receiving more of both. Reactions
+// follow their post, mostly soon after it, until options.To
+func generateReactions(r *rand.Rand, options Options, followers []int, ds *Dataset) {
+	n := len(ds.Users)
+	for i := range ds.Posts {
+		p := &ds.Posts[i]
+		reach := float64(followers[p.AuthorID])
+		keks := reactors(r, n, p.AuthorID, int(r.ExpFloat64()*(0.5+0.2*reach)))
+		nos := reactors(r, n, p.AuthorID, int(r.ExpFloat64()*(0.1+0.05*reach)))
+		for _, id := range keks {
+			ds.Keks = append(ds.Keks, Reaction{UserID: id, PostID: p.ID, CreatedAt: reactionTime(r, options, p)})
+		}
+		for _, id := range nos {
+			ds.Nos = append(ds.Nos, Reaction{UserID: id, PostID: p.ID, CreatedAt: reactionTime(r, options, p)})
+		}
+		p.Keks = len(keks)
+		p.Nos = len(nos)
+	}
+}
+
+// reactionTime returns a time after the post was created
+// and no later than options.To
+func reactionTime(r *rand.Rand, options Options, p *data.Post) time.Time {
+	t := p.CreatedAt.Add(time.Duration(r.ExpFloat64() * float64(meanReactionDelay)))
+	if t.After(options.To) {
+		return options.To
+	}
+	return t
+}
+
+// reactors picks k distinct users other than the author
+func reactors(r *rand.Rand, n int, authorID int64, k int) []int64 {
+	if k > n-1 {
+		k = n - 1
+	}
+	picked := make(map[int64]bool, k)
+	for len(picked) < k {
+		id := int64(r.Intn(n) + 1)
+		if id != authorID {
+			picked[id] = true
+		}
+	}
+	return sortedIDs(picked)
+}
+
+// sortedIDs returns the keys of the set in order, since
+// map iteration order would make datasets irreproducible
+func sortedIDs(set map[int64]bool) []int64 {
+	ids := make([]int64, 0, len(set))
+	for id := range set {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+	return ids
+}
+
+func randomTime(r *rand.Rand, from, to time.Time) time.Time {
+	return from.Add(time.Duration(r.Int63n(int64(to.Sub(from)))))
+}
+
+func sentence(r *rand.Rand) string {
+	words := make([]string, 4+r.Intn(20))
+	for i := range words {
+		words[i] = vocabulary[r.Intn(len(vocabulary))]
+	}
+	words[0] = strings.ToUpper(words[0][:1]) + words[0][1:]
+	return strings.Join(words, " ") + punctuation[r.Intn(len(punctuation))]
+}
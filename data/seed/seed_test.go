@@ -0,0 +1,147 @@
+package seed
+
+import (
+	"bytes"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testOptions = Options{
+	Seed:        42,
+	Users:       200,
+	MeanFollows: 10,
+	MeanPosts:   5,
+	From:        time.Date(2016, time.January, 1, 0, 0, 0, 0, time.UTC),
+	To:          time.Date(2016, time.July, 1, 0, 0, 0, 0, time.UTC),
+}
+
+func TestGenerateIsReproducible(t *testing.T) {
+	a := Generate(testOptions)
+	b := Generate(testOptions)
+	if !reflect.DeepEqual(a, b) {
+		t.Error("Expected datasets generated with equal options to be equal")
+		t.Fail()
+	}
+
+	options := testOptions
+	options.Seed = 43
+	c := Generate(options)
+	if reflect.DeepEqual(a.Users, c.Users) {
+		t.Error("Expected datasets generated with different seeds to differ")
+		t.Fail()
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	ds := Generate(testOptions)
+	if len(ds.Users) != testOptions.Users {
+		t.Errorf("Expected %d users, got %d", testOptions.Users, len(ds.Users))
+		t.FailNow()
+	}
+
+	usernameRegex := regexp.MustCompile("^[0-9a-zA-Z_]+$")
+	usernames := make(map[string]bool)
+	for i, u := range ds.Users {
+		if u.ID != int64(i+1) {
+			t.Errorf("Expected user ID %d, got %d", i+1, u.ID)
+			t.FailNow()
+		}
+		if !usernameRegex.MatchString(u.Username) || usernames[u.Username] {
+			t.Errorf("Expected unique valid username, got %s", u.Username)
+			t.Fail()
+		}
+		usernames[u.Username] = true
+	}
+
+	follows := make(map[[2]int64]bool)
+	followers := make(map[int64]int)
+	for _, f := range ds.Follows {
+		key := [2]int64{f.FollowerID, f.FolloweeID}
+		if f.FollowerID == f.FolloweeID || follows[key] {
+			t.Errorf("Unexpected self or duplicate follow %v", key)
+			t.FailNow()
+		}
+		follows[key] = true
+		followers[f.FolloweeID]++
+	}
+	most := 0
+	for _, n := range followers {
+		if n > most {
+			most = n
+		}
+	}
+	if most < 5*testOptions.MeanFollows {
+		t.Errorf("Expected a heavy tailed follower graph, the most followed user has %d followers", most)
+		t.Fail()
+	}
+
+	for i, p := range ds.Posts {
+		if p.ID != int64(i+1) {
+			t.Errorf("Expected post ID %d, got %d", i+1, p.ID)
+			t.FailNow()
+		}
+		if i > 0 && p.CreatedAt.Before(ds.Posts[i-1].CreatedAt.Time) {
+			t.Error("Expected posts ordered by creation time")
+			t.FailNow()
+		}
+		if p.CreatedAt.Before(testOptions.From) || !p.CreatedAt.Before(testOptions.To) {
+			t.Errorf("Post created at %s is outside of the range", p.CreatedAt)
+			t.Fail()
+		}
+	}
+
+	keks := make(map[int64]int)
+	for _, r := range ds.Keks {
+		if ds.Posts[r.PostID-1].AuthorID == r.UserID {
+			t.Error("Expected authors not to kek their own posts")
+			t.Fail()
+		}
+		if r.CreatedAt.Before(ds.Posts[r.PostID-1].CreatedAt.Time) || r.CreatedAt.After(testOptions.To) {
+			t.Errorf("Kek created at %s is outside of the life of post %d", r.CreatedAt, r.PostID)
+			t.Fail()
+		}
+		keks[r.PostID]++
+	}
+	for _, p := range ds.Posts {
+		if p.Keks != keks[p.ID] {
+			t.Errorf("Expected %d keks on post %d, got %d", keks[p.ID], p.ID, p.Keks)
+			t.FailNow()
+		}
+	}
+}
+
+func TestWriteSQL(t *testing.T) {
+	ds := Generate(Options{Seed: 1, Users: 3, MeanPosts: 5})
+	if len(ds.Posts) == 0 {
+		t.Error("Expected posts to be generated")
+		t.FailNow()
+	}
+	ds.Posts[0].Contents = []byte("tab\tand\\slash\nnewline")
+
+	var buf bytes.Buffer
+	err := WriteSQL(&buf, ds)
+	if err != nil {
+		t.Error(err.Error())
+		t.FailNow()
+	}
+	sql := buf.String()
+	if !strings.Contains(sql, "COPY users (id, created_at, updated_at, username, email, password, actual_name, dob) FROM stdin;\n1\t") {
+		t.Error("Expected a COPY statement for users")
+		t.Fail()
+	}
+	if !strings.Contains(sql, `tab\tand\\slash\nnewline`) {
+		t.Error("Expected contents to be escaped for COPY")
+		t.Fail()
+	}
+	if !strings.Contains(sql, "INSERT INTO timelines") {
+		t.Error("Expected timelines to be backfilled")
+		t.Fail()
+	}
+	if strings.Count(sql, "\\.\n") != len(tables) {
+		t.Errorf("Expected %d terminated COPY statements", len(tables))
+		t.Fail()
+	}
+}
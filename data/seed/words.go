@@ -0,0 +1,36 @@
+package seed
+
+var firstNames = []string{
+	"Aaliyah", "Aaron", "Abigail", "Adam", "Aiden", "Alex", "Alice", "Amelia", "Andre", "Anna",
+	"Aria", "Ava", "Benjamin", "Bianca", "Caleb", "Camila", "Carlos", "Charlotte", "Chloe", "Daniel",
+	"David", "Diego", "Elena", "Eli", "Elijah", "Emily", "Emma", "Ethan", "Evelyn", "Fatima",
+	"Gabriel", "Grace", "Hana", "Harper", "Henry", "Isaac", "Isabella", "Jack", "James", "Jasmine",
+	"Javier", "Jin", "Jonah", "Julia", "Kai", "Kenji", "Layla", "Leah", "Liam", "Lucas",
+	"Luna", "Maya", "Mei", "Mia", "Mohammed", "Nadia", "Noah", "Nora", "Oliver", "Olivia",
+	"Omar", "Priya", "Rafael", "Ravi", "Rosa", "Ryan", "Samuel", "Sara", "Sofia", "Theo",
+	"Valentina", "Wei", "William", "Yara", "Yusuf", "Zoe",
+}
+
+var lastNames = []string{
+	"Adams", "Ahmed", "Alvarez", "Anderson", "Baker", "Bennett", "Brown", "Campbell", "Chen", "Clark",
+	"Cohen", "Collins", "Cruz", "Davis", "Diaz", "Edwards", "Evans", "Fischer", "Garcia", "Gonzalez",
+	"Green", "Gupta", "Hall", "Harris", "Hernandez", "Hill", "Ito", "Jackson", "Johnson", "Kim",
+	"King", "Kowalski", "Lee", "Lewis", "Lopez", "Martin", "Martinez", "Miller", "Moore", "Murphy",
+	"Nguyen", "Novak", "Okafor", "Park", "Patel", "Perez", "Reyes", "Robinson", "Rossi", "Sanchez",
+	"Santos", "Schmidt", "Scott", "Silva", "Singh", "Smith", "Suzuki", "Taylor", "Thomas", "Thompson",
+	"Torres", "Walker", "Wang", "White", "Williams", "Wilson", "Wong", "Wright", "Young", "Zhang",
+}
+
+var vocabulary = []string{
+	"me", "irl", "when", "the", "coffee", "hits", "monday", "again", "my", "cat",
+	"just", "finished", "a", "marathon", "of", "cooking", "show", "episodes", "and", "now",
+	"i", "want", "pizza", "honestly", "nobody", "asked", "but", "here", "we", "go",
+	"weekend", "plans", "cancelled", "because", "rain", "dog", "found", "sock", "under", "couch",
+	"new", "job", "who", "dis", "commute", "was", "wild", "today", "bus", "late",
+	"tried", "baking", "bread", "it", "went", "about", "as", "expected", "smoke", "alarm",
+	"mood", "that", "feeling", "forgot", "password", "third", "time", "this", "week", "sigh",
+	"gym", "tomorrow", "definitely", "maybe", "not", "podcast", "recommendations", "please", "tea", "over",
+	"everything", "brunch", "with", "friends", "sunset", "was", "unreal", "concert", "tickets", "sold",
+}
+
+var punctuation = []string{"", ".", "!", "?", "...", " lol", " smh"}
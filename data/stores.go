@@ -1,5 +1,12 @@
 package data
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
 // ErrNoEnt is returned by stores when a desired entity could
 // not be found
 var ErrNoEnt = Error{Message: "Entity not found"}
@@ -62,6 +69,148 @@ const (
 
 	// PostSortByNos designates a post sort by nos
 	PostSortByNos
+
+	// PostSortByID designates a post sort by post id
+	PostSortByID
+
+	// PostSortByScore designates a post sort by a score that
+	// combines net keks with recency. Lists sorted by score are
+	// paginated with a ScoreMarker
+	PostSortByScore
+)
+
+// ScoreMarker marks a position in a list of posts sorted by score.
+// Scores are computed as of AsOf, counting only the posts and
+// reactions that existed then, so that pages continue to fit
+// together as posts age and are reacted to
+type ScoreMarker struct {
+	AsOf  time.Time
+	Score float64
+	ID    int64
+}
+
+// String encodes the marker as AsOf in microseconds since the
+// epoch, the score and the post id, separated by colons
+func (m ScoreMarker) String() string {
+	return strconv.FormatInt(m.AsOf.UnixNano()/int64(time.Microsecond), 10) + ":" +
+		strconv.FormatFloat(m.Score, 'f', -1, 64) + ":" +
+		strconv.FormatInt(m.ID, 10)
+}
+
+// ParseScoreMarker decodes a marker encoded by ScoreMarker.String
+func ParseScoreMarker(s string) (ScoreMarker, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 3 {
+		return ScoreMarker{}, fmt.Errorf("invalid score marker %q", s)
+	}
+	asOf, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return ScoreMarker{}, fmt.Errorf("invalid score marker %q", s)
+	}
+	score, err := strconv.ParseFloat(parts[1], 64)
+	if err != nil {
+		return ScoreMarker{}, fmt.Errorf("invalid score marker %q", s)
+	}
+	id, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return ScoreMarker{}, fmt.Errorf("invalid score marker %q", s)
+	}
+	return ScoreMarker{AsOf: time.Unix(0, asOf*int64(time.Microsecond)), Score: score, ID: id}, nil
+}
+
+// TimeMarker marks a position in a list sorted by time, after the
+// entity with the given ID at Time. Times are as precise as stored by
+// Postgres and entities at the same time are ordered by ID, so that
+// pages neither skip nor repeat entities sharing a time
+type TimeMarker struct {
+	Time time.Time
+	ID   int64
+}
+
+// String encodes the marker as Time in microseconds since
+// the epoch and the id, separated by a colon
+func (m TimeMarker) String() string {
+	return strconv.FormatInt(m.Time.UnixNano()/int64(time.Microsecond), 10) + ":" +
+		strconv.FormatInt(m.ID, 10)
+}
+
+// ParseTimeMarker decodes a marker encoded by TimeMarker.String
+func ParseTimeMarker(s string) (TimeMarker, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 {
+		return TimeMarker{}, fmt.Errorf("invalid time marker %q", s)
+	}
+	micros, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return TimeMarker{}, fmt.Errorf("invalid time marker %q", s)
+	}
+	id, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return TimeMarker{}, fmt.Errorf("invalid time marker %q", s)
+	}
+	return TimeMarker{Time: time.Unix(0, micros*int64(time.Microsecond)), ID: id}, nil
+}
+
+// TrendingWindow is a sliding window over which
+// trending posts are ranked by kek velocity
+type TrendingWindow string
+
+const (
+	// TrendingHour designates the past hour
+	TrendingHour TrendingWindow = "1h"
+
+	// TrendingDay designates the past day
+	TrendingDay TrendingWindow = "24h"
+
+	// TrendingWeek designates the past week
+	TrendingWeek TrendingWindow = "7d"
+)
+
+// TrendingWindows are the windows trending posts are ranked over
+var TrendingWindows = []TrendingWindow{TrendingHour, TrendingDay, TrendingWeek}
+
+// Duration returns the length of the window,
+// or 0 if the window is unknown
+func (w TrendingWindow) Duration() time.Duration {
+	switch w {
+	case TrendingHour:
+		return time.Hour
+	case TrendingDay:
+		return 24 * time.Hour
+	case TrendingWeek:
+		return 7 * 24 * time.Hour
+	}
+	return 0
+}
+
+// Reaction is a reaction of a user to a post
+type Reaction int
+
+const (
+	// ReactionKek designates a kek
+	ReactionKek Reaction = iota
+
+	// ReactionNo designates a no
+	ReactionNo
+)
+
+// NotificationType is the type of activity
+// a notification is about
+type NotificationType string
+
+const (
+	// NotificationFollow designates a notification of a new follower
+	NotificationFollow NotificationType = "follow"
+
+	// NotificationKek designates a notification of a kek of a post
+	NotificationKek NotificationType = "kek"
+
+	// NotificationNo designates a notification of a no of a post
+	NotificationNo NotificationType = "no"
+
+	// NotificationMention designates a notification of a
+	// mention in a post
+	NotificationMention NotificationType = "mention"
 )
 
 // Stores is a collection of all data
@@ -69,6 +218,10 @@ const (
 type Stores struct {
 	UserStore
 	PostStore
+	NotificationStore
+	WebhookStore
+	SearchStore
+	BlockStore
 }
 
 // UserStore represents a common gateway for
@@ -84,6 +237,13 @@ type UserStore interface {
 	UnFollow(followerID, followeeID int64) error
 	Followers(id int64, options ListOptions, sort UserSortMethod) ([]User, error)
 	Following(id int64, options ListOptions, sort UserSortMethod) ([]User, error)
+	FollowingIDs(id int64) ([]int64, error)
+	SetPrivate(id int64, private bool) error
+	IsFollowing(followerID, followeeID int64) (bool, error)
+	RequestFollow(followerID, followeeID int64) error
+	ApproveFollow(followerID, followeeID int64) error
+	DeleteFollowRequest(followerID, followeeID int64) error
+	FollowRequests(id int64, options ListOptions) ([]User, error)
 }
 
 // PostStore represents a common gateway for
@@ -91,8 +251,140 @@ type UserStore interface {
 type PostStore interface {
 	Create(post *Post) (int64, error)
 	Get(id int64) (*Post, error)
-	Update(id int64, contents []byte) error
+	Update(id int64, contents []byte, entities []PostEntity) error
 	Delete(id int64) error
 	UserPosts(userID int64, options ListOptions, sort PostSortMethod) ([]Post, error)
 	Feed(userID int64, options ListOptions, sort PostSortMethod) ([]Post, error)
+	Mentions(userID int64, options ListOptions, sort PostSortMethod) ([]Post, error)
+	TaggedPosts(tag string, options ListOptions, sort PostSortMethod) ([]Post, error)
+	Ancestors(id, viewerID int64, limit int) ([]Post, error)
+	Descendants(id, viewerID int64, depth, maxNodes int, options ListOptions) ([]Post, error)
+	Recent(options ListOptions) ([]Post, error)
+	Trending(window TrendingWindow, options ListOptions) ([]Post, error)
+	React(postID, userID int64, reaction Reaction) error
+	UnReact(postID, userID int64, reaction Reaction) error
+	Repost(postID, userID int64) error
+	UnRepost(postID, userID int64) error
+}
+
+// NotificationStore represents a common gateway for
+// notification data stores
+type NotificationStore interface {
+	Notify(userID, actorID int64, notificationType NotificationType, postID int64) (int64, error)
+	Get(id int64) (*Notification, error)
+	List(userID int64, options ListOptions, unreadOnly bool) ([]Notification, error)
+	UnreadCount(userID int64) (int, error)
+	MarkRead(userID int64, ids []int64) error
+	MarkAllRead(userID int64) error
+	Preferences(userID int64) (*NotificationPreferences, error)
+	SetPreferences(userID int64, preferences *NotificationPreferences) error
+}
+
+// WebhookStore represents a common gateway for
+// webhook data stores
+type WebhookStore interface {
+	Create(webhook *Webhook) (int64, error)
+	Get(id int64) (*Webhook, error)
+	List(userID int64) ([]Webhook, error)
+	Delete(id int64) error
+	Subscribed(event string, userIDs []int64) ([]Webhook, error)
+	CreateDelivery(delivery *WebhookDelivery) (int64, error)
+	GetDelivery(id int64) (*WebhookDelivery, error)
+	Deliveries(webhookID int64, options ListOptions) ([]WebhookDelivery, error)
+	ClaimDeliveries(limit int, lease time.Duration) ([]WebhookDelivery, error)
+	UpdateDelivery(delivery *WebhookDelivery) error
+}
+
+// SearchSort is the method of sorting search results
+type SearchSort int
+
+const (
+	// SearchByRelevance designates a search result sort by how
+	// well results match the query, best first
+	SearchByRelevance SearchSort = iota
+
+	// SearchByDate designates a search result sort
+	// by creation date
+	SearchByDate
+)
+
+// PostQuery is a query for posts. Text is in web search syntax, where
+// quoted words are phrases, words prefixed by - are excluded and words
+// separated by or are alternatives. Zero fields other than Text do not
+// filter results. Posts by users the viewer blocked or was blocked by
+// and by private users the viewer does not follow are excluded, where
+// anonymous viewers have an ID of 0
+type PostQuery struct {
+	Text     string
+	AuthorID int64
+	ViewerID int64
+	Since    time.Time
+	Until    time.Time
+	Sort     SearchSort
+}
+
+// SearchMarker marks a position in a list of search results, after
+// the result with the given ID and rank if sorted by relevance or
+// creation time if sorted by date
+type SearchMarker struct {
+	Rank      float64
+	CreatedAt time.Time
+	ID        int64
+}
+
+// String encodes the marker as the rank, or the creation time in
+// microseconds since the epoch, and the result id separated by a colon
+func (m SearchMarker) String(sort SearchSort) string {
+	value := strconv.FormatFloat(m.Rank, 'f', -1, 64)
+	if sort == SearchByDate {
+		value = strconv.FormatInt(m.CreatedAt.UnixNano()/int64(time.Microsecond), 10)
+	}
+	return value + ":" + strconv.FormatInt(m.ID, 10)
+}
+
+// ParseSearchMarker decodes a marker encoded by SearchMarker.String
+// with the same sort
+func ParseSearchMarker(s string, sort SearchSort) (SearchMarker, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 {
+		return SearchMarker{}, fmt.Errorf("invalid search marker %q", s)
+	}
+	var m SearchMarker
+	var err error
+	if sort == SearchByDate {
+		var micros int64
+		micros, err = strconv.ParseInt(parts[0], 10, 64)
+		m.CreatedAt = time.Unix(0, micros*int64(time.Microsecond))
+	} else {
+		m.Rank, err = strconv.ParseFloat(parts[0], 64)
+	}
+	if err != nil {
+		return SearchMarker{}, fmt.Errorf("invalid search marker %q", s)
+	}
+	m.ID, err = strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return SearchMarker{}, fmt.Errorf("invalid search marker %q", s)
+	}
+	return m, nil
+}
+
+// SearchStore represents a common gateway for
+// search data stores
+type SearchStore interface {
+	SearchPosts(query PostQuery, options ListOptions) ([]PostSearchResult, error)
+	SearchUsers(query string, limit int) ([]User, error)
+	CompleteUsernames(prefix string, limit int) ([]User, error)
+}
+
+// BlockStore represents a common gateway for
+// block and mute data stores
+type BlockStore interface {
+	Block(blockerID, blockedID int64) error
+	UnBlock(blockerID, blockedID int64) error
+	Mute(muterID, mutedID int64) error
+	UnMute(muterID, mutedID int64) error
+	IsBlocked(userID, otherID int64) (bool, error)
+	Blocked(userID int64, options ListOptions) ([]User, error)
+	Muted(userID int64, options ListOptions) ([]User, error)
+	MutedIDs(userID int64) ([]int64, error)
 }
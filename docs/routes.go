@@ -0,0 +1,772 @@
+package docs
+
+import (
+	"net/http"
+
+	"github.com/boxtown/meirl/api"
+	"github.com/boxtown/meirl/data"
+)
+
+// Route documents a single route registered with the router
+type Route struct {
+	Method      string
+	Path        string
+	OperationID string
+	Summary     string
+	Tag         string
+	Auth        bool
+	Query       []Param
+	Request     interface{}
+	Responses   []RouteResponse
+}
+
+// Param documents a query parameter
+type Param struct {
+	Name        string
+	Type        string
+	Description string
+}
+
+// RouteResponse documents a response of a route. Body is a value of
+// the type of the JSON response body, or nil if there is none
+type RouteResponse struct {
+	Status      int
+	Description string
+	Body        interface{}
+}
+
+// common responses
+var (
+	problem         = api.ProblemResponse{}
+	badRequest      = RouteResponse{Status: http.StatusBadRequest, Description: "Malformed request, validation error or invalid credentials"}
+	notFound        = RouteResponse{Status: http.StatusNotFound, Description: "Entity not found"}
+	tooManyRequests = RouteResponse{Status: http.StatusTooManyRequests, Description: "Rate limit exceeded", Body: problem}
+	unavailable     = RouteResponse{Status: http.StatusServiceUnavailable, Description: "Data store error"}
+)
+
+// listParams are the query parameters accepted by list routes
+var listParams = []Param{
+	{Name: "offset", Type: "integer", Description: "Number of entities to skip"},
+	{Name: "limit", Type: "integer", Description: "Maximum number of entities to return, defaults to 10"},
+	{Name: "desc", Type: "boolean", Description: "Sort descending"},
+	{Name: "marker", Type: "string", Description: "Return entities after this value of the sort field"},
+}
+
+// feedParams are the query parameters accepted by feed routes, which
+// are sorted by creation time, or repost time for reposts, or by score
+var feedParams = []Param{
+	listParams[0], listParams[1], listParams[2],
+	{Name: "sort", Type: "string", Description: "date, the default, or top to rank posts by net keks decayed by age. Top posts are listed best first unless desc is given"},
+	{Name: "marker", Type: "string", Description: "Return posts after the marker of the last post of the previous page, or after this time in seconds since the epoch, or before it if desc"},
+}
+
+// newestParams are the query parameters accepted by routes
+// listing posts by creation time or by score, newest or best first
+var newestParams = []Param{
+	listParams[0], listParams[1],
+	{Name: "desc", Type: "boolean", Description: "Sort descending, defaults to true"},
+	feedParams[3],
+	{Name: "marker", Type: "string", Description: "Return posts after the marker of the last post of the previous page, or after this time in seconds since the epoch, or before it if desc"},
+}
+
+// relationshipParams are the query parameters accepted by
+// routes listing blocked and muted users, which are sorted by ID
+var relationshipParams = []Param{
+	listParams[0], listParams[1], listParams[2],
+	{Name: "marker", Type: "integer", Description: "Return users after the user with this ID"},
+}
+
+// exploreParams are the query parameters accepted by the
+// explore route, which is sorted by creation time
+var exploreParams = []Param{
+	listParams[0], listParams[1],
+	{Name: "desc", Type: "boolean", Description: "Sort descending, defaults to true"},
+	{Name: "marker", Type: "string", Description: "Return posts after the marker of the last post of the previous page, or after this time in seconds since the epoch, or before it if desc"},
+}
+
+// trendingParams are the query parameters accepted by the
+// trending route, which is sorted by rank
+var trendingParams = []Param{
+	listParams[0], listParams[1],
+	{Name: "window", Type: "string", Description: "1h, 24h or 7d, defaults to 24h"},
+	{Name: "marker", Type: "string", Description: "Return posts ranked after the marker of the last post of the previous page"},
+}
+
+// searchPostParams are the query parameters accepted
+// by the post search route
+var searchPostParams = []Param{
+	{Name: "q", Type: "string", Description: "Required words to match, with quoted phrases, -excluded words and or between alternatives"},
+	{Name: "author", Type: "string", Description: "Only match posts by the user with this username"},
+	{Name: "since", Type: "integer", Description: "Only match posts created at or after this time in seconds since the epoch"},
+	{Name: "until", Type: "integer", Description: "Only match posts created before this time in seconds since the epoch"},
+	{Name: "sort", Type: "string", Description: "relevance, the default, or date"},
+	listParams[1],
+	{Name: "desc", Type: "boolean", Description: "Sort by date descending, defaults to true"},
+	{Name: "marker", Type: "string", Description: "Return posts after the marker of the last post of the previous page"},
+}
+
+// notificationParams are the query parameters accepted by the
+// notification list route, which is sorted by latest activity
+var notificationParams = []Param{
+	listParams[0], listParams[1],
+	{Name: "desc", Type: "boolean", Description: "Sort descending, defaults to true"},
+	{Name: "marker", Type: "string", Description: "Return notifications after the marker of the last notification of the previous page, or active after this time in seconds since the epoch, or before it if desc"},
+	{Name: "unread", Type: "boolean", Description: "Only list unread notifications"},
+}
+
+// deliveryParams are the query parameters accepted by the
+// webhook delivery log route, which is sorted by delivery ID
+var deliveryParams = []Param{
+	listParams[0], listParams[1],
+	{Name: "desc", Type: "boolean", Description: "Sort descending, defaults to true"},
+	{Name: "marker", Type: "integer", Description: "Return deliveries after this delivery ID, or before it if desc"},
+}
+
+// Routes documents every route registered by the MeIRL router.
+// Paths are mux path templates
+var Routes = []Route{
+	{
+		Method:      "GET",
+		Path:        api.PrefixAPIPath("user/{id:[0-9]+}"),
+		OperationID: "getUser",
+		Summary:     "Get a user by ID",
+		Tag:         "users",
+		Responses: []RouteResponse{
+			{Status: http.StatusOK, Description: "The user", Body: data.User{}},
+			notFound, tooManyRequests, unavailable,
+		},
+	},
+	{
+		Method:      "GET",
+		Path:        api.PrefixAPIPath("user/me"),
+		OperationID: "getMe",
+		Summary:     "Get the authenticated user",
+		Tag:         "users",
+		Auth:        true,
+		Responses: []RouteResponse{
+			{Status: http.StatusOK, Description: "The authenticated user", Body: data.User{}},
+			badRequest, notFound, tooManyRequests, unavailable,
+		},
+	},
+	{
+		Method:      "GET",
+		Path:        api.PrefixAPIPath("user/me/feed/stream"),
+		OperationID: "streamFeed",
+		Summary:     "Stream new posts in the feed of the authenticated user as server-sent events",
+		Tag:         "users",
+		Auth:        true,
+		Query: []Param{
+			{Name: "lastEventId", Type: "integer", Description: "Resume after this post ID, for clients that cannot send the Last-Event-ID header"},
+		},
+		Responses: []RouteResponse{
+			{Status: http.StatusOK, Description: "A text/event-stream of post events, each with the post ID as event ID and the post as JSON data"},
+			badRequest, tooManyRequests, unavailable,
+		},
+	},
+	{
+		Method:      "GET",
+		Path:        api.PrefixAPIPath("user/me/socket"),
+		OperationID: "connectSocket",
+		Summary:     "Open a WebSocket to subscribe to the feed, post reactions and notifications of the authenticated user",
+		Tag:         "users",
+		Auth:        true,
+		Query: []Param{
+			{Name: "access_token", Type: "string", Description: "Access token, for clients that cannot send the Authorization header"},
+		},
+		Responses: []RouteResponse{
+			{Status: http.StatusSwitchingProtocols, Description: "A WebSocket exchanging JSON messages", Body: api.SocketMessage{}},
+			badRequest, tooManyRequests, unavailable,
+		},
+	},
+	{
+		Method:      "GET",
+		Path:        api.PrefixAPIPath("user/{id:[0-9]+}/feed"),
+		OperationID: "getFeed",
+		Summary:     "Get the post feed of the authenticated user",
+		Tag:         "users",
+		Auth:        true,
+		Query:       feedParams,
+		Responses: []RouteResponse{
+			{Status: http.StatusOK, Description: "Posts by and reposted by the user and the users they follow, each listed once", Body: []data.Post{}},
+			{Status: http.StatusForbidden, Description: "The feed is not the feed of the authenticated user", Body: problem},
+			badRequest, notFound, tooManyRequests, unavailable,
+		},
+	},
+	{
+		Method:      "GET",
+		Path:        api.PrefixAPIPath("user/me/mentions"),
+		OperationID: "getMentions",
+		Summary:     "List the posts mentioning the authenticated user, except those they may not see",
+		Tag:         "users",
+		Auth:        true,
+		Query:       newestParams,
+		Responses: []RouteResponse{
+			{Status: http.StatusOK, Description: "Posts mentioning the user", Body: []data.Post{}},
+			badRequest, tooManyRequests, unavailable,
+		},
+	},
+	{
+		Method:      "POST",
+		Path:        api.PrefixAPIPath("user/new"),
+		OperationID: "createUser",
+		Summary:     "Sign up a new user",
+		Tag:         "users",
+		Request:     data.User{},
+		Responses: []RouteResponse{
+			{Status: http.StatusCreated, Description: "The ID of the created user", Body: api.IDResponse{}},
+			badRequest, tooManyRequests, unavailable,
+		},
+	},
+	{
+		Method:      "POST",
+		Path:        api.PrefixAPIPath("user/login"),
+		OperationID: "login",
+		Summary:     "Log in by username or email and password",
+		Tag:         "users",
+		Request:     data.User{},
+		Responses: []RouteResponse{
+			{Status: http.StatusOK, Description: "An access token valid for one hour", Body: api.TokenResponse{}},
+			{Status: http.StatusForbidden, Description: "The user is disabled", Body: problem},
+			badRequest, tooManyRequests, unavailable,
+		},
+	},
+	{
+		Method:      "POST",
+		Path:        api.PrefixAPIPath("user/{id:[0-9]+}/followers"),
+		OperationID: "followUser",
+		Summary:     "Follow a user as the authenticated user",
+		Tag:         "users",
+		Auth:        true,
+		Responses: []RouteResponse{
+			{Status: http.StatusAccepted, Description: "The user is followed, or a request to follow them is pending if they are private", Body: api.FollowResponse{}},
+			{Status: http.StatusForbidden, Description: "Either user blocked the other", Body: problem},
+			badRequest, tooManyRequests, unavailable,
+		},
+	},
+	{
+		Method:      "DELETE",
+		Path:        api.PrefixAPIPath("user/{id:[0-9]+}/followers"),
+		OperationID: "unFollowUser",
+		Summary:     "Unfollow a user as the authenticated user, withdrawing any request to follow them",
+		Tag:         "users",
+		Auth:        true,
+		Responses: []RouteResponse{
+			{Status: http.StatusAccepted, Description: "The user is no longer followed"},
+			badRequest, tooManyRequests, unavailable,
+		},
+	},
+	{
+		Method:      "GET",
+		Path:        api.PrefixAPIPath("user/me/followers/requests"),
+		OperationID: "listFollowRequests",
+		Summary:     "List the users requesting to follow the authenticated user",
+		Tag:         "users",
+		Auth:        true,
+		Query:       relationshipParams,
+		Responses: []RouteResponse{
+			{Status: http.StatusOK, Description: "Requesting users by ID, without emails", Body: []data.User{}},
+			badRequest, tooManyRequests, unavailable,
+		},
+	},
+	{
+		Method:      "POST",
+		Path:        api.PrefixAPIPath("user/me/followers/requests/{id:[0-9]+}"),
+		OperationID: "approveFollowRequest",
+		Summary:     "Approve the request of a user to follow the authenticated user",
+		Tag:         "users",
+		Auth:        true,
+		Responses: []RouteResponse{
+			{Status: http.StatusAccepted, Description: "The user follows the authenticated user"},
+			badRequest, notFound, tooManyRequests, unavailable,
+		},
+	},
+	{
+		Method:      "DELETE",
+		Path:        api.PrefixAPIPath("user/me/followers/requests/{id:[0-9]+}"),
+		OperationID: "rejectFollowRequest",
+		Summary:     "Reject the request of a user to follow the authenticated user",
+		Tag:         "users",
+		Auth:        true,
+		Responses: []RouteResponse{
+			{Status: http.StatusAccepted, Description: "The request is rejected"},
+			badRequest, tooManyRequests, unavailable,
+		},
+	},
+	{
+		Method:      "PUT",
+		Path:        api.PrefixAPIPath("user/me/privacy"),
+		OperationID: "setPrivacy",
+		Summary:     "Make the authenticated user private, limiting their posts to approved followers, or public, approving pending requests",
+		Tag:         "users",
+		Auth:        true,
+		Request:     api.PrivacyRequest{},
+		Responses: []RouteResponse{
+			{Status: http.StatusAccepted, Description: "The privacy of the user is set"},
+			badRequest, notFound, tooManyRequests, unavailable,
+		},
+	},
+	{
+		Method:      "GET",
+		Path:        api.PrefixAPIPath("user/{id:[0-9]+}/posts"),
+		OperationID: "getUserPosts",
+		Summary:     "Get the posts of a user, hidden if the optionally authenticated user blocked or was blocked by them or does not follow them while they are private",
+		Tag:         "users",
+		Query:       newestParams,
+		Responses: []RouteResponse{
+			{Status: http.StatusOK, Description: "Posts by the user", Body: []data.Post{}},
+			badRequest, notFound, tooManyRequests, unavailable,
+		},
+	},
+	{
+		Method:      "POST",
+		Path:        api.PrefixAPIPath("user/{id:[0-9]+}/block"),
+		OperationID: "blockUser",
+		Summary:     "Block a user as the authenticated user, removing the follows between them",
+		Tag:         "users",
+		Auth:        true,
+		Responses: []RouteResponse{
+			{Status: http.StatusAccepted, Description: "The user is blocked"},
+			badRequest, tooManyRequests, unavailable,
+		},
+	},
+	{
+		Method:      "DELETE",
+		Path:        api.PrefixAPIPath("user/{id:[0-9]+}/block"),
+		OperationID: "unBlockUser",
+		Summary:     "Unblock a user as the authenticated user",
+		Tag:         "users",
+		Auth:        true,
+		Responses: []RouteResponse{
+			{Status: http.StatusAccepted, Description: "The user is no longer blocked"},
+			badRequest, tooManyRequests, unavailable,
+		},
+	},
+	{
+		Method:      "POST",
+		Path:        api.PrefixAPIPath("user/{id:[0-9]+}/mute"),
+		OperationID: "muteUser",
+		Summary:     "Hide the posts and reposts of a user from the feed of the authenticated user",
+		Tag:         "users",
+		Auth:        true,
+		Responses: []RouteResponse{
+			{Status: http.StatusAccepted, Description: "The user is muted"},
+			badRequest, tooManyRequests, unavailable,
+		},
+	},
+	{
+		Method:      "DELETE",
+		Path:        api.PrefixAPIPath("user/{id:[0-9]+}/mute"),
+		OperationID: "unMuteUser",
+		Summary:     "Unmute a user as the authenticated user",
+		Tag:         "users",
+		Auth:        true,
+		Responses: []RouteResponse{
+			{Status: http.StatusAccepted, Description: "The user is no longer muted"},
+			badRequest, tooManyRequests, unavailable,
+		},
+	},
+	{
+		Method:      "GET",
+		Path:        api.PrefixAPIPath("user/me/blocked"),
+		OperationID: "listBlocked",
+		Summary:     "List the users blocked by the authenticated user",
+		Tag:         "users",
+		Auth:        true,
+		Query:       relationshipParams,
+		Responses: []RouteResponse{
+			{Status: http.StatusOK, Description: "Blocked users by ID, without emails", Body: []data.User{}},
+			badRequest, tooManyRequests, unavailable,
+		},
+	},
+	{
+		Method:      "GET",
+		Path:        api.PrefixAPIPath("user/me/muted"),
+		OperationID: "listMuted",
+		Summary:     "List the users muted by the authenticated user",
+		Tag:         "users",
+		Auth:        true,
+		Query:       relationshipParams,
+		Responses: []RouteResponse{
+			{Status: http.StatusOK, Description: "Muted users by ID, without emails", Body: []data.User{}},
+			badRequest, tooManyRequests, unavailable,
+		},
+	},
+	{
+		Method:      "DELETE",
+		Path:        api.PrefixAPIPath("user/{id:[0-9]+}"),
+		OperationID: "deleteUser",
+		Summary:     "Delete a user",
+		Tag:         "users",
+		Responses: []RouteResponse{
+			{Status: http.StatusAccepted, Description: "The user is deleted"},
+			tooManyRequests, unavailable,
+		},
+	},
+	{
+		Method:      "GET",
+		Path:        api.PrefixAPIPath("post/{id:[0-9]+}"),
+		OperationID: "getPost",
+		Summary:     "Get a post by ID, hidden if the optionally authenticated user blocked or was blocked by its author or does not follow its private author",
+		Tag:         "posts",
+		Responses: []RouteResponse{
+			{Status: http.StatusOK, Description: "The post", Body: data.Post{}},
+			notFound, tooManyRequests, unavailable,
+		},
+	},
+	{
+		Method:      "GET",
+		Path:        api.PrefixAPIPath("post/{id:[0-9]+}/thread"),
+		OperationID: "getThread",
+		Summary:     "Get a post with the posts it replies to and a page of its replies, omitting posts the optionally authenticated user may not see",
+		Tag:         "posts",
+		Query: []Param{
+			listParams[1],
+			{Name: "desc", Type: "boolean", Description: "List replies newest first"},
+			{Name: "marker", Type: "integer", Description: "Return replies after the reply with this ID"},
+			{Name: "depth", Type: "integer", Description: "Levels of replies to include, defaults to 3 and at most 10"},
+		},
+		Responses: []RouteResponse{
+			{Status: http.StatusOK, Description: "The thread of the post", Body: api.ThreadResponse{}},
+			badRequest, notFound, tooManyRequests, unavailable,
+		},
+	},
+	{
+		Method:      "POST",
+		Path:        api.PrefixAPIPath("post/new"),
+		OperationID: "createPost",
+		Summary:     "Create a post as the authenticated user",
+		Tag:         "posts",
+		Auth:        true,
+		Request:     data.Post{},
+		Responses: []RouteResponse{
+			{Status: http.StatusCreated, Description: "The ID of the created post", Body: api.IDResponse{}},
+			badRequest, notFound, tooManyRequests, unavailable,
+		},
+	},
+	{
+		Method:      "GET",
+		Path:        api.PrefixAPIPath("explore"),
+		OperationID: "explore",
+		Summary:     "List the recent posts of every public user",
+		Tag:         "posts",
+		Query:       exploreParams,
+		Responses: []RouteResponse{
+			{Status: http.StatusOK, Description: "Recent posts, newest first unless desc is given", Body: []data.Post{}},
+			badRequest, tooManyRequests, unavailable,
+		},
+	},
+	{
+		Method:      "GET",
+		Path:        api.PrefixAPIPath("trending"),
+		OperationID: "trending",
+		Summary:     "List the posts of public users with the most keks per hour over a window",
+		Tag:         "posts",
+		Query:       trendingParams,
+		Responses: []RouteResponse{
+			{Status: http.StatusOK, Description: "Trending posts by rank, as of the last minute", Body: []data.Post{}},
+			badRequest, tooManyRequests, unavailable,
+		},
+	},
+	{
+		Method:      "GET",
+		Path:        api.PrefixAPIPath("tag/{tag:[0-9a-zA-Z_]+}/posts"),
+		OperationID: "getTaggedPosts",
+		Summary:     "List the posts of public users tagged with a hashtag, regardless of case",
+		Tag:         "posts",
+		Query:       newestParams,
+		Responses: []RouteResponse{
+			{Status: http.StatusOK, Description: "Posts tagged with the hashtag", Body: []data.Post{}},
+			badRequest, tooManyRequests, unavailable,
+		},
+	},
+	{
+		Method:      "POST",
+		Path:        api.PrefixAPIPath("post/{id:[0-9]+}/reposts"),
+		OperationID: "repost",
+		Summary:     "Repost a post to the feeds of the followers of the authenticated user",
+		Tag:         "posts",
+		Auth:        true,
+		Responses: []RouteResponse{
+			{Status: http.StatusAccepted, Description: "The post is reposted"},
+			badRequest, notFound, tooManyRequests, unavailable,
+		},
+	},
+	{
+		Method:      "DELETE",
+		Path:        api.PrefixAPIPath("post/{id:[0-9]+}/reposts"),
+		OperationID: "unRepost",
+		Summary:     "Undo the repost of a post by the authenticated user",
+		Tag:         "posts",
+		Auth:        true,
+		Responses: []RouteResponse{
+			{Status: http.StatusAccepted, Description: "The post is no longer reposted"},
+			badRequest, notFound, tooManyRequests, unavailable,
+		},
+	},
+	{
+		Method:      "POST",
+		Path:        api.PrefixAPIPath("post/{id:[0-9]+}/keks"),
+		OperationID: "kekPost",
+		Summary:     "Kek a post as the authenticated user",
+		Tag:         "posts",
+		Auth:        true,
+		Responses: []RouteResponse{
+			{Status: http.StatusAccepted, Description: "The post is kekked"},
+			badRequest, notFound, tooManyRequests, unavailable,
+		},
+	},
+	{
+		Method:      "DELETE",
+		Path:        api.PrefixAPIPath("post/{id:[0-9]+}/keks"),
+		OperationID: "unKekPost",
+		Summary:     "Remove the kek of the authenticated user from a post",
+		Tag:         "posts",
+		Auth:        true,
+		Responses: []RouteResponse{
+			{Status: http.StatusAccepted, Description: "The post is no longer kekked"},
+			badRequest, notFound, tooManyRequests, unavailable,
+		},
+	},
+	{
+		Method:      "POST",
+		Path:        api.PrefixAPIPath("post/{id:[0-9]+}/nos"),
+		OperationID: "noPost",
+		Summary:     "No a post as the authenticated user",
+		Tag:         "posts",
+		Auth:        true,
+		Responses: []RouteResponse{
+			{Status: http.StatusAccepted, Description: "The post is noed"},
+			badRequest, notFound, tooManyRequests, unavailable,
+		},
+	},
+	{
+		Method:      "DELETE",
+		Path:        api.PrefixAPIPath("post/{id:[0-9]+}/nos"),
+		OperationID: "unNoPost",
+		Summary:     "Remove the no of the authenticated user from a post",
+		Tag:         "posts",
+		Auth:        true,
+		Responses: []RouteResponse{
+			{Status: http.StatusAccepted, Description: "The post is no longer noed"},
+			badRequest, notFound, tooManyRequests, unavailable,
+		},
+	},
+	{
+		Method:      "GET",
+		Path:        api.PrefixAPIPath("user/me/notifications"),
+		OperationID: "listNotifications",
+		Summary:     "List the notifications of the authenticated user, most recently active first",
+		Tag:         "notifications",
+		Auth:        true,
+		Query:       notificationParams,
+		Responses: []RouteResponse{
+			{Status: http.StatusOK, Description: "Notifications, grouped by type and post while unread", Body: []data.Notification{}},
+			badRequest, tooManyRequests, unavailable,
+		},
+	},
+	{
+		Method:      "GET",
+		Path:        api.PrefixAPIPath("user/me/notifications/unread"),
+		OperationID: "countUnreadNotifications",
+		Summary:     "Count the unread notifications of the authenticated user",
+		Tag:         "notifications",
+		Auth:        true,
+		Responses: []RouteResponse{
+			{Status: http.StatusOK, Description: "The number of unread notifications", Body: api.UnreadCountResponse{}},
+			badRequest, tooManyRequests, unavailable,
+		},
+	},
+	{
+		Method:      "POST",
+		Path:        api.PrefixAPIPath("user/me/notifications/read"),
+		OperationID: "markNotificationsRead",
+		Summary:     "Mark notifications of the authenticated user as read, or all of them if no IDs are given",
+		Tag:         "notifications",
+		Auth:        true,
+		Request:     api.MarkReadRequest{},
+		Responses: []RouteResponse{
+			{Status: http.StatusAccepted, Description: "The notifications are read"},
+			badRequest, tooManyRequests, unavailable,
+		},
+	},
+	{
+		Method:      "GET",
+		Path:        api.PrefixAPIPath("user/me/notifications/preferences"),
+		OperationID: "getNotificationPreferences",
+		Summary:     "Get the types of notifications the authenticated user receives",
+		Tag:         "notifications",
+		Auth:        true,
+		Responses: []RouteResponse{
+			{Status: http.StatusOK, Description: "The notification preferences", Body: data.NotificationPreferences{}},
+			badRequest, tooManyRequests, unavailable,
+		},
+	},
+	{
+		Method:      "PUT",
+		Path:        api.PrefixAPIPath("user/me/notifications/preferences"),
+		OperationID: "setNotificationPreferences",
+		Summary:     "Set the types of notifications the authenticated user receives. Omitted types are unchanged",
+		Tag:         "notifications",
+		Auth:        true,
+		Request:     data.NotificationPreferences{},
+		Responses: []RouteResponse{
+			{Status: http.StatusOK, Description: "The updated notification preferences", Body: data.NotificationPreferences{}},
+			badRequest, tooManyRequests, unavailable,
+		},
+	},
+	{
+		Method:      "POST",
+		Path:        api.PrefixAPIPath("webhook/new"),
+		OperationID: "createWebhook",
+		Summary:     "Register a webhook receiving the given events that involve the authenticated user",
+		Tag:         "webhooks",
+		Auth:        true,
+		Request:     data.Webhook{},
+		Responses: []RouteResponse{
+			{Status: http.StatusCreated, Description: "The webhook, including the secret deliveries are signed with", Body: data.Webhook{}},
+			badRequest, tooManyRequests, unavailable,
+		},
+	},
+	{
+		Method:      "GET",
+		Path:        api.PrefixAPIPath("user/me/webhooks"),
+		OperationID: "listWebhooks",
+		Summary:     "List the webhooks of the authenticated user",
+		Tag:         "webhooks",
+		Auth:        true,
+		Responses: []RouteResponse{
+			{Status: http.StatusOK, Description: "Webhooks, without their secrets", Body: []data.Webhook{}},
+			badRequest, tooManyRequests, unavailable,
+		},
+	},
+	{
+		Method:      "DELETE",
+		Path:        api.PrefixAPIPath("webhook/{id:[0-9]+}"),
+		OperationID: "deleteWebhook",
+		Summary:     "Delete a webhook of the authenticated user along with its deliveries",
+		Tag:         "webhooks",
+		Auth:        true,
+		Responses: []RouteResponse{
+			{Status: http.StatusAccepted, Description: "The webhook is deleted"},
+			badRequest, notFound, tooManyRequests, unavailable,
+		},
+	},
+	{
+		Method:      "GET",
+		Path:        api.PrefixAPIPath("webhook/{id:[0-9]+}/deliveries"),
+		OperationID: "listWebhookDeliveries",
+		Summary:     "List the delivery log of a webhook of the authenticated user, most recent first",
+		Tag:         "webhooks",
+		Auth:        true,
+		Query:       deliveryParams,
+		Responses: []RouteResponse{
+			{Status: http.StatusOK, Description: "Deliveries and the outcome of their latest attempt", Body: []data.WebhookDelivery{}},
+			badRequest, notFound, tooManyRequests, unavailable,
+		},
+	},
+	{
+		Method:      "POST",
+		Path:        api.PrefixAPIPath("webhook/delivery/{id:[0-9]+}/redeliver"),
+		OperationID: "redeliverWebhookDelivery",
+		Summary:     "Queue a new delivery of the payload of a previous delivery",
+		Tag:         "webhooks",
+		Auth:        true,
+		Responses: []RouteResponse{
+			{Status: http.StatusAccepted, Description: "The ID of the new delivery", Body: api.IDResponse{}},
+			badRequest, notFound, tooManyRequests, unavailable,
+		},
+	},
+	{
+		Method:      "GET",
+		Path:        "/healthz",
+		OperationID: "live",
+		Summary:     "Liveness probe",
+		Tag:         "ops",
+		Responses: []RouteResponse{
+			{Status: http.StatusOK, Description: "The process is alive", Body: api.HealthResponse{}},
+		},
+	},
+	{
+		Method:      "GET",
+		Path:        "/readyz",
+		OperationID: "ready",
+		Summary:     "Readiness probe reporting the health of each dependency",
+		Tag:         "ops",
+		Responses: []RouteResponse{
+			{Status: http.StatusOK, Description: "The instance can take traffic, possibly degraded", Body: api.HealthResponse{}},
+			{Status: http.StatusServiceUnavailable, Description: "A critical dependency is failing or the instance is shutting down", Body: api.HealthResponse{}},
+		},
+	},
+	{
+		Method:      "GET",
+		Path:        "/metrics",
+		OperationID: "metrics",
+		Summary:     "Prometheus metrics in the text exposition format",
+		Tag:         "ops",
+		Responses: []RouteResponse{
+			{Status: http.StatusOK, Description: "Prometheus metrics"},
+		},
+	},
+	{
+		Method:      "GET",
+		Path:        SpecPath,
+		OperationID: "openAPI",
+		Summary:     "This OpenAPI document",
+		Tag:         "ops",
+		Responses: []RouteResponse{
+			{Status: http.StatusOK, Description: "The OpenAPI document"},
+		},
+	},
+	{
+		Method:      "GET",
+		Path:        UIPath,
+		OperationID: "docs",
+		Summary:     "Rendered API documentation",
+		Tag:         "ops",
+		Responses: []RouteResponse{
+			{Status: http.StatusOK, Description: "An HTML documentation page"},
+		},
+	},
+	{
+		Method:      "GET",
+		Path:        api.PrefixAPIPath("search/posts"),
+		OperationID: "searchPosts",
+		Summary:     "Search the contents of posts, excluding authors the optionally authenticated user blocked, was blocked by or does not follow while they are private",
+		Tag:         "search",
+		Query:       searchPostParams,
+		Responses: []RouteResponse{
+			{Status: http.StatusOK, Description: "Matching posts with highlighted snippets", Body: []data.PostSearchResult{}},
+			badRequest, tooManyRequests, unavailable,
+		},
+	},
+	{
+		Method:      "GET",
+		Path:        api.PrefixAPIPath("search/users"),
+		OperationID: "searchUsers",
+		Summary:     "Search users by username prefix and actual name",
+		Tag:         "search",
+		Query: []Param{
+			{Name: "q", Type: "string", Description: "Required start of a username or approximate actual name"},
+			{Name: "limit", Type: "integer", Description: "Maximum number of users to return, defaults to 10 and at most 50"},
+		},
+		Responses: []RouteResponse{
+			{Status: http.StatusOK, Description: "Matching users without emails, best and most followed first", Body: []data.User{}},
+			badRequest, tooManyRequests, unavailable,
+		},
+	},
+	{
+		Method:      "GET",
+		Path:        api.PrefixAPIPath("search/users/autocomplete"),
+		OperationID: "autocompleteUsers",
+		Summary:     "Suggest users to mention by username prefix",
+		Tag:         "search",
+		Query: []Param{
+			{Name: "q", Type: "string", Description: "Required start of a username, optionally prefixed by @"},
+			{Name: "limit", Type: "integer", Description: "Maximum number of suggestions, defaults to 5 and at most 20"},
+		},
+		Responses: []RouteResponse{
+			{Status: http.StatusOK, Description: "Suggested users, most followed first", Body: []api.UserSuggestion{}},
+			badRequest, tooManyRequests, unavailable,
+		},
+	},
+}
@@ -0,0 +1,169 @@
+package docs
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Paths the documentation is served at
+const (
+	SpecPath = "/openapi.json"
+	UIPath   = "/docs"
+)
+
+const bearerAuth = "bearerAuth"
+
+// matches mux path variables of the form {name} or {name:pattern}
+var pathVarRegex = regexp.MustCompile(`\{([^{}:]+)(?::([^{}]+))?\}`)
+
+// OpenAPIPath converts a mux path template into an OpenAPI path
+func OpenAPIPath(template string) string {
+	return pathVarRegex.ReplaceAllString(template, "{$1}")
+}
+
+// Spec builds the OpenAPI document describing the given routes
+func Spec(version string, routes []Route) *Document {
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info: Info{
+			Title:       "MeIRL API",
+			Description: "The MeIRL social network API",
+			Version:     version,
+		},
+		Paths: make(map[string]PathItem),
+		Components: Components{
+			Schemas: make(map[string]*Schema),
+			SecuritySchemes: map[string]SecurityScheme{
+				bearerAuth: {
+					Type:         "http",
+					Scheme:       "bearer",
+					BearerFormat: "JWT",
+					Description:  "Access token returned by login",
+				},
+			},
+		},
+	}
+	tags := make(map[string]bool)
+	for _, route := range routes {
+		path := OpenAPIPath(route.Path)
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = make(PathItem)
+		}
+		doc.Paths[path][strings.ToLower(route.Method)] = operation(route, doc.Components.Schemas)
+		if !tags[route.Tag] {
+			tags[route.Tag] = true
+			doc.Tags = append(doc.Tags, Tag{Name: route.Tag})
+		}
+	}
+	return doc
+}
+
+func operation(route Route, schemas map[string]*Schema) *Operation {
+	op := &Operation{
+		OperationID: route.OperationID,
+		Summary:     route.Summary,
+		Tags:        []string{route.Tag},
+		Responses:   make(map[string]Response),
+	}
+	for _, match := range pathVarRegex.FindAllStringSubmatch(route.Path, -1) {
+		op.Parameters = append(op.Parameters, Parameter{
+			Name:     match[1],
+			In:       "path",
+			Required: true,
+			Schema:   &Schema{Type: "string", Pattern: match[2]},
+		})
+	}
+	for _, param := range route.Query {
+		op.Parameters = append(op.Parameters, Parameter{
+			Name:        param.Name,
+			In:          "query",
+			Description: param.Description,
+			Schema:      &Schema{Type: param.Type},
+		})
+	}
+	if route.Auth {
+		op.Security = []map[string][]string{{bearerAuth: {}}}
+	}
+	if route.Request != nil {
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content: map[string]MediaType{
+				"application/json": {Schema: schemaFor(reflect.TypeOf(route.Request), schemas)},
+			},
+		}
+	}
+	for _, resp := range route.Responses {
+		r := Response{Description: resp.Description}
+		if resp.Body != nil {
+			contentType := "application/json"
+			if resp.Status == http.StatusTooManyRequests || resp.Status == http.StatusInternalServerError {
+				contentType = "application/problem+json"
+			}
+			r.Content = map[string]MediaType{
+				contentType: {Schema: schemaFor(reflect.TypeOf(resp.Body), schemas)},
+			}
+		}
+		if resp.Status == http.StatusTooManyRequests {
+			r.Headers = map[string]Header{
+				"Retry-After": {Description: "Seconds until a request may be retried", Schema: &Schema{Type: "integer"}},
+			}
+		}
+		op.Responses[strconv.Itoa(resp.Status)] = r
+	}
+	// any handler may panic
+	op.Responses[strconv.Itoa(http.StatusInternalServerError)] = Response{
+		Description: "Unexpected server error",
+		Content: map[string]MediaType{
+			"application/problem+json": {Schema: schemaFor(reflect.TypeOf(problem), schemas)},
+		},
+	}
+	return op
+}
+
+// SpecHandler returns an http handler that serves the
+// OpenAPI document describing the given routes as JSON
+func SpecHandler(version string, routes []Route) http.HandlerFunc {
+	var once sync.Once
+	var body []byte
+	var err error
+	return func(w http.ResponseWriter, r *http.Request) {
+		once.Do(func() {
+			body, err = json.Marshal(Spec(version, routes))
+		})
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Add("Content-Type", "application/json")
+		w.Write(body)
+	}
+}
+
+// UIHandler returns an http handler that serves an HTML page
+// rendering the OpenAPI document served at SpecPath
+func UIHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(uiPage))
+	}
+}
+
+const uiPage = `<!DOCTYPE html>
+<html>
+<head>
+  <title>MeIRL API</title>
+  <meta charset="utf-8"/>
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <style>body { margin: 0; padding: 0; }</style>
+</head>
+<body>
+  <redoc spec-url="` + SpecPath + `"></redoc>
+  <script src="https://cdn.redoc.ly/redoc/latest/bundles/redoc.standalone.js"></script>
+</body>
+</html>
+`
@@ -0,0 +1,57 @@
+package docs
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestOpenAPIPath(t *testing.T) {
+	path := OpenAPIPath("/1.0/user/{id:[0-9]+}/feed")
+	if path != "/1.0/user/{id}/feed" {
+		t.Errorf("Expected /1.0/user/{id}/feed, got %s", path)
+		t.Fail()
+	}
+}
+
+func TestSpec(t *testing.T) {
+	doc := Spec("1.0", Routes)
+	if _, err := json.Marshal(doc); err != nil {
+		t.Error(err.Error())
+		t.FailNow()
+	}
+
+	op := doc.Paths["/1.0/user/{id}"]["get"]
+	if op == nil {
+		t.Error("Expected GET /1.0/user/{id} to be documented")
+		t.FailNow()
+	}
+	if len(op.Parameters) != 1 || op.Parameters[0].Schema.Pattern != "[0-9]+" {
+		t.Error("Expected id path parameter with its pattern")
+		t.Fail()
+	}
+
+	post, ok := doc.Components.Schemas["Post"]
+	if !ok {
+		t.Error("Expected a Post schema")
+		t.FailNow()
+	}
+	for _, prop := range []string{"id", "createdAt", "updatedAt", "authorID", "contents", "keks", "nos"} {
+		if _, ok := post.Properties[prop]; !ok {
+			t.Errorf("Expected Post schema to have property %s", prop)
+			t.Fail()
+		}
+	}
+	if post.Properties["createdAt"].Type != "integer" {
+		t.Error("Expected times to be documented as integers")
+		t.Fail()
+	}
+	if post.Properties["contents"].Format != "byte" {
+		t.Error("Expected contents to be documented as base64 bytes")
+		t.Fail()
+	}
+
+	if op := doc.Paths["/1.0/post/new"]["post"]; op == nil || len(op.Security) != 1 {
+		t.Error("Expected POST /1.0/post/new to require bearer auth")
+		t.Fail()
+	}
+}
@@ -1,31 +1,197 @@
 package main
 
 import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
 	"time"
 
 	graceful "gopkg.in/tylerb/graceful.v1"
 
 	"github.com/boxtown/meirl/api"
+	"github.com/boxtown/meirl/config"
 	"github.com/boxtown/meirl/data"
 	"github.com/boxtown/meirl/data/postgres"
+	"github.com/boxtown/meirl/metrics"
+	"github.com/boxtown/meirl/ratelimit"
+	"github.com/boxtown/meirl/realtime"
+	"github.com/boxtown/meirl/webhook"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/uber-go/zap"
 )
 
 func main() {
-	db, err := postgres.InitDB(pgUser, pgPass, pgHost, pgPort, pgDBName)
+	cfg, err := config.Load(os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	db, err := postgres.OpenDB(cfg.Postgres.ConnectionString())
 	if err != nil {
 		panic(err)
 	}
 	defer db.Close()
+	db.SetMaxOpenConns(cfg.Postgres.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.Postgres.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.Postgres.ConnMaxLifetime)
+
+	prometheus.MustRegister(metrics.NewDBStatsCollector(cfg.Postgres.Database, db.DB))
+
+	migrations, err := postgres.LoadMigrations(cfg.Postgres.MigrationsDir)
+	if err != nil {
+		panic(err)
+	}
+	schemaVersion := postgres.LatestVersion(migrations)
+	health := api.NewHealthAPI(2*time.Second,
+		api.HealthCheck{
+			Name:     "postgres",
+			Critical: true,
+			Check:    db.PingContext,
+		},
+		api.HealthCheck{
+			Name:     "migrations",
+			Critical: true,
+			Check: func(ctx context.Context) error {
+				return postgres.CheckSchemaVersion(db, schemaVersion)
+			},
+		},
+	)
 
 	userStore := postgres.NewUserStore(db)
 	postStore := postgres.NewPostStore(db)
-	r := Router(data.Stores{
-		UserStore: userStore,
-		PostStore: postStore,
+	if cfg.Feed.Strategy == config.FeedTimeline {
+		postStore = postgres.NewTimelinePostStore(db)
+	}
+	notificationStore := postgres.NewNotificationStore(db)
+	webhookStore := postgres.NewWebhookStore(db)
+	searchStore := postgres.NewSearchStore(db)
+	blockStore := postgres.NewBlockStore(db)
+	stores := metrics.InstrumentStores(data.Stores{
+		UserStore:         userStore,
+		PostStore:         postStore,
+		NotificationStore: notificationStore,
+		WebhookStore:      webhookStore,
+		SearchStore:       searchStore,
+		BlockStore:        blockStore,
 	})
-	graceful.Run(":8080", 10*time.Second,
-		api.LimitBodySize(
-			api.CORS(r.ServeHTTP), requestBodyMaxBytes,
-		),
-	)
+	logger := newLogger(cfg)
+
+	bus := realtime.NewBus()
+	var events realtime.Publisher = bus
+	if cfg.Realtime.Backend == config.RealtimePostgres {
+		events = postgres.NewNotifier(db)
+		listener, err := postgres.ListenEvents(cfg.Postgres.ConnectionString(), bus, func(err error) {
+			logger.Error("realtime event listener error", zap.Error(err))
+		})
+		if err != nil {
+			panic(err)
+		}
+		defer listener.Close()
+	}
+	// events recorded by the stores are relayed from the outbox by one
+	// instance at a time, so webhooks are not delivered twice. Streams of
+	// other instances only see them through the postgres backend
+	sinks := map[string]realtime.Publisher{
+		"realtime":  events,
+		"webhooks":  webhook.NewDispatcher(stores),
+		"timelines": postgres.NewTimelineFanout(db, cfg.Feed.FanoutLimit),
+	}
+	if cfg.Realtime.LogEvents {
+		sinks["log"] = logPublisher{logger}
+	}
+	relay := postgres.NewOutboxRelay(db, sinks, func(err error) {
+		logger.Error("outbox relay error", zap.Error(err))
+	})
+	go relay.Run()
+	trending := postgres.NewTrendingAggregator(db, func(err error) {
+		logger.Error("trending aggregator error", zap.Error(err))
+	})
+	go trending.Run()
+	worker := webhook.NewWorker(stores.WebhookStore, cfg.Webhooks.AllowPrivateAddrs, logger)
+	go worker.Run()
+
+	stream := api.NewStreamAPI(stores, bus, cfg.Debug())
+	socket := api.NewSocketAPI(stores, bus, cfg.Debug())
+
+	r := Router(cfg, services{
+		stores:  stores,
+		health:  health,
+		limiter: newRateLimitStore(cfg),
+		events:  events,
+		stream:  stream,
+		socket:  socket,
+	})
+	srv := &graceful.Server{
+		Timeout: cfg.Server.ShutdownTimeout,
+		ShutdownInitiated: func() {
+			health.Shutdown()
+			stream.Shutdown()
+			socket.Shutdown()
+			relay.Stop()
+			worker.Stop()
+			trending.Stop()
+		},
+		Server: &http.Server{
+			Addr: cfg.Server.ListenAddr,
+			Handler: api.LogRequests(logger, r,
+				api.InstrumentRequests(r,
+					api.RecoverPanics(
+						api.LimitBodySize(
+							api.CORS(r.ServeHTTP), cfg.Server.RequestBodyMaxBytes,
+						),
+					),
+				),
+			),
+			ReadTimeout:  cfg.Server.ReadTimeout,
+			WriteTimeout: cfg.Server.WriteTimeout,
+			IdleTimeout:  cfg.Server.IdleTimeout,
+		},
+	}
+	if err := srv.ListenAndServe(); err != nil {
+		// graceful shutdown surfaces as an accept error on the closed listener
+		if opErr, ok := err.(*net.OpError); !ok || opErr.Op != "accept" {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+	relay.Stop()
+	worker.Stop()
+	trending.Stop()
+	relay.Wait()
+	worker.Wait()
+	trending.Wait()
+}
+
+// newLogger returns a human readable logger for development
+// and a JSON logger for production
+func newLogger(cfg *config.Config) zap.Logger {
+	if cfg.Debug() {
+		return zap.New(zap.NewTextEncoder(), zap.DebugLevel)
+	}
+	return zap.New(zap.NewJSONEncoder())
+}
+
+// logPublisher is a realtime.Publisher that logs every event
+type logPublisher struct {
+	logger zap.Logger
+}
+
+func (p logPublisher) Publish(e realtime.Event) error {
+	p.logger.Info("domain event",
+		zap.String("type", e.Type),
+		zap.Int64("id", e.ID),
+		zap.Int64("user_id", e.UserID))
+	return nil
+}
+
+// newRateLimitStore returns the rate limit backend
+// selected by the configuration
+func newRateLimitStore(cfg *config.Config) ratelimit.Store {
+	if cfg.RateLimit.Backend == config.RateLimitRedis {
+		return ratelimit.NewRedisStore(ratelimit.NewRedisPool(cfg.RateLimit.RedisAddr), "meirl:ratelimit:")
+	}
+	return ratelimit.NewMemoryStore()
 }
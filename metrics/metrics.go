@@ -0,0 +1,108 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "meirl"
+
+// HTTP metrics
+var (
+	// HTTPRequests counts completed HTTP requests by
+	// route template, method and status code
+	HTTPRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "Completed HTTP requests by route template, method and status.",
+		},
+		[]string{"route", "method", "status"},
+	)
+
+	// HTTPRequestDuration observes HTTP request latencies by
+	// route template, method and status code
+	HTTPRequestDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "HTTP request latencies by route template, method and status.",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"route", "method", "status"},
+	)
+)
+
+// Panics counts panics recovered from HTTP handlers by route template
+var Panics = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "panics_total",
+		Help:      "Panics recovered from HTTP handlers by route template.",
+	},
+	[]string{"route"},
+)
+
+// StoreQueryDuration observes data store method latencies
+// by store and method
+var StoreQueryDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "store",
+		Name:      "query_duration_seconds",
+		Help:      "Data store method latencies by store and method.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	},
+	[]string{"store", "method"},
+)
+
+// WebhookAttempts counts attempted webhook deliveries by outcome,
+// either succeeded, retrying or failed
+var WebhookAttempts = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "webhook",
+		Name:      "attempts_total",
+		Help:      "Attempted webhook deliveries by outcome.",
+	},
+	[]string{"outcome"},
+)
+
+// Business counters
+var (
+	// Signups counts successfully created users
+	Signups = newCounter("signups_total", "Successfully created users.")
+
+	// Logins counts successful logins
+	Logins = newCounter("logins_total", "Successful logins.")
+
+	// PostsCreated counts successfully created posts
+	PostsCreated = newCounter("posts_created_total", "Successfully created posts.")
+
+	// Follows counts successfully created follow relationships
+	Follows = newCounter("follows_total", "Successfully created follow relationships.")
+)
+
+func newCounter(name, help string) prometheus.Counter {
+	return prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      name,
+		Help:      help,
+	})
+}
+
+func init() {
+	prometheus.MustRegister(
+		HTTPRequests,
+		HTTPRequestDuration,
+		Panics,
+		StoreQueryDuration,
+		WebhookAttempts,
+		Signups,
+		Logins,
+		PostsCreated,
+		Follows,
+	)
+}
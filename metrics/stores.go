@@ -0,0 +1,447 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/boxtown/meirl/data"
+)
+
+// InstrumentStores wraps each store in stores with a decorator
+// that observes method latencies in StoreQueryDuration
+func InstrumentStores(stores data.Stores) data.Stores {
+	return data.Stores{
+		UserStore:         InstrumentUserStore(stores.UserStore),
+		PostStore:         InstrumentPostStore(stores.PostStore),
+		NotificationStore: InstrumentNotificationStore(stores.NotificationStore),
+		WebhookStore:      InstrumentWebhookStore(stores.WebhookStore),
+		SearchStore:       InstrumentSearchStore(stores.SearchStore),
+		BlockStore:        InstrumentBlockStore(stores.BlockStore),
+	}
+}
+
+func observe(store, method string, start time.Time) {
+	StoreQueryDuration.WithLabelValues(store, method).Observe(time.Since(start).Seconds())
+}
+
+/* ********************** *
+ * Instrumented UserStore *
+ * ********************** */
+
+// InstrumentUserStore wraps store with a decorator that observes
+// method latencies in StoreQueryDuration
+func InstrumentUserStore(store data.UserStore) data.UserStore {
+	return instrumentedUserStore{store}
+}
+
+type instrumentedUserStore struct {
+	store data.UserStore
+}
+
+func (s instrumentedUserStore) Create(user *data.User) (int64, error) {
+	defer observe("user", "Create", time.Now())
+	return s.store.Create(user)
+}
+
+func (s instrumentedUserStore) Get(id int64) (*data.User, error) {
+	defer observe("user", "Get", time.Now())
+	return s.store.Get(id)
+}
+
+func (s instrumentedUserStore) GetByUsername(username string) (*data.User, error) {
+	defer observe("user", "GetByUsername", time.Now())
+	return s.store.GetByUsername(username)
+}
+
+func (s instrumentedUserStore) GetByEmail(email string) (*data.User, error) {
+	defer observe("user", "GetByEmail", time.Now())
+	return s.store.GetByEmail(email)
+}
+
+func (s instrumentedUserStore) Update(id int64, user *data.User) error {
+	defer observe("user", "Update", time.Now())
+	return s.store.Update(id, user)
+}
+
+func (s instrumentedUserStore) Delete(id int64) error {
+	defer observe("user", "Delete", time.Now())
+	return s.store.Delete(id)
+}
+
+func (s instrumentedUserStore) Follow(followerID, followeeID int64) error {
+	defer observe("user", "Follow", time.Now())
+	return s.store.Follow(followerID, followeeID)
+}
+
+func (s instrumentedUserStore) UnFollow(followerID, followeeID int64) error {
+	defer observe("user", "UnFollow", time.Now())
+	return s.store.UnFollow(followerID, followeeID)
+}
+
+func (s instrumentedUserStore) Followers(
+	id int64,
+	options data.ListOptions,
+	sort data.UserSortMethod) ([]data.User, error) {
+	defer observe("user", "Followers", time.Now())
+	return s.store.Followers(id, options, sort)
+}
+
+func (s instrumentedUserStore) Following(
+	id int64,
+	options data.ListOptions,
+	sort data.UserSortMethod) ([]data.User, error) {
+	defer observe("user", "Following", time.Now())
+	return s.store.Following(id, options, sort)
+}
+
+func (s instrumentedUserStore) FollowingIDs(id int64) ([]int64, error) {
+	defer observe("user", "FollowingIDs", time.Now())
+	return s.store.FollowingIDs(id)
+}
+
+func (s instrumentedUserStore) SetPrivate(id int64, private bool) error {
+	defer observe("user", "SetPrivate", time.Now())
+	return s.store.SetPrivate(id, private)
+}
+
+func (s instrumentedUserStore) IsFollowing(followerID, followeeID int64) (bool, error) {
+	defer observe("user", "IsFollowing", time.Now())
+	return s.store.IsFollowing(followerID, followeeID)
+}
+
+func (s instrumentedUserStore) RequestFollow(followerID, followeeID int64) error {
+	defer observe("user", "RequestFollow", time.Now())
+	return s.store.RequestFollow(followerID, followeeID)
+}
+
+func (s instrumentedUserStore) ApproveFollow(followerID, followeeID int64) error {
+	defer observe("user", "ApproveFollow", time.Now())
+	return s.store.ApproveFollow(followerID, followeeID)
+}
+
+func (s instrumentedUserStore) DeleteFollowRequest(followerID, followeeID int64) error {
+	defer observe("user", "DeleteFollowRequest", time.Now())
+	return s.store.DeleteFollowRequest(followerID, followeeID)
+}
+
+func (s instrumentedUserStore) FollowRequests(id int64, options data.ListOptions) ([]data.User, error) {
+	defer observe("user", "FollowRequests", time.Now())
+	return s.store.FollowRequests(id, options)
+}
+
+/* ********************** *
+ * Instrumented PostStore *
+ * ********************** */
+
+// InstrumentPostStore wraps store with a decorator that observes
+// method latencies in StoreQueryDuration
+func InstrumentPostStore(store data.PostStore) data.PostStore {
+	return instrumentedPostStore{store}
+}
+
+type instrumentedPostStore struct {
+	store data.PostStore
+}
+
+func (s instrumentedPostStore) Create(post *data.Post) (int64, error) {
+	defer observe("post", "Create", time.Now())
+	return s.store.Create(post)
+}
+
+func (s instrumentedPostStore) Get(id int64) (*data.Post, error) {
+	defer observe("post", "Get", time.Now())
+	return s.store.Get(id)
+}
+
+func (s instrumentedPostStore) Update(id int64, contents []byte, entities []data.PostEntity) error {
+	defer observe("post", "Update", time.Now())
+	return s.store.Update(id, contents, entities)
+}
+
+func (s instrumentedPostStore) Delete(id int64) error {
+	defer observe("post", "Delete", time.Now())
+	return s.store.Delete(id)
+}
+
+func (s instrumentedPostStore) UserPosts(
+	userID int64,
+	options data.ListOptions,
+	sort data.PostSortMethod) ([]data.Post, error) {
+	defer observe("post", "UserPosts", time.Now())
+	return s.store.UserPosts(userID, options, sort)
+}
+
+func (s instrumentedPostStore) Feed(
+	userID int64,
+	options data.ListOptions,
+	sort data.PostSortMethod) ([]data.Post, error) {
+	defer observe("post", "Feed", time.Now())
+	return s.store.Feed(userID, options, sort)
+}
+
+func (s instrumentedPostStore) Mentions(
+	userID int64,
+	options data.ListOptions,
+	sort data.PostSortMethod) ([]data.Post, error) {
+	defer observe("post", "Mentions", time.Now())
+	return s.store.Mentions(userID, options, sort)
+}
+
+func (s instrumentedPostStore) TaggedPosts(
+	tag string,
+	options data.ListOptions,
+	sort data.PostSortMethod) ([]data.Post, error) {
+	defer observe("post", "TaggedPosts", time.Now())
+	return s.store.TaggedPosts(tag, options, sort)
+}
+
+func (s instrumentedPostStore) Ancestors(id, viewerID int64, limit int) ([]data.Post, error) {
+	defer observe("post", "Ancestors", time.Now())
+	return s.store.Ancestors(id, viewerID, limit)
+}
+
+func (s instrumentedPostStore) Descendants(
+	id, viewerID int64,
+	depth, maxNodes int,
+	options data.ListOptions) ([]data.Post, error) {
+	defer observe("post", "Descendants", time.Now())
+	return s.store.Descendants(id, viewerID, depth, maxNodes, options)
+}
+
+func (s instrumentedPostStore) Recent(options data.ListOptions) ([]data.Post, error) {
+	defer observe("post", "Recent", time.Now())
+	return s.store.Recent(options)
+}
+
+func (s instrumentedPostStore) Trending(window data.TrendingWindow, options data.ListOptions) ([]data.Post, error) {
+	defer observe("post", "Trending", time.Now())
+	return s.store.Trending(window, options)
+}
+
+func (s instrumentedPostStore) React(postID, userID int64, reaction data.Reaction) error {
+	defer observe("post", "React", time.Now())
+	return s.store.React(postID, userID, reaction)
+}
+
+func (s instrumentedPostStore) UnReact(postID, userID int64, reaction data.Reaction) error {
+	defer observe("post", "UnReact", time.Now())
+	return s.store.UnReact(postID, userID, reaction)
+}
+
+func (s instrumentedPostStore) Repost(postID, userID int64) error {
+	defer observe("post", "Repost", time.Now())
+	return s.store.Repost(postID, userID)
+}
+
+func (s instrumentedPostStore) UnRepost(postID, userID int64) error {
+	defer observe("post", "UnRepost", time.Now())
+	return s.store.UnRepost(postID, userID)
+}
+
+/* ****************************** *
+ * Instrumented NotificationStore *
+ * ****************************** */
+
+// InstrumentNotificationStore wraps store with a decorator that
+// observes method latencies in StoreQueryDuration
+func InstrumentNotificationStore(store data.NotificationStore) data.NotificationStore {
+	return instrumentedNotificationStore{store}
+}
+
+type instrumentedNotificationStore struct {
+	store data.NotificationStore
+}
+
+func (s instrumentedNotificationStore) Notify(
+	userID, actorID int64,
+	notificationType data.NotificationType,
+	postID int64) (int64, error) {
+	defer observe("notification", "Notify", time.Now())
+	return s.store.Notify(userID, actorID, notificationType, postID)
+}
+
+func (s instrumentedNotificationStore) Get(id int64) (*data.Notification, error) {
+	defer observe("notification", "Get", time.Now())
+	return s.store.Get(id)
+}
+
+func (s instrumentedNotificationStore) List(
+	userID int64,
+	options data.ListOptions,
+	unreadOnly bool) ([]data.Notification, error) {
+	defer observe("notification", "List", time.Now())
+	return s.store.List(userID, options, unreadOnly)
+}
+
+func (s instrumentedNotificationStore) UnreadCount(userID int64) (int, error) {
+	defer observe("notification", "UnreadCount", time.Now())
+	return s.store.UnreadCount(userID)
+}
+
+func (s instrumentedNotificationStore) MarkRead(userID int64, ids []int64) error {
+	defer observe("notification", "MarkRead", time.Now())
+	return s.store.MarkRead(userID, ids)
+}
+
+func (s instrumentedNotificationStore) MarkAllRead(userID int64) error {
+	defer observe("notification", "MarkAllRead", time.Now())
+	return s.store.MarkAllRead(userID)
+}
+
+func (s instrumentedNotificationStore) Preferences(userID int64) (*data.NotificationPreferences, error) {
+	defer observe("notification", "Preferences", time.Now())
+	return s.store.Preferences(userID)
+}
+
+func (s instrumentedNotificationStore) SetPreferences(userID int64, preferences *data.NotificationPreferences) error {
+	defer observe("notification", "SetPreferences", time.Now())
+	return s.store.SetPreferences(userID, preferences)
+}
+
+/* ************************* *
+ * Instrumented WebhookStore *
+ * ************************* */
+
+// InstrumentWebhookStore wraps store with a decorator that
+// observes method latencies in StoreQueryDuration
+func InstrumentWebhookStore(store data.WebhookStore) data.WebhookStore {
+	return instrumentedWebhookStore{store}
+}
+
+type instrumentedWebhookStore struct {
+	store data.WebhookStore
+}
+
+func (s instrumentedWebhookStore) Create(webhook *data.Webhook) (int64, error) {
+	defer observe("webhook", "Create", time.Now())
+	return s.store.Create(webhook)
+}
+
+func (s instrumentedWebhookStore) Get(id int64) (*data.Webhook, error) {
+	defer observe("webhook", "Get", time.Now())
+	return s.store.Get(id)
+}
+
+func (s instrumentedWebhookStore) List(userID int64) ([]data.Webhook, error) {
+	defer observe("webhook", "List", time.Now())
+	return s.store.List(userID)
+}
+
+func (s instrumentedWebhookStore) Delete(id int64) error {
+	defer observe("webhook", "Delete", time.Now())
+	return s.store.Delete(id)
+}
+
+func (s instrumentedWebhookStore) Subscribed(event string, userIDs []int64) ([]data.Webhook, error) {
+	defer observe("webhook", "Subscribed", time.Now())
+	return s.store.Subscribed(event, userIDs)
+}
+
+func (s instrumentedWebhookStore) CreateDelivery(delivery *data.WebhookDelivery) (int64, error) {
+	defer observe("webhook", "CreateDelivery", time.Now())
+	return s.store.CreateDelivery(delivery)
+}
+
+func (s instrumentedWebhookStore) GetDelivery(id int64) (*data.WebhookDelivery, error) {
+	defer observe("webhook", "GetDelivery", time.Now())
+	return s.store.GetDelivery(id)
+}
+
+func (s instrumentedWebhookStore) Deliveries(webhookID int64, options data.ListOptions) ([]data.WebhookDelivery, error) {
+	defer observe("webhook", "Deliveries", time.Now())
+	return s.store.Deliveries(webhookID, options)
+}
+
+func (s instrumentedWebhookStore) ClaimDeliveries(limit int, lease time.Duration) ([]data.WebhookDelivery, error) {
+	defer observe("webhook", "ClaimDeliveries", time.Now())
+	return s.store.ClaimDeliveries(limit, lease)
+}
+
+func (s instrumentedWebhookStore) UpdateDelivery(delivery *data.WebhookDelivery) error {
+	defer observe("webhook", "UpdateDelivery", time.Now())
+	return s.store.UpdateDelivery(delivery)
+}
+
+/* ************************ *
+ * Instrumented SearchStore *
+ * ************************ */
+
+// InstrumentSearchStore wraps store with a decorator that
+// observes method latencies in StoreQueryDuration
+func InstrumentSearchStore(store data.SearchStore) data.SearchStore {
+	return instrumentedSearchStore{store}
+}
+
+type instrumentedSearchStore struct {
+	store data.SearchStore
+}
+
+func (s instrumentedSearchStore) SearchPosts(
+	query data.PostQuery,
+	options data.ListOptions) ([]data.PostSearchResult, error) {
+	defer observe("search", "SearchPosts", time.Now())
+	return s.store.SearchPosts(query, options)
+}
+
+func (s instrumentedSearchStore) SearchUsers(query string, limit int) ([]data.User, error) {
+	defer observe("search", "SearchUsers", time.Now())
+	return s.store.SearchUsers(query, limit)
+}
+
+func (s instrumentedSearchStore) CompleteUsernames(prefix string, limit int) ([]data.User, error) {
+	defer observe("search", "CompleteUsernames", time.Now())
+	return s.store.CompleteUsernames(prefix, limit)
+}
+
+/* *********************** *
+ * Instrumented BlockStore *
+ * *********************** */
+
+// InstrumentBlockStore wraps store with a decorator that
+// observes method latencies in StoreQueryDuration
+func InstrumentBlockStore(store data.BlockStore) data.BlockStore {
+	return instrumentedBlockStore{store}
+}
+
+type instrumentedBlockStore struct {
+	store data.BlockStore
+}
+
+func (s instrumentedBlockStore) Block(blockerID, blockedID int64) error {
+	defer observe("block", "Block", time.Now())
+	return s.store.Block(blockerID, blockedID)
+}
+
+func (s instrumentedBlockStore) UnBlock(blockerID, blockedID int64) error {
+	defer observe("block", "UnBlock", time.Now())
+	return s.store.UnBlock(blockerID, blockedID)
+}
+
+func (s instrumentedBlockStore) Mute(muterID, mutedID int64) error {
+	defer observe("block", "Mute", time.Now())
+	return s.store.Mute(muterID, mutedID)
+}
+
+func (s instrumentedBlockStore) UnMute(muterID, mutedID int64) error {
+	defer observe("block", "UnMute", time.Now())
+	return s.store.UnMute(muterID, mutedID)
+}
+
+func (s instrumentedBlockStore) IsBlocked(userID, otherID int64) (bool, error) {
+	defer observe("block", "IsBlocked", time.Now())
+	return s.store.IsBlocked(userID, otherID)
+}
+
+func (s instrumentedBlockStore) Blocked(userID int64, options data.ListOptions) ([]data.User, error) {
+	defer observe("block", "Blocked", time.Now())
+	return s.store.Blocked(userID, options)
+}
+
+func (s instrumentedBlockStore) Muted(userID int64, options data.ListOptions) ([]data.User, error) {
+	defer observe("block", "Muted", time.Now())
+	return s.store.Muted(userID, options)
+}
+
+func (s instrumentedBlockStore) MutedIDs(userID int64) ([]int64, error) {
+	defer observe("block", "MutedIDs", time.Now())
+	return s.store.MutedIDs(userID)
+}
@@ -0,0 +1,37 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/boxtown/meirl/data"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+type fakePostStore struct {
+	data.PostStore
+	got int64
+}
+
+func (store *fakePostStore) Get(id int64) (*data.Post, error) {
+	store.got = id
+	return &data.Post{AuthorID: 1}, nil
+}
+
+func TestInstrumentPostStore(t *testing.T) {
+	fake := &fakePostStore{}
+	store := InstrumentPostStore(fake)
+
+	post, err := store.Get(3)
+	if err != nil {
+		t.Error(err.Error())
+		t.FailNow()
+	}
+	if fake.got != 3 || post.AuthorID != 1 {
+		t.Error("Instrumented store did not delegate to the wrapped store")
+		t.Fail()
+	}
+	if n := testutil.CollectAndCount(StoreQueryDuration); n != 1 {
+		t.Errorf("Expected 1 observed store method, got %d", n)
+		t.Fail()
+	}
+}
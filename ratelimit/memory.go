@@ -0,0 +1,69 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// sweep full buckets out of memory after this many takes
+const sweepInterval = 10000
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	rate   Rate
+}
+
+// MemoryStore is an in-process Store. Buckets are not shared
+// between processes, so limits apply per instance
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	takes   int
+	now     func() time.Time
+}
+
+// NewMemoryStore returns a newly constructed MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+// Take takes a token from the bucket identified by key
+func (store *MemoryStore) Take(key string, rate Rate) (Result, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	now := store.now()
+	store.takes++
+	if store.takes%sweepInterval == 0 {
+		store.sweep(now)
+	}
+
+	b, ok := store.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(rate.Limit), last: now}
+		store.buckets[key] = b
+	}
+	b.tokens = refill(b.tokens, now.Sub(b.last), rate)
+	b.last = now
+	b.rate = rate
+
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+	return newResult(allowed, b.tokens, rate), nil
+}
+
+// Delete buckets that would have refilled completely, since
+// they are indistinguishable from new buckets
+func (store *MemoryStore) sweep(now time.Time) {
+	for key, b := range store.buckets {
+		if refill(b.tokens, now.Sub(b.last), b.rate) >= float64(b.rate.Limit) {
+			delete(store.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package ratelimit
+
+import (
+	"math"
+	"time"
+)
+
+// Rate is a token bucket rate. Buckets hold up to Limit tokens
+// and refill at Limit tokens per Period, so clients may burst up
+// to Limit requests before being limited to the steady rate
+type Rate struct {
+	Limit  int
+	Period time.Duration
+}
+
+// perSecond returns the refill rate in tokens per second
+func (r Rate) perSecond() float64 {
+	return float64(r.Limit) / r.Period.Seconds()
+}
+
+// Result is the outcome of taking a token from a bucket
+type Result struct {
+	// Allowed is true if a token was taken
+	Allowed bool
+
+	// Limit is the capacity of the bucket
+	Limit int
+
+	// Remaining is the number of whole tokens left in the bucket
+	Remaining int
+
+	// Reset is the time until the bucket is full again
+	Reset time.Duration
+
+	// RetryAfter is the time until the next token is available.
+	// Zero if the request was allowed
+	RetryAfter time.Duration
+}
+
+// Store is a backend for rate limit token buckets
+type Store interface {
+	// Take attempts to take a token from the bucket identified
+	// by key, creating a full bucket with the given rate if
+	// none exists
+	Take(key string, rate Rate) (Result, error)
+}
+
+// newResult builds a Result from the tokens left in a bucket
+// after attempting to take a token
+func newResult(allowed bool, tokens float64, rate Rate) Result {
+	perSecond := rate.perSecond()
+	result := Result{
+		Allowed:   allowed,
+		Limit:     rate.Limit,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     secondsToDuration((float64(rate.Limit) - tokens) / perSecond),
+	}
+	if !allowed {
+		result.RetryAfter = secondsToDuration((1 - tokens) / perSecond)
+	}
+	return result
+}
+
+// refill returns the tokens within a bucket after elapsed
+// time has passed since it last held tokens
+func refill(tokens float64, elapsed time.Duration, rate Rate) float64 {
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	return math.Min(float64(rate.Limit), tokens+elapsed.Seconds()*rate.perSecond())
+}
+
+func secondsToDuration(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,92 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis"
+)
+
+type clock struct {
+	t time.Time
+}
+
+func (c *clock) now() time.Time {
+	return c.t
+}
+
+func testStore(t *testing.T, store Store, c *clock) {
+	rate := Rate{Limit: 2, Period: time.Second}
+
+	for i := 0; i < 2; i++ {
+		result, err := store.Take("key", rate)
+		if err != nil {
+			t.Error(err.Error())
+			t.FailNow()
+		}
+		if !result.Allowed {
+			t.Errorf("Expected take %d within burst to be allowed", i)
+			t.Fail()
+		}
+		if result.Remaining != 1-i {
+			t.Errorf("Expected %d remaining, got %d", 1-i, result.Remaining)
+			t.Fail()
+		}
+	}
+
+	result, err := store.Take("key", rate)
+	if err != nil {
+		t.Error(err.Error())
+		t.FailNow()
+	}
+	if result.Allowed {
+		t.Error("Expected take beyond burst to be limited")
+		t.Fail()
+	}
+	if result.RetryAfter != 500*time.Millisecond {
+		t.Errorf("Expected retry after 500ms, got %s", result.RetryAfter)
+		t.Fail()
+	}
+	if result.Reset != time.Second {
+		t.Errorf("Expected reset after 1s, got %s", result.Reset)
+		t.Fail()
+	}
+
+	result, err = store.Take("other", rate)
+	if err != nil || !result.Allowed {
+		t.Error("Expected buckets to be independent per key")
+		t.Fail()
+	}
+
+	c.t = c.t.Add(500 * time.Millisecond)
+	result, err = store.Take("key", rate)
+	if err != nil || !result.Allowed {
+		t.Error("Expected bucket to refill over time")
+		t.Fail()
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	c := &clock{t: time.Unix(1000, 0)}
+	store := NewMemoryStore()
+	store.now = c.now
+	testStore(t, store, c)
+}
+
+func TestRedisStore(t *testing.T) {
+	server, err := miniredis.Run()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer server.Close()
+
+	c := &clock{t: time.Unix(1000, 0)}
+	store := NewRedisStore(NewRedisPool(server.Addr()), "ratelimit:")
+	store.now = c.now
+	testStore(t, store, c)
+
+	if !server.Exists("ratelimit:key") {
+		t.Error("Expected bucket to be stored under the key prefix")
+		t.Fail()
+	}
+}
@@ -0,0 +1,105 @@
+package ratelimit
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// takeScript atomically refills and takes a token from the bucket hash
+// at KEYS[1]. ARGV holds the capacity, the refill rate in tokens per
+// millisecond, the current time in milliseconds and the key TTL in
+// milliseconds. Token counts are returned as strings since Lua numbers
+// are truncated to integers in Redis replies
+var takeScript = redis.NewScript(1, `
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local ttl = tonumber(ARGV[4])
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+	tokens = capacity
+	ts = now
+end
+
+tokens = math.min(capacity, tokens + math.max(0, now - ts) * rate)
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call('HMSET', KEYS[1], 'tokens', tostring(tokens), 'ts', tostring(now))
+redis.call('PEXPIRE', KEYS[1], ttl)
+return {allowed, tostring(tokens)}
+`)
+
+// RedisStore is a Store backed by Redis or any server speaking
+// the Redis protocol with Lua scripting support. Buckets are shared
+// by every instance using the same server
+type RedisStore struct {
+	pool   *redis.Pool
+	prefix string
+	now    func() time.Time
+}
+
+// NewRedisStore returns a newly constructed RedisStore using
+// connections from the given pool. Bucket keys are prefixed
+// with prefix
+func NewRedisStore(pool *redis.Pool, prefix string) *RedisStore {
+	return &RedisStore{
+		pool:   pool,
+		prefix: prefix,
+		now:    time.Now,
+	}
+}
+
+// NewRedisPool returns a connection pool for the Redis
+// server at addr
+func NewRedisPool(addr string) *redis.Pool {
+	return &redis.Pool{
+		MaxIdle:     10,
+		IdleTimeout: 4 * time.Minute,
+		Dial: func() (redis.Conn, error) {
+			return redis.Dial("tcp", addr,
+				redis.DialConnectTimeout(time.Second),
+				redis.DialReadTimeout(time.Second),
+				redis.DialWriteTimeout(time.Second),
+			)
+		},
+	}
+}
+
+// Take takes a token from the bucket identified by key
+func (store *RedisStore) Take(key string, rate Rate) (Result, error) {
+	conn := store.pool.Get()
+	defer conn.Close()
+
+	perMilli := rate.perSecond() / 1000
+	nowMilli := store.now().UnixNano() / int64(time.Millisecond)
+	ttlMilli := int64(rate.Period/time.Millisecond) + 1000
+	reply, err := redis.Values(takeScript.Do(conn,
+		store.prefix+key,
+		rate.Limit,
+		strconv.FormatFloat(perMilli, 'g', -1, 64),
+		nowMilli,
+		ttlMilli,
+	))
+	if err != nil {
+		return Result{}, err
+	}
+	var allowed int
+	var tokens string
+	if _, err := redis.Scan(reply, &allowed, &tokens); err != nil {
+		return Result{}, err
+	}
+	remaining, err := strconv.ParseFloat(tokens, 64)
+	if err != nil {
+		return Result{}, err
+	}
+	return newResult(allowed == 1, remaining, rate), nil
+}
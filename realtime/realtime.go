@@ -0,0 +1,184 @@
+// Package realtime distributes events, such as created posts,
+// to subscribers within the process
+package realtime
+
+import "sync"
+
+// Event types
+const (
+	// UserCreated is published when a user signs up. ID
+	// and UserID are both the ID of the user
+	UserCreated = "user.created"
+
+	// UserUpdated is published when the profile of a user is updated
+	// or the user is disabled or enabled. ID and UserID are both the
+	// ID of the user
+	UserUpdated = "user.updated"
+
+	// UserDeleted is published when a user is deleted along with their
+	// posts and follows. ID and UserID are both the ID of the user
+	UserDeleted = "user.deleted"
+
+	// PostCreated is published when a post is created. ID is the
+	// ID of the post and UserID the ID of its author
+	PostCreated = "post.created"
+
+	// PostUpdated is published when the contents of a post are
+	// updated. ID is the ID of the post and UserID the ID of its author
+	PostUpdated = "post.updated"
+
+	// PostDeleted is published when a post is deleted. ID is the
+	// ID of the post and UserID the ID of its author
+	PostDeleted = "post.deleted"
+
+	// UserFollowed is published when a user follows another. ID is
+	// the ID of the followee and UserID the ID of the follower
+	UserFollowed = "user.followed"
+
+	// UserUnFollowed is published when a user unfollows another. ID
+	// is the ID of the followee and UserID the ID of the follower
+	UserUnFollowed = "user.unfollowed"
+
+	// PostReactionsChanged is published when a user reacts to a post
+	// or removes a reaction. ID is the ID of the post and UserID the
+	// ID of the reacting user
+	PostReactionsChanged = "post.reactions"
+
+	// PostReposted is published when a user reposts a post. ID is the
+	// ID of the post and UserID the ID of the reposting user
+	PostReposted = "post.reposted"
+
+	// PostUnReposted is published when a user undoes a repost. ID is
+	// the ID of the post and UserID the ID of the reposting user
+	PostUnReposted = "post.unreposted"
+
+	// NotificationCreated is published when a notification is created.
+	// ID is the ID of the notification and UserID the ID of its recipient
+	NotificationCreated = "notification.created"
+)
+
+// Event is a notification that an entity changed. Events only carry
+// IDs so that they fit within a Postgres NOTIFY payload; subscribers
+// load the entities they need. EventID is the ID of the outbox event
+// the event was relayed from, and zero for events published directly.
+// Events may be relayed more than once, so sinks with side effects
+// should ignore events whose EventID they have already handled
+type Event struct {
+	Type    string `json:"type"`
+	ID      int64  `json:"id"`
+	UserID  int64  `json:"userId"`
+	EventID int64  `json:"eventId,omitempty"`
+}
+
+// Publisher publishes events to subscribers
+type Publisher interface {
+	Publish(e Event) error
+}
+
+type discard struct{}
+
+func (discard) Publish(e Event) error {
+	return nil
+}
+
+// Discard is a Publisher that drops every event
+var Discard Publisher = discard{}
+
+type multi []Publisher
+
+func (m multi) Publish(e Event) error {
+	var first error
+	for _, pub := range m {
+		if err := pub.Publish(e); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// Multi returns a Publisher that publishes every event to each of
+// the given publishers in order. Every publisher is published to even
+// if another fails, and the first error is returned
+func Multi(publishers ...Publisher) Publisher {
+	return multi(publishers)
+}
+
+// subscriptionBuffer is the number of events buffered
+// for each subscription
+const subscriptionBuffer = 64
+
+// Bus is an in-process Publisher that delivers every
+// event to every open Subscription
+type Bus struct {
+	mu   sync.RWMutex
+	subs map[*Subscription]struct{}
+}
+
+// NewBus returns a newly constructed Bus
+func NewBus() *Bus {
+	return &Bus{subs: make(map[*Subscription]struct{})}
+}
+
+// Publish delivers the event to every subscription without
+// blocking. A subscription too slow to keep up with its buffer is
+// closed rather than being allowed to miss events silently
+func (b *Bus) Publish(e Event) error {
+	var slow []*Subscription
+	b.mu.RLock()
+	for sub := range b.subs {
+		select {
+		case sub.c <- e:
+		default:
+			slow = append(slow, sub)
+		}
+	}
+	b.mu.RUnlock()
+	for _, sub := range slow {
+		sub.Close()
+	}
+	return nil
+}
+
+// Subscribe returns a new subscription to every event
+// published on the bus. The subscription must be closed
+func (b *Bus) Subscribe() *Subscription {
+	sub := &Subscription{
+		bus: b,
+		c:   make(chan Event, subscriptionBuffer),
+	}
+	b.mu.Lock()
+	b.subs[sub] = struct{}{}
+	b.mu.Unlock()
+	return sub
+}
+
+// Subscribers returns the number of open subscriptions
+func (b *Bus) Subscribers() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.subs)
+}
+
+// Subscription receives the events published on a Bus
+type Subscription struct {
+	bus  *Bus
+	c    chan Event
+	once sync.Once
+}
+
+// Events returns the channel events are received on. The channel
+// is closed when the subscription is closed, including when the
+// bus closes it for falling behind
+func (s *Subscription) Events() <-chan Event {
+	return s.c
+}
+
+// Close unsubscribes from the bus. Close is idempotent
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		s.bus.mu.Lock()
+		delete(s.bus.subs, s)
+		close(s.c)
+		s.bus.mu.Unlock()
+	})
+}
@@ -0,0 +1,79 @@
+package realtime
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBus(t *testing.T) {
+	bus := NewBus()
+	a := bus.Subscribe()
+	b := bus.Subscribe()
+	defer b.Close()
+
+	e := Event{Type: PostCreated, ID: 1, UserID: 2}
+	bus.Publish(e)
+	for _, sub := range []*Subscription{a, b} {
+		if received := <-sub.Events(); received != e {
+			t.Errorf("Expected %v, received %v", e, received)
+			t.Fail()
+		}
+	}
+
+	a.Close()
+	a.Close()
+	if _, ok := <-a.Events(); ok {
+		t.Error("Expected closed subscription channel")
+		t.Fail()
+	}
+	if bus.Subscribers() != 1 {
+		t.Errorf("Expected 1 subscriber, got %d", bus.Subscribers())
+		t.Fail()
+	}
+}
+
+func TestBusClosesSlowSubscriptions(t *testing.T) {
+	bus := NewBus()
+	sub := bus.Subscribe()
+	for i := 0; i <= subscriptionBuffer; i++ {
+		bus.Publish(Event{Type: PostCreated, ID: int64(i)})
+	}
+	n := 0
+	for range sub.Events() {
+		n++
+	}
+	if n != subscriptionBuffer {
+		t.Errorf("Expected %d buffered events before closing, got %d", subscriptionBuffer, n)
+		t.Fail()
+	}
+	if bus.Subscribers() != 0 {
+		t.Error("Expected slow subscription to be removed")
+		t.Fail()
+	}
+}
+
+type failingPublisher struct {
+	err error
+}
+
+func (p failingPublisher) Publish(e Event) error {
+	return p.err
+}
+
+func TestMulti(t *testing.T) {
+	bus := NewBus()
+	sub := bus.Subscribe()
+	defer sub.Close()
+
+	err := errors.New("unavailable")
+	pub := Multi(failingPublisher{err}, bus, failingPublisher{errors.New("second")})
+	e := Event{Type: PostCreated, ID: 1, UserID: 2}
+	if published := pub.Publish(e); published != err {
+		t.Errorf("Expected the first error, got %v", published)
+		t.Fail()
+	}
+	if received := <-sub.Events(); received != e {
+		t.Errorf("Expected %v, received %v", e, received)
+		t.Fail()
+	}
+}
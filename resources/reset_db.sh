@@ -6,5 +6,7 @@
 echo "DROP DATABASE meirldb; \q\n" | psql -U postgres
 
 psql -U postgres -f ./sql/create_database.sql
-psql -U postgres -d meirldb -f ./sql/create_schema.sql
+for migration in ./sql/migrations/*.sql; do
+    psql -U postgres -d meirldb -f "$migration"
+done
 psql -U postgres -d meirldb -f ./sql/seed.sql
\ No newline at end of file
@@ -2,6 +2,14 @@
 
 CREATE SCHEMA IF NOT EXISTS public;
 
+-- Schema migrations table
+
+CREATE TABLE IF NOT EXISTS public.schema_migrations (
+    version     integer PRIMARY KEY,
+    applied_at  timestamp with time zone NOT NULL DEFAULT now()
+);
+GRANT SELECT ON public.schema_migrations TO api;
+
 -- Users table
 
 CREATE TABLE IF NOT EXISTS public.users (
@@ -110,4 +118,6 @@ GRANT SELECT, USAGE ON post_nos_id_seq TO api;
 
 --     RETURN ROWS;
 -- END
--- $func$ LANGUAGE plpgsql;
\ No newline at end of file
+-- $func$ LANGUAGE plpgsql;
+
+INSERT INTO schema_migrations (version) VALUES (1);
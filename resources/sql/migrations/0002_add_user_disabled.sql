@@ -0,0 +1,5 @@
+-- Disabled users may not log in
+
+ALTER TABLE public.users ADD COLUMN IF NOT EXISTS disabled boolean NOT NULL DEFAULT false;
+
+INSERT INTO schema_migrations (version) VALUES (2);
@@ -0,0 +1,11 @@
+-- Users react to a post at most once with each reaction
+
+CREATE UNIQUE INDEX IF NOT EXISTS post_keks_post_id_author_id_key ON public.post_keks (post_id, author_id);
+CREATE UNIQUE INDEX IF NOT EXISTS post_nos_post_id_author_id_key ON public.post_nos (post_id, author_id);
+
+-- 0001 granted privileges on posts in place of the reaction tables
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON public.post_keks TO api;
+GRANT SELECT, INSERT, UPDATE, DELETE ON public.post_nos TO api;
+
+INSERT INTO schema_migrations (version) VALUES (3);
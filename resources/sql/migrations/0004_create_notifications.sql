@@ -0,0 +1,57 @@
+-- Notifications table. Unread notifications of the same type
+-- about the same post are grouped into a single notification
+
+CREATE TABLE IF NOT EXISTS public.notifications (
+    id          serial PRIMARY KEY,
+    created_at  timestamp with time zone NOT NULL DEFAULT now(),
+    updated_at  timestamp with time zone NOT NULL DEFAULT now(),
+    user_id     integer NOT NULL,
+    type        text NOT NULL CHECK (type <> ''),
+    post_id     integer,
+    read        boolean NOT NULL DEFAULT false,
+    FOREIGN KEY (user_id) REFERENCES users (id)
+        ON UPDATE CASCADE
+        ON DELETE CASCADE,
+    FOREIGN KEY (post_id) REFERENCES posts (id)
+        ON UPDATE CASCADE
+        ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS notifications_unread_group_key
+    ON public.notifications (user_id, type, COALESCE(post_id, 0)) WHERE NOT read;
+CREATE INDEX IF NOT EXISTS notifications_user_id_updated_at_idx
+    ON public.notifications (user_id, updated_at);
+GRANT SELECT, INSERT, UPDATE, DELETE ON public.notifications TO api;
+GRANT SELECT, USAGE ON notifications_id_seq TO api;
+
+-- Notification actors table, the users whose activity
+-- a notification is about
+
+CREATE TABLE IF NOT EXISTS public.notification_actors (
+    notification_id integer NOT NULL,
+    actor_id        integer NOT NULL,
+    created_at      timestamp with time zone NOT NULL DEFAULT now(),
+    PRIMARY KEY (notification_id, actor_id),
+    FOREIGN KEY (notification_id) REFERENCES notifications (id)
+        ON UPDATE CASCADE
+        ON DELETE CASCADE,
+    FOREIGN KEY (actor_id) REFERENCES users (id)
+        ON UPDATE CASCADE
+        ON DELETE CASCADE
+);
+GRANT SELECT, INSERT, UPDATE, DELETE ON public.notification_actors TO api;
+
+-- Notification preferences table. Notification types
+-- without a preference are enabled
+
+CREATE TABLE IF NOT EXISTS public.notification_preferences (
+    user_id     integer NOT NULL,
+    type        text NOT NULL CHECK (type <> ''),
+    enabled     boolean NOT NULL,
+    PRIMARY KEY (user_id, type),
+    FOREIGN KEY (user_id) REFERENCES users (id)
+        ON UPDATE CASCADE
+        ON DELETE CASCADE
+);
+GRANT SELECT, INSERT, UPDATE, DELETE ON public.notification_preferences TO api;
+
+INSERT INTO schema_migrations (version) VALUES (4);
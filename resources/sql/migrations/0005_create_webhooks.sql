@@ -0,0 +1,45 @@
+-- Webhooks table. Webhooks without a user are registered
+-- by admins and receive every event
+
+CREATE TABLE IF NOT EXISTS public.webhooks (
+    id          serial PRIMARY KEY,
+    created_at  timestamp with time zone NOT NULL DEFAULT now(),
+    updated_at  timestamp with time zone NOT NULL DEFAULT now(),
+    user_id     integer,
+    url         text NOT NULL CHECK (url <> ''),
+    secret      text NOT NULL CHECK (secret <> ''),
+    events      text[] NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users (id)
+        ON UPDATE CASCADE
+        ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS webhooks_user_id_idx ON public.webhooks (user_id);
+GRANT SELECT, INSERT, UPDATE, DELETE ON public.webhooks TO api;
+GRANT SELECT, USAGE ON webhooks_id_seq TO api;
+
+-- Webhook deliveries table, the delivery log of every webhook
+
+CREATE TABLE IF NOT EXISTS public.webhook_deliveries (
+    id              serial PRIMARY KEY,
+    created_at      timestamp with time zone NOT NULL DEFAULT now(),
+    updated_at      timestamp with time zone NOT NULL DEFAULT now(),
+    webhook_id      integer NOT NULL,
+    event           text NOT NULL CHECK (event <> ''),
+    payload         jsonb NOT NULL,
+    status          text NOT NULL DEFAULT 'pending',
+    attempts        integer NOT NULL DEFAULT 0,
+    response_status integer NOT NULL DEFAULT 0,
+    last_error      text NOT NULL DEFAULT '',
+    next_attempt_at timestamp with time zone NOT NULL DEFAULT now(),
+    FOREIGN KEY (webhook_id) REFERENCES webhooks (id)
+        ON UPDATE CASCADE
+        ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS webhook_deliveries_pending_idx
+    ON public.webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS webhook_deliveries_webhook_id_idx
+    ON public.webhook_deliveries (webhook_id, id);
+GRANT SELECT, INSERT, UPDATE, DELETE ON public.webhook_deliveries TO api;
+GRANT SELECT, USAGE ON webhook_deliveries_id_seq TO api;
+
+INSERT INTO schema_migrations (version) VALUES (5);
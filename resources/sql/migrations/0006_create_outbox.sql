@@ -0,0 +1,30 @@
+-- Outbox table. Domain events are recorded in the same transaction
+-- as the change they describe and relayed to each sink in order.
+-- IDs are assigned before transactions commit, so events are relayed
+-- in the order of the transaction that recorded them once every
+-- earlier transaction has finished
+
+CREATE TABLE IF NOT EXISTS public.outbox (
+    id          bigserial PRIMARY KEY,
+    created_at  timestamp with time zone NOT NULL DEFAULT now(),
+    tx_id       bigint NOT NULL DEFAULT txid_current(),
+    type        text NOT NULL CHECK (type <> ''),
+    entity_id   bigint NOT NULL,
+    user_id     bigint NOT NULL
+);
+CREATE INDEX IF NOT EXISTS outbox_tx_id_id_idx ON public.outbox (tx_id, id);
+CREATE INDEX IF NOT EXISTS outbox_created_at_idx ON public.outbox (created_at);
+GRANT SELECT, INSERT, DELETE ON public.outbox TO api;
+GRANT SELECT, USAGE ON outbox_id_seq TO api;
+
+-- Outbox cursors table, the last event relayed to each sink
+
+CREATE TABLE IF NOT EXISTS public.outbox_cursors (
+    sink        text PRIMARY KEY,
+    tx_id       bigint NOT NULL,
+    event_id    bigint NOT NULL,
+    updated_at  timestamp with time zone NOT NULL DEFAULT now()
+);
+GRANT SELECT, INSERT, UPDATE, DELETE ON public.outbox_cursors TO api;
+
+INSERT INTO schema_migrations (version) VALUES (6);
@@ -0,0 +1,51 @@
+-- Timelines table, the materialized feed of every user. Posts are
+-- fanned out to the timelines of the followers of their author when
+-- created, except for authors with too many followers, whose posts
+-- are read from the posts table instead
+
+CREATE TABLE IF NOT EXISTS public.timelines (
+    user_id     integer NOT NULL,
+    post_id     integer NOT NULL,
+    author_id   integer NOT NULL,
+    created_at  timestamp with time zone NOT NULL,
+    PRIMARY KEY (user_id, post_id),
+    FOREIGN KEY (user_id) REFERENCES users (id)
+        ON UPDATE CASCADE
+        ON DELETE CASCADE,
+    FOREIGN KEY (post_id) REFERENCES posts (id)
+        ON UPDATE CASCADE
+        ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS timelines_user_id_author_id_idx
+    ON public.timelines (user_id, author_id);
+GRANT SELECT, INSERT, DELETE ON public.timelines TO api;
+
+-- Timeline pull authors table, the authors whose posts are
+-- not fanned out and are read when feeds are retrieved
+
+CREATE TABLE IF NOT EXISTS public.timeline_pull_authors (
+    author_id   integer PRIMARY KEY,
+    created_at  timestamp with time zone NOT NULL DEFAULT now(),
+    FOREIGN KEY (author_id) REFERENCES users (id)
+        ON UPDATE CASCADE
+        ON DELETE CASCADE
+);
+GRANT SELECT, INSERT, DELETE ON public.timeline_pull_authors TO api;
+
+CREATE INDEX IF NOT EXISTS followers_followee_id_idx ON public.followers (followee_id);
+CREATE INDEX IF NOT EXISTS posts_author_id_created_at_idx ON public.posts (author_id, created_at);
+
+-- Backfill the timelines of existing follows
+
+INSERT INTO timelines (user_id, post_id, author_id, created_at)
+    SELECT followers.follower_id, posts.id, posts.author_id, posts.created_at
+    FROM posts INNER JOIN followers ON followers.followee_id=posts.author_id
+    ON CONFLICT DO NOTHING;
+
+-- Replay the retained outbox to the timelines sink. Fanning out is
+-- idempotent, so events already reflected by the backfill are harmless
+
+INSERT INTO outbox_cursors (sink, tx_id, event_id) VALUES ('timelines', 0, 0)
+    ON CONFLICT DO NOTHING;
+
+INSERT INTO schema_migrations (version) VALUES (7);
@@ -0,0 +1,9 @@
+-- Reaction times, so that posts can be scored as of a point in time.
+-- Existing reactions are recorded as of this migration
+
+ALTER TABLE public.post_keks
+    ADD COLUMN IF NOT EXISTS created_at timestamp with time zone NOT NULL DEFAULT now();
+ALTER TABLE public.post_nos
+    ADD COLUMN IF NOT EXISTS created_at timestamp with time zone NOT NULL DEFAULT now();
+
+INSERT INTO schema_migrations (version) VALUES (8);
@@ -0,0 +1,23 @@
+-- Trending posts table, the posts with the most keks per hour
+-- over each trending window as of the last aggregation
+
+CREATE TABLE IF NOT EXISTS public.trending_posts (
+    span        text NOT NULL CHECK (span <> ''),
+    post_id     integer NOT NULL,
+    rank        integer NOT NULL,
+    velocity    double precision NOT NULL,
+    created_at  timestamp with time zone NOT NULL DEFAULT now(),
+    PRIMARY KEY (span, post_id),
+    FOREIGN KEY (post_id) REFERENCES posts (id)
+        ON UPDATE CASCADE
+        ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS trending_posts_span_rank_idx ON public.trending_posts (span, rank);
+GRANT SELECT, INSERT, DELETE ON public.trending_posts TO api;
+
+-- Recent keks are aggregated by time and posts explored by time
+
+CREATE INDEX IF NOT EXISTS post_keks_created_at_idx ON public.post_keks (created_at);
+CREATE INDEX IF NOT EXISTS posts_created_at_idx ON public.posts (created_at);
+
+INSERT INTO schema_migrations (version) VALUES (9);
@@ -0,0 +1,9 @@
+-- Post search documents, generated from the contents of posts so
+-- that every insert, including bulk loads, maintains them, and
+-- matched through a GIN index
+
+ALTER TABLE public.posts ADD COLUMN IF NOT EXISTS search tsvector
+    GENERATED ALWAYS AS (to_tsvector('english', contents)) STORED;
+CREATE INDEX IF NOT EXISTS posts_search_idx ON public.posts USING GIN (search);
+
+INSERT INTO schema_migrations (version) VALUES (10);
@@ -0,0 +1,11 @@
+-- User search indexes. Usernames are matched by case insensitive
+-- prefix and actual names by trigram similarity
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS users_lower_username_idx
+    ON public.users (lower(username) text_pattern_ops);
+CREATE INDEX IF NOT EXISTS users_actual_name_trgm_idx
+    ON public.users USING GIN (actual_name gin_trgm_ops);
+
+INSERT INTO schema_migrations (version) VALUES (11);
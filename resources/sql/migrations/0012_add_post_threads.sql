@@ -0,0 +1,32 @@
+-- Reply threads. Replies reference the post they reply to, which
+-- may since have been deleted, and every post belongs to the
+-- conversation of the post that started its thread
+
+ALTER TABLE public.posts ADD COLUMN IF NOT EXISTS parent_id integer
+    REFERENCES posts (id) ON UPDATE CASCADE ON DELETE SET NULL;
+ALTER TABLE public.posts ADD COLUMN IF NOT EXISTS conversation_id integer;
+UPDATE posts SET conversation_id=id WHERE conversation_id IS NULL;
+ALTER TABLE public.posts ALTER COLUMN conversation_id SET NOT NULL;
+
+-- Posts inserted without a conversation, such as bulk loaded ones,
+-- join the conversation of their parent or start their own
+CREATE OR REPLACE FUNCTION posts_conversation_id() RETURNS trigger AS
+$func$
+BEGIN
+    IF NEW.conversation_id IS NULL THEN
+        NEW.conversation_id := COALESCE(
+            (SELECT parent.conversation_id FROM posts AS parent WHERE parent.id=NEW.parent_id),
+            NEW.id);
+    END IF;
+    RETURN NEW;
+END
+$func$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS posts_conversation_id ON public.posts;
+CREATE TRIGGER posts_conversation_id BEFORE INSERT ON public.posts
+    FOR EACH ROW EXECUTE PROCEDURE posts_conversation_id();
+
+CREATE INDEX IF NOT EXISTS posts_parent_id_idx ON public.posts (parent_id, id);
+CREATE INDEX IF NOT EXISTS posts_conversation_id_idx ON public.posts (conversation_id);
+
+INSERT INTO schema_migrations (version) VALUES (12);
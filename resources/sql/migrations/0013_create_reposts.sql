@@ -0,0 +1,26 @@
+-- Reposts table. Reposts put a post in the feeds of the followers
+-- of the reposting user, and are read when feeds are retrieved
+
+CREATE TABLE IF NOT EXISTS public.reposts (
+    post_id     integer NOT NULL,
+    user_id     integer NOT NULL,
+    created_at  timestamp with time zone NOT NULL DEFAULT now(),
+    PRIMARY KEY (post_id, user_id),
+    FOREIGN KEY (post_id) REFERENCES posts (id)
+        ON UPDATE CASCADE
+        ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users (id)
+        ON UPDATE CASCADE
+        ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS reposts_user_id_idx ON public.reposts (user_id);
+GRANT SELECT, INSERT, DELETE ON public.reposts TO api;
+
+-- Quote posts reference the post they quote, which
+-- may since have been deleted
+
+ALTER TABLE public.posts ADD COLUMN IF NOT EXISTS quote_id integer
+    REFERENCES posts (id) ON UPDATE CASCADE ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS posts_quote_id_idx ON public.posts (quote_id);
+
+INSERT INTO schema_migrations (version) VALUES (13);
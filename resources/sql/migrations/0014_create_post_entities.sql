@@ -0,0 +1,36 @@
+-- Mentions and hashtags parsed from the contents of posts, replaced
+-- whenever the contents change. Offsets are in bytes, from the leading
+-- @ or # up to the end of the entity
+
+CREATE TABLE IF NOT EXISTS public.post_mentions (
+    post_id       integer NOT NULL,
+    start_offset  integer NOT NULL,
+    end_offset    integer NOT NULL,
+    user_id       integer NOT NULL,
+    PRIMARY KEY (post_id, start_offset),
+    FOREIGN KEY (post_id) REFERENCES posts (id)
+        ON UPDATE CASCADE
+        ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users (id)
+        ON UPDATE CASCADE
+        ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS post_mentions_user_id_idx ON public.post_mentions (user_id);
+GRANT SELECT, INSERT, DELETE ON public.post_mentions TO api;
+
+-- Tags are stored lowercase
+
+CREATE TABLE IF NOT EXISTS public.post_hashtags (
+    post_id       integer NOT NULL,
+    start_offset  integer NOT NULL,
+    end_offset    integer NOT NULL,
+    tag           text NOT NULL,
+    PRIMARY KEY (post_id, start_offset),
+    FOREIGN KEY (post_id) REFERENCES posts (id)
+        ON UPDATE CASCADE
+        ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS post_hashtags_tag_idx ON public.post_hashtags (tag);
+GRANT SELECT, INSERT, DELETE ON public.post_hashtags TO api;
+
+INSERT INTO schema_migrations (version) VALUES (14);
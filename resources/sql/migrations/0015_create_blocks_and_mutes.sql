@@ -0,0 +1,35 @@
+-- Blocks hide the posts of each user from the other and
+-- prevent them from following each other
+
+CREATE TABLE IF NOT EXISTS public.blocks (
+    blocker_id  integer NOT NULL,
+    blocked_id  integer NOT NULL,
+    created_at  timestamp with time zone NOT NULL DEFAULT now(),
+    PRIMARY KEY (blocker_id, blocked_id),
+    FOREIGN KEY (blocker_id) REFERENCES users (id)
+        ON UPDATE CASCADE
+        ON DELETE CASCADE,
+    FOREIGN KEY (blocked_id) REFERENCES users (id)
+        ON UPDATE CASCADE
+        ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS blocks_blocked_id_idx ON public.blocks (blocked_id);
+GRANT SELECT, INSERT, DELETE ON public.blocks TO api;
+
+-- Mutes hide the posts of the muted user from the feed of the muter
+
+CREATE TABLE IF NOT EXISTS public.mutes (
+    muter_id    integer NOT NULL,
+    muted_id    integer NOT NULL,
+    created_at  timestamp with time zone NOT NULL DEFAULT now(),
+    PRIMARY KEY (muter_id, muted_id),
+    FOREIGN KEY (muter_id) REFERENCES users (id)
+        ON UPDATE CASCADE
+        ON DELETE CASCADE,
+    FOREIGN KEY (muted_id) REFERENCES users (id)
+        ON UPDATE CASCADE
+        ON DELETE CASCADE
+);
+GRANT SELECT, INSERT, DELETE ON public.mutes TO api;
+
+INSERT INTO schema_migrations (version) VALUES (15);
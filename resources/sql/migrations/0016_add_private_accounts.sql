@@ -0,0 +1,21 @@
+-- The posts of private users are only visible to their followers,
+-- who must request to follow them
+
+ALTER TABLE public.users ADD COLUMN IF NOT EXISTS private boolean NOT NULL DEFAULT false;
+
+CREATE TABLE IF NOT EXISTS public.follow_requests (
+    requester_id  integer NOT NULL,
+    target_id     integer NOT NULL,
+    created_at    timestamp with time zone NOT NULL DEFAULT now(),
+    PRIMARY KEY (requester_id, target_id),
+    FOREIGN KEY (requester_id) REFERENCES users (id)
+        ON UPDATE CASCADE
+        ON DELETE CASCADE,
+    FOREIGN KEY (target_id) REFERENCES users (id)
+        ON UPDATE CASCADE
+        ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS follow_requests_target_id_idx ON public.follow_requests (target_id);
+GRANT SELECT, INSERT, DELETE ON public.follow_requests TO api;
+
+INSERT INTO schema_migrations (version) VALUES (16);
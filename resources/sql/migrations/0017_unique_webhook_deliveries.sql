@@ -0,0 +1,8 @@
+-- Outbox events are relayed at least once, so deliveries record the
+-- event delivered and each event is delivered to a webhook only once
+
+ALTER TABLE public.webhook_deliveries ADD COLUMN IF NOT EXISTS event_id bigint;
+CREATE UNIQUE INDEX IF NOT EXISTS webhook_deliveries_webhook_id_event_id_idx
+    ON public.webhook_deliveries (webhook_id, event_id);
+
+INSERT INTO schema_migrations (version) VALUES (17);
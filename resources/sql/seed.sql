@@ -0,0 +1,1146 @@
+-- Generated by meirlctl seed, do not edit
+
+COPY users (id, created_at, updated_at, username, email, password, actual_name, dob) FROM stdin;
+1	2015-12-31 02:26:23.712886Z	2015-12-31 02:26:23.712886Z	amiller	amiller@example.com	$2a$10$7F6PXU8WI4zdhoXQGEStSe0d7Fe.5jWlrk1b3G/L1QKFuCm/hycCe	Alex Miller	2004-01-27 00:00:00Z
+2	2015-12-09 01:25:59.51972Z	2015-12-09 01:25:59.51972Z	lperez	lperez@example.com	$2a$10$7F6PXU8WI4zdhoXQGEStSe0d7Fe.5jWlrk1b3G/L1QKFuCm/hycCe	Layla Perez	1999-03-16 00:00:00Z
+3	2015-12-08 15:45:28.788554Z	2015-12-08 15:45:28.788554Z	henry_novak	henry_novak@example.com	$2a$10$7F6PXU8WI4zdhoXQGEStSe0d7Fe.5jWlrk1b3G/L1QKFuCm/hycCe	Henry Novak	1987-03-18 00:00:00Z
+4	2015-12-11 00:38:06.146646Z	2015-12-11 00:38:06.146646Z	agupta	agupta@example.com	$2a$10$7F6PXU8WI4zdhoXQGEStSe0d7Fe.5jWlrk1b3G/L1QKFuCm/hycCe	Abigail Gupta	1956-09-26 00:00:00Z
+5	2015-12-31 11:17:53.178133Z	2015-12-31 11:17:53.178133Z	eevans	eevans@example.com	$2a$10$7F6PXU8WI4zdhoXQGEStSe0d7Fe.5jWlrk1b3G/L1QKFuCm/hycCe	Eli Evans	1951-07-02 00:00:00Z
+6	2015-12-25 20:12:09.111701Z	2015-12-25 20:12:09.111701Z	zoet	zoet@example.com	$2a$10$7F6PXU8WI4zdhoXQGEStSe0d7Fe.5jWlrk1b3G/L1QKFuCm/hycCe	Zoe Taylor	1950-01-24 00:00:00Z
+7	2015-12-23 00:22:03.424358Z	2015-12-23 00:22:03.424358Z	amoore	amoore@example.com	$2a$10$7F6PXU8WI4zdhoXQGEStSe0d7Fe.5jWlrk1b3G/L1QKFuCm/hycCe	Anna Moore	1975-12-21 00:00:00Z
+8	2015-12-03 20:28:55.526612Z	2015-12-03 20:28:55.526612Z	isabellac	isabellac@example.com	$2a$10$7F6PXU8WI4zdhoXQGEStSe0d7Fe.5jWlrk1b3G/L1QKFuCm/hycCe	Isabella Campbell	1970-11-13 00:00:00Z
+9	2015-12-09 15:50:16.688865Z	2015-12-09 15:50:16.688865Z	ryanc	ryanc@example.com	$2a$10$7F6PXU8WI4zdhoXQGEStSe0d7Fe.5jWlrk1b3G/L1QKFuCm/hycCe	Ryan Cohen	1952-05-05 00:00:00Z
+10	2015-12-18 13:04:08.557038Z	2015-12-18 13:04:08.557038Z	zoec	zoec@example.com	$2a$10$7F6PXU8WI4zdhoXQGEStSe0d7Fe.5jWlrk1b3G/L1QKFuCm/hycCe	Zoe Chen	1986-11-07 00:00:00Z
+11	2015-12-24 04:17:51.949735Z	2015-12-24 04:17:51.949735Z	priya_gupta	priya_gupta@example.com	$2a$10$7F6PXU8WI4zdhoXQGEStSe0d7Fe.5jWlrk1b3G/L1QKFuCm/hycCe	Priya Gupta	1991-04-22 00:00:00Z
+12	2015-12-16 08:36:38.293656Z	2015-12-16 08:36:38.293656Z	jack_thomas	jack_thomas@example.com	$2a$10$7F6PXU8WI4zdhoXQGEStSe0d7Fe.5jWlrk1b3G/L1QKFuCm/hycCe	Jack Thomas	1982-10-30 00:00:00Z
+13	2015-12-04 09:37:20.735024Z	2015-12-04 09:37:20.735024Z	adam_adams	adam_adams@example.com	$2a$10$7F6PXU8WI4zdhoXQGEStSe0d7Fe.5jWlrk1b3G/L1QKFuCm/hycCe	Adam Adams	1985-02-02 00:00:00Z
+14	2015-12-14 00:07:19.016668Z	2015-12-14 00:07:19.016668Z	jgupta	jgupta@example.com	$2a$10$7F6PXU8WI4zdhoXQGEStSe0d7Fe.5jWlrk1b3G/L1QKFuCm/hycCe	Javier Gupta	1953-07-13 00:00:00Z
+15	2015-12-27 05:19:01.758665Z	2015-12-27 05:19:01.758665Z	asuzuki	asuzuki@example.com	$2a$10$7F6PXU8WI4zdhoXQGEStSe0d7Fe.5jWlrk1b3G/L1QKFuCm/hycCe	Amelia Suzuki	1962-02-07 00:00:00Z
+16	2015-12-19 23:34:36.021766Z	2015-12-19 23:34:36.021766Z	emmac	emmac@example.com	$2a$10$7F6PXU8WI4zdhoXQGEStSe0d7Fe.5jWlrk1b3G/L1QKFuCm/hycCe	Emma Campbell	1974-02-19 00:00:00Z
+17	2015-12-15 17:36:50.046937Z	2015-12-15 17:36:50.046937Z	zoep	zoep@example.com	$2a$10$7F6PXU8WI4zdhoXQGEStSe0d7Fe.5jWlrk1b3G/L1QKFuCm/hycCe	Zoe Park	1957-06-04 00:00:00Z
+18	2015-12-13 12:05:22.627187Z	2015-12-13 12:05:22.627187Z	rsilva	rsilva@example.com	$2a$10$7F6PXU8WI4zdhoXQGEStSe0d7Fe.5jWlrk1b3G/L1QKFuCm/hycCe	Ravi Silva	1950-09-11 00:00:00Z
+19	2015-12-23 02:32:24.327453Z	2015-12-23 02:32:24.327453Z	samuel_wong	samuel_wong@example.com	$2a$10$7F6PXU8WI4zdhoXQGEStSe0d7Fe.5jWlrk1b3G/L1QKFuCm/hycCe	Samuel Wong	1995-04-02 00:00:00Z
+20	2015-12-29 05:15:51.281549Z	2015-12-29 05:15:51.281549Z	olivia_wang	olivia_wang@example.com	$2a$10$7F6PXU8WI4zdhoXQGEStSe0d7Fe.5jWlrk1b3G/L1QKFuCm/hycCe	Olivia Wang	1993-01-03 00:00:00Z
+21	2015-12-10 12:07:55.22543Z	2015-12-10 12:07:55.22543Z	alex_kowalski	alex_kowalski@example.com	$2a$10$7F6PXU8WI4zdhoXQGEStSe0d7Fe.5jWlrk1b3G/L1QKFuCm/hycCe	Alex Kowalski	1996-02-08 00:00:00Z
+22	2015-12-13 14:06:05.353278Z	2015-12-13 14:06:05.353278Z	jonaha	jonaha@example.com	$2a$10$7F6PXU8WI4zdhoXQGEStSe0d7Fe.5jWlrk1b3G/L1QKFuCm/hycCe	Jonah Adams	1986-03-10 00:00:00Z
+23	2015-12-28 11:59:33.528145Z	2015-12-28 11:59:33.528145Z	yusufw	yusufw@example.com	$2a$10$7F6PXU8WI4zdhoXQGEStSe0d7Fe.5jWlrk1b3G/L1QKFuCm/hycCe	Yusuf White	1978-10-16 00:00:00Z
+24	2015-12-22 11:54:57.215769Z	2015-12-22 11:54:57.215769Z	charlottej	charlottej@example.com	$2a$10$7F6PXU8WI4zdhoXQGEStSe0d7Fe.5jWlrk1b3G/L1QKFuCm/hycCe	Charlotte Jackson	1981-09-21 00:00:00Z
+25	2015-12-16 18:05:10.595877Z	2015-12-16 18:05:10.595877Z	henry_wang	henry_wang@example.com	$2a$10$7F6PXU8WI4zdhoXQGEStSe0d7Fe.5jWlrk1b3G/L1QKFuCm/hycCe	Henry Wang	1963-05-10 00:00:00Z
+26	2015-12-03 18:14:31.643876Z	2015-12-03 18:14:31.643876Z	gabrield	gabrield@example.com	$2a$10$7F6PXU8WI4zdhoXQGEStSe0d7Fe.5jWlrk1b3G/L1QKFuCm/hycCe	Gabriel Davis	1981-12-13 00:00:00Z
+27	2015-12-16 13:10:18.562448Z	2015-12-16 13:10:18.562448Z	omarh	omarh@example.com	$2a$10$7F6PXU8WI4zdhoXQGEStSe0d7Fe.5jWlrk1b3G/L1QKFuCm/hycCe	Omar Hill	1958-09-24 00:00:00Z
+28	2015-12-21 17:23:54.121454Z	2015-12-21 17:23:54.121454Z	echen	echen@example.com	$2a$10$7F6PXU8WI4zdhoXQGEStSe0d7Fe.5jWlrk1b3G/L1QKFuCm/hycCe	Emily Chen	2003-12-23 00:00:00Z
+29	2015-12-12 11:52:15.730121Z	2015-12-12 11:52:15.730121Z	aaliyahc	aaliyahc@example.com	$2a$10$7F6PXU8WI4zdhoXQGEStSe0d7Fe.5jWlrk1b3G/L1QKFuCm/hycCe	Aaliyah Campbell	1968-10-18 00:00:00Z
+30	2015-12-20 07:16:36.649421Z	2015-12-20 07:16:36.649421Z	aking	aking@example.com	$2a$10$7F6PXU8WI4zdhoXQGEStSe0d7Fe.5jWlrk1b3G/L1QKFuCm/hycCe	Ava King	1961-08-27 00:00:00Z
+31	2015-12-08 09:37:16.221504Z	2015-12-08 09:37:16.221504Z	elijahw	elijahw@example.com	$2a$10$7F6PXU8WI4zdhoXQGEStSe0d7Fe.5jWlrk1b3G/L1QKFuCm/hycCe	Elijah Walker	1995-03-03 00:00:00Z
+32	2015-12-25 14:58:16.860791Z	2015-12-25 14:58:16.860791Z	olivia_kowalski	olivia_kowalski@example.com	$2a$10$7F6PXU8WI4zdhoXQGEStSe0d7Fe.5jWlrk1b3G/L1QKFuCm/hycCe	Olivia Kowalski	1952-05-03 00:00:00Z
+33	2015-12-05 15:50:08.40062Z	2015-12-05 15:50:08.40062Z	graceg	graceg@example.com	$2a$10$7F6PXU8WI4zdhoXQGEStSe0d7Fe.5jWlrk1b3G/L1QKFuCm/hycCe	Grace Gupta	1997-07-04 00:00:00Z
+34	2015-12-21 03:24:31.49345Z	2015-12-21 03:24:31.49345Z	grace_okafor	grace_okafor@example.com	$2a$10$7F6PXU8WI4zdhoXQGEStSe0d7Fe.5jWlrk1b3G/L1QKFuCm/hycCe	Grace Okafor	1974-04-14 00:00:00Z
+35	2015-12-14 22:37:16.747654Z	2015-12-14 22:37:16.747654Z	rcohen	rcohen@example.com	$2a$10$7F6PXU8WI4zdhoXQGEStSe0d7Fe.5jWlrk1b3G/L1QKFuCm/hycCe	Rafael Cohen	1982-08-06 00:00:00Z
+36	2015-12-18 23:05:58.814865Z	2015-12-18 23:05:58.814865Z	layla_taylor	layla_taylor@example.com	$2a$10$7F6PXU8WI4zdhoXQGEStSe0d7Fe.5jWlrk1b3G/L1QKFuCm/hycCe	Layla Taylor	1988-02-20 00:00:00Z
+37	2015-12-06 09:02:07.877257Z	2015-12-06 09:02:07.877257Z	jonahs	jonahs@example.com	$2a$10$7F6PXU8WI4zdhoXQGEStSe0d7Fe.5jWlrk1b3G/L1QKFuCm/hycCe	Jonah Silva	1976-07-09 00:00:00Z
+38	2015-12-04 10:00:21.791358Z	2015-12-04 10:00:21.791358Z	ravi_king	ravi_king@example.com	$2a$10$7F6PXU8WI4zdhoXQGEStSe0d7Fe.5jWlrk1b3G/L1QKFuCm/hycCe	Ravi King	1974-01-22 00:00:00Z
+39	2015-12-29 00:39:09.438523Z	2015-12-29 00:39:09.438523Z	aalvarez	aalvarez@example.com	$2a$10$7F6PXU8WI4zdhoXQGEStSe0d7Fe.5jWlrk1b3G/L1QKFuCm/hycCe	Alex Alvarez	1992-01-23 00:00:00Z
+40	2015-12-20 12:14:38.037583Z	2015-12-20 12:14:38.037583Z	sofia_hill	sofia_hill@example.com	$2a$10$7F6PXU8WI4zdhoXQGEStSe0d7Fe.5jWlrk1b3G/L1QKFuCm/hycCe	Sofia Hill	1952-04-03 00:00:00Z
+41	2015-12-22 15:04:00.908423Z	2015-12-22 15:04:00.908423Z	hokafor	hokafor@example.com	$2a$10$7F6PXU8WI4zdhoXQGEStSe0d7Fe.5jWlrk1b3G/L1QKFuCm/hycCe	Harper Okafor	1999-06-19 00:00:00Z
+42	2015-12-20 13:51:17.648053Z	2015-12-20 13:51:17.648053Z	javier_clark	javier_clark@example.com	$2a$10$7F6PXU8WI4zdhoXQGEStSe0d7Fe.5jWlrk1b3G/L1QKFuCm/hycCe	Javier Clark	2001-01-31 00:00:00Z
+43	2015-12-08 05:45:39.385686Z	2015-12-08 05:45:39.385686Z	jkowalski	jkowalski@example.com	$2a$10$7F6PXU8WI4zdhoXQGEStSe0d7Fe.5jWlrk1b3G/L1QKFuCm/hycCe	Javier Kowalski	1958-11-03 00:00:00Z
+44	2015-12-30 01:49:58.567739Z	2015-12-30 01:49:58.567739Z	ngonzalez	ngonzalez@example.com	$2a$10$7F6PXU8WI4zdhoXQGEStSe0d7Fe.5jWlrk1b3G/L1QKFuCm/hycCe	Noah Gonzalez	1952-09-02 00:00:00Z
+45	2015-12-22 19:36:00.471707Z	2015-12-22 19:36:00.471707Z	iwalker	iwalker@example.com	$2a$10$7F6PXU8WI4zdhoXQGEStSe0d7Fe.5jWlrk1b3G/L1QKFuCm/hycCe	Isaac Walker	1978-06-05 00:00:00Z
+46	2015-12-25 20:23:42.420199Z	2015-12-25 20:23:42.420199Z	charlottet	charlottet@example.com	$2a$10$7F6PXU8WI4zdhoXQGEStSe0d7Fe.5jWlrk1b3G/L1QKFuCm/hycCe	Charlotte Torres	1952-09-06 00:00:00Z
+47	2015-12-07 23:50:51.845349Z	2015-12-07 23:50:51.845349Z	elijaht	elijaht@example.com	$2a$10$7F6PXU8WI4zdhoXQGEStSe0d7Fe.5jWlrk1b3G/L1QKFuCm/hycCe	Elijah Torres	1965-03-20 00:00:00Z
+48	2015-12-23 05:06:48.139706Z	2015-12-23 05:06:48.139706Z	nking	nking@example.com	$2a$10$7F6PXU8WI4zdhoXQGEStSe0d7Fe.5jWlrk1b3G/L1QKFuCm/hycCe	Noah King	1977-02-18 00:00:00Z
+49	2015-12-19 14:07:53.372221Z	2015-12-19 14:07:53.372221Z	aaliyah_ahmed	aaliyah_ahmed@example.com	$2a$10$7F6PXU8WI4zdhoXQGEStSe0d7Fe.5jWlrk1b3G/L1QKFuCm/hycCe	Aaliyah Ahmed	1968-03-05 00:00:00Z
+50	2015-12-16 11:29:27.760803Z	2015-12-16 11:29:27.760803Z	davidt	davidt@example.com	$2a$10$7F6PXU8WI4zdhoXQGEStSe0d7Fe.5jWlrk1b3G/L1QKFuCm/hycCe	David Thompson	1951-04-14 00:00:00Z
+\.
+
+COPY followers (follower_id, followee_id, created_at) FROM stdin;
+1	2	2016-07-21 05:11:05.976802Z
+1	8	2016-04-22 21:01:09.844622Z
+1	10	2016-11-17 06:27:49.009153Z
+1	43	2016-08-11 11:34:29.23568Z
+2	5	2016-02-14 20:19:12.104828Z
+2	7	2016-12-25 12:28:01.822148Z
+2	10	2016-08-23 21:02:28.283578Z
+2	12	2016-03-17 20:31:58.825248Z
+2	13	2016-04-10 18:08:37.679293Z
+2	17	2016-05-03 08:46:51.335423Z
+2	24	2016-05-07 03:41:18.773245Z
+2	26	2016-11-04 19:54:00.284507Z
+2	30	2016-09-30 11:03:35.977042Z
+2	35	2016-06-13 06:24:53.759828Z
+3	5	2016-04-06 08:24:51.911834Z
+3	7	2016-05-14 20:51:59.269759Z
+3	10	2016-12-24 13:03:39.281803Z
+3	20	2016-04-18 11:25:50.895431Z
+3	28	2016-11-23 23:46:13.352074Z
+3	31	2016-11-18 16:53:21.586657Z
+4	7	2016-11-12 20:43:40.07317Z
+4	8	2016-09-30 15:24:17.321374Z
+4	10	2016-05-06 22:36:35.763654Z
+4	13	2016-11-16 22:25:44.050367Z
+4	18	2016-04-17 09:10:26.698357Z
+4	22	2016-11-14 19:50:40.606781Z
+4	23	2016-12-03 00:30:09.063232Z
+4	26	2016-02-25 00:30:31.836355Z
+4	28	2016-03-18 09:33:17.762776Z
+4	37	2016-11-20 09:24:34.780061Z
+4	41	2016-02-04 20:35:06.064775Z
+4	43	2016-08-23 23:51:56.035425Z
+4	45	2016-04-18 19:52:04.825921Z
+5	1	2016-06-17 01:37:12.042919Z
+5	10	2016-10-11 00:11:42.601745Z
+5	12	2016-12-31 05:01:19.423074Z
+5	23	2016-02-14 05:57:29.766814Z
+5	24	2016-08-29 05:21:26.138984Z
+5	31	2016-10-13 21:13:44.472172Z
+6	10	2016-11-28 09:41:29.477806Z
+6	23	2016-06-01 01:25:43.908344Z
+6	24	2016-09-11 18:53:04.156277Z
+7	5	2016-12-23 09:56:40.034838Z
+7	9	2016-01-10 05:36:18.860634Z
+7	10	2016-10-07 09:29:13.371801Z
+7	12	2016-06-16 00:10:45.054327Z
+7	23	2016-12-21 05:12:49.511317Z
+7	24	2016-08-01 01:16:58.395826Z
+7	36	2016-08-05 01:15:48.702385Z
+7	45	2016-08-01 20:37:41.822406Z
+7	50	2016-01-27 12:53:33.094106Z
+8	5	2016-02-22 11:24:37.296376Z
+8	10	2016-11-30 20:08:32.966687Z
+8	23	2016-02-06 05:02:31.887931Z
+8	29	2016-06-28 06:42:12.013421Z
+8	30	2016-02-20 18:01:04.774306Z
+9	7	2016-02-20 04:01:16.297839Z
+9	17	2016-06-22 04:05:41.422149Z
+10	5	2016-02-08 05:38:36.757833Z
+10	12	2016-07-21 15:36:58.789381Z
+10	20	2016-01-26 19:40:33.983192Z
+10	21	2016-09-09 09:43:09.762429Z
+10	24	2016-05-11 05:30:39.016562Z
+10	36	2016-07-22 02:49:25.601631Z
+10	38	2016-03-06 18:38:54.572741Z
+10	45	2016-07-18 06:33:42.245901Z
+11	7	2016-09-01 20:34:00.388692Z
+11	15	2016-03-18 05:02:35.814225Z
+11	35	2016-05-07 07:58:33.612625Z
+11	38	2016-08-04 19:01:29.701838Z
+12	3	2016-04-23 05:48:17.841832Z
+12	5	2016-03-10 06:56:14.411071Z
+12	10	2016-05-15 23:48:04.893518Z
+12	23	2016-06-14 23:37:14.978866Z
+12	24	2016-05-05 01:09:17.719951Z
+12	30	2016-07-03 12:06:08.316218Z
+12	31	2016-05-12 23:42:22.224776Z
+12	39	2016-01-20 12:14:10.537819Z
+12	43	2016-09-04 18:26:24.566776Z
+12	50	2016-04-10 23:35:13.662711Z
+13	10	2016-10-17 05:09:21.354317Z
+14	10	2016-10-17 18:03:37.891565Z
+14	25	2016-10-15 00:39:14.333962Z
+14	26	2016-07-08 01:37:23.058966Z
+14	29	2016-08-12 10:27:33.88774Z
+14	30	2016-10-23 00:02:20.862379Z
+14	35	2016-07-28 03:50:11.603003Z
+15	45	2016-07-29 18:13:42.205447Z
+16	5	2016-06-17 20:05:04.882803Z
+16	10	2016-01-25 20:05:53.761444Z
+16	13	2016-12-13 23:39:11.770986Z
+16	28	2016-04-14 14:29:08.05373Z
+16	31	2016-03-19 12:50:28.224208Z
+16	49	2016-04-15 19:53:01.323191Z
+16	50	2016-06-06 20:56:15.186266Z
+17	9	2016-01-08 03:11:28.393966Z
+17	10	2016-09-26 13:29:45.592578Z
+17	13	2016-12-06 02:56:02.466853Z
+17	24	2016-07-08 06:07:02.720332Z
+17	31	2016-08-06 16:24:25.938077Z
+17	44	2016-06-02 14:37:07.526121Z
+17	45	2016-04-13 02:59:56.927712Z
+17	49	2016-02-09 06:14:31.017498Z
+18	12	2016-02-22 18:56:01.859419Z
+18	15	2016-11-09 16:22:21.16324Z
+19	10	2016-12-05 13:08:37.391226Z
+19	24	2016-01-08 13:22:48.225008Z
+19	30	2016-02-20 12:55:09.391699Z
+20	4	2016-02-29 07:25:59.445812Z
+20	5	2016-07-11 22:54:08.236612Z
+20	7	2016-08-16 14:40:54.407909Z
+20	8	2016-10-04 01:28:39.120802Z
+20	10	2016-11-11 03:42:37.078947Z
+20	12	2016-05-22 12:28:20.17394Z
+20	16	2016-07-13 20:36:50.928888Z
+20	22	2016-05-05 12:00:25.391549Z
+20	26	2016-03-25 06:45:35.54195Z
+20	28	2016-03-23 01:33:22.979003Z
+20	29	2016-10-01 10:20:41.726565Z
+20	31	2016-08-23 16:33:35.33365Z
+20	35	2016-11-21 23:11:17.405403Z
+20	39	2016-10-09 20:43:14.528929Z
+20	49	2016-08-06 03:09:19.322171Z
+21	8	2016-10-27 01:24:24.49934Z
+21	10	2016-05-28 18:50:31.128134Z
+21	13	2016-01-19 00:17:44.636184Z
+21	19	2016-10-26 01:58:17.117366Z
+21	26	2016-03-16 07:21:33.212409Z
+21	30	2016-06-06 20:27:08.566066Z
+21	31	2016-01-09 14:29:58.758208Z
+22	12	2016-07-16 12:56:02.91077Z
+22	23	2016-01-31 20:49:25.117612Z
+22	36	2016-02-17 11:54:16.411459Z
+23	7	2016-07-05 01:55:32.68677Z
+24	5	2016-12-05 18:55:05.427697Z
+24	12	2016-10-25 16:59:51.479176Z
+24	23	2016-08-25 21:20:06.87073Z
+24	28	2016-01-23 00:03:46.533728Z
+24	48	2016-12-06 00:41:24.890557Z
+25	12	2016-06-26 23:42:17.664409Z
+26	5	2016-05-13 00:26:10.644473Z
+26	7	2016-03-26 21:33:37.759764Z
+26	10	2016-12-30 14:19:44.161869Z
+26	22	2016-05-18 18:46:04.364911Z
+26	25	2016-12-19 23:54:54.037539Z
+26	35	2016-02-22 21:05:42.596985Z
+26	42	2016-07-09 05:27:56.235696Z
+27	7	2016-07-27 13:53:14.455769Z
+27	48	2016-10-16 21:27:37.878064Z
+28	5	2016-08-11 20:24:28.087372Z
+28	7	2016-07-29 22:21:18.498794Z
+28	10	2016-08-12 23:00:10.886052Z
+28	12	2016-03-23 13:40:33.071312Z
+28	26	2016-12-24 06:42:24.174116Z
+28	38	2016-06-28 16:43:48.334081Z
+29	17	2016-07-05 10:59:28.797Z
+29	39	2016-01-28 02:19:45.355987Z
+30	5	2016-09-24 04:52:07.822531Z
+30	7	2016-11-22 17:36:39.456075Z
+30	10	2016-07-12 15:57:03.883975Z
+30	12	2016-02-19 18:35:35.2364Z
+30	18	2016-05-05 23:30:27.451673Z
+30	24	2016-11-06 23:50:10.591492Z
+30	45	2016-11-20 04:20:56.023182Z
+30	49	2016-03-24 19:13:25.520104Z
+31	4	2016-01-11 08:04:09.778561Z
+31	7	2016-05-06 11:41:27.395059Z
+31	9	2016-10-11 15:40:17.917573Z
+31	10	2016-05-21 09:48:01.033925Z
+31	12	2016-11-29 12:53:45.567137Z
+32	5	2016-12-08 06:15:03.816211Z
+32	9	2016-01-27 05:50:52.833928Z
+32	10	2016-06-19 15:12:40.475558Z
+32	16	2016-09-22 05:26:06.676544Z
+32	20	2016-08-18 19:45:54.28436Z
+32	24	2016-05-11 14:39:29.950723Z
+32	30	2016-06-23 15:07:06.005676Z
+32	49	2016-03-06 01:41:43.263926Z
+32	50	2016-08-06 11:27:01.633622Z
+33	3	2016-01-24 12:41:24.41267Z
+33	5	2016-03-08 19:38:36.479634Z
+33	7	2016-05-08 19:13:35.908781Z
+33	10	2016-11-13 08:20:01.285209Z
+33	12	2016-02-13 02:28:25.449641Z
+33	17	2016-01-25 09:21:36.646903Z
+33	23	2016-07-06 17:49:06.174806Z
+33	24	2016-12-25 00:15:35.659635Z
+33	26	2016-05-01 22:01:56.34847Z
+33	28	2016-06-04 20:27:12.194605Z
+33	29	2016-11-14 22:20:26.911131Z
+33	30	2016-01-31 18:36:57.901952Z
+33	31	2016-03-17 11:41:51.153315Z
+33	35	2016-05-18 06:43:38.015408Z
+33	45	2016-04-06 09:31:15.441444Z
+33	48	2016-03-15 03:21:37.26278Z
+33	50	2016-04-17 13:30:05.395901Z
+34	12	2016-06-22 17:15:17.681224Z
+35	6	2016-01-12 12:15:54.212229Z
+35	7	2016-07-17 15:51:56.952148Z
+35	10	2016-12-08 06:11:28.123938Z
+35	12	2016-03-05 11:52:55.330172Z
+35	22	2016-09-25 01:43:24.829421Z
+35	23	2016-06-22 02:40:47.082337Z
+35	26	2016-01-28 08:43:04.278059Z
+35	27	2016-01-22 06:49:47.307206Z
+35	28	2016-10-18 11:18:25.364826Z
+35	30	2016-12-15 00:42:46.995314Z
+35	42	2016-08-23 05:19:19.455756Z
+35	43	2016-05-29 22:36:01.786511Z
+36	10	2016-11-09 10:04:35.757522Z
+36	12	2016-09-20 03:40:55.443366Z
+36	30	2016-09-22 16:42:30.43057Z
+36	38	2016-09-05 11:38:06.688851Z
+37	3	2016-11-10 21:00:55.017446Z
+37	5	2016-03-07 08:17:15.396485Z
+37	10	2016-12-21 00:26:16.506952Z
+37	12	2016-05-01 09:47:10.867021Z
+37	17	2016-08-29 08:59:05.877387Z
+37	24	2016-08-27 02:07:03.650955Z
+37	32	2016-03-26 00:23:12.312018Z
+37	45	2016-04-14 16:57:53.392086Z
+37	49	2016-10-13 02:46:30.251002Z
+38	5	2016-10-13 06:23:55.249861Z
+38	10	2016-07-05 13:00:34.534151Z
+38	18	2016-03-17 11:55:14.831884Z
+38	19	2016-04-22 17:24:57.329593Z
+38	23	2016-03-16 01:24:29.586145Z
+38	24	2016-11-03 04:10:45.685511Z
+38	27	2016-01-27 12:27:13.661467Z
+38	43	2016-06-15 19:59:09.805154Z
+38	49	2016-03-21 09:01:39.455969Z
+39	7	2016-02-28 01:40:27.938641Z
+39	12	2016-09-14 22:20:23.604507Z
+39	25	2016-02-17 14:20:19.546022Z
+40	7	2016-06-24 01:09:57.614809Z
+40	9	2016-05-20 12:57:48.136429Z
+40	10	2016-08-18 07:40:03.402515Z
+40	12	2016-10-04 12:54:48.149995Z
+40	22	2016-08-03 00:25:07.141772Z
+40	24	2016-02-12 14:26:59.346422Z
+40	25	2016-11-01 10:15:17.755766Z
+40	29	2016-05-09 22:52:32.713031Z
+40	30	2016-10-18 19:48:55.586581Z
+40	45	2016-09-06 03:38:33.435251Z
+41	2	2016-10-11 13:49:41.41263Z
+41	5	2016-05-30 16:38:29.043808Z
+41	7	2016-05-09 01:01:57.254707Z
+41	9	2016-01-21 10:06:10.390831Z
+41	10	2016-05-04 23:46:52.368114Z
+41	12	2016-03-09 21:18:30.02296Z
+41	13	2016-12-02 15:49:19.708573Z
+41	17	2016-12-22 02:07:26.853487Z
+41	20	2016-03-27 23:09:33.739272Z
+41	22	2016-01-20 17:46:25.048126Z
+41	24	2016-08-31 16:39:36.624939Z
+41	26	2016-03-19 19:41:19.820097Z
+41	30	2016-08-24 05:23:10.112828Z
+41	31	2016-06-29 11:22:37.389437Z
+41	45	2016-05-06 03:28:34.434962Z
+41	47	2016-09-22 01:40:51.538548Z
+42	5	2016-09-29 04:00:38.096891Z
+42	10	2016-04-17 06:23:35.972377Z
+42	12	2016-07-03 16:49:34.630127Z
+42	21	2016-10-04 17:51:23.347579Z
+42	36	2016-10-31 05:58:37.604809Z
+42	37	2016-09-01 05:30:16.312221Z
+43	4	2016-01-25 18:54:49.057541Z
+43	5	2016-01-13 04:18:18.631553Z
+43	10	2016-11-06 03:05:28.783842Z
+43	21	2016-06-21 18:57:09.073258Z
+44	10	2016-08-30 06:51:26.881436Z
+44	28	2016-10-13 05:20:54.401488Z
+44	29	2016-01-25 23:04:22.075223Z
+45	5	2016-02-24 22:06:39.696698Z
+45	7	2016-06-01 17:35:47.983715Z
+45	9	2016-08-10 19:15:06.658571Z
+45	10	2016-10-01 20:36:56.451896Z
+45	12	2016-10-22 08:14:41.451713Z
+45	22	2016-02-28 04:29:09.201046Z
+45	26	2016-12-09 23:47:09.066205Z
+45	31	2016-11-07 21:42:04.432429Z
+45	35	2016-03-07 14:23:06.563498Z
+45	40	2016-03-11 05:18:11.47386Z
+45	42	2016-09-06 06:00:29.961096Z
+45	48	2016-05-06 15:41:25.041668Z
+46	10	2016-07-19 19:53:47.575005Z
+46	12	2016-02-19 13:57:45.975699Z
+46	13	2016-06-06 04:25:57.024472Z
+47	4	2016-01-28 05:56:26.271045Z
+47	5	2016-01-21 15:03:34.683456Z
+47	10	2016-07-23 09:53:55.377197Z
+47	12	2016-08-21 13:56:46.258354Z
+47	17	2016-07-08 17:42:53.356761Z
+47	30	2016-02-18 01:10:22.09779Z
+47	31	2016-04-08 08:45:37.74953Z
+47	35	2016-05-09 16:15:23.443337Z
+47	39	2016-05-30 03:05:05.014856Z
+47	43	2016-09-01 22:24:30.232748Z
+47	50	2016-06-10 04:01:05.194418Z
+48	5	2016-07-11 04:32:58.598317Z
+48	10	2016-10-03 22:41:54.905729Z
+48	30	2016-01-25 11:20:21.704624Z
+48	32	2016-02-19 13:30:59.030331Z
+48	49	2016-03-25 05:18:53.103237Z
+49	6	2016-10-06 15:00:29.385316Z
+49	7	2016-05-20 18:07:03.420706Z
+49	10	2016-01-12 19:18:45.465421Z
+49	12	2016-09-01 03:47:00.084832Z
+49	23	2016-07-13 19:37:37.043634Z
+49	24	2016-04-07 09:06:43.209183Z
+50	10	2016-09-05 15:08:58.160001Z
+50	24	2016-09-11 10:25:01.252691Z
+50	30	2016-11-07 06:13:10.780813Z
+50	46	2016-01-11 21:50:14.960092Z
+\.
+
+COPY posts (id, created_at, updated_at, author_id, contents) FROM stdin;
+1	2016-01-01 20:24:19.208928Z	2016-01-01 20:24:19.208928Z	30	Dog everything asked dog honestly now bus podcast commute dis wild dog everything went smh
+2	2016-01-02 10:30:49.038882Z	2016-01-02 10:30:49.038882Z	43	Third of password my password week monday time show bus third brunch went tried want the tea dis not under marathon time the smh
+3	2016-01-04 06:13:05.750095Z	2016-01-04 06:13:05.750095Z	43	Recommendations was honestly dog nobody today my weekend want...
+4	2016-01-05 01:19:09.717569Z	2016-01-05 01:19:09.717569Z	7	Finished hits was and lol
+5	2016-01-05 04:08:20.902178Z	2016-01-05 04:08:20.902178Z	2	That friends found unreal third finished i and smoke it please sock brunch over concert job?
+6	2016-01-05 05:21:25.38779Z	2016-01-05 05:21:25.38779Z	7	Dis now new commute when new because coffee now concert about finished over commute expected honestly commute gym cooking irl
+7	2016-01-06 02:05:38.562924Z	2016-01-06 02:05:38.562924Z	19	Cat because brunch tomorrow of sold cancelled asked tea tea was today?
+8	2016-01-06 06:44:12.009818Z	2016-01-06 06:44:12.009818Z	2	Smoke episodes hits just mood the and dog about with sock commute bus nobody commute me feeling lol
+9	2016-01-06 15:53:23.812374Z	2016-01-06 15:53:23.812374Z	40	The nobody bus please maybe marathon concert hits who maybe cooking time dis commute dis with here cancelled brunch nobody?
+10	2016-01-09 02:49:13.296206Z	2016-01-09 02:49:13.296206Z	35	Commute that about who a
+11	2016-01-11 03:22:08.156874Z	2016-01-11 03:22:08.156874Z	35	Smoke monday want of go cancelled who episodes it because password want because weekend as tea unreal that sigh tea.
+12	2016-01-15 03:52:03.735628Z	2016-01-15 03:52:03.735628Z	20	Was everything again we i tried dog asked go irl feeling job monday nobody cancelled today tried plans now pizza because...
+13	2016-01-15 05:30:04.311842Z	2016-01-15 05:30:04.311842Z	7	Cancelled again went episodes tickets smh
+14	2016-01-22 23:24:50.158658Z	2016-01-22 23:24:50.158658Z	24	Third expected bus sock?
+15	2016-01-24 20:52:46.22158Z	2016-01-24 20:52:46.22158Z	33	Mood commute week sock wild recommendations with this maybe it.
+16	2016-01-25 04:31:48.444531Z	2016-01-25 04:31:48.444531Z	35	Week please this was again sigh me irl unreal new marathon podcast smoke feeling commute it.
+17	2016-01-27 14:05:04.3505Z	2016-01-27 14:05:04.3505Z	24	Everything now show again show everything monday smh
+18	2016-01-28 02:09:22.410823Z	2016-01-28 02:09:22.410823Z	7	Not sock go smoke finished commute
+19	2016-02-01 07:40:10.633838Z	2016-02-01 07:40:10.633838Z	30	Concert cooking cooking want brunch wild this commute couch hits friends brunch cooking because dis i please tried sigh want expected
+20	2016-02-05 12:14:29.485888Z	2016-02-05 12:14:29.485888Z	7	It nobody of show wild honestly of with job marathon now we want commute unreal time late
+21	2016-02-06 23:23:05.301785Z	2016-02-06 23:23:05.301785Z	30	Sigh new over sigh of irl this!
+22	2016-02-07 11:55:58.773899Z	2016-02-07 11:55:58.773899Z	30	Dis who concert again sigh bread but honestly gym commute wild of it show under and we asked about i sunset and password!
+23	2016-02-09 10:53:07.866319Z	2016-02-09 10:53:07.866319Z	16	As cat the alarm everything definitely irl the dis who now forgot but recommendations smh
+24	2016-02-11 16:14:46.643838Z	2016-02-11 16:14:46.643838Z	24	Just sunset sunset was
+25	2016-02-12 20:49:26.335298Z	2016-02-12 20:49:26.335298Z	35	Password weekend and about forgot weekend sigh sock recommendations recommendations episodes the time of everything that lol
+26	2016-02-16 06:26:06.098794Z	2016-02-16 06:26:06.098794Z	7	Rain me a was commute about marathon just job dis tickets again me tomorrow password i of smh
+27	2016-02-18 15:00:27.942223Z	2016-02-18 15:00:27.942223Z	43	This again expected cooking password maybe time a go irl went monday plans today with went coffee irl smoke sold over concert finished lol
+28	2016-02-21 16:32:18.223897Z	2016-02-21 16:32:18.223897Z	10	Late now dog me cooking baking plans job monday found bread marathon definitely everything third my just this under definitely was
+29	2016-02-25 04:32:38.586641Z	2016-02-25 04:32:38.586641Z	35	Marathon rain nobody here this show who but dog cooking alarm wild everything commute it.
+30	2016-02-26 06:33:32.084557Z	2016-02-26 06:33:32.084557Z	5	This concert couch gym bread but episodes.
+31	2016-02-26 13:18:04.79737Z	2016-02-26 13:18:04.79737Z	24	Please alarm as pizza sold because monday found third week sock maybe gym commute everything mood week of about...
+32	2016-02-26 20:42:22.202476Z	2016-02-26 20:42:22.202476Z	43	We tomorrow found mood smoke late alarm went over smoke sold as now cooking plans feeling lol
+33	2016-02-26 22:44:57.344603Z	2016-02-26 22:44:57.344603Z	14	Under cooking job of third password
+34	2016-02-27 02:04:59.203802Z	2016-02-27 02:04:59.203802Z	39	Baking hits bread definitely baking sigh monday today show dog sold friends found of please!
+35	2016-02-28 15:56:50.565736Z	2016-02-28 15:56:50.565736Z	3	The recommendations new i
+36	2016-03-02 15:36:58.510949Z	2016-03-02 15:36:58.510949Z	37	Gym my with tea who smoke we hits show.
+37	2016-03-04 00:46:51.597668Z	2016-03-04 00:46:51.597668Z	7	Because hits gym hits please because cooking nobody now a smoke plans a!
+38	2016-03-04 18:52:23.622592Z	2016-03-04 18:52:23.622592Z	37	Just time cooking plans gym irl forgot definitely couch maybe hits irl couch we this about this this go feeling sock password go...
+39	2016-03-05 18:37:45.531965Z	2016-03-05 18:37:45.531965Z	29	Sold password time time friends bread...
+40	2016-03-07 19:47:03.904475Z	2016-03-07 19:47:03.904475Z	29	Unreal please dog and who sigh this episodes over with go nobody dis lol
+41	2016-03-09 16:49:16.577854Z	2016-03-09 16:49:16.577854Z	2	Please but this coffee over late as time who maybe please finished mood honestly time plans as dog expected just
+42	2016-03-11 01:05:04.633545Z	2016-03-11 01:05:04.633545Z	44	Sunset hits coffee wild cooking again just again everything me forgot bread unreal asked bus was podcast plans over i smh
+43	2016-03-12 07:42:39.126721Z	2016-03-12 07:42:39.126721Z	18	Want me third a because week dis because late found week me and irl week brunch sold
+44	2016-03-12 10:24:36.297752Z	2016-03-12 10:24:36.297752Z	1	Concert under cat cancelled definitely dog feeling sold job was gym marathon lol
+45	2016-03-13 06:06:19.576449Z	2016-03-13 06:06:19.576449Z	20	About it recommendations wild job honestly me third found brunch sunset cancelled honestly the dog now honestly lol
+46	2016-03-13 11:47:52.858616Z	2016-03-13 11:47:52.858616Z	11	Sunset password that again show nobody mood late recommendations of who irl friends recommendations definitely plans coffee this this hits lol
+47	2016-03-13 18:11:36.778153Z	2016-03-13 18:11:36.778153Z	47	Cat went rain job everything dog about weekend dis now please tried of?
+48	2016-03-14 22:16:27.946045Z	2016-03-14 22:16:27.946045Z	33	Under maybe concert cancelled lol
+49	2016-03-16 22:39:56.665546Z	2016-03-16 22:39:56.665546Z	25	Please couch again over friends bus sock over concert couch...
+50	2016-03-17 05:14:44.995728Z	2016-03-17 05:14:44.995728Z	2	Tried because under smoke when today my definitely nobody brunch recommendations?
+51	2016-03-18 04:06:08.8632Z	2016-03-18 04:06:08.8632Z	48	Week asked over mood feeling late go sold.
+52	2016-03-18 12:45:05.740666Z	2016-03-18 12:45:05.740666Z	35	Friends mood brunch wild wild today weekend show?
+53	2016-03-18 22:15:30.895908Z	2016-03-18 22:15:30.895908Z	1	Please tea tomorrow recommendations here and nobody hits?
+54	2016-03-19 00:10:59.66572Z	2016-03-19 00:10:59.66572Z	48	A bus recommendations found please about brunch this asked weekend as commute marathon gym mood over of my irl sunset bread was just smh
+55	2016-03-23 04:49:04.666765Z	2016-03-23 04:49:04.666765Z	2	Went time hits baking of want plans expected tomorrow coffee who of pizza with with brunch coffee finished smh
+56	2016-03-23 12:18:32.592018Z	2016-03-23 12:18:32.592018Z	7	Marathon went but of mood irl please please under who mood sold here lol
+57	2016-03-25 21:49:42.485949Z	2016-03-25 21:49:42.485949Z	10	Wild bus cooking a sock monday that and tea coffee recommendations nobody over password!
+58	2016-03-26 10:36:21.921197Z	2016-03-26 10:36:21.921197Z	13	Just definitely smoke tried friends week as mood under smoke as dis baking mood smoke definitely bread honestly!
+59	2016-03-29 03:54:36.219136Z	2016-03-29 03:54:36.219136Z	19	Not plans plans forgot expected wild a irl because was mood sock dog just!
+60	2016-03-30 01:31:50.834898Z	2016-03-30 01:31:50.834898Z	10	Irl everything a asked was sold rain!
+61	2016-04-01 10:17:13.568616Z	2016-04-01 10:17:13.568616Z	2	Sigh unreal it bread new tomorrow couch just sunset i that went was asked tickets smh
+62	2016-04-01 21:50:10.194725Z	2016-04-01 21:50:10.194725Z	43	Because wild podcast that rain time it about because commute job!
+63	2016-04-02 20:05:54.123545Z	2016-04-02 20:05:54.123545Z	37	Gym was tickets asked just went!
+64	2016-04-03 19:40:34.753728Z	2016-04-03 19:40:34.753728Z	42	Dis wild everything definitely bread!
+65	2016-04-04 19:11:26.915485Z	2016-04-04 19:11:26.915485Z	7	It cooking week third weekend alarm maybe asked when nobody dis my want it couch just!
+66	2016-04-04 20:00:08.093766Z	2016-04-04 20:00:08.093766Z	7	Show marathon here baking found?
+67	2016-04-06 08:52:19.115995Z	2016-04-06 08:52:19.115995Z	11	Unreal bread pizza forgot as feeling when alarm about honestly bread week who about found recommendations password and
+68	2016-04-07 23:54:29.808248Z	2016-04-07 23:54:29.808248Z	35	Third nobody sold mood my alarm smoke couch job was commute time rain alarm lol
+69	2016-04-10 01:29:33.436196Z	2016-04-10 01:29:33.436196Z	14	Episodes maybe irl unreal concert
+70	2016-04-11 01:13:06.106469Z	2016-04-11 01:13:06.106469Z	29	But me expected recommendations coffee now bus sunset hits sigh bread show with was dis go want couch lol
+71	2016-04-11 01:35:13.999818Z	2016-04-11 01:35:13.999818Z	22	With forgot sigh honestly everything.
+72	2016-04-13 11:32:26.655586Z	2016-04-13 11:32:26.655586Z	18	Forgot sunset new cat about smh
+73	2016-04-13 16:23:21.035016Z	2016-04-13 16:23:21.035016Z	33	Finished plans brunch not bus smh
+74	2016-04-14 06:59:09.700018Z	2016-04-14 06:59:09.700018Z	3	Episodes monday found now bread week want but tried irl was bread weekend week pizza?
+75	2016-04-14 19:38:25.627942Z	2016-04-14 19:38:25.627942Z	39	Went new baking me definitely friends smoke not couch definitely dog now just again we!
+76	2016-04-14 19:43:03.348596Z	2016-04-14 19:43:03.348596Z	12	Friends alarm was sunset as marathon feeling couch definitely show honestly.
+77	2016-04-18 07:08:26.14746Z	2016-04-18 07:08:26.14746Z	41	Because this honestly maybe maybe friends again found tried not alarm found went cancelled monday...
+78	2016-04-19 08:50:17.662739Z	2016-04-19 08:50:17.662739Z	33	Found feeling sunset not mood couch!
+79	2016-04-19 23:21:18.164888Z	2016-04-19 23:21:18.164888Z	15	Finished not monday cat maybe my weekend weekend baking hits found i friends podcast smh
+80	2016-04-23 15:32:29.573195Z	2016-04-23 15:32:29.573195Z	35	Dis sold a coffee it honestly unreal dis went as pizza...
+81	2016-04-25 09:09:34.257499Z	2016-04-25 09:09:34.257499Z	7	A please i as gym and not found monday time recommendations new now week cat expected brunch episodes sock cat asked the marathon!
+82	2016-04-26 00:05:57.194898Z	2016-04-26 00:05:57.194898Z	7	Password just again time went who want cooking but asked me found.
+83	2016-04-26 06:08:32.894169Z	2016-04-26 06:08:32.894169Z	35	Monday friends finished time today third rain cooking i about concert under concert
+84	2016-04-26 12:44:27.689243Z	2016-04-26 12:44:27.689243Z	7	Pizza now because gym nobody commute was cancelled commute was feeling want because go gym because couch was sock my pizza sold recommendations.
+85	2016-04-27 06:37:22.802462Z	2016-04-27 06:37:22.802462Z	33	Of recommendations under plans tomorrow was bus smoke dis with and smh
+86	2016-04-29 05:55:30.074918Z	2016-04-29 05:55:30.074918Z	47	Monday who not because a today smoke who it found hits baking my under gym finished gym please pizza show because lol
+87	2016-04-29 13:34:34.319206Z	2016-04-29 13:34:34.319206Z	43	Late mood concert recommendations mood unreal because everything forgot cooking week smoke commute just now went me!
+88	2016-05-03 17:26:04.195357Z	2016-05-03 17:26:04.195357Z	35	That went here concert was sock bus monday sigh concert want when tomorrow gym lol
+89	2016-05-04 20:29:19.342103Z	2016-05-04 20:29:19.342103Z	7	Episodes hits cancelled who smh
+90	2016-05-06 08:11:57.01962Z	2016-05-06 08:11:57.01962Z	12	Show tea marathon tomorrow we but everything please tea nobody dog that was that went a expected coffee cooking want again coffee smh
+91	2016-05-06 10:22:04.307938Z	2016-05-06 10:22:04.307938Z	27	Just pizza we honestly brunch me my smoke my
+92	2016-05-07 18:17:57.634938Z	2016-05-07 18:17:57.634938Z	50	When the time cooking sigh that mood commute me over and baking smoke dis lol
+93	2016-05-07 20:07:30.9333Z	2016-05-07 20:07:30.9333Z	48	Tried with tomorrow i as that concert hits monday concert who asked dis sunset i again asked tried under password late please?
+94	2016-05-09 10:27:59.209604Z	2016-05-09 10:27:59.209604Z	35	Sunset asked nobody coffee definitely recommendations monday who irl and hits as podcast mood here tomorrow cancelled forgot tried...
+95	2016-05-11 12:32:00.048879Z	2016-05-11 12:32:00.048879Z	35	With it as concert
+96	2016-05-13 18:52:06.48881Z	2016-05-13 18:52:06.48881Z	40	About bread me who bread commute here forgot we please coffee unreal go the unreal bread pizza because job gym lol
+97	2016-05-15 03:00:38.196517Z	2016-05-15 03:00:38.196517Z	28	Weekend recommendations tomorrow here maybe definitely when want sold rain show found that bread recommendations job hits password cooking...
+98	2016-05-19 08:24:10.884033Z	2016-05-19 08:24:10.884033Z	34	Cat coffee as bus that today of podcast was just bread forgot tried smh
+99	2016-05-21 05:53:53.682425Z	2016-05-21 05:53:53.682425Z	33	Cooking this new we nobody with wild wild marathon pizza tickets wild go marathon found again wild time smh
+100	2016-05-21 09:08:42.523317Z	2016-05-21 09:08:42.523317Z	2	Honestly cat was baking it want mood dog tomorrow password went show who my who mood brunch weekend episodes pizza want!
+101	2016-05-22 09:23:42.670063Z	2016-05-22 09:23:42.670063Z	7	Tea with time i gym monday irl with but sigh forgot again again please smh
+102	2016-05-22 17:42:15.925549Z	2016-05-22 17:42:15.925549Z	34	Mood everything was the lol
+103	2016-05-27 02:23:07.351891Z	2016-05-27 02:23:07.351891Z	11	Expected week smoke concert cancelled alarm finished with alarm found
+104	2016-05-27 11:49:05.704954Z	2016-05-27 11:49:05.704954Z	34	Of me the we that and new the bus that alarm sigh my maybe cat as!
+105	2016-05-27 12:40:28.343697Z	2016-05-27 12:40:28.343697Z	3	Time unreal a was commute a recommendations!
+106	2016-05-29 19:43:58.487099Z	2016-05-29 19:43:58.487099Z	35	New just commute marathon but and password coffee plans me tomorrow over show smh
+107	2016-06-04 13:15:08.879011Z	2016-06-04 13:15:08.879011Z	2	Marathon sunset tomorrow concert want under maybe sunset my sold this and gym sold with went alarm baking cooking not tomorrow me and!
+108	2016-06-05 15:13:21.079454Z	2016-06-05 15:13:21.079454Z	21	Sigh marathon week sigh episodes it here me again bus commute as lol
+109	2016-06-05 15:38:41.539469Z	2016-06-05 15:38:41.539469Z	32	Episodes marathon unreal we brunch was went baking couch job alarm time cat alarm baking week go dis just new unreal here today!
+110	2016-06-14 22:23:02.148275Z	2016-06-14 22:23:02.148275Z	39	Alarm tickets tea tried tomorrow new sold tried we about asked tea alarm tried but and bus tickets rain third mood wild baking
+111	2016-06-14 23:49:18.814831Z	2016-06-14 23:49:18.814831Z	40	Me me wild alarm as was hits baking cat tomorrow
+112	2016-06-15 23:32:35.997892Z	2016-06-15 23:32:35.997892Z	39	Weekend feeling concert gym forgot recommendations monday wild me new bread who
+113	2016-06-16 23:52:25.851128Z	2016-06-16 23:52:25.851128Z	48	A but friends want sock irl nobody...
+114	2016-06-17 05:26:02.420235Z	2016-06-17 05:26:02.420235Z	14	Bus baking and pizza forgot i brunch today forgot i concert job alarm brunch nobody not episodes maybe everything rain lol
+115	2016-06-17 06:42:45.557411Z	2016-06-17 06:42:45.557411Z	35	Everything cat third brunch bus cancelled go sunset unreal because feeling smh
+116	2016-06-19 13:15:38.613007Z	2016-06-19 13:15:38.613007Z	7	Job expected about my cancelled honestly was of expected it found definitely honestly i tea gym weekend job bread was.
+117	2016-06-22 04:17:25.359615Z	2016-06-22 04:17:25.359615Z	25	Coffee pizza was the found sunset plans again a go a go show marathon go pizza show couch alarm
+118	2016-06-22 10:51:04.756431Z	2016-06-22 10:51:04.756431Z	11	Baking job of the irl gym not just definitely finished
+119	2016-06-22 21:12:31.479578Z	2016-06-22 21:12:31.479578Z	39	Coffee honestly went show as bread weekend wild brunch time definitely maybe pizza nobody asked everything!
+120	2016-06-23 22:10:45.510477Z	2016-06-23 22:10:45.510477Z	25	Honestly unreal dog here concert pizza when want as as maybe cat marathon bus smoke smh
+121	2016-06-24 00:41:13.21346Z	2016-06-24 00:41:13.21346Z	29	Plans gym definitely bread honestly tea was finished sold third lol
+122	2016-06-26 07:55:44.396396Z	2016-06-26 07:55:44.396396Z	13	Rain it tickets tickets here it i want go lol
+123	2016-06-27 19:31:39.493455Z	2016-06-27 19:31:39.493455Z	14	Dog friends password alarm commute tomorrow couch tried maybe podcast was smh
+124	2016-06-27 22:25:30.643971Z	2016-06-27 22:25:30.643971Z	48	Hits couch sold asked cooking coffee
+125	2016-06-28 05:46:19.1451Z	2016-06-28 05:46:19.1451Z	14	Coffee cooking who episodes week again me?
+126	2016-07-01 20:36:18.323289Z	2016-07-01 20:36:18.323289Z	42	Maybe couch went third time with i asked now.
+127	2016-07-03 14:44:50.190231Z	2016-07-03 14:44:50.190231Z	35	Now of today we please?
+128	2016-07-05 18:40:00.797943Z	2016-07-05 18:40:00.797943Z	48	Just recommendations job monday cooking with podcast dis rain wild third alarm forgot found we cat password smoke!
+129	2016-07-06 16:16:27.83062Z	2016-07-06 16:16:27.83062Z	14	As hits time everything plans here not bread monday because cooking marathon coffee tea!
+130	2016-07-07 04:35:07.513071Z	2016-07-07 04:35:07.513071Z	10	About maybe definitely unreal tickets sold cooking episodes cooking cat definitely definitely and dog cat bus because late we sock me brunch?
+131	2016-07-08 17:31:39.335211Z	2016-07-08 17:31:39.335211Z	41	Rain again bread cooking me podcast went smh
+132	2016-07-09 14:55:20.429654Z	2016-07-09 14:55:20.429654Z	21	Friends finished just definitely job time as sold asked tomorrow password a episodes brunch late third again but password just expected tomorrow...
+133	2016-07-09 18:46:47.142985Z	2016-07-09 18:46:47.142985Z	30	Couch monday monday we not plans please lol
+134	2016-07-12 09:42:47.125957Z	2016-07-12 09:42:47.125957Z	28	New pizza plans found went here about tomorrow who job smoke of lol
+135	2016-07-17 19:57:25.056901Z	2016-07-17 19:57:25.056901Z	2	Tomorrow under new nobody dog coffee here i just not this commute nobody that lol
+136	2016-07-19 08:17:34.82196Z	2016-07-19 08:17:34.82196Z	34	Wild and episodes cooking couch honestly that podcast time week marathon finished sock but irl hits as new now tried dis show
+137	2016-07-20 01:26:11.041407Z	2016-07-20 01:26:11.041407Z	16	Sock sunset we wild time sigh episodes week dog dis time tomorrow gym password monday lol
+138	2016-07-20 23:42:09.151032Z	2016-07-20 23:42:09.151032Z	7	Tried please new the a this sigh tickets sold about bus weekend a wild found concert!
+139	2016-07-21 11:47:18.035505Z	2016-07-21 11:47:18.035505Z	29	Bread sock found about tomorrow couch was but under marathon cat everything cat tomorrow monday...
+140	2016-07-22 12:28:21.278416Z	2016-07-22 12:28:21.278416Z	12	Me password coffee bus feeling dis rain show of tomorrow everything definitely when today maybe pizza couch dog who tickets sigh?
+141	2016-07-23 18:31:07.535145Z	2016-07-23 18:31:07.535145Z	28	Marathon was it episodes rain want the baking unreal because because a wild irl...
+142	2016-07-26 09:52:40.43063Z	2016-07-26 09:52:40.43063Z	7	Not now tea want unreal marathon tomorrow here who sunset password this recommendations tried pizza show was bus nobody it!
+143	2016-07-26 23:01:09.933083Z	2016-07-26 23:01:09.933083Z	10	Friends tried me found hits but baking plans baking was go forgot tried i wild expected and today friends podcast.
+144	2016-07-28 10:30:10.130656Z	2016-07-28 10:30:10.130656Z	30	Show tickets this maybe password here sock as cat third again baking smh
+145	2016-07-31 03:21:27.297831Z	2016-07-31 03:21:27.297831Z	25	Mood marathon cat cat hits not today tomorrow everything again week marathon my lol
+146	2016-07-31 11:39:20.793765Z	2016-07-31 11:39:20.793765Z	17	We maybe show it and honestly was plans episodes hits today sock everything maybe third we my mood found today cancelled monday was lol
+147	2016-08-02 20:43:00.650985Z	2016-08-02 20:43:00.650985Z	47	Concert brunch irl nobody want maybe dog smoke a asked gym gym smoke smh
+148	2016-08-03 02:33:02.357523Z	2016-08-03 02:33:02.357523Z	30	A rain who nobody friends sock under baking sold!
+149	2016-08-03 07:03:16.160338Z	2016-08-03 07:03:16.160338Z	30	Finished expected the cooking third episodes commute asked sigh as because forgot because tried but...
+150	2016-08-03 15:18:22.923034Z	2016-08-03 15:18:22.923034Z	11	Sold week as me smh
+151	2016-08-03 20:30:56.841894Z	2016-08-03 20:30:56.841894Z	24	Plans but episodes my my alarm lol
+152	2016-08-04 06:50:03.673046Z	2016-08-04 06:50:03.673046Z	21	Feeling the bus who forgot cat commute commute password this me alarm cooking marathon episodes but password job as this who lol
+153	2016-08-05 18:06:06.491729Z	2016-08-05 18:06:06.491729Z	2	Was everything go monday hits new went week now late tickets i tickets new about now late found again hits who unreal sold lol
+154	2016-08-06 20:04:19.358599Z	2016-08-06 20:04:19.358599Z	2	Definitely feeling tea dis under about i honestly couch.
+155	2016-08-07 06:00:36.62799Z	2016-08-07 06:00:36.62799Z	30	With concert we marathon tried that feeling found friends of this baking it under over asked...
+156	2016-08-08 06:03:58.965475Z	2016-08-08 06:03:58.965475Z	35	Under was sigh brunch third coffee concert sold was weekend baking this unreal third found this time because asked
+157	2016-08-10 00:16:46.187954Z	2016-08-10 00:16:46.187954Z	35	Over baking monday that wild mood the cancelled alarm bread wild we of gym with that friends baking but irl who concert lol
+158	2016-08-10 05:38:18.66606Z	2016-08-10 05:38:18.66606Z	41	Late today please irl because
+159	2016-08-12 12:00:24.50148Z	2016-08-12 12:00:24.50148Z	7	Here time who week weekend not third couch brunch here the brunch just concert rain...
+160	2016-08-13 00:42:11.664704Z	2016-08-13 00:42:11.664704Z	40	Dis want smoke not of want feeling lol
+161	2016-08-14 10:21:47.106604Z	2016-08-14 10:21:47.106604Z	19	Was dog tried it with monday with of marathon late sold?
+162	2016-08-15 11:34:37.612206Z	2016-08-15 11:34:37.612206Z	7	Password go me friends dis!
+163	2016-08-16 13:09:31.508727Z	2016-08-16 13:09:31.508727Z	21	About with but weekend plans not irl smoke.
+164	2016-08-21 10:21:27.216342Z	2016-08-21 10:21:27.216342Z	25	Week irl finished found...
+165	2016-08-22 01:10:44.818599Z	2016-08-22 01:10:44.818599Z	7	Went about monday was my mood marathon week hits tea nobody now was plans monday not with week password sigh brunch cooking?
+166	2016-08-22 11:56:28.886128Z	2016-08-22 11:56:28.886128Z	43	Third expected forgot gym but weekend commute everything this baking show about password lol
+167	2016-08-22 23:33:23.804742Z	2016-08-22 23:33:23.804742Z	35	Because plans again commute over now honestly plans tomorrow tickets now over bus asked baking monday over expected...
+168	2016-08-26 14:15:27.454932Z	2016-08-26 14:15:27.454932Z	30	My cooking now tea job coffee password i wild now recommendations coffee this found tomorrow smoke when went today coffee the everything friends...
+169	2016-08-27 21:46:08.602425Z	2016-08-27 21:46:08.602425Z	37	Asked was recommendations when episodes bread definitely monday sold please bread this cat rain smh
+170	2016-08-29 08:34:47.793785Z	2016-08-29 08:34:47.793785Z	33	Plans feeling finished please irl go tea baking plans cancelled episodes because smoke commute friends late because hits when with now...
+171	2016-08-30 20:30:28.986474Z	2016-08-30 20:30:28.986474Z	42	Everything because was everything was couch this just forgot this!
+172	2016-08-31 19:48:45.412537Z	2016-08-31 19:48:45.412537Z	7	But episodes unreal please who tickets dog a me maybe everything unreal my friends as here smh
+173	2016-09-02 00:03:55.581862Z	2016-09-02 00:03:55.581862Z	39	Gym alarm as sock cat a today over password cancelled gym and a sigh bread expected
+174	2016-09-04 21:04:54.964051Z	2016-09-04 21:04:54.964051Z	35	Friends smoke weekend irl a late feeling wild forgot was monday want dog a.
+175	2016-09-05 00:07:11.596917Z	2016-09-05 00:07:11.596917Z	33	Dis when and my recommendations gym friends password brunch hits because job
+176	2016-09-05 10:51:23.021877Z	2016-09-05 10:51:23.021877Z	29	Tickets the tried who asked sold was job the third alarm tea my sunset that sigh marathon i because cancelled again lol
+177	2016-09-07 03:01:49.067116Z	2016-09-07 03:01:49.067116Z	29	Brunch me i alarm cooking tea the as as sock definitely weekend feeling job smh
+178	2016-09-07 03:47:40.421457Z	2016-09-07 03:47:40.421457Z	29	Forgot who over with coffee finished coffee everything again time here third podcast concert plans bus over here episodes...
+179	2016-09-07 18:35:02.016239Z	2016-09-07 18:35:02.016239Z	33	Sunset here unreal about irl everything dog expected maybe bus smoke new feeling about me gym feeling gym coffee everything just lol
+180	2016-09-08 06:33:00.155372Z	2016-09-08 06:33:00.155372Z	48	As nobody when couch honestly tried it smh
+181	2016-09-10 11:53:36.65679Z	2016-09-10 11:53:36.65679Z	5	Went bread concert about was the unreal finished please went and smh
+182	2016-09-12 02:44:55.546642Z	2016-09-12 02:44:55.546642Z	24	Late about not forgot couch now tickets gym was gym as everything bus wild recommendations couch time the a everything who late the?
+183	2016-09-14 00:22:54.563316Z	2016-09-14 00:22:54.563316Z	50	Baking went monday plans friends forgot sigh...
+184	2016-09-16 01:29:53.377191Z	2016-09-16 01:29:53.377191Z	7	Unreal unreal gym me gym now week bus want maybe went.
+185	2016-09-16 16:28:16.366954Z	2016-09-16 16:28:16.366954Z	10	Hits maybe cancelled gym just mood sunset weekend was when hits tickets coffee dog not week asked but week couch rain time just smh
+186	2016-09-18 07:05:10.415576Z	2016-09-18 07:05:10.415576Z	20	Bread was forgot wild sock alarm now we feeling password it alarm but with time everything hits just weekend recommendations commute smh
+187	2016-09-20 07:18:27.388183Z	2016-09-20 07:18:27.388183Z	39	Sigh over monday sock maybe was found a third i unreal expected time mood we...
+188	2016-09-20 07:39:17.550942Z	2016-09-20 07:39:17.550942Z	11	Sigh tickets here as tickets couch sock sock unreal time
+189	2016-09-20 12:23:27.758158Z	2016-09-20 12:23:27.758158Z	35	Forgot under cat we mood rain...
+190	2016-09-20 23:38:07.812776Z	2016-09-20 23:38:07.812776Z	10	Over of bus but episodes bread forgot we maybe feeling just now dog again a irl nobody bus.
+191	2016-09-22 03:16:42.015948Z	2016-09-22 03:16:42.015948Z	39	Nobody job and this new sold today about friends week finished baking again we again nobody irl third who alarm tomorrow and
+192	2016-09-22 11:10:20.080537Z	2016-09-22 11:10:20.080537Z	19	Not forgot my unreal was about job under smoke was tomorrow smoke this just now plans a my definitely but...
+193	2016-09-22 19:17:07.792877Z	2016-09-22 19:17:07.792877Z	30	Sigh about i cat about definitely as not unreal sunset friends episodes!
+194	2016-09-24 20:45:11.141931Z	2016-09-24 20:45:11.141931Z	33	Third this marathon sunset as please episodes tomorrow new found show it everything plans alarm but week tried maybe feeling bread asked.
+195	2016-09-26 19:15:12.440809Z	2016-09-26 19:15:12.440809Z	28	Sold marathon show here here today expected when monday was password gym baking smh
+196	2016-09-26 21:04:46.857695Z	2016-09-26 21:04:46.857695Z	42	When monday maybe late everything the tea feeling baking went went tried and honestly...
+197	2016-09-27 03:21:03.642853Z	2016-09-27 03:21:03.642853Z	39	Here not irl not about me as me sigh was password asked finished bus irl it today here dis lol
+198	2016-09-30 13:43:17.574461Z	2016-09-30 13:43:17.574461Z	19	Not but tickets feeling podcast sunset everything...
+199	2016-10-02 18:56:56.311854Z	2016-10-02 18:56:56.311854Z	33	Bus of we expected new bus tomorrow over sold friends concert couch because asked coffee coffee not cooking
+200	2016-10-04 22:07:14.698969Z	2016-10-04 22:07:14.698969Z	2	Bread sock cancelled sold gym went wild password plans gym about who now.
+201	2016-10-07 23:28:59.424793Z	2016-10-07 23:28:59.424793Z	14	Hits tried irl gym pizza maybe everything with found honestly me this brunch alarm!
+202	2016-10-08 15:48:49.503513Z	2016-10-08 15:48:49.503513Z	29	Over alarm rain bread expected hits expected brunch me podcast want but tea a cat!
+203	2016-10-08 16:51:39.249343Z	2016-10-08 16:51:39.249343Z	7	Sunset dog now dog everything recommendations sigh smoke pizza asked?
+204	2016-10-12 07:10:25.417734Z	2016-10-12 07:10:25.417734Z	34	Everything couch smoke mood tomorrow tomorrow sunset went cooking forgot commute time couch was with who.
+205	2016-10-12 09:15:11.93411Z	2016-10-12 09:15:11.93411Z	34	As a about was coffee tried cat sunset nobody because tea?
+206	2016-10-12 23:35:25.021771Z	2016-10-12 23:35:25.021771Z	48	Expected coffee but want who episodes tea week marathon my it i everything want just under the episodes under couch tickets.
+207	2016-10-14 07:47:44.479094Z	2016-10-14 07:47:44.479094Z	41	Coffee baking here late just just today maybe over i rain baking lol
+208	2016-10-14 22:26:15.635329Z	2016-10-14 22:26:15.635329Z	43	Sock week password but dog smh
+209	2016-10-20 23:01:45.116509Z	2016-10-20 23:01:45.116509Z	47	Cat bus sunset expected dis tea found my couch!
+210	2016-10-22 09:35:23.097547Z	2016-10-22 09:35:23.097547Z	2	Alarm commute everything expected go friends unreal cancelled now password was today...
+211	2016-10-22 09:55:12.110063Z	2016-10-22 09:55:12.110063Z	47	Coffee sigh everything sunset sunset wild bread my a dis finished dis third nobody because tried please time everything smh
+212	2016-10-25 10:57:21.435331Z	2016-10-25 10:57:21.435331Z	40	Dog cooking sunset cat mood?
+213	2016-10-27 03:11:28.795422Z	2016-10-27 03:11:28.795422Z	14	Smoke asked go with podcast show hits everything it gym show about brunch show here late forgot couch smoke tomorrow week.
+214	2016-10-27 06:57:56.65166Z	2016-10-27 06:57:56.65166Z	25	Now my finished feeling third third tried because nobody bread alarm was nobody alarm forgot was gym recommendations definitely please...
+215	2016-10-30 02:24:15.792633Z	2016-10-30 02:24:15.792633Z	21	Gym when go with feeling job?
+216	2016-10-30 03:31:52.185669Z	2016-10-30 03:31:52.185669Z	25	Tickets want episodes wild nobody new password rain feeling but coffee but go show just friends...
+217	2016-10-31 11:46:26.625537Z	2016-10-31 11:46:26.625537Z	3	Sold the cancelled definitely please found tomorrow late new it not about cat this marathon was week alarm podcast job that me again...
+218	2016-11-01 07:43:42.746448Z	2016-11-01 07:43:42.746448Z	35	Wild expected gym friends everything rain coffee but password commute under of sigh commute couch!
+219	2016-11-02 14:00:45.902436Z	2016-11-02 14:00:45.902436Z	36	Definitely week couch my the who over my tried gym finished pizza nobody when about.
+220	2016-11-02 14:35:55.558628Z	2016-11-02 14:35:55.558628Z	29	Finished this password monday time dis tried pizza password smh
+221	2016-11-08 12:18:55.666641Z	2016-11-08 12:18:55.666641Z	19	Password please with marathon was week me as i plans gym cooking feeling!
+222	2016-11-08 18:38:55.2433Z	2016-11-08 18:38:55.2433Z	7	Found couch dog week new asked couch couch irl bread
+223	2016-11-11 23:35:04.905815Z	2016-11-11 23:35:04.905815Z	8	Cooking bread about plans recommendations everything it about go over this mood week under plans lol
+224	2016-11-13 00:18:51.117285Z	2016-11-13 00:18:51.117285Z	2	Pizza new please the commute we bread baking just go found definitely new cat brunch couch feeling as podcast cancelled again as plans...
+225	2016-11-19 04:22:38.940542Z	2016-11-19 04:22:38.940542Z	39	Plans here week coffee third me!
+226	2016-11-19 07:45:18.226884Z	2016-11-19 07:45:18.226884Z	1	Recommendations please nobody this password tea it over rain hits baking not that but of but feeling smh
+227	2016-11-19 18:48:35.073324Z	2016-11-19 18:48:35.073324Z	21	Feeling feeling please third plans found my as plans of recommendations my
+228	2016-11-22 01:14:28.117052Z	2016-11-22 01:14:28.117052Z	40	About podcast recommendations new asked the finished and when today cancelled podcast week couch was expected was with found of a commute?
+229	2016-11-23 21:46:22.065598Z	2016-11-23 21:46:22.065598Z	3	Recommendations marathon please unreal bus not just was mood who because the about but went lol
+230	2016-11-24 18:49:24.384507Z	2016-11-24 18:49:24.384507Z	30	Not time it this sigh feeling smoke bread honestly plans today go third?
+231	2016-11-27 19:32:11.041914Z	2016-11-27 19:32:11.041914Z	29	My honestly finished third monday found went maybe unreal over friends...
+232	2016-11-28 14:19:11.490584Z	2016-11-28 14:19:11.490584Z	24	Dis job time sigh i cat alarm maybe new sock.
+233	2016-11-29 13:07:17.996986Z	2016-11-29 13:07:17.996986Z	45	Pizza time sold sunset sold monday marathon was wild friends alarm friends cancelled and here of sigh about everything dis honestly gym found.
+234	2016-12-03 07:42:53.081562Z	2016-12-03 07:42:53.081562Z	10	Week of tickets that baking just not when friends show go alarm marathon friends with wild today friends not baking recommendations lol
+235	2016-12-03 07:58:54.125182Z	2016-12-03 07:58:54.125182Z	21	Forgot irl here third please me couch monday wild bread week under asked when wild late!
+236	2016-12-04 07:39:37.636055Z	2016-12-04 07:39:37.636055Z	3	Dog recommendations expected expected
+237	2016-12-09 14:25:35.294793Z	2016-12-09 14:25:35.294793Z	41	Brunch cancelled podcast everything was concert now pizza time it now sock?
+238	2016-12-10 22:39:39.384479Z	2016-12-10 22:39:39.384479Z	39	Episodes podcast cooking it a everything because dog third podcast went episodes feeling...
+239	2016-12-11 01:31:33.512051Z	2016-12-11 01:31:33.512051Z	40	About my and sock brunch episodes please tomorrow bread irl not smoke of asked sunset unreal smoke that brunch and!
+240	2016-12-11 04:31:13.928323Z	2016-12-11 04:31:13.928323Z	29	Episodes brunch was bread this i late not concert my this when third show wild maybe maybe asked recommendations tried.
+241	2016-12-11 09:21:58.877556Z	2016-12-11 09:21:58.877556Z	19	The job week late sunset but smh
+242	2016-12-11 14:07:15.305331Z	2016-12-11 14:07:15.305331Z	35	Everything cooking a cancelled bus recommendations plans bread over smoke we baking want coffee a it.
+243	2016-12-12 02:15:34.399164Z	2016-12-12 02:15:34.399164Z	39	Wild hits monday maybe the this the commute time the sunset wild today of gym because baking sock late this wild the lol
+244	2016-12-17 17:18:20.437127Z	2016-12-17 17:18:20.437127Z	11	With week with a because brunch podcast hits cancelled was definitely want here who new alarm nobody now because but but who password!
+245	2016-12-18 15:23:14.773693Z	2016-12-18 15:23:14.773693Z	41	My tried go new podcast here with who.
+246	2016-12-18 18:16:44.042339Z	2016-12-18 18:16:44.042339Z	29	The mood marathon cat dis me rain that as time late new commute lol
+247	2016-12-20 00:51:14.742828Z	2016-12-20 00:51:14.742828Z	44	Again sunset forgot sold nobody sock go.
+248	2016-12-22 07:27:09.046812Z	2016-12-22 07:27:09.046812Z	28	Was cooking under bus here plans go cat was couch baking lol
+249	2016-12-22 19:15:47.117938Z	2016-12-22 19:15:47.117938Z	43	Because gym tea plans went me forgot not password time my lol
+250	2016-12-23 08:03:59.688049Z	2016-12-23 08:03:59.688049Z	29	Nobody of about week when feeling it irl under definitely couch my coffee wild baking cancelled...
+251	2016-12-24 13:23:28.963398Z	2016-12-24 13:23:28.963398Z	13	Plans asked today that but sock brunch but cat late job?
+252	2016-12-24 17:28:00.972045Z	2016-12-24 17:28:00.972045Z	7	Nobody definitely sigh pizza commute lol
+253	2016-12-26 19:53:14.036978Z	2016-12-26 19:53:14.036978Z	10	Unreal mood week with went alarm third when smoke just tea here new definitely but coffee dog and lol
+254	2016-12-28 08:24:08.133458Z	2016-12-28 08:24:08.133458Z	1	Monday i recommendations episodes late this finished irl not late!
+255	2016-12-28 09:47:47.66992Z	2016-12-28 09:47:47.66992Z	4	Who here nobody commute password nobody hits concert because bread baking when everything finished concert who bread?
+256	2016-12-29 13:29:31.958675Z	2016-12-29 13:29:31.958675Z	2	Rain marathon job sock my sock this under bread dis irl podcast again over podcast concert was tried alarm me
+257	2016-12-29 17:13:37.105482Z	2016-12-29 17:13:37.105482Z	7	Sold unreal show under asked dis just under plans monday hits alarm coffee alarm week and.
+\.
+
+COPY post_keks (author_id, post_id, created_at) FROM stdin;
+6	1	2016-01-02 05:42:18.824641Z
+16	1	2016-01-02 03:38:26.306863Z
+1	4	2016-01-05 01:24:35.996876Z
+8	4	2016-01-05 01:41:14.310934Z
+11	4	2016-01-05 02:41:30.329515Z
+15	4	2016-01-05 02:53:45.959815Z
+18	4	2016-01-05 01:28:12.462662Z
+24	4	2016-01-05 01:24:41.151472Z
+37	4	2016-01-05 01:41:34.008013Z
+38	4	2016-01-05 19:47:32.26438Z
+39	4	2016-01-05 05:14:08.339772Z
+1	6	2016-01-05 05:29:56.630151Z
+18	6	2016-01-05 09:21:46.365285Z
+31	6	2016-01-05 05:33:23.376679Z
+35	6	2016-01-05 08:24:20.732735Z
+48	6	2016-01-05 08:40:48.591219Z
+25	8	2016-01-06 12:47:42.067222Z
+6	11	2016-01-11 10:04:12.491819Z
+15	11	2016-01-12 02:47:55.209249Z
+28	11	2016-01-11 06:16:07.587984Z
+39	11	2016-01-12 09:13:05.435375Z
+49	11	2016-01-11 04:08:59.684802Z
+26	12	2016-01-15 08:48:40.837039Z
+3	13	2016-01-15 11:29:55.86122Z
+6	13	2016-01-16 03:08:50.750996Z
+10	13	2016-01-15 07:32:03.422319Z
+14	13	2016-01-15 08:27:03.985012Z
+20	13	2016-01-15 09:05:21.01007Z
+25	13	2016-01-15 11:58:07.258321Z
+33	13	2016-01-15 07:11:23.414193Z
+35	13	2016-01-15 05:58:51.631987Z
+38	13	2016-01-15 15:33:35.698946Z
+40	13	2016-01-15 08:44:07.547577Z
+44	13	2016-01-15 08:05:14.95178Z
+47	13	2016-01-15 15:45:48.442961Z
+49	13	2016-01-15 15:08:31.423073Z
+3	14	2016-01-22 23:50:40.334171Z
+37	14	2016-01-23 07:27:52.792991Z
+2	15	2016-01-24 23:15:49.608009Z
+8	15	2016-01-24 21:53:18.590084Z
+16	15	2016-01-25 06:25:19.855983Z
+11	17	2016-01-28 14:47:41.57593Z
+22	17	2016-01-27 17:15:14.177381Z
+28	17	2016-01-27 21:01:37.504262Z
+40	17	2016-01-27 23:39:37.134626Z
+41	17	2016-01-27 23:22:24.119296Z
+17	18	2016-01-28 02:43:26.47559Z
+23	18	2016-01-28 04:41:41.284426Z
+13	19	2016-02-01 21:28:17.563864Z
+21	19	2016-02-01 16:47:39.326139Z
+22	19	2016-02-01 21:29:15.590038Z
+23	19	2016-02-01 16:06:42.625719Z
+25	19	2016-02-01 11:18:43.968282Z
+29	19	2016-02-01 08:25:43.901523Z
+31	19	2016-02-01 09:54:45.550333Z
+32	19	2016-02-01 08:59:26.66331Z
+37	19	2016-02-01 08:18:14.687971Z
+38	19	2016-02-01 08:02:14.478855Z
+40	19	2016-02-01 10:16:03.653822Z
+44	19	2016-02-01 12:24:25.778729Z
+48	19	2016-02-01 09:47:33.224083Z
+23	20	2016-02-05 19:12:24.301766Z
+1	21	2016-02-07 04:42:57.67579Z
+3	21	2016-02-07 07:02:46.946815Z
+8	21	2016-02-07 01:46:41.01569Z
+9	21	2016-02-07 00:38:28.032269Z
+13	21	2016-02-08 04:04:50.752338Z
+17	21	2016-02-07 04:37:14.82638Z
+20	21	2016-02-07 00:30:45.147724Z
+24	21	2016-02-07 07:38:50.152108Z
+26	21	2016-02-06 23:53:21.996761Z
+28	21	2016-02-07 07:34:26.322834Z
+29	21	2016-02-07 00:45:19.298135Z
+31	21	2016-02-07 10:24:15.921337Z
+33	21	2016-02-07 05:05:42.700397Z
+35	21	2016-02-07 08:36:00.845124Z
+36	21	2016-02-07 02:29:23.338236Z
+44	21	2016-02-07 03:07:45.451561Z
+45	21	2016-02-07 01:42:18.277906Z
+47	21	2016-02-07 01:01:29.09472Z
+48	21	2016-02-06 23:39:55.951627Z
+50	21	2016-02-07 02:40:40.554456Z
+14	22	2016-02-07 13:52:09.431563Z
+18	22	2016-02-07 17:50:12.470974Z
+33	22	2016-02-07 19:21:11.394042Z
+41	22	2016-02-07 13:45:35.886704Z
+6	24	2016-02-11 17:18:18.761225Z
+29	24	2016-02-11 23:14:10.950842Z
+31	24	2016-02-12 02:43:54.401335Z
+33	24	2016-02-11 16:57:00.97468Z
+49	24	2016-02-11 20:17:59.447972Z
+35	28	2016-02-23 04:36:52.471832Z
+38	29	2016-02-25 04:50:02.622635Z
+17	30	2016-02-26 22:22:32.159468Z
+40	30	2016-02-26 07:49:52.564129Z
+47	30	2016-02-26 17:17:41.884864Z
+3	31	2016-02-26 21:51:27.295182Z
+4	31	2016-02-26 20:52:43.271128Z
+8	31	2016-02-26 15:58:13.64098Z
+9	31	2016-02-27 04:05:02.940569Z
+10	31	2016-02-26 14:00:48.427972Z
+26	31	2016-02-26 13:42:55.453322Z
+28	31	2016-02-26 17:36:41.074158Z
+33	31	2016-02-26 15:30:37.667985Z
+34	31	2016-02-26 14:04:46.448288Z
+40	31	2016-02-26 13:59:01.218798Z
+41	31	2016-02-26 14:42:42.124741Z
+49	31	2016-02-26 14:22:29.518158Z
+20	32	2016-02-27 15:28:36.11207Z
+21	33	2016-02-27 04:55:46.186509Z
+18	34	2016-02-27 07:34:09.196204Z
+24	34	2016-02-27 07:42:39.455583Z
+30	34	2016-02-27 11:29:20.76855Z
+1	37	2016-03-04 03:59:57.806255Z
+3	37	2016-03-04 14:10:41.747321Z
+4	37	2016-03-04 08:51:51.572811Z
+8	37	2016-03-05 00:08:16.585507Z
+13	37	2016-03-04 10:45:06.781461Z
+16	37	2016-03-04 01:01:08.789242Z
+18	37	2016-03-04 08:54:57.747997Z
+24	37	2016-03-04 04:36:38.670283Z
+26	37	2016-03-04 01:45:57.595259Z
+39	37	2016-03-04 02:04:25.478939Z
+44	37	2016-03-04 04:02:14.118183Z
+46	37	2016-03-04 01:46:23.832513Z
+21	39	2016-03-06 02:16:53.83059Z
+16	40	2016-03-07 20:20:39.673121Z
+42	49	2016-03-17 03:05:03.308444Z
+46	50	2016-03-17 05:38:20.574905Z
+46	51	2016-03-18 06:22:41.50354Z
+6	52	2016-03-18 17:06:35.672704Z
+5	56	2016-03-23 16:51:53.1118Z
+44	56	2016-03-23 18:22:28.819446Z
+24	57	2016-03-25 22:16:44.120823Z
+14	58	2016-03-26 19:49:02.346864Z
+34	59	2016-03-29 06:53:02.854577Z
+6	60	2016-03-30 17:33:14.249898Z
+7	60	2016-03-30 05:07:42.736523Z
+43	60	2016-03-30 02:43:48.024599Z
+38	61	2016-04-02 10:41:10.918294Z
+15	65	2016-04-04 20:12:32.283725Z
+45	65	2016-04-05 04:37:46.548686Z
+4	66	2016-04-05 02:56:49.923791Z
+13	66	2016-04-04 20:01:04.258598Z
+14	66	2016-04-04 22:37:36.161038Z
+28	66	2016-04-05 08:20:45.271848Z
+30	66	2016-04-04 21:09:02.25907Z
+36	66	2016-04-05 00:13:30.137407Z
+40	66	2016-04-05 01:40:51.720899Z
+45	66	2016-04-05 07:39:10.376447Z
+37	71	2016-04-11 03:03:49.532553Z
+9	72	2016-04-13 13:11:31.082679Z
+14	74	2016-04-14 09:34:43.472676Z
+47	74	2016-04-14 17:31:13.277965Z
+20	76	2016-04-14 20:36:40.202309Z
+36	81	2016-04-25 17:37:04.93156Z
+3	83	2016-04-26 14:31:42.899131Z
+11	83	2016-04-26 14:34:54.367062Z
+21	83	2016-04-26 09:57:26.876944Z
+25	83	2016-04-26 07:21:52.405313Z
+48	83	2016-04-26 13:44:26.15719Z
+3	84	2016-04-26 16:28:47.444815Z
+12	84	2016-04-26 13:46:45.479664Z
+14	84	2016-04-26 13:33:27.405496Z
+22	84	2016-04-26 22:04:28.176116Z
+25	84	2016-04-26 13:03:06.808626Z
+26	84	2016-04-26 18:46:32.310923Z
+40	84	2016-04-26 13:42:46.121827Z
+43	84	2016-04-26 12:52:35.493254Z
+5	88	2016-05-04 01:35:42.231663Z
+13	88	2016-05-03 18:52:28.636937Z
+16	88	2016-05-04 11:53:41.347497Z
+17	88	2016-05-04 07:29:18.550028Z
+23	88	2016-05-04 03:31:04.107496Z
+28	88	2016-05-03 21:00:24.133509Z
+32	88	2016-05-03 18:45:38.31633Z
+36	88	2016-05-04 02:02:48.562492Z
+39	88	2016-05-04 00:11:14.415723Z
+40	88	2016-05-03 20:56:44.955088Z
+14	89	2016-05-04 21:46:42.840749Z
+41	89	2016-05-04 20:41:11.3528Z
+2	90	2016-05-06 09:11:07.081227Z
+9	90	2016-05-06 18:47:36.007488Z
+11	90	2016-05-06 23:40:28.587094Z
+13	90	2016-05-06 16:02:03.114922Z
+16	90	2016-05-06 13:14:37.753279Z
+17	90	2016-05-06 08:21:10.535562Z
+18	90	2016-05-06 09:44:36.071681Z
+21	90	2016-05-06 09:48:32.804912Z
+22	90	2016-05-06 19:23:36.219163Z
+26	90	2016-05-06 10:13:55.584963Z
+33	90	2016-05-06 19:32:47.575733Z
+36	90	2016-05-06 19:34:10.383797Z
+49	90	2016-05-06 08:35:00.354918Z
+50	90	2016-05-07 06:44:29.521205Z
+44	93	2016-05-07 23:36:23.063875Z
+1	95	2016-05-11 14:17:44.309634Z
+11	95	2016-05-12 02:01:56.830852Z
+29	95	2016-05-11 18:41:01.008705Z
+37	95	2016-05-11 17:33:54.921537Z
+39	95	2016-05-11 17:39:23.469254Z
+42	95	2016-05-11 17:59:18.083312Z
+43	95	2016-05-12 07:58:37.581907Z
+49	95	2016-05-11 15:41:19.072564Z
+50	95	2016-05-11 17:38:15.832164Z
+27	100	2016-05-21 10:08:10.919835Z
+38	100	2016-05-21 09:55:00.868537Z
+14	101	2016-05-22 15:30:08.088234Z
+1	103	2016-05-27 03:44:04.115617Z
+9	109	2016-06-06 09:43:02.964729Z
+5	110	2016-06-15 02:14:27.089795Z
+12	110	2016-06-15 13:55:27.64699Z
+11	113	2016-06-17 01:30:37.950494Z
+37	113	2016-06-17 04:39:27.96001Z
+5	114	2016-06-17 06:22:43.211466Z
+14	115	2016-06-18 06:32:38.625761Z
+16	115	2016-06-17 06:53:23.549326Z
+24	115	2016-06-17 18:22:40.277293Z
+30	115	2016-06-17 10:17:52.089885Z
+43	115	2016-06-17 09:23:18.716175Z
+22	116	2016-06-19 15:16:15.792266Z
+35	116	2016-06-19 14:29:07.909521Z
+13	117	2016-06-22 05:54:12.157045Z
+37	117	2016-06-22 11:47:15.915061Z
+25	119	2016-06-23 08:52:08.164433Z
+47	119	2016-06-22 21:40:13.092658Z
+5	120	2016-06-24 00:37:19.825451Z
+18	120	2016-06-24 02:10:30.10475Z
+28	120	2016-06-23 22:30:58.185327Z
+30	120	2016-06-23 22:48:30.239736Z
+1	122	2016-06-26 19:13:26.467643Z
+7	122	2016-06-26 18:05:34.812584Z
+33	122	2016-06-26 20:12:00.450575Z
+49	122	2016-06-27 09:01:48.822852Z
+45	128	2016-07-05 22:08:00.833941Z
+5	133	2016-07-10 15:01:09.672043Z
+6	133	2016-07-10 15:13:59.675958Z
+8	133	2016-07-09 19:18:39.219793Z
+14	133	2016-07-09 19:07:26.702426Z
+17	133	2016-07-09 23:50:22.667901Z
+18	133	2016-07-09 22:13:17.539276Z
+24	133	2016-07-09 21:32:28.257282Z
+25	133	2016-07-09 19:21:02.591231Z
+27	133	2016-07-09 22:48:07.987918Z
+32	133	2016-07-09 20:09:12.321498Z
+36	133	2016-07-09 20:58:46.570741Z
+40	133	2016-07-09 19:19:42.438542Z
+44	133	2016-07-09 21:38:43.308098Z
+12	134	2016-07-12 12:49:52.088098Z
+22	134	2016-07-12 13:39:25.708026Z
+43	134	2016-07-12 16:44:08.934514Z
+49	134	2016-07-12 11:13:10.941457Z
+11	137	2016-07-20 08:54:24.682331Z
+14	137	2016-07-20 03:38:55.494741Z
+3	138	2016-07-21 14:06:19.82657Z
+20	138	2016-07-21 01:12:10.776169Z
+24	138	2016-07-21 16:11:47.061138Z
+13	139	2016-07-22 01:55:25.3538Z
+17	139	2016-07-21 21:36:22.215757Z
+8	140	2016-07-22 12:46:24.882188Z
+42	140	2016-07-22 17:50:21.442385Z
+25	143	2016-07-27 10:03:10.977199Z
+47	143	2016-07-27 08:07:56.727207Z
+25	144	2016-07-28 21:06:41.524403Z
+27	144	2016-07-29 01:22:31.163149Z
+28	144	2016-07-29 04:44:21.953687Z
+33	144	2016-07-28 21:18:35.644291Z
+45	145	2016-07-31 05:50:49.088357Z
+1	148	2016-08-03 06:42:35.455104Z
+13	148	2016-08-03 02:46:35.544086Z
+21	148	2016-08-03 03:10:58.828831Z
+27	148	2016-08-03 04:59:59.992252Z
+29	148	2016-08-03 06:25:26.705549Z
+32	148	2016-08-03 06:29:26.112419Z
+34	148	2016-08-03 10:41:49.346873Z
+41	148	2016-08-03 06:43:18.959617Z
+44	148	2016-08-03 03:55:47.34139Z
+45	148	2016-08-03 10:12:06.927897Z
+5	149	2016-08-03 19:48:12.243254Z
+27	149	2016-08-03 12:35:51.315342Z
+39	149	2016-08-03 15:40:38.216429Z
+45	149	2016-08-03 13:07:13.315822Z
+11	151	2016-08-04 04:29:04.191499Z
+47	151	2016-08-03 20:59:56.710312Z
+17	153	2016-08-05 20:38:31.322132Z
+17	155	2016-08-07 23:02:55.562267Z
+27	155	2016-08-07 06:30:29.667196Z
+34	155	2016-08-07 12:06:45.257034Z
+13	157	2016-08-10 02:26:12.776017Z
+17	157	2016-08-10 05:24:52.339088Z
+47	157	2016-08-10 14:05:28.467012Z
+14	158	2016-08-10 18:17:33.764005Z
+32	159	2016-08-13 01:52:58.695063Z
+21	160	2016-08-13 04:04:37.361009Z
+3	162	2016-08-15 11:40:33.701091Z
+17	162	2016-08-15 14:05:33.130859Z
+29	162	2016-08-15 12:31:44.02179Z
+41	162	2016-08-16 08:12:23.045544Z
+42	162	2016-08-15 13:45:01.658309Z
+16	163	2016-08-16 14:19:44.58292Z
+48	164	2016-08-21 14:05:40.2961Z
+12	165	2016-08-22 01:39:07.641572Z
+22	165	2016-08-22 04:12:21.133702Z
+35	165	2016-08-22 09:45:05.666827Z
+36	166	2016-08-22 17:50:34.357592Z
+3	167	2016-08-23 01:36:46.817006Z
+11	167	2016-08-23 10:44:04.25581Z
+27	167	2016-08-22 23:34:27.494627Z
+46	167	2016-08-23 04:14:25.753488Z
+5	172	2016-09-01 05:12:33.543818Z
+5	175	2016-09-05 01:03:49.439814Z
+9	176	2016-09-05 12:41:49.286013Z
+24	177	2016-09-07 03:47:42.35937Z
+11	178	2016-09-07 16:32:12.882698Z
+19	178	2016-09-07 05:59:38.896719Z
+36	178	2016-09-07 04:58:32.062773Z
+11	180	2016-09-08 23:51:28.054415Z
+19	180	2016-09-09 06:40:27.809686Z
+37	180	2016-09-08 20:36:45.847198Z
+1	181	2016-09-10 16:27:21.864015Z
+12	185	2016-09-16 22:17:30.956031Z
+16	185	2016-09-17 00:50:59.662538Z
+36	185	2016-09-17 00:04:51.833619Z
+42	185	2016-09-16 22:13:22.324409Z
+46	185	2016-09-16 18:23:00.088307Z
+47	185	2016-09-16 17:00:13.538713Z
+48	185	2016-09-16 20:07:18.630283Z
+49	185	2016-09-17 10:10:05.66508Z
+6	186	2016-09-18 15:53:44.074658Z
+32	186	2016-09-18 11:42:56.431489Z
+32	189	2016-09-21 10:17:50.057577Z
+46	189	2016-09-21 08:15:42.107377Z
+11	190	2016-09-21 03:48:16.364346Z
+20	190	2016-09-21 01:22:02.173262Z
+21	190	2016-09-21 13:18:39.494207Z
+31	190	2016-09-21 03:16:06.368125Z
+35	190	2016-09-21 01:31:46.627613Z
+37	190	2016-09-21 03:04:01.909384Z
+42	190	2016-09-21 05:45:51.434102Z
+46	190	2016-09-21 05:29:47.16016Z
+18	191	2016-09-22 15:03:06.489598Z
+25	191	2016-09-22 09:45:15.358847Z
+16	192	2016-09-22 14:29:23.039926Z
+4	195	2016-09-26 23:32:12.873494Z
+16	195	2016-09-27 03:31:52.286607Z
+30	195	2016-09-26 21:23:35.8069Z
+43	197	2016-09-27 04:19:33.069269Z
+23	199	2016-10-02 23:15:57.628204Z
+6	202	2016-10-08 21:43:54.632862Z
+37	202	2016-10-09 00:41:04.766239Z
+31	203	2016-10-08 18:51:57.032915Z
+1	208	2016-10-15 07:11:05.493745Z
+7	208	2016-10-15 12:33:12.797146Z
+27	208	2016-10-15 03:38:47.307412Z
+29	208	2016-10-14 23:15:56.286235Z
+33	208	2016-10-15 01:08:24.466357Z
+37	208	2016-10-15 22:01:14.073586Z
+29	209	2016-10-21 00:35:39.931355Z
+37	209	2016-10-20 23:52:30.058297Z
+47	210	2016-10-22 16:03:19.63529Z
+39	218	2016-11-01 16:53:42.604085Z
+41	218	2016-11-01 14:34:28.797402Z
+14	219	2016-11-02 17:53:45.30653Z
+22	219	2016-11-02 20:55:11.467924Z
+23	220	2016-11-02 22:05:58.90699Z
+34	220	2016-11-02 19:54:01.667186Z
+20	222	2016-11-08 22:49:59.606847Z
+25	222	2016-11-09 03:52:51.239196Z
+27	222	2016-11-09 02:39:04.54031Z
+1	223	2016-11-11 23:44:57.181441Z
+10	223	2016-11-12 03:23:00.295347Z
+12	223	2016-11-12 07:53:24.809987Z
+47	223	2016-11-11 23:53:02.748406Z
+8	232	2016-11-28 20:16:16.218259Z
+12	232	2016-11-28 15:49:37.698637Z
+14	232	2016-11-28 16:31:44.490132Z
+22	232	2016-11-28 17:33:07.033738Z
+30	232	2016-11-28 22:55:23.274812Z
+31	232	2016-11-28 18:11:49.844582Z
+10	233	2016-11-29 16:25:59.804524Z
+17	233	2016-11-29 19:09:01.492919Z
+20	233	2016-11-30 03:03:11.925659Z
+34	233	2016-11-29 13:12:10.315806Z
+38	233	2016-11-29 15:12:59.202183Z
+3	234	2016-12-03 13:50:48.723354Z
+4	234	2016-12-03 14:37:53.365895Z
+6	234	2016-12-03 09:55:02.183973Z
+34	234	2016-12-03 13:27:45.812466Z
+8	235	2016-12-03 11:02:57.193226Z
+49	235	2016-12-03 15:29:31.519578Z
+2	236	2016-12-04 18:53:38.477559Z
+2	237	2016-12-10 12:23:36.427917Z
+1	242	2016-12-12 14:42:55.766371Z
+12	242	2016-12-12 16:51:30.416745Z
+27	242	2016-12-11 21:55:23.006137Z
+12	246	2016-12-18 20:23:15.112581Z
+21	246	2016-12-18 22:23:30.443004Z
+32	247	2016-12-20 04:04:15.207126Z
+6	248	2016-12-22 13:17:54.364854Z
+9	248	2016-12-22 08:30:17.244719Z
+12	248	2016-12-22 08:22:32.543879Z
+16	248	2016-12-22 09:43:37.426595Z
+30	248	2016-12-22 11:20:42.742621Z
+33	248	2016-12-22 08:32:40.91535Z
+39	248	2016-12-22 08:44:08.807432Z
+48	248	2016-12-22 08:09:59.18809Z
+21	250	2016-12-23 13:50:50.835288Z
+46	250	2016-12-23 14:09:35.186364Z
+47	250	2016-12-23 13:26:01.723051Z
+7	251	2016-12-24 15:11:06.839896Z
+50	251	2016-12-24 17:49:53.053934Z
+14	252	2016-12-25 15:33:43.97982Z
+43	252	2016-12-24 20:23:35.120412Z
+3	253	2016-12-27 01:56:32.900604Z
+4	253	2016-12-27 08:45:43.382176Z
+8	253	2016-12-26 20:59:42.675353Z
+12	253	2016-12-27 05:22:27.780859Z
+14	253	2016-12-27 02:12:20.877281Z
+31	253	2016-12-26 23:53:10.562903Z
+36	253	2016-12-26 23:38:25.735494Z
+38	253	2016-12-27 00:32:08.833729Z
+40	253	2016-12-27 07:49:30.84475Z
+43	253	2016-12-27 18:32:21.215332Z
+45	253	2016-12-27 04:35:20.157468Z
+47	253	2016-12-26 22:04:41.440241Z
+48	253	2016-12-27 04:04:52.636293Z
+50	253	2016-12-26 21:47:19.235141Z
+12	254	2016-12-28 09:01:50.552843Z
+23	254	2016-12-28 11:40:45.686021Z
+3	257	2016-12-29 20:24:28.757253Z
+8	257	2016-12-29 22:55:28.449542Z
+29	257	2016-12-30 02:34:05.215541Z
+35	257	2016-12-30 05:53:41.08676Z
+39	257	2016-12-30 16:08:36.78558Z
+41	257	2016-12-30 07:49:49.471149Z
+\.
+
+COPY post_nos (author_id, post_id, created_at) FROM stdin;
+35	4	2016-01-05 08:52:25.721954Z
+7	11	2016-01-11 06:51:51.686565Z
+2	13	2016-01-15 07:26:24.389207Z
+6	16	2016-01-25 11:40:15.182312Z
+42	18	2016-01-29 08:23:50.250691Z
+13	20	2016-02-05 14:51:37.250253Z
+22	21	2016-02-07 01:38:21.23575Z
+4	26	2016-02-16 08:10:27.812105Z
+22	26	2016-02-16 07:36:13.81347Z
+8	28	2016-02-21 18:39:36.738554Z
+32	30	2016-02-26 19:03:09.241724Z
+47	30	2016-02-26 19:54:31.71593Z
+12	60	2016-03-30 05:00:51.679171Z
+46	62	2016-04-02 09:27:08.760859Z
+5	76	2016-04-15 09:45:52.042935Z
+6	76	2016-04-15 01:58:14.21861Z
+1	80	2016-04-23 18:36:49.519003Z
+46	81	2016-04-25 10:07:47.604883Z
+23	83	2016-04-26 11:24:25.192804Z
+1	89	2016-05-04 20:54:14.421231Z
+47	89	2016-05-05 01:48:25.537174Z
+14	90	2016-05-06 12:52:37.503668Z
+21	90	2016-05-06 11:13:37.506698Z
+30	97	2016-05-15 07:59:46.033954Z
+2	121	2016-06-24 02:06:54.826488Z
+18	133	2016-07-10 03:09:50.370226Z
+29	133	2016-07-09 23:22:01.788917Z
+48	133	2016-07-09 19:23:16.739008Z
+19	140	2016-07-22 23:49:06.284461Z
+20	140	2016-07-22 17:59:30.112632Z
+39	140	2016-07-22 16:50:10.341956Z
+34	142	2016-07-26 15:50:23.646525Z
+15	143	2016-07-27 00:12:50.11954Z
+31	144	2016-07-28 11:51:56.926158Z
+33	151	2016-08-03 20:58:02.289539Z
+37	151	2016-08-04 01:02:09.523354Z
+16	165	2016-08-22 07:29:25.337932Z
+8	181	2016-09-11 01:07:19.000177Z
+14	185	2016-09-17 01:07:22.354884Z
+20	185	2016-09-17 01:07:55.974771Z
+22	185	2016-09-16 20:53:04.637279Z
+29	185	2016-09-16 19:59:33.444839Z
+5	190	2016-09-21 01:44:47.141683Z
+15	190	2016-09-21 00:46:42.412272Z
+21	190	2016-09-21 04:07:09.590852Z
+24	190	2016-09-21 05:06:15.58918Z
+44	190	2016-09-21 10:26:30.89635Z
+45	190	2016-09-21 07:21:46.836551Z
+3	193	2016-09-22 21:01:13.288054Z
+7	193	2016-09-23 00:30:09.607476Z
+13	208	2016-10-16 02:47:43.477501Z
+8	222	2016-11-08 20:17:04.027883Z
+6	234	2016-12-03 08:30:15.319476Z
+13	234	2016-12-03 07:45:15.607991Z
+15	234	2016-12-03 09:16:44.749438Z
+21	234	2016-12-03 12:14:46.778837Z
+32	234	2016-12-03 18:44:31.235358Z
+40	234	2016-12-03 18:55:41.0046Z
+45	234	2016-12-03 18:10:59.352292Z
+49	234	2016-12-03 11:43:51.562605Z
+44	242	2016-12-11 15:32:55.517412Z
+44	248	2016-12-22 08:15:09.149508Z
+5	257	2016-12-29 18:10:49.291619Z
+31	257	2016-12-29 20:47:57.145277Z
+46	257	2016-12-29 20:28:37.600034Z
+\.
+
+SELECT
+    setval('users_id_seq', (SELECT COALESCE(MAX(id), 0) + 1 FROM users), false),
+    setval('posts_id_seq', (SELECT COALESCE(MAX(id), 0) + 1 FROM posts), false);
+
+INSERT INTO timelines (user_id, post_id, author_id, created_at)
+    SELECT followers.follower_id, posts.id, posts.author_id, posts.created_at
+    FROM posts INNER JOIN followers ON followers.followee_id=posts.author_id
+    ON CONFLICT DO NOTHING;
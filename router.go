@@ -2,68 +2,347 @@ package main
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/boxtown/meirl/api"
+	"github.com/boxtown/meirl/config"
 	"github.com/boxtown/meirl/data"
+	"github.com/boxtown/meirl/docs"
+	"github.com/boxtown/meirl/ratelimit"
+	"github.com/boxtown/meirl/realtime"
 	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
-// Router initializes an http.Handler that routes requests to the proper
+// Per-route rate limits. Authenticated routes are limited per user,
+// all others per client IP
+var (
+	defaultRate = ratelimit.Rate{Limit: 300, Period: time.Minute}
+	signupRate  = ratelimit.Rate{Limit: 5, Period: time.Hour}
+	loginRate   = ratelimit.Rate{Limit: 10, Period: time.Minute}
+	followRate  = ratelimit.Rate{Limit: 60, Period: time.Minute}
+	postRate    = ratelimit.Rate{Limit: 30, Period: time.Minute}
+	reactRate   = ratelimit.Rate{Limit: 120, Period: time.Minute}
+	searchRate  = ratelimit.Rate{Limit: 60, Period: time.Minute}
+)
+
+// services holds the long-lived dependencies shared
+// by MeIRL request handlers
+type services struct {
+	stores  data.Stores
+	health  *api.HealthAPI
+	limiter ratelimit.Store
+	events  realtime.Publisher
+	stream  *api.StreamAPI
+	socket  *api.SocketAPI
+}
+
+// Router initializes a router that routes requests to the proper
 // MeIRL request handlers
-func Router(stores data.Stores) http.Handler {
+func Router(cfg *config.Config, svc services) *mux.Router {
 	r := mux.NewRouter()
-	initUserRoutes(r, stores)
-	initPostRoutes(r, stores)
+	signingKey := []byte(cfg.SigningKey)
+	initUserRoutes(r, svc, signingKey, cfg.Debug())
+	initPostRoutes(r, svc, signingKey, cfg.Debug())
+	initNotificationRoutes(r, svc, signingKey, cfg.Debug())
+	initWebhookRoutes(r, svc, signingKey, cfg.Debug())
+	initSearchRoutes(r, svc, signingKey, cfg.Debug())
+	initOpsRoutes(r, svc)
 	return r
 }
 
-func initUserRoutes(r *mux.Router, stores data.Stores) {
-	userAPI := api.NewUserAPI(stores, api.NewAuth(), debug())
+// limit wraps the handler in a rate limiter namespaced by name
+func limit(svc services, name string, rate ratelimit.Rate, next http.HandlerFunc) http.HandlerFunc {
+	return api.RateLimitMiddleware(svc.limiter, name, rate, next)
+}
+
+func initUserRoutes(r *mux.Router, svc services, signingKey []byte, debug bool) {
+	userAPI := api.NewUserAPI(svc.stores, api.NewAuth(), svc.events, debug)
 	r.HandleFunc(
 		api.PrefixAPIPath("user/{id:[0-9]+}"),
-		api.GetIDMiddleware(userAPI.GetUser()),
+		limit(svc, "user.get", defaultRate, api.GetIDMiddleware(userAPI.GetUser())),
 	).Methods("GET")
 
 	r.HandleFunc(
 		api.PrefixAPIPath("user/me"),
-		api.GetClaimsMiddleware(signingKey, userAPI.GetMe()),
+		api.GetClaimsMiddleware(signingKey, svc.stores.UserStore, limit(svc, "user.me", defaultRate, userAPI.GetMe())),
+	).Methods("GET")
+
+	r.HandleFunc(
+		api.PrefixAPIPath("user/me/feed/stream"),
+		api.GetClaimsMiddleware(signingKey, svc.stores.UserStore, limit(svc, "user.stream", defaultRate, svc.stream.FeedStream())),
+	).Methods("GET")
+
+	r.HandleFunc(
+		api.PrefixAPIPath("user/me/socket"),
+		api.GetSocketClaimsMiddleware(signingKey, svc.stores.UserStore, limit(svc, "user.socket", defaultRate, svc.socket.Connect())),
 	).Methods("GET")
 
 	r.HandleFunc(
 		api.PrefixAPIPath("user/{id:[0-9]+}/feed"),
-		api.GetIDMiddleware(userAPI.GetFeed()),
+		api.GetIDMiddleware(api.GetClaimsMiddleware(signingKey, svc.stores.UserStore,
+			limit(svc, "user.feed", defaultRate, userAPI.GetFeed()),
+		)),
+	).Methods("GET")
+
+	r.HandleFunc(
+		api.PrefixAPIPath("user/{id:[0-9]+}/posts"),
+		api.GetIDMiddleware(api.GetOptionalClaimsMiddleware(signingKey, svc.stores.UserStore,
+			limit(svc, "user.posts", defaultRate, userAPI.GetUserPosts()),
+		)),
 	).Methods("GET")
 
 	r.HandleFunc(
 		api.PrefixAPIPath("user/new"),
-		userAPI.CreateUser(),
+		limit(svc, "user.new", signupRate, userAPI.CreateUser()),
 	).Methods("POST")
 
 	r.HandleFunc(
 		api.PrefixAPIPath("user/login"),
-		userAPI.Login(signingKey),
+		limit(svc, "user.login", loginRate, userAPI.Login(signingKey)),
 	).Methods("POST")
 
 	r.HandleFunc(
 		api.PrefixAPIPath("user/{id:[0-9]+}/followers"),
-		api.GetIDMiddleware(api.GetClaimsMiddleware(signingKey, userAPI.FollowUser())),
+		api.GetIDMiddleware(api.GetClaimsMiddleware(signingKey, svc.stores.UserStore,
+			limit(svc, "user.follow", followRate, userAPI.FollowUser()),
+		)),
 	).Methods("POST")
 
+	r.HandleFunc(
+		api.PrefixAPIPath("user/{id:[0-9]+}/followers"),
+		api.GetIDMiddleware(api.GetClaimsMiddleware(signingKey, svc.stores.UserStore,
+			limit(svc, "user.unfollow", followRate, userAPI.UnFollowUser()),
+		)),
+	).Methods("DELETE")
+
+	r.HandleFunc(
+		api.PrefixAPIPath("user/me/followers/requests"),
+		api.GetClaimsMiddleware(signingKey, svc.stores.UserStore, limit(svc, "user.followrequests", defaultRate, userAPI.ListFollowRequests())),
+	).Methods("GET")
+
+	r.HandleFunc(
+		api.PrefixAPIPath("user/me/followers/requests/{id:[0-9]+}"),
+		api.GetIDMiddleware(api.GetClaimsMiddleware(signingKey, svc.stores.UserStore,
+			limit(svc, "user.approvefollow", followRate, userAPI.ApproveFollowRequest()),
+		)),
+	).Methods("POST")
+
+	r.HandleFunc(
+		api.PrefixAPIPath("user/me/followers/requests/{id:[0-9]+}"),
+		api.GetIDMiddleware(api.GetClaimsMiddleware(signingKey, svc.stores.UserStore,
+			limit(svc, "user.rejectfollow", followRate, userAPI.RejectFollowRequest()),
+		)),
+	).Methods("DELETE")
+
+	r.HandleFunc(
+		api.PrefixAPIPath("user/me/privacy"),
+		api.GetClaimsMiddleware(signingKey, svc.stores.UserStore, limit(svc, "user.privacy", defaultRate, userAPI.SetPrivacy())),
+	).Methods("PUT")
+
+	relationships := []struct {
+		path          string
+		name          string
+		create, clear http.HandlerFunc
+	}{
+		{"user/{id:[0-9]+}/block", "block", userAPI.BlockUser(), userAPI.UnBlockUser()},
+		{"user/{id:[0-9]+}/mute", "mute", userAPI.MuteUser(), userAPI.UnMuteUser()},
+	}
+	for _, relationship := range relationships {
+		r.HandleFunc(
+			api.PrefixAPIPath(relationship.path),
+			api.GetIDMiddleware(api.GetClaimsMiddleware(signingKey, svc.stores.UserStore,
+				limit(svc, "user."+relationship.name, followRate, relationship.create),
+			)),
+		).Methods("POST")
+
+		r.HandleFunc(
+			api.PrefixAPIPath(relationship.path),
+			api.GetIDMiddleware(api.GetClaimsMiddleware(signingKey, svc.stores.UserStore,
+				limit(svc, "user.un"+relationship.name, followRate, relationship.clear),
+			)),
+		).Methods("DELETE")
+	}
+
+	r.HandleFunc(
+		api.PrefixAPIPath("user/me/blocked"),
+		api.GetClaimsMiddleware(signingKey, svc.stores.UserStore, limit(svc, "user.blocked", defaultRate, userAPI.ListBlocked())),
+	).Methods("GET")
+
+	r.HandleFunc(
+		api.PrefixAPIPath("user/me/muted"),
+		api.GetClaimsMiddleware(signingKey, svc.stores.UserStore, limit(svc, "user.muted", defaultRate, userAPI.ListMuted())),
+	).Methods("GET")
+
 	r.HandleFunc(
 		api.PrefixAPIPath("user/{id:[0-9]+}"),
-		api.GetIDMiddleware(userAPI.DeleteUser()),
+		limit(svc, "user.delete", defaultRate, api.GetIDMiddleware(userAPI.DeleteUser())),
 	).Methods("DELETE")
 }
 
-func initPostRoutes(r *mux.Router, stores data.Stores) {
-	postAPI := api.NewPostAPI(stores, debug())
+func initPostRoutes(r *mux.Router, svc services, signingKey []byte, debug bool) {
+	postAPI := api.NewPostAPI(svc.stores, svc.events, debug)
 	r.HandleFunc(
 		api.PrefixAPIPath("post/{id:[0-9]+}"),
-		api.GetIDMiddleware(postAPI.GetPost()),
+		api.GetIDMiddleware(api.GetOptionalClaimsMiddleware(signingKey, svc.stores.UserStore,
+			limit(svc, "post.get", defaultRate, postAPI.GetPost()),
+		)),
+	).Methods("GET")
+
+	r.HandleFunc(
+		api.PrefixAPIPath("post/{id:[0-9]+}/thread"),
+		api.GetIDMiddleware(api.GetOptionalClaimsMiddleware(signingKey, svc.stores.UserStore,
+			limit(svc, "post.thread", defaultRate, postAPI.GetThread()),
+		)),
 	).Methods("GET")
 
 	r.HandleFunc(
 		api.PrefixAPIPath("post/new"),
-		api.GetClaimsMiddleware(signingKey, postAPI.CreatePost()),
+		api.GetClaimsMiddleware(signingKey, svc.stores.UserStore, limit(svc, "post.new", postRate, postAPI.CreatePost())),
+	).Methods("POST")
+
+	r.HandleFunc(
+		api.PrefixAPIPath("explore"),
+		limit(svc, "post.explore", defaultRate, postAPI.Explore()),
+	).Methods("GET")
+
+	r.HandleFunc(
+		api.PrefixAPIPath("trending"),
+		limit(svc, "post.trending", defaultRate, postAPI.Trending()),
+	).Methods("GET")
+
+	r.HandleFunc(
+		api.PrefixAPIPath("tag/{tag:[0-9a-zA-Z_]+}/posts"),
+		limit(svc, "post.tagged", defaultRate, postAPI.TaggedPosts()),
+	).Methods("GET")
+
+	r.HandleFunc(
+		api.PrefixAPIPath("user/me/mentions"),
+		api.GetClaimsMiddleware(signingKey, svc.stores.UserStore, limit(svc, "post.mentions", defaultRate, postAPI.Mentions())),
+	).Methods("GET")
+
+	r.HandleFunc(
+		api.PrefixAPIPath("post/{id:[0-9]+}/reposts"),
+		api.GetIDMiddleware(api.GetClaimsMiddleware(signingKey, svc.stores.UserStore,
+			limit(svc, "post.repost", reactRate, postAPI.Repost()),
+		)),
+	).Methods("POST")
+
+	r.HandleFunc(
+		api.PrefixAPIPath("post/{id:[0-9]+}/reposts"),
+		api.GetIDMiddleware(api.GetClaimsMiddleware(signingKey, svc.stores.UserStore,
+			limit(svc, "post.unrepost", reactRate, postAPI.UnRepost()),
+		)),
+	).Methods("DELETE")
+
+	reactions := []struct {
+		path     string
+		reaction data.Reaction
+	}{
+		{"post/{id:[0-9]+}/keks", data.ReactionKek},
+		{"post/{id:[0-9]+}/nos", data.ReactionNo},
+	}
+	for _, reaction := range reactions {
+		r.HandleFunc(
+			api.PrefixAPIPath(reaction.path),
+			api.GetIDMiddleware(api.GetClaimsMiddleware(signingKey, svc.stores.UserStore,
+				limit(svc, "post.react", reactRate, postAPI.React(reaction.reaction)),
+			)),
+		).Methods("POST")
+
+		r.HandleFunc(
+			api.PrefixAPIPath(reaction.path),
+			api.GetIDMiddleware(api.GetClaimsMiddleware(signingKey, svc.stores.UserStore,
+				limit(svc, "post.unreact", reactRate, postAPI.UnReact(reaction.reaction)),
+			)),
+		).Methods("DELETE")
+	}
+}
+
+func initNotificationRoutes(r *mux.Router, svc services, signingKey []byte, debug bool) {
+	notificationAPI := api.NewNotificationAPI(svc.stores, debug)
+	r.HandleFunc(
+		api.PrefixAPIPath("user/me/notifications"),
+		api.GetClaimsMiddleware(signingKey, svc.stores.UserStore, limit(svc, "notifications.list", defaultRate, notificationAPI.ListNotifications())),
+	).Methods("GET")
+
+	r.HandleFunc(
+		api.PrefixAPIPath("user/me/notifications/unread"),
+		api.GetClaimsMiddleware(signingKey, svc.stores.UserStore, limit(svc, "notifications.unread", defaultRate, notificationAPI.UnreadCount())),
+	).Methods("GET")
+
+	r.HandleFunc(
+		api.PrefixAPIPath("user/me/notifications/read"),
+		api.GetClaimsMiddleware(signingKey, svc.stores.UserStore, limit(svc, "notifications.read", defaultRate, notificationAPI.MarkRead())),
+	).Methods("POST")
+
+	r.HandleFunc(
+		api.PrefixAPIPath("user/me/notifications/preferences"),
+		api.GetClaimsMiddleware(signingKey, svc.stores.UserStore, limit(svc, "notifications.preferences", defaultRate, notificationAPI.GetPreferences())),
+	).Methods("GET")
+
+	r.HandleFunc(
+		api.PrefixAPIPath("user/me/notifications/preferences"),
+		api.GetClaimsMiddleware(signingKey, svc.stores.UserStore, limit(svc, "notifications.preferences", defaultRate, notificationAPI.SetPreferences())),
+	).Methods("PUT")
+}
+
+func initWebhookRoutes(r *mux.Router, svc services, signingKey []byte, debug bool) {
+	webhookAPI := api.NewWebhookAPI(svc.stores, debug)
+	r.HandleFunc(
+		api.PrefixAPIPath("webhook/new"),
+		api.GetClaimsMiddleware(signingKey, svc.stores.UserStore, limit(svc, "webhook.new", defaultRate, webhookAPI.CreateWebhook())),
+	).Methods("POST")
+
+	r.HandleFunc(
+		api.PrefixAPIPath("user/me/webhooks"),
+		api.GetClaimsMiddleware(signingKey, svc.stores.UserStore, limit(svc, "webhook.list", defaultRate, webhookAPI.ListWebhooks())),
+	).Methods("GET")
+
+	r.HandleFunc(
+		api.PrefixAPIPath("webhook/{id:[0-9]+}"),
+		api.GetIDMiddleware(api.GetClaimsMiddleware(signingKey, svc.stores.UserStore,
+			limit(svc, "webhook.delete", defaultRate, webhookAPI.DeleteWebhook()),
+		)),
+	).Methods("DELETE")
+
+	r.HandleFunc(
+		api.PrefixAPIPath("webhook/{id:[0-9]+}/deliveries"),
+		api.GetIDMiddleware(api.GetClaimsMiddleware(signingKey, svc.stores.UserStore,
+			limit(svc, "webhook.deliveries", defaultRate, webhookAPI.ListDeliveries()),
+		)),
+	).Methods("GET")
+
+	r.HandleFunc(
+		api.PrefixAPIPath("webhook/delivery/{id:[0-9]+}/redeliver"),
+		api.GetIDMiddleware(api.GetClaimsMiddleware(signingKey, svc.stores.UserStore,
+			limit(svc, "webhook.redeliver", defaultRate, webhookAPI.Redeliver()),
+		)),
 	).Methods("POST")
 }
+
+func initSearchRoutes(r *mux.Router, svc services, signingKey []byte, debug bool) {
+	searchAPI := api.NewSearchAPI(svc.stores, debug)
+	r.HandleFunc(
+		api.PrefixAPIPath("search/posts"),
+		api.GetOptionalClaimsMiddleware(signingKey, svc.stores.UserStore, limit(svc, "search.posts", searchRate, searchAPI.SearchPosts())),
+	).Methods("GET")
+
+	r.HandleFunc(
+		api.PrefixAPIPath("search/users"),
+		limit(svc, "search.users", searchRate, searchAPI.SearchUsers()),
+	).Methods("GET")
+
+	r.HandleFunc(
+		api.PrefixAPIPath("search/users/autocomplete"),
+		limit(svc, "search.autocomplete", defaultRate, searchAPI.AutocompleteUsers()),
+	).Methods("GET")
+}
+
+func initOpsRoutes(r *mux.Router, svc services) {
+	r.HandleFunc("/healthz", svc.health.Live()).Methods("GET")
+	r.HandleFunc("/readyz", svc.health.Ready()).Methods("GET")
+	r.Handle("/metrics", promhttp.Handler()).Methods("GET")
+	r.HandleFunc(docs.SpecPath, docs.SpecHandler(api.Version, docs.Routes)).Methods("GET")
+	r.HandleFunc(docs.UIPath, docs.UIHandler()).Methods("GET")
+}
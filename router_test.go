@@ -0,0 +1,66 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/boxtown/meirl/api"
+	"github.com/boxtown/meirl/config"
+	"github.com/boxtown/meirl/data"
+	"github.com/boxtown/meirl/docs"
+	"github.com/boxtown/meirl/ratelimit"
+	"github.com/boxtown/meirl/realtime"
+	"github.com/gorilla/mux"
+)
+
+func testRouter() *mux.Router {
+	return Router(&config.Config{SigningKey: "test"}, services{
+		health:  api.NewHealthAPI(time.Second),
+		limiter: ratelimit.NewMemoryStore(),
+		events:  realtime.Discard,
+		stream:  api.NewStreamAPI(data.Stores{}, realtime.NewBus(), false),
+		socket:  api.NewSocketAPI(data.Stores{}, realtime.NewBus(), false),
+	})
+}
+
+// Every registered route must be documented in docs.Routes
+// and every documented route must be registered
+func TestRoutesAreDocumented(t *testing.T) {
+	documented := make(map[string]bool)
+	for _, route := range docs.Routes {
+		documented[route.Method+" "+route.Path] = true
+	}
+
+	registered := make(map[string]bool)
+	err := testRouter().Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		if err != nil {
+			return err
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			t.Errorf("Route %s must be restricted to methods", path)
+			t.Fail()
+			return nil
+		}
+		for _, method := range methods {
+			key := method + " " + path
+			registered[key] = true
+			if !documented[key] {
+				t.Errorf("Route %s is not documented in docs.Routes", key)
+				t.Fail()
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Error(err.Error())
+		t.FailNow()
+	}
+	for key := range documented {
+		if !registered[key] {
+			t.Errorf("Documented route %s is not registered", key)
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package webhook
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/boxtown/meirl/data"
+	"github.com/boxtown/meirl/realtime"
+)
+
+// Webhook events
+const (
+	// EventPostCreated is delivered when a post is created
+	EventPostCreated = "post.created"
+
+	// EventUserFollowed is delivered when a user follows another
+	EventUserFollowed = "user.followed"
+
+	// EventPostReacted is delivered when a user reacts to a
+	// post or removes a reaction
+	EventPostReacted = "post.reacted"
+)
+
+// Events are the events webhooks may subscribe to
+var Events = []string{EventPostCreated, EventUserFollowed, EventPostReacted}
+
+// eventNames maps realtime event types to webhook events
+var eventNames = map[string]string{
+	realtime.PostCreated:          EventPostCreated,
+	realtime.UserFollowed:         EventUserFollowed,
+	realtime.PostReactionsChanged: EventPostReacted,
+}
+
+// IsEvent returns true if webhooks may subscribe to the event
+func IsEvent(event string) bool {
+	for _, e := range Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Payload is the JSON body of a delivery. Only the
+// IDs relevant to the event are set
+type Payload struct {
+	Event      string `json:"event"`
+	Timestamp  int64  `json:"timestamp"`
+	PostID     int64  `json:"postID,omitempty"`
+	AuthorID   int64  `json:"authorID,omitempty"`
+	UserID     int64  `json:"userID,omitempty"`
+	FollowerID int64  `json:"followerID,omitempty"`
+	FolloweeID int64  `json:"followeeID,omitempty"`
+}
+
+// Dispatcher is a realtime.Publisher that queues a delivery of each
+// published event to every webhook subscribed to it. User webhooks are
+// only sent the events their user is involved in: their own posts, their
+// follows and followers, and reactions by them or to their posts
+type Dispatcher struct {
+	stores data.Stores
+	now    func() time.Time
+}
+
+// NewDispatcher returns a newly constructed Dispatcher
+func NewDispatcher(stores data.Stores) *Dispatcher {
+	return &Dispatcher{
+		stores: stores,
+		now:    time.Now,
+	}
+}
+
+// Publish queues deliveries of the event. Events webhooks may not
+// subscribe to are ignored, and an event relayed again is not
+// queued for the webhooks it was already queued for
+func (d *Dispatcher) Publish(e realtime.Event) error {
+	event, ok := eventNames[e.Type]
+	if !ok {
+		return nil
+	}
+	payload := Payload{Event: event, Timestamp: d.now().Unix()}
+	var users []int64
+	switch e.Type {
+	case realtime.PostCreated:
+		payload.PostID, payload.AuthorID = e.ID, e.UserID
+		users = []int64{e.UserID}
+	case realtime.UserFollowed:
+		payload.FollowerID, payload.FolloweeID = e.UserID, e.ID
+		users = []int64{e.UserID, e.ID}
+	case realtime.PostReactionsChanged:
+		payload.PostID, payload.UserID = e.ID, e.UserID
+		users = []int64{e.UserID}
+		post, err := d.stores.PostStore.Get(e.ID)
+		if err == data.ErrNoEnt {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		payload.AuthorID = post.AuthorID
+		users = append(users, post.AuthorID)
+	}
+
+	webhooks, err := d.stores.WebhookStore.Subscribed(event, users)
+	if err != nil || len(webhooks) == 0 {
+		return err
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	for _, webhook := range webhooks {
+		_, err := d.stores.WebhookStore.CreateDelivery(&data.WebhookDelivery{
+			WebhookID: webhook.ID,
+			EventID:   e.EventID,
+			Event:     event,
+			Payload:   body,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+// Package webhook delivers events to the HTTP endpoints registered
+// by users and admins. Deliveries are signed with HMAC-SHA256 using
+// the secret of the webhook and retried with exponential backoff
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Delivery request headers
+const (
+	EventHeader     = "X-MeIRL-Event"
+	DeliveryHeader  = "X-MeIRL-Delivery"
+	TimestampHeader = "X-MeIRL-Timestamp"
+	SignatureHeader = "X-MeIRL-Signature"
+)
+
+// signaturePrefix names the signature algorithm
+const signaturePrefix = "sha256="
+
+// Errors returned by Verify
+var (
+	ErrBadSignature = errors.New("webhook signature does not match")
+	ErrExpired      = errors.New("webhook timestamp is outside the tolerance")
+)
+
+// Sign returns the signature of a delivery body sent at the given time
+// in seconds since the epoch. The signature is the hex encoded
+// HMAC-SHA256 of the timestamp, a period and the body, keyed by
+// the secret of the webhook
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte{'.'})
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature headers of a delivery received with
+// the given body. Deliveries signed more than tolerance from now are
+// rejected to limit replays. Receivers implemented in Go may use
+// Verify directly
+func Verify(secret string, header http.Header, body []byte, tolerance time.Duration) error {
+	timestamp, err := strconv.ParseInt(header.Get(TimestampHeader), 10, 64)
+	if err != nil {
+		return ErrBadSignature
+	}
+	signature := header.Get(SignatureHeader)
+	if !hmac.Equal([]byte(signature), []byte(Sign(secret, timestamp, body))) {
+		return ErrBadSignature
+	}
+	age := time.Since(time.Unix(timestamp, 0))
+	if age > tolerance || age < -tolerance {
+		return ErrExpired
+	}
+	return nil
+}
+
+// NewSecret returns a random secret for signing deliveries
+func NewSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// ValidateURL returns an error if the raw URL is not
+// an absolute http or https URL
+func ValidateURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("webhook URL must be an absolute http or https URL")
+	}
+	return nil
+}
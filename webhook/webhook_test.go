@@ -0,0 +1,438 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/boxtown/meirl/data"
+	"github.com/boxtown/meirl/realtime"
+	"github.com/uber-go/zap"
+)
+
+// memoryStore is an in memory data.WebhookStore
+// implementing the methods used by this package
+type memoryStore struct {
+	data.WebhookStore
+
+	mu         sync.Mutex
+	webhooks   map[int64]*data.Webhook
+	deliveries []data.WebhookDelivery
+	subscribed []int64
+}
+
+func newMemoryStore(webhooks ...data.Webhook) *memoryStore {
+	store := &memoryStore{webhooks: map[int64]*data.Webhook{}}
+	for i := range webhooks {
+		store.webhooks[webhooks[i].ID] = &webhooks[i]
+	}
+	return store
+}
+
+func (store *memoryStore) Get(id int64) (*data.Webhook, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	webhook, ok := store.webhooks[id]
+	if !ok {
+		return nil, data.ErrNoEnt
+	}
+	return webhook, nil
+}
+
+func (store *memoryStore) Subscribed(event string, userIDs []int64) ([]data.Webhook, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.subscribed = userIDs
+	var webhooks []data.Webhook
+	for _, webhook := range store.webhooks {
+		if webhook.UserID != nil && !contains(userIDs, *webhook.UserID) {
+			continue
+		}
+		for _, e := range webhook.Events {
+			if e == event {
+				webhooks = append(webhooks, *webhook)
+			}
+		}
+	}
+	return webhooks, nil
+}
+
+func (store *memoryStore) CreateDelivery(delivery *data.WebhookDelivery) (int64, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	for _, d := range store.deliveries {
+		if delivery.EventID != 0 && d.WebhookID == delivery.WebhookID && d.EventID == delivery.EventID {
+			return d.ID, nil
+		}
+	}
+	d := *delivery
+	d.ID = int64(len(store.deliveries) + 1)
+	d.Status = data.DeliveryPending
+	store.deliveries = append(store.deliveries, d)
+	return d.ID, nil
+}
+
+func (store *memoryStore) ClaimDeliveries(limit int, lease time.Duration) ([]data.WebhookDelivery, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	var claimed []data.WebhookDelivery
+	for _, d := range store.deliveries {
+		if d.Status == data.DeliveryPending && len(claimed) < limit {
+			claimed = append(claimed, d)
+		}
+	}
+	return claimed, nil
+}
+
+func (store *memoryStore) UpdateDelivery(delivery *data.WebhookDelivery) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.deliveries[delivery.ID-1] = *delivery
+	return nil
+}
+
+func (store *memoryStore) delivery(id int64) data.WebhookDelivery {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	return store.deliveries[id-1]
+}
+
+type postStore struct {
+	data.PostStore
+	authorID int64
+}
+
+func (store postStore) Get(id int64) (*data.Post, error) {
+	return &data.Post{Mutable: data.Mutable{AutoIncr: data.AutoIncr{ID: id}}, AuthorID: store.authorID}, nil
+}
+
+func contains(ids []int64, id int64) bool {
+	for _, x := range ids {
+		if x == id {
+			return true
+		}
+	}
+	return false
+}
+
+// newTestWorker allows private addresses so
+// that httptest servers may receive deliveries
+func newTestWorker(store data.WebhookStore) *Worker {
+	return NewWorker(store, true, zap.New(zap.NewTextEncoder()))
+}
+
+func TestSignAndVerify(t *testing.T) {
+	body := []byte(`{"event":"post.created"}`)
+	now := time.Now().Unix()
+	header := http.Header{}
+	header.Set(TimestampHeader, "1")
+	header.Set(SignatureHeader, Sign("secret", now, body))
+	if err := Verify("secret", header, body, time.Minute); err != ErrBadSignature {
+		t.Errorf("Expected %v for mismatched timestamp, got %v", ErrBadSignature, err)
+		t.Fail()
+	}
+
+	header.Set(TimestampHeader, "invalid")
+	if err := Verify("secret", header, body, time.Minute); err != ErrBadSignature {
+		t.Errorf("Expected %v for invalid timestamp, got %v", ErrBadSignature, err)
+		t.Fail()
+	}
+
+	header.Set(TimestampHeader, strconv.FormatInt(now, 10))
+	if err := Verify("secret", header, body, time.Minute); err != nil {
+		t.Error(err.Error())
+		t.Fail()
+	}
+	if err := Verify("other", header, body, time.Minute); err != ErrBadSignature {
+		t.Errorf("Expected %v for wrong secret, got %v", ErrBadSignature, err)
+		t.Fail()
+	}
+	if err := Verify("secret", header, []byte("{}"), time.Minute); err != ErrBadSignature {
+		t.Errorf("Expected %v for tampered body, got %v", ErrBadSignature, err)
+		t.Fail()
+	}
+
+	old := now - 600
+	header.Set(TimestampHeader, strconv.FormatInt(old, 10))
+	header.Set(SignatureHeader, Sign("secret", old, body))
+	if err := Verify("secret", header, body, time.Minute); err != ErrExpired {
+		t.Errorf("Expected %v, got %v", ErrExpired, err)
+		t.Fail()
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		expected time.Duration
+	}{
+		{1, 10 * time.Second},
+		{2, 20 * time.Second},
+		{4, 80 * time.Second},
+		{10, time.Hour},
+		{100, time.Hour},
+	}
+	for _, test := range tests {
+		if d := Backoff(test.attempts); d != test.expected {
+			t.Errorf("Expected backoff of %v after %d attempts, got %v", test.expected, test.attempts, d)
+			t.Fail()
+		}
+	}
+}
+
+func TestValidateURL(t *testing.T) {
+	for _, raw := range []string{"http://localhost:8080/hook", "https://example.com"} {
+		if err := ValidateURL(raw); err != nil {
+			t.Errorf("Expected %s to be valid, got %v", raw, err)
+			t.Fail()
+		}
+	}
+	for _, raw := range []string{"", "example.com", "ftp://example.com", "http://"} {
+		if err := ValidateURL(raw); err == nil {
+			t.Errorf("Expected %s to be invalid", raw)
+			t.Fail()
+		}
+	}
+}
+
+func TestDispatcherScopesUserWebhooks(t *testing.T) {
+	author, reactor, other := int64(1), int64(2), int64(3)
+	store := newMemoryStore(
+		data.Webhook{Mutable: data.Mutable{AutoIncr: data.AutoIncr{ID: 1}}, UserID: &author, Events: []string{EventPostReacted}},
+		data.Webhook{Mutable: data.Mutable{AutoIncr: data.AutoIncr{ID: 2}}, UserID: &other, Events: []string{EventPostReacted}},
+		data.Webhook{Mutable: data.Mutable{AutoIncr: data.AutoIncr{ID: 3}}, Events: Events},
+	)
+	dispatcher := NewDispatcher(data.Stores{
+		PostStore:    postStore{authorID: author},
+		WebhookStore: store,
+	})
+	dispatcher.now = func() time.Time { return time.Unix(100, 0) }
+
+	err := dispatcher.Publish(realtime.Event{Type: realtime.PostReactionsChanged, ID: 10, UserID: reactor})
+	if err != nil {
+		t.Error(err.Error())
+		t.FailNow()
+	}
+	if !contains(store.subscribed, author) || !contains(store.subscribed, reactor) {
+		t.Errorf("Expected the author and reactor to be involved, got %v", store.subscribed)
+		t.Fail()
+	}
+	if len(store.deliveries) != 2 {
+		t.Errorf("Expected deliveries to the author and admin webhooks, got %d deliveries", len(store.deliveries))
+		t.FailNow()
+	}
+	for _, d := range store.deliveries {
+		if d.WebhookID == 2 {
+			t.Error("Expected no delivery to an uninvolved user")
+			t.Fail()
+		}
+		var payload Payload
+		if err := json.Unmarshal(d.Payload, &payload); err != nil {
+			t.Error(err.Error())
+			t.FailNow()
+		}
+		expected := Payload{Event: EventPostReacted, Timestamp: 100, PostID: 10, AuthorID: author, UserID: reactor}
+		if payload != expected || d.Event != EventPostReacted {
+			t.Errorf("Expected payload %v, got %v", expected, payload)
+			t.Fail()
+		}
+	}
+
+	// events webhooks may not subscribe to are ignored
+	err = dispatcher.Publish(realtime.Event{Type: realtime.UserUnFollowed, ID: author, UserID: other})
+	if err != nil || len(store.deliveries) != 2 {
+		t.Errorf("Expected unfollows to be ignored, got %d deliveries and %v", len(store.deliveries), err)
+		t.Fail()
+	}
+}
+
+func TestDispatcherQueuesRelayedEventsOnce(t *testing.T) {
+	store := newMemoryStore(data.Webhook{Mutable: data.Mutable{AutoIncr: data.AutoIncr{ID: 1}}, Events: Events})
+	dispatcher := NewDispatcher(data.Stores{WebhookStore: store})
+
+	events := []realtime.Event{
+		{Type: realtime.PostCreated, ID: 10, UserID: 1, EventID: 7},
+		// relayed again after the relay failed to record its progress
+		{Type: realtime.PostCreated, ID: 10, UserID: 1, EventID: 7},
+		{Type: realtime.PostCreated, ID: 11, UserID: 1, EventID: 8},
+	}
+	for _, e := range events {
+		if err := dispatcher.Publish(e); err != nil {
+			t.Error(err.Error())
+			t.FailNow()
+		}
+	}
+	if len(store.deliveries) != 2 {
+		t.Errorf("Expected a delivery per outbox event, got %d deliveries", len(store.deliveries))
+		t.FailNow()
+	}
+	for i, eventID := range []int64{7, 8} {
+		if store.deliveries[i].EventID != eventID {
+			t.Errorf("Expected delivery %d of event %d, got %d", i, eventID, store.deliveries[i].EventID)
+			t.Fail()
+		}
+	}
+}
+
+func TestWorkerDeliversSignedPayload(t *testing.T) {
+	var received struct {
+		header http.Header
+		body   []byte
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received.header = r.Header
+		received.body, _ = ioutil.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	store := newMemoryStore(data.Webhook{
+		Mutable: data.Mutable{AutoIncr: data.AutoIncr{ID: 1}},
+		URL:     server.URL,
+		Secret:  "secret",
+		Events:  Events,
+	})
+	payload := json.RawMessage(`{"event":"post.created","postID":1}`)
+	id, _ := store.CreateDelivery(&data.WebhookDelivery{WebhookID: 1, Event: EventPostCreated, Payload: payload})
+	n, err := newTestWorker(store).DeliverDue()
+	if err != nil {
+		t.Error(err.Error())
+		t.FailNow()
+	}
+	if n != 1 {
+		t.Errorf("Expected 1 delivery attempted, got %d", n)
+		t.FailNow()
+	}
+	if err := Verify("secret", received.header, received.body, time.Minute); err != nil {
+		t.Errorf("Expected a valid signature, got %v", err)
+		t.Fail()
+	}
+	if string(received.body) != string(payload) || received.header.Get(EventHeader) != EventPostCreated {
+		t.Errorf("Expected %s event with body %s, got %s with %s",
+			EventPostCreated, payload, received.header.Get(EventHeader), received.body)
+		t.Fail()
+	}
+	d := store.delivery(id)
+	if d.Status != data.DeliverySucceeded || d.Attempts != 1 || d.ResponseStatus != http.StatusNoContent {
+		t.Errorf("Expected a successful first attempt, got %s after %d attempts with status %d",
+			d.Status, d.Attempts, d.ResponseStatus)
+		t.Fail()
+	}
+}
+
+func TestWorkerRetriesWithBackoff(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	store := newMemoryStore(data.Webhook{Mutable: data.Mutable{AutoIncr: data.AutoIncr{ID: 1}}, URL: server.URL, Events: Events})
+	id, _ := store.CreateDelivery(&data.WebhookDelivery{WebhookID: 1, Event: EventPostCreated, Payload: json.RawMessage("{}")})
+	worker := newTestWorker(store)
+	now := time.Unix(1000, 0)
+	worker.now = func() time.Time { return now }
+
+	if _, err := worker.DeliverDue(); err != nil {
+		t.Error(err.Error())
+		t.FailNow()
+	}
+	d := store.delivery(id)
+	if d.Status != data.DeliveryPending || d.ResponseStatus != http.StatusInternalServerError || d.LastError == "" {
+		t.Errorf("Expected a pending retry after a 500, got %s with status %d", d.Status, d.ResponseStatus)
+		t.Fail()
+	}
+	if !d.NextAttemptAt.Time.Equal(now.Add(Backoff(1))) {
+		t.Errorf("Expected next attempt at %v, got %v", now.Add(Backoff(1)), d.NextAttemptAt.Time)
+		t.Fail()
+	}
+
+	for i := 1; i < MaxAttempts; i++ {
+		if _, err := worker.DeliverDue(); err != nil {
+			t.Error(err.Error())
+			t.FailNow()
+		}
+	}
+	d = store.delivery(id)
+	if d.Status != data.DeliveryFailed || d.Attempts != MaxAttempts {
+		t.Errorf("Expected failure after %d attempts, got %s after %d", MaxAttempts, d.Status, d.Attempts)
+		t.Fail()
+	}
+	if n, _ := worker.DeliverDue(); n != 0 {
+		t.Error("Expected failed deliveries not to be attempted again")
+		t.Fail()
+	}
+}
+
+func TestWorkerRejectsPrivateAddresses(t *testing.T) {
+	received := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = true
+	}))
+	defer server.Close()
+
+	store := newMemoryStore(data.Webhook{Mutable: data.Mutable{AutoIncr: data.AutoIncr{ID: 1}}, URL: server.URL, Events: Events})
+	id, _ := store.CreateDelivery(&data.WebhookDelivery{WebhookID: 1, Event: EventPostCreated, Payload: json.RawMessage("{}")})
+	worker := NewWorker(store, false, zap.New(zap.NewTextEncoder()))
+	if _, err := worker.DeliverDue(); err != nil {
+		t.Error(err.Error())
+		t.FailNow()
+	}
+	if received {
+		t.Error("Expected the loopback receiver not to be reached")
+		t.Fail()
+	}
+	d := store.delivery(id)
+	if d.Status != data.DeliveryPending || !strings.Contains(d.LastError, ErrPrivateAddr.Error()) {
+		t.Errorf("Expected a pending retry failing with %v, got %s with %s", ErrPrivateAddr, d.Status, d.LastError)
+		t.Fail()
+	}
+}
+
+func TestWorkerDoesNotFollowRedirects(t *testing.T) {
+	followed := false
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		followed = true
+	}))
+	defer target.Close()
+	server := httptest.NewServer(http.RedirectHandler(target.URL, http.StatusTemporaryRedirect))
+	defer server.Close()
+
+	store := newMemoryStore(data.Webhook{Mutable: data.Mutable{AutoIncr: data.AutoIncr{ID: 1}}, URL: server.URL, Events: Events})
+	id, _ := store.CreateDelivery(&data.WebhookDelivery{WebhookID: 1, Event: EventPostCreated, Payload: json.RawMessage("{}")})
+	if _, err := newTestWorker(store).DeliverDue(); err != nil {
+		t.Error(err.Error())
+		t.FailNow()
+	}
+	if followed {
+		t.Error("Expected the redirect not to be followed")
+		t.Fail()
+	}
+	d := store.delivery(id)
+	if d.Status != data.DeliveryPending || d.ResponseStatus != http.StatusTemporaryRedirect {
+		t.Errorf("Expected a pending retry after a redirect, got %s with status %d", d.Status, d.ResponseStatus)
+		t.Fail()
+	}
+}
+
+func TestIsPublic(t *testing.T) {
+	for _, raw := range []string{"93.184.216.34", "8.8.8.8", "2606:2800:220:1:248:1893:25c8:1946"} {
+		if !isPublic(net.ParseIP(raw)) {
+			t.Errorf("Expected %s to be public", raw)
+			t.Fail()
+		}
+	}
+	for _, raw := range []string{"127.0.0.1", "10.1.2.3", "172.16.0.1", "192.168.1.1", "169.254.169.254",
+		"0.0.0.0", "100.64.0.1", "::1", "::", "fd00::1", "fe80::1", "::ffff:127.0.0.1"} {
+		if isPublic(net.ParseIP(raw)) {
+			t.Errorf("Expected %s not to be public", raw)
+			t.Fail()
+		}
+	}
+}
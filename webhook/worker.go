@@ -0,0 +1,200 @@
+package webhook
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/boxtown/meirl/data"
+	"github.com/boxtown/meirl/metrics"
+	"github.com/uber-go/zap"
+)
+
+const (
+	// MaxAttempts is the number of times a delivery is
+	// attempted before it is marked as failed
+	MaxAttempts = 8
+
+	// minBackoff and maxBackoff bound the delay between attempts
+	minBackoff = 10 * time.Second
+	maxBackoff = time.Hour
+
+	// maxResponseBytes is the most of a response body read
+	// so that the connection may be reused
+	maxResponseBytes = 64 << 10
+)
+
+// Backoff returns the delay before retrying a delivery that has
+// been attempted the given number of times. The delay doubles with
+// every attempt from 10 seconds up to an hour
+func Backoff(attempts int) time.Duration {
+	d := minBackoff
+	for i := 1; i < attempts && d < maxBackoff; i++ {
+		d *= 2
+	}
+	if d > maxBackoff {
+		d = maxBackoff
+	}
+	return d
+}
+
+// Worker attempts due webhook deliveries. Deliveries are claimed
+// from the store, so any number of workers may run concurrently
+type Worker struct {
+	store    data.WebhookStore
+	client   *http.Client
+	logger   zap.Logger
+	interval time.Duration
+	batch    int
+	lease    time.Duration
+	now      func() time.Time
+
+	done    chan struct{}
+	stopped chan struct{}
+	once    sync.Once
+}
+
+// NewWorker returns a newly constructed Worker that polls
+// the store for due deliveries every second. Deliveries are
+// only made to public addresses unless allowPrivate is true
+func NewWorker(store data.WebhookStore, allowPrivate bool, logger zap.Logger) *Worker {
+	return &Worker{
+		store:    store,
+		client:   newClient(allowPrivate),
+		logger:   logger,
+		interval: time.Second,
+		batch:    50,
+		lease:    time.Minute,
+		now:      time.Now,
+		done:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+}
+
+// Run attempts due deliveries until Stop is called
+func (w *Worker) Run() {
+	defer close(w.stopped)
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+			// keep going while there is a backlog
+			for {
+				n, err := w.DeliverDue()
+				if err != nil {
+					w.logger.Error("could not claim webhook deliveries", zap.Error(err))
+				}
+				if n < w.batch || w.stopping() {
+					break
+				}
+			}
+		}
+	}
+}
+
+// Stop signals Run to return once the attempts
+// in progress complete
+func (w *Worker) Stop() {
+	w.once.Do(func() {
+		close(w.done)
+	})
+}
+
+func (w *Worker) stopping() bool {
+	select {
+	case <-w.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Wait waits for Run to return after Stop is called
+func (w *Worker) Wait() {
+	<-w.stopped
+}
+
+// DeliverDue claims a batch of due deliveries and attempts them
+// concurrently, returning the number of deliveries attempted
+func (w *Worker) DeliverDue() (int, error) {
+	deliveries, err := w.store.ClaimDeliveries(w.batch, w.lease)
+	if err != nil {
+		return 0, err
+	}
+	var wg sync.WaitGroup
+	for i := range deliveries {
+		wg.Add(1)
+		go func(d *data.WebhookDelivery) {
+			defer wg.Done()
+			w.attempt(d)
+		}(&deliveries[i])
+	}
+	wg.Wait()
+	return len(deliveries), nil
+}
+
+// attempt sends the delivery and records the outcome, scheduling
+// a retry if the attempt failed and attempts remain
+func (w *Worker) attempt(d *data.WebhookDelivery) {
+	webhook, err := w.store.Get(d.WebhookID)
+	if err == data.ErrNoEnt {
+		// deleted along with its deliveries
+		return
+	} else if err != nil {
+		w.logger.Error("could not load webhook", zap.Int64("webhook_id", d.WebhookID), zap.Error(err))
+		return
+	}
+
+	d.Attempts++
+	d.ResponseStatus, err = w.send(webhook, d)
+	outcome := data.DeliverySucceeded
+	d.Status, d.LastError = data.DeliverySucceeded, ""
+	if err != nil {
+		d.LastError = err.Error()
+		if d.Attempts >= MaxAttempts {
+			d.Status, outcome = data.DeliveryFailed, data.DeliveryFailed
+		} else {
+			d.Status, outcome = data.DeliveryPending, "retrying"
+			d.NextAttemptAt = data.Time{Time: w.now().Add(Backoff(d.Attempts))}
+		}
+	}
+	metrics.WebhookAttempts.WithLabelValues(outcome).Inc()
+	if err := w.store.UpdateDelivery(d); err != nil {
+		// the lease expires and the delivery is attempted again
+		w.logger.Error("could not record webhook delivery", zap.Int64("delivery_id", d.ID), zap.Error(err))
+	}
+}
+
+// send posts the signed payload to the webhook, returning the
+// response status. Responses other than 2xx are errors
+func (w *Worker) send(webhook *data.Webhook, d *data.WebhookDelivery) (int, error) {
+	r, err := http.NewRequest("POST", webhook.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := w.now().Unix()
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("User-Agent", "MeIRL-Webhook")
+	r.Header.Set(EventHeader, d.Event)
+	r.Header.Set(DeliveryHeader, strconv.FormatInt(d.ID, 10))
+	r.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	r.Header.Set(SignatureHeader, Sign(webhook.Secret, timestamp, d.Payload))
+	resp, err := w.client.Do(r)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, maxResponseBytes))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}